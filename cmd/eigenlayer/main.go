@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	"github.com/Layr-Labs/eigenlayer-cli/internal/versionupdate"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
 
 	"github.com/urfave/cli/v2"
@@ -34,17 +36,29 @@ func main() {
 
 	// Initialize the dependencies
 	prompter := utils.NewPrompter()
+	app.Flags = append(app.Flags, pkg.GlobalFlags()...)
+	app.Before = pkg.BeforeRunAction()
+	afterRunAction := pkg.AfterRunAction()
 	app.After = func(c *cli.Context) error {
+		if telemetry.IsFlushRun(c) {
+			return nil
+		}
 		versionupdate.Check(app.Version)
-		return nil
+		return afterRunAction(c)
 	}
 
 	app.Commands = append(app.Commands, pkg.OperatorCmd(prompter))
 	app.Commands = append(app.Commands, pkg.RewardsCmd(prompter))
 	app.Commands = append(app.Commands, pkg.KeysCmd(prompter))
 	app.Commands = append(app.Commands, pkg.EigenPodCmd(prompter))
+	app.Commands = append(app.Commands, pkg.StatusCmd())
+	app.Commands = append(app.Commands, pkg.TxHistoryCmd())
+	app.Commands = append(app.Commands, pkg.HealthcheckCmd())
+	app.Commands = append(app.Commands, pkg.SelftestCmd())
+	app.Commands = append(app.Commands, telemetry.FlushCmd())
 
 	if err := app.Run(os.Args); err != nil {
+		pkg.RunErrorHook(context.Background(), err)
 		_, err := fmt.Fprintln(os.Stderr, err)
 		if err != nil {
 			return
@@ -5,11 +5,12 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"strconv"
 	"strings"
-	"time"
 
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/profile"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
 
@@ -37,6 +38,9 @@ func StatusCmd(p utils.Prompter) *cli.Command {
 		Name:  "status",
 		Usage: "Get the status of an EigenPod",
 		Action: func(c *cli.Context) error {
+			if profile.IsFanOut(c) {
+				return profile.FanOut(c)
+			}
 			return status(c, p)
 		},
 		After: telemetry.AfterRunAction(),
@@ -47,6 +51,8 @@ func StatusCmd(p utils.Prompter) *cli.Command {
 			&flags.OutputFileFlag,
 			&flags.OutputTypeFlag,
 			&PodAddressFlag,
+			&profile.AllProfilesFlag,
+			&profile.ProfilesFlag,
 		},
 	}
 }
@@ -61,7 +67,13 @@ func status(cCtx *cli.Context, p utils.Prompter) error {
 	}
 	cCtx.App.Metadata["network"] = cfg.chainID.String()
 	eigenPodStatus := core.GetStatus(ctx, cfg.podAddress, cfg.ethClient, cfg.beaconClient)
-	if cfg.outputType == "json" {
+	if cfg.outputType == string(common.OutputType_Markdown) {
+		out := getStatusMarkdown(cfg.podAddress, eigenPodStatus)
+		if cfg.outputFile != "" {
+			return common.WriteToFile([]byte(out), cfg.outputFile)
+		}
+		fmt.Print(out)
+	} else if cfg.outputType == "json" {
 		jsonData, err := json.MarshalIndent(eigenPodStatus, "", "  ")
 		if err != nil {
 			return err
@@ -107,9 +119,9 @@ func status(cCtx *cli.Context, p utils.Prompter) error {
 		)
 
 		if eigenPodStatus.ActiveCheckpoint != nil {
-			startTime := time.Unix(int64(eigenPodStatus.ActiveCheckpoint.StartedAt), 0)
+			startTime := common.FormatUnixTime(int64(eigenPodStatus.ActiveCheckpoint.StartedAt))
 
-			color.Blue("!NOTE: There is a checkpoint active! (started at: %s)\n", startTime.String())
+			color.Blue("!NOTE: There is a checkpoint active! (started at: %s)\n", startTime)
 			color.Blue("\t- If you finish it, you may receive up to %f shares. (%f -> %f)\n", deltaETH, eigenPodStatus.CurrentTotalSharesETH, eigenPodStatus.TotalSharesAfterCheckpointETH)
 			color.Blue("\t- %d proof(s) remaining until completion.\n", eigenPodStatus.ActiveCheckpoint.ProofsRemaining)
 		} else {
@@ -127,40 +139,63 @@ func status(cCtx *cli.Context, p utils.Prompter) error {
 	return nil
 }
 
-func prettyPrintValidator(validators []core.Validator) {
-	column := formatStringColumns("Validator Index", 15) +
-		" | " + formatStringColumns("Public Key", 98) +
-		" | " + formatStringColumns("Effective Balance (GWei)", 24) +
-		" | " + formatStringColumns("Current Balance (GWei)", 24) +
-		" | " + formatStringColumns("Slashed", 8)
-
-	fmt.Println(strings.Repeat("-", len(column)))
-	fmt.Println(column)
-	fmt.Println(strings.Repeat("-", len(column)))
-
-	for _, validator := range validators {
-		fmt.Printf(
-			"%s | %s | %s | %s | %s\n",
-			formatUint64Columns(validator.Index, 15),
-			formatStringColumns(validator.PublicKey, 98),
-			formatUint64Columns(validator.EffectiveBalance, 24),
-			formatUint64Columns(validator.CurrentBalance, 24),
-			formatBoolColumns(validator.Slashed, 8),
-		)
+// getStatusMarkdown returns the status of the pod as markdown, with a table of validators per status
+func getStatusMarkdown(podAddress string, eigenPodStatus core.EigenpodStatus) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## EigenPod %s\n\n", common.RedactText(podAddress))
+	fmt.Fprintf(&b, "- Proof submitter: `%s`\n", common.RedactText(eigenPodStatus.ProofSubmitter.String()))
+	fmt.Fprintf(
+		&b,
+		"- Current shares (ETH): %s\n",
+		common.RedactAmount(fmt.Sprintf("%f", eigenPodStatus.CurrentTotalSharesETH)),
+	)
+	fmt.Fprintf(
+		&b,
+		"- Shares after checkpoint (ETH): %s\n",
+		common.RedactAmount(fmt.Sprintf("%f", eigenPodStatus.TotalSharesAfterCheckpointETH)),
+	)
+
+	inactiveValidators, activeValidators, withdrawnValidators := core.SortByStatus(eigenPodStatus.Validators)
+	sections := []struct {
+		title      string
+		validators []core.Validator
+	}{
+		{title: "Inactive Validators", validators: inactiveValidators},
+		{title: "Active Validators", validators: activeValidators},
+		{title: "Withdrawn Validators", validators: withdrawnValidators},
 	}
-	fmt.Println(strings.Repeat("-", len(column)))
-}
-
-func formatBoolColumns(value bool, size int32) string {
-	return fmt.Sprintf("%-*t", size, value)
+	for _, section := range sections {
+		if len(section.validators) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s (%d)\n\n", section.title, len(section.validators))
+		b.WriteString(newValidatorsTable(section.validators).Markdown())
+	}
+	return b.String()
 }
 
-func formatUint64Columns(value uint64, size int32) string {
-	return fmt.Sprintf("%-*d", size, value)
+func prettyPrintValidator(validators []core.Validator) {
+	newValidatorsTable(validators).Print()
 }
 
-func formatStringColumns(columnName string, size int32) string {
-	return fmt.Sprintf("%-*s", size, columnName)
+func newValidatorsTable(validators []core.Validator) *common.Table {
+	table := common.NewTable(
+		common.TableColumn{Header: "Validator Index", Align: common.AlignRight},
+		common.TableColumn{Header: "Public Key"},
+		common.TableColumn{Header: "Effective Balance (GWei)", Align: common.AlignRight},
+		common.TableColumn{Header: "Current Balance (GWei)", Align: common.AlignRight},
+		common.TableColumn{Header: "Slashed"},
+	)
+	for _, validator := range validators {
+		table.AddRow(
+			strconv.FormatUint(validator.Index, 10),
+			validator.PublicKey,
+			strconv.FormatUint(validator.EffectiveBalance, 10),
+			strconv.FormatUint(validator.CurrentBalance, 10),
+			strconv.FormatBool(validator.Slashed),
+		)
+	}
+	return table
 }
 
 func readAndValidateConfig(c *cli.Context, logger logging.Logger) (*statusConfig, error) {
@@ -170,7 +205,7 @@ func readAndValidateConfig(c *cli.Context, logger logging.Logger) (*statusConfig
 	chainID := utils.NetworkNameToChainId(network)
 
 	ethRpcUrl := c.String(flags.ETHRpcUrlFlag.Name)
-	ethRpcClient, err := ethclient.Dial(ethRpcUrl)
+	ethRpcClient, err := common.DialEthClient(ethRpcUrl)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,264 @@
+package pkg
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/profile"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	ReadOnlyFlag = cli.BoolFlag{
+		Name:    "read-only",
+		Usage:   "Refuse to construct, sign or send any transaction",
+		EnvVars: []string{"EIGENLAYER_READ_ONLY"},
+	}
+
+	SkipCompatibilityCheckFlag = cli.BoolFlag{
+		Name:    "skip-compatibility-check",
+		Usage:   "Run commands even if the deployed contracts are known to be incompatible with this version of the CLI",
+		EnvVars: []string{"EIGENLAYER_SKIP_COMPATIBILITY_CHECK"},
+	}
+
+	VerifyContractCodeFlag = cli.BoolFlag{
+		Name:    "verify-contract-code",
+		Usage:   "Verify the deployed code of the target of every transaction has the called function before sending it",
+		EnvVars: []string{"EIGENLAYER_VERIFY_CONTRACT_CODE"},
+	}
+
+	ConfirmationsFlag = cli.Uint64Flag{
+		Name:    "confirmations",
+		Usage:   "Number of blocks to wait for on top of the block of a transaction, re-checking its receipt for reorgs",
+		EnvVars: []string{"EIGENLAYER_CONFIRMATIONS"},
+	}
+
+	RebroadcastOnReorgFlag = cli.BoolFlag{
+		Name:    "rebroadcast-on-reorg",
+		Usage:   "Send a transaction again if its confirmation disappears after a reorg",
+		EnvVars: []string{"EIGENLAYER_REBROADCAST_ON_REORG"},
+	}
+
+	StatsFlag = cli.StringFlag{
+		Name:    "stats",
+		Usage:   "Print the duration of the phases, the RPC calls and the downloaded bytes to stderr. One of 'pretty' or 'json'",
+		EnvVars: []string{"EIGENLAYER_STATS"},
+	}
+
+	MetaFileFlag = cli.StringFlag{
+		Name:    "meta-file",
+		Usage:   "Write the metadata of the run, e.g. the verification of downloaded rewards snapshots, to this JSON file",
+		EnvVars: []string{"EIGENLAYER_META_FILE"},
+	}
+
+	RedactFlag = cli.BoolFlag{
+		Name:    "redact",
+		Usage:   "Mask addresses and amounts in human-readable output and logs, so they can be shared publicly",
+		EnvVars: []string{"EIGENLAYER_REDACT"},
+	}
+
+	LocaleFlag = cli.StringFlag{
+		Name:    "locale",
+		Usage:   "Locale of numbers and timestamps in human-readable output, e.g. 'de-DE'. JSON and CSV stay as they are",
+		EnvVars: []string{"EIGENLAYER_LOCALE"},
+	}
+
+	TimezoneFlag = cli.StringFlag{
+		Name:    "tz",
+		Usage:   "Time zone of timestamps in human-readable output. 'local', 'utc' or a name like 'Europe/Berlin'",
+		Value:   common.TimezoneLocal,
+		EnvVars: []string{"EIGENLAYER_TZ"},
+	}
+
+	PreBroadcastHookFlag = cli.StringFlag{
+		Name:    "pre-broadcast-hook",
+		Usage:   "Script to run before a transaction is sent. A non-zero exit code stops the transaction",
+		EnvVars: []string{"EIGENLAYER_PRE_BROADCAST_HOOK"},
+	}
+
+	PostReceiptHookFlag = cli.StringFlag{
+		Name:    "post-receipt-hook",
+		Usage:   "Script to run after the receipt of a transaction is received",
+		EnvVars: []string{"EIGENLAYER_POST_RECEIPT_HOOK"},
+	}
+
+	OnErrorHookFlag = cli.StringFlag{
+		Name:    "on-error-hook",
+		Usage:   "Script to run when a command fails",
+		EnvVars: []string{"EIGENLAYER_ON_ERROR_HOOK"},
+	}
+)
+
+// GlobalFlags are the app level flags which apply to every command
+func GlobalFlags() []cli.Flag {
+	return append([]cli.Flag{
+		&ReadOnlyFlag,
+		&SkipCompatibilityCheckFlag,
+		&VerifyContractCodeFlag,
+		&ConfirmationsFlag,
+		&RebroadcastOnReorgFlag,
+		&StatsFlag,
+		&MetaFileFlag,
+		&RedactFlag,
+		&LocaleFlag,
+		&TimezoneFlag,
+		&PreBroadcastHookFlag,
+		&PostReceiptHookFlag,
+		&OnErrorHookFlag,
+		&flags.GlobalVerboseFlag,
+		&flags.VeryVerboseFlag,
+		&flags.TraceFlag,
+		&flags.LogFilterFlag,
+	}, profile.GlobalFlags()...)
+}
+
+// BeforeRunAction sets up the global flags before any command runs
+func BeforeRunAction() cli.BeforeFunc {
+	profileBeforeRunAction := profile.BeforeRunAction()
+	return func(cCtx *cli.Context) error {
+		// Nothing is left over from an earlier run in the same process, e.g. a job of the scheduler
+		common.ResetRunState()
+		if cCtx.Bool(ReadOnlyFlag.Name) {
+			common.SetReadOnly()
+		}
+		if cCtx.Bool(SkipCompatibilityCheckFlag.Name) {
+			common.SetSkipCompatibilityCheck()
+		}
+		if cCtx.Bool(VerifyContractCodeFlag.Name) {
+			common.SetVerifyContractCode()
+		}
+		common.SetConfirmations(cCtx.Uint64(ConfirmationsFlag.Name))
+		if cCtx.Bool(RebroadcastOnReorgFlag.Name) {
+			common.SetRebroadcastOnReorg()
+		}
+		if format := cCtx.String(StatsFlag.Name); !common.IsEmptyString(format) {
+			if err := common.EnableStats(common.OutputType(format)); err != nil {
+				return err
+			}
+		}
+		common.SetMetaFile(cCtx.String(MetaFileFlag.Name))
+		if cCtx.Bool(RedactFlag.Name) {
+			common.SetRedact()
+		}
+		if name := cCtx.String(LocaleFlag.Name); !common.IsEmptyString(name) {
+			if err := common.SetLocale(name); err != nil {
+				return err
+			}
+		}
+		if err := common.SetTimezone(cCtx.String(TimezoneFlag.Name)); err != nil {
+			return err
+		}
+		if err := profileBeforeRunAction(cCtx); err != nil {
+			return err
+		}
+		setHooks(cCtx)
+		checkCompatibilityOnStart(cCtx.App.Commands)
+		return nil
+	}
+}
+
+// AfterRunAction prints the stats of the run, if enabled, and writes the metadata of the run
+func AfterRunAction() cli.AfterFunc {
+	return func(cCtx *cli.Context) error {
+		common.PrintRunStats(os.Stderr)
+		return common.WriteRunMeta()
+	}
+}
+
+// RunErrorHook runs the on-error hook, if one is set, with the error the app failed with
+func RunErrorHook(ctx context.Context, err error) {
+	common.RunErrorHook(ctx, err)
+}
+
+// setHooks sets the hooks of the run. Hooks set with flags take precedence over the ones of the profile.
+func setHooks(cCtx *cli.Context) {
+	hooks := common.GetHooks()
+	if cCtx.IsSet(PreBroadcastHookFlag.Name) {
+		hooks.PreBroadcast = cCtx.String(PreBroadcastHookFlag.Name)
+	}
+	if cCtx.IsSet(PostReceiptHookFlag.Name) {
+		hooks.PostReceipt = cCtx.String(PostReceiptHookFlag.Name)
+	}
+	if cCtx.IsSet(OnErrorHookFlag.Name) {
+		hooks.OnError = cCtx.String(OnErrorHookFlag.Name)
+	}
+	common.SetHooks(hooks)
+	common.SetHookCommand(getCommandPath(cCtx.App.Commands, cCtx.Args().Slice()))
+}
+
+// checkCompatibilityOnStart checks the deployed contracts before any command which talks to a
+// network runs. The network and RPC are flags of the commands, so the check runs once the flags of
+// the command are parsed, after its own Before.
+func checkCompatibilityOnStart(commands []*cli.Command) {
+	for _, command := range commands {
+		checkCompatibilityOnStart(command.Subcommands)
+		if command.Action == nil || !hasFlag(command, flags.NetworkFlag.Name) || !hasFlag(command, flags.ETHRpcUrlFlag.Name) {
+			continue
+		}
+		before := command.Before
+		command.Before = func(cCtx *cli.Context) error {
+			if before != nil {
+				if err := before(cCtx); err != nil {
+					return err
+				}
+			}
+			return checkCompatibility(cCtx)
+		}
+	}
+}
+
+func checkCompatibility(cCtx *cli.Context) error {
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	// Every profile of a fan-out is checked by its own run
+	if common.IsEmptyString(rpcUrl) || profile.IsFanOut(cCtx) {
+		return nil
+	}
+	ethClient, err := common.DialEthClient(rpcUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+	defer ethClient.Close()
+	chainID := utils.NetworkNameToChainId(cCtx.String(flags.NetworkFlag.Name))
+	return common.CheckCompatibility(cCtx, ethClient, chainID, common.GetLogger(cCtx))
+}
+
+func hasFlag(command *cli.Command, name string) bool {
+	for _, flag := range command.Flags {
+		for _, flagName := range flag.Names() {
+			if flagName == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getCommandPath returns the name of the command the args select, e.g. 'rewards claim'
+func getCommandPath(commands []*cli.Command, args []string) string {
+	path := make([]string, 0)
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		var command *cli.Command
+		for _, c := range commands {
+			if c.HasName(arg) {
+				command = c
+				break
+			}
+		}
+		if command == nil {
+			break
+		}
+		path = append(path, command.Name)
+		commands = command.Subcommands
+	}
+	return strings.Join(path, " ")
+}
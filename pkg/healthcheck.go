@@ -0,0 +1,105 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/healthcheck"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/profile"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	HeartbeatFileFlag = cli.StringFlag{
+		Name:    "heartbeat-file",
+		Usage:   "Heartbeat file of the daemon to check. Defaults to every heartbeat in $HOME/.eigenlayer/heartbeats",
+		EnvVars: []string{"EIGENLAYER_HEARTBEAT_FILE"},
+	}
+
+	MaxHeartbeatAgeFlag = cli.DurationFlag{
+		Name:    "max-heartbeat-age",
+		Usage:   "Maximum age of the heartbeat of daemon mode. Set to 0 to skip the heartbeat check",
+		Value:   15 * time.Minute,
+		EnvVars: []string{"EIGENLAYER_MAX_HEARTBEAT_AGE"},
+	}
+
+	CacheDirFlag = cli.StringFlag{
+		Name:    "cache-dir",
+		Usage:   "Directory the CLI keeps its state in. Defaults to $HOME/.eigenlayer",
+		EnvVars: []string{"EIGENLAYER_CACHE_DIR"},
+	}
+)
+
+func HealthcheckCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "healthcheck",
+		Usage:     "Fast local check of the CLI setup and daemon mode, for container and Kubernetes probes",
+		UsageText: "healthcheck",
+		Description: `
+Checks without any network access that
+- the profiles file is readable and valid
+- the cache directory is writable
+- the heartbeats of daemon mode are fresher than --max-heartbeat-age. Every 'operator monitor' and
+  embedded scheduler writes its own heartbeat in $HOME/.eigenlayer/heartbeats and removes it when it
+  stops, so a stale heartbeat is a stuck or crashed daemon. --heartbeat-file checks a single one
+
+Exits with 0 if all checks pass and with 1 otherwise, so it can be used as a Dockerfile HEALTHCHECK
+or a Kubernetes liveness probe:
+
+HEALTHCHECK --interval=1m CMD eigenlayer healthcheck
+		`,
+		Flags: []cli.Flag{
+			&HeartbeatFileFlag,
+			&MaxHeartbeatAgeFlag,
+			&CacheDirFlag,
+		},
+		Action: func(cCtx *cli.Context) error {
+			profilesFile := cCtx.String(profile.ProfilesFileFlag.Name)
+			explicit := !common.IsEmptyString(profilesFile)
+			if !explicit {
+				profilesFile = profile.DefaultPath()
+			}
+
+			cacheDir := cCtx.String(CacheDirFlag.Name)
+			if common.IsEmptyString(cacheDir) {
+				homePath, err := os.UserHomeDir()
+				if err != nil {
+					return cli.Exit(fmt.Sprintf("failed to find the home directory: %s", err), 1)
+				}
+				cacheDir = filepath.Join(homePath, ".eigenlayer")
+			}
+
+			results := []healthcheck.Result{
+				healthcheck.CheckConfig(profilesFile, explicit),
+				healthcheck.CheckCacheDir(cacheDir),
+			}
+			if maxAge := cCtx.Duration(MaxHeartbeatAgeFlag.Name); maxAge > 0 {
+				heartbeatFile := cCtx.String(HeartbeatFileFlag.Name)
+				if common.IsEmptyString(heartbeatFile) {
+					results = append(results, healthcheck.CheckHeartbeats(common.GetHeartbeatDir(), maxAge, time.Now())...)
+				} else {
+					results = append(results, healthcheck.CheckHeartbeat(heartbeatFile, maxAge, time.Now()))
+				}
+			}
+
+			healthy := true
+			for _, result := range results {
+				mark := utils.EmojiCheckMark
+				if !result.OK {
+					mark = utils.EmojiCrossMark
+					healthy = false
+				}
+				fmt.Printf("%s %s: %s\n", mark, result.Check, result.Message)
+			}
+			if !healthy {
+				return cli.Exit("healthcheck failed", 1)
+			}
+			return nil
+		},
+	}
+}
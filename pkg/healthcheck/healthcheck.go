@@ -0,0 +1,106 @@
+package healthcheck
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/profile"
+)
+
+// Result is the outcome of a single local check. Checks never talk to the network, so the
+// healthcheck stays fast enough for container probes.
+type Result struct {
+	Check   string `json:"check"`
+	OK      bool   `json:"ok"`
+	Message string `json:"message"`
+}
+
+// CheckConfig checks the profiles file can be read and parsed. A missing file is fine unless it
+// was set explicitly, since profiles are optional.
+func CheckConfig(path string, explicit bool) Result {
+	result := Result{Check: "config"}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) && !explicit {
+		result.OK = true
+		result.Message = fmt.Sprintf("no profiles file at %s", path)
+		return result
+	}
+	config, err := profile.Load(path)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	result.OK = true
+	result.Message = fmt.Sprintf("%d profiles in %s", len(config.Names()), path)
+	return result
+}
+
+// CheckCacheDir checks files can be created in the directory the CLI keeps its state in, e.g.
+// the tx history and the telemetry queue
+func CheckCacheDir(dir string) Result {
+	result := Result{Check: "cache"}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		result.Message = fmt.Sprintf("failed to create %s: %s", dir, err)
+		return result
+	}
+	file, err := os.CreateTemp(dir, ".healthcheck-*")
+	if err != nil {
+		result.Message = fmt.Sprintf("%s is not writable: %s", dir, err)
+		return result
+	}
+	_ = file.Close()
+	_ = os.Remove(file.Name())
+	result.OK = true
+	result.Message = fmt.Sprintf("%s is writable", dir)
+	return result
+}
+
+// CheckHeartbeats checks every daemon with a heartbeat in dir wrote it within maxAge. Daemons
+// remove their heartbeat when they stop, so a stale one is a stuck or crashed daemon.
+func CheckHeartbeats(dir string, maxAge time.Duration, now time.Time) []Result {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return []Result{{Check: "heartbeat", Message: err.Error()}}
+	}
+	if len(paths) == 0 {
+		return []Result{{Check: "heartbeat", Message: fmt.Sprintf("no heartbeat in %s, no daemon completed a round", dir)}}
+	}
+	sort.Strings(paths)
+	results := make([]Result, 0, len(paths))
+	for _, path := range paths {
+		results = append(results, CheckHeartbeat(path, maxAge, now))
+	}
+	return results
+}
+
+// CheckHeartbeat checks the daemon wrote its heartbeat within maxAge
+func CheckHeartbeat(path string, maxAge time.Duration, now time.Time) Result {
+	result := Result{Check: "heartbeat"}
+	heartbeat, err := common.ReadHeartbeat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		result.Message = fmt.Sprintf("no heartbeat at %s, the daemon never completed a round", path)
+		return result
+	}
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	age := now.Sub(heartbeat.Timestamp).Truncate(time.Second)
+	if age > maxAge {
+		result.Message = fmt.Sprintf(
+			"last heartbeat of %s in %s is %s old, more than %s",
+			heartbeat.Command,
+			path,
+			age,
+			maxAge,
+		)
+		return result
+	}
+	result.OK = true
+	result.Message = fmt.Sprintf("last heartbeat of %s in %s is %s old", heartbeat.Command, path, age)
+	return result
+}
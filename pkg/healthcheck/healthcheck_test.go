@@ -0,0 +1,63 @@
+package healthcheck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckConfig(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing.yaml")
+	assert.True(t, CheckConfig(missing, false).OK)
+	assert.False(t, CheckConfig(missing, true).OK)
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	assert.NoError(t, os.WriteFile(invalid, []byte("profiles: ["), 0o600))
+	assert.False(t, CheckConfig(invalid, false).OK)
+
+	valid := filepath.Join(dir, "profiles.yaml")
+	assert.NoError(t, os.WriteFile(valid, []byte("profiles:\n  mainnet:\n    flags:\n      network: mainnet\n"), 0o600))
+	result := CheckConfig(valid, false)
+	assert.True(t, result.OK, result.Message)
+}
+
+func TestCheckCacheDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), ".eigenlayer")
+	assert.True(t, CheckCacheDir(dir).OK)
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestCheckHeartbeat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "heartbeat.json")
+	now := time.Now()
+	assert.False(t, CheckHeartbeat(path, time.Minute, now).OK)
+
+	assert.NoError(t, common.WriteHeartbeat(path, "operator monitor", now.Add(-30*time.Second)))
+	assert.True(t, CheckHeartbeat(path, time.Minute, now).OK)
+	assert.False(t, CheckHeartbeat(path, 10*time.Second, now).OK)
+}
+
+func TestCheckHeartbeats(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	results := CheckHeartbeats(dir, time.Minute, now)
+	assert.Len(t, results, 1)
+	assert.False(t, results[0].OK)
+
+	// Every daemon has its own heartbeat, a stuck one fails the healthcheck
+	assert.NoError(t, common.WriteHeartbeat(filepath.Join(dir, "operator-monitor-1.json"), "operator monitor", now))
+	assert.NoError(t, common.WriteHeartbeat(filepath.Join(dir, "scheduler-2.json"), "scheduler", now.Add(-time.Hour)))
+	results = CheckHeartbeats(dir, time.Minute, now)
+	assert.Len(t, results, 2)
+	assert.True(t, results[0].OK)
+	assert.False(t, results[1].OK)
+	assert.Contains(t, results[1].Message, "scheduler")
+}
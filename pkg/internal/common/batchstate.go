@@ -0,0 +1,137 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const batchStateVersion = 1
+
+var ErrBatchStateMismatch = errors.New("state file belongs to a different batch")
+
+// BatchState is the progress of a long batch operation. It is written to a state file after every
+// completed step, so an interrupted run can be resumed with --resume, skipping completed items.
+// Items are pending while their transaction is sent. A run interrupted then can't tell whether
+// the transaction landed, so the command checks the pending items on chain when it is resumed.
+type BatchState struct {
+	Version   int                  `json:"version"`
+	Command   string               `json:"command"`
+	InputHash string               `json:"inputHash"`
+	Params    map[string]string    `json:"params,omitempty"`
+	Completed map[string]BatchItem `json:"completed"`
+	Pending   map[string]time.Time `json:"pending,omitempty"`
+	UpdatedAt time.Time            `json:"updatedAt"`
+	path      string
+}
+
+// BatchItem is a completed item of a batch
+type BatchItem struct {
+	TxHash      string    `json:"txHash"`
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+// HashBatchInput returns the hash a state file uses to make sure it is resumed with the same input
+func HashBatchInput(input []byte) string {
+	hash := sha256.Sum256(input)
+	return hex.EncodeToString(hash[:])
+}
+
+// LoadBatchState reads the state file at path, or starts a new state if there is none yet. The
+// state has to be for the same command, input and params, since the completed items would
+// otherwise refer to something else.
+func LoadBatchState(path string, command string, inputHash string, params map[string]string) (*BatchState, error) {
+	state := &BatchState{
+		Version:   batchStateVersion,
+		Command:   command,
+		InputHash: inputHash,
+		Params:    params,
+		Completed: make(map[string]BatchItem),
+		Pending:   make(map[string]time.Time),
+		path:      path,
+	}
+
+	data, err := ReadFileLocked(path)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(data) == 0) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var saved BatchState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	if saved.Version != batchStateVersion {
+		return nil, fmt.Errorf("unsupported state file version %d", saved.Version)
+	}
+	if saved.Command != command {
+		return nil, fmt.Errorf("%w: state is for command '%s', not '%s'", ErrBatchStateMismatch, saved.Command, command)
+	}
+	if saved.InputHash != inputHash {
+		return nil, fmt.Errorf("%w: the batch input changed since the state was written", ErrBatchStateMismatch)
+	}
+	for key, value := range params {
+		if saved.Params[key] != value {
+			return nil, fmt.Errorf(
+				"%w: state is for %s %s, not %s",
+				ErrBatchStateMismatch,
+				key,
+				saved.Params[key],
+				value,
+			)
+		}
+	}
+	if saved.Completed != nil {
+		state.Completed = saved.Completed
+	}
+	if saved.Pending != nil {
+		state.Pending = saved.Pending
+	}
+	return state, nil
+}
+
+func (s *BatchState) IsCompleted(key string) bool {
+	_, ok := s.Completed[key]
+	return ok
+}
+
+func (s *BatchState) IsPending(key string) bool {
+	_, ok := s.Pending[key]
+	return ok
+}
+
+// MarkPending records the items as sent in a transaction whose receipt is not known yet and
+// persists the state
+func (s *BatchState) MarkPending(keys []string) error {
+	now := time.Now().UTC()
+	for _, key := range keys {
+		s.Pending[key] = now
+	}
+	return s.write(now)
+}
+
+// MarkCompleted records the items as completed by the transaction and persists the state. The
+// transaction hash is empty for pending items which were found completed on chain.
+func (s *BatchState) MarkCompleted(keys []string, txHash string) error {
+	now := time.Now().UTC()
+	for _, key := range keys {
+		s.Completed[key] = BatchItem{TxHash: txHash, CompletedAt: now}
+		delete(s.Pending, key)
+	}
+	return s.write(now)
+}
+
+func (s *BatchState) write(now time.Time) error {
+	s.UpdatedAt = now
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileLocked(data, s.path, 0o600)
+}
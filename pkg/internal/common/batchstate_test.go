@@ -0,0 +1,54 @@
+package common
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchStateResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "claims.state")
+	inputHash := HashBatchInput([]byte("- earner_address: 0x1"))
+	params := map[string]string{"rootIndex": "5"}
+
+	state, err := LoadBatchState(path, "rewards claim", inputHash, params)
+	assert.NoError(t, err)
+	assert.False(t, state.IsCompleted("0:0x1"))
+	assert.NoError(t, state.MarkCompleted([]string{"0:0x1"}, "0xabc"))
+
+	resumed, err := LoadBatchState(path, "rewards claim", inputHash, params)
+	assert.NoError(t, err)
+	assert.True(t, resumed.IsCompleted("0:0x1"))
+	assert.Equal(t, "0xabc", resumed.Completed["0:0x1"].TxHash)
+	assert.False(t, resumed.IsCompleted("1:0x2"))
+
+	_, err = LoadBatchState(path, "rewards claim", HashBatchInput([]byte("changed")), params)
+	assert.True(t, errors.Is(err, ErrBatchStateMismatch))
+	_, err = LoadBatchState(path, "rewards claim", inputHash, map[string]string{"rootIndex": "6"})
+	assert.True(t, errors.Is(err, ErrBatchStateMismatch))
+	_, err = LoadBatchState(path, "operator register", inputHash, params)
+	assert.True(t, errors.Is(err, ErrBatchStateMismatch))
+}
+
+func TestBatchStatePending(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "claims.state")
+	inputHash := HashBatchInput([]byte("- earner_address: 0x1"))
+
+	state, err := LoadBatchState(path, "rewards claim", inputHash, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, state.MarkPending([]string{"0:0x1", "1:0x2"}))
+
+	resumed, err := LoadBatchState(path, "rewards claim", inputHash, nil)
+	assert.NoError(t, err)
+	assert.True(t, resumed.IsPending("0:0x1"))
+	assert.False(t, resumed.IsCompleted("0:0x1"))
+
+	assert.NoError(t, resumed.MarkCompleted([]string{"0:0x1"}, ""))
+	resumed, err = LoadBatchState(path, "rewards claim", inputHash, nil)
+	assert.NoError(t, err)
+	assert.False(t, resumed.IsPending("0:0x1"))
+	assert.True(t, resumed.IsCompleted("0:0x1"))
+	assert.True(t, resumed.IsPending("1:0x2"))
+}
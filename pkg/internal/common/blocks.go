@@ -0,0 +1,60 @@
+package common
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// LogsChunkBlocks is the block range of a log query, which most RPC providers accept
+const LogsChunkBlocks = 10000
+
+type HeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error)
+}
+
+// BlockAtTimestamp returns the first block with a timestamp at or after timestamp, found with a
+// binary search over the block headers. For a timestamp after the head it returns the block after
+// the head.
+func BlockAtTimestamp(ctx context.Context, client HeaderReader, timestamp uint64) (uint64, error) {
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	if head.Time < timestamp {
+		return head.Number.Uint64() + 1, nil
+	}
+	low, high := uint64(0), head.Number.Uint64()
+	for low < high {
+		mid := low + (high-low)/2
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return 0, err
+		}
+		if header.Time < timestamp {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low, nil
+}
+
+// FilterBlockRange runs filter on the blocks from start to end, LogsChunkBlocks blocks at a time,
+// in chain order
+func FilterBlockRange(
+	ctx context.Context,
+	start uint64,
+	end uint64,
+	filter func(opts *bind.FilterOpts) error,
+) error {
+	for from := start; from <= end; from += LogsChunkBlocks {
+		to := min(from+LogsChunkBlocks-1, end)
+		if err := filter(&bind.FilterOpts{Start: from, End: &to, Context: ctx}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package common
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeHeaderReader has blocks 0 to len-1 with the timestamps in the slice
+type fakeHeaderReader []uint64
+
+func (f fakeHeaderReader) HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error) {
+	if number == nil {
+		number = big.NewInt(int64(len(f) - 1))
+	}
+	return &gethtypes.Header{Number: number, Time: f[number.Int64()]}, nil
+}
+
+func TestBlockAtTimestamp(t *testing.T) {
+	blocks := fakeHeaderReader{100, 112, 124, 136, 148, 160}
+	tests := []struct {
+		timestamp uint64
+		want      uint64
+	}{
+		{timestamp: 0, want: 0},
+		{timestamp: 100, want: 0},
+		{timestamp: 101, want: 1},
+		{timestamp: 136, want: 3},
+		{timestamp: 150, want: 5},
+		{timestamp: 160, want: 5},
+		{timestamp: 161, want: 6},
+	}
+	for _, tt := range tests {
+		block, err := BlockAtTimestamp(context.Background(), blocks, tt.timestamp)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, block, "timestamp %d", tt.timestamp)
+	}
+}
+
+func TestFilterBlockRange(t *testing.T) {
+	ranges := make([][2]uint64, 0)
+	err := FilterBlockRange(context.Background(), 5, 2*LogsChunkBlocks+10, func(opts *bind.FilterOpts) error {
+		ranges = append(ranges, [2]uint64{opts.Start, *opts.End})
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]uint64{
+		{5, LogsChunkBlocks + 4},
+		{LogsChunkBlocks + 5, 2*LogsChunkBlocks + 4},
+		{2*LogsChunkBlocks + 5, 2*LogsChunkBlocks + 10},
+	}, ranges)
+
+	ranges = ranges[:0]
+	err = FilterBlockRange(context.Background(), 10, 9, func(opts *bind.FilterOpts) error {
+		ranges = append(ranges, [2]uint64{opts.Start, *opts.End})
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, ranges)
+}
@@ -0,0 +1,189 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IRewardsCoordinator"
+	eigensdkLogger "github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var ErrContractDrift = errors.New("target contract doesn't match the expected ABI")
+
+// implementationSlot is the EIP-1967 storage slot holding the implementation of a proxy
+var implementationSlot = gethcommon.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+
+// contractABIs is the registry of the ABIs the CLI encodes transactions with, by contract
+var contractABIs = map[string]string{
+	ContractRewardsCoordinator: rewardscoordinator.ContractIRewardsCoordinatorMetaData.ABI,
+	ContractDelegationManager:  delegationmanager.ContractDelegationManagerMetaData.ABI,
+}
+
+var verifyContractCode atomic.Bool
+
+// SetVerifyContractCode makes every transaction check the code of its target before it is sent
+func SetVerifyContractCode() {
+	verifyContractCode.Store(true)
+}
+
+// codeReader is the part of the eth client needed to read deployed code
+type codeReader interface {
+	CodeAt(ctx context.Context, contract gethcommon.Address, blockNumber *big.Int) ([]byte, error)
+	StorageAt(ctx context.Context, account gethcommon.Address, key gethcommon.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+// codeCheckTxManager verifies the code deployed at the target of every transaction before sending
+// it, to catch a config pointing at a proxy which was upgraded to an incompatible implementation.
+// The function called has to be in the dispatcher of the code, and for contracts of the registry
+// it also has to be part of the ABI the CLI was built with.
+type codeCheckTxManager struct {
+	txmgr.TxManager
+	client  codeReader
+	chainID *big.Int
+	logger  eigensdkLogger.Logger
+}
+
+func withCodeCheck(
+	txMgr txmgr.TxManager,
+	client codeReader,
+	chainID *big.Int,
+	logger eigensdkLogger.Logger,
+) txmgr.TxManager {
+	if !verifyContractCode.Load() {
+		return txMgr
+	}
+	return &codeCheckTxManager{TxManager: txMgr, client: client, chainID: chainID, logger: logger}
+}
+
+func (m *codeCheckTxManager) Send(
+	ctx context.Context,
+	tx *gethtypes.Transaction,
+	waitForReceipt bool,
+) (*gethtypes.Receipt, error) {
+	if err := m.verify(ctx, tx); err != nil {
+		return nil, err
+	}
+	return m.TxManager.Send(ctx, tx, waitForReceipt)
+}
+
+func (m *codeCheckTxManager) verify(ctx context.Context, tx *gethtypes.Transaction) error {
+	// Plain transfers and contract creations don't call a function
+	if tx.To() == nil || len(tx.Data()) < 4 {
+		return nil
+	}
+	target := *tx.To()
+	selector := tx.Data()[:4]
+
+	code, err := m.client.CodeAt(ctx, target, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get code of %s: %w", target.Hex(), err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("%w: no contract deployed at %s", ErrContractDrift, target.Hex())
+	}
+
+	implementation := target
+	slot, err := m.client.StorageAt(ctx, target, implementationSlot, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get implementation of %s: %w", target.Hex(), err)
+	}
+	if address := gethcommon.BytesToAddress(slot); address != (gethcommon.Address{}) {
+		implementation = address
+		code, err = m.client.CodeAt(ctx, implementation, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get code of %s: %w", implementation.Hex(), err)
+		}
+	}
+	m.logger.Debugf(
+		"Code hash of %s (implementation %s): %s",
+		target.Hex(),
+		implementation.Hex(),
+		crypto.Keccak256Hash(code).Hex(),
+	)
+
+	if contract := getRegistryContract(m.chainID, target); contract != "" {
+		ok, err := hasABIMethod(contractABIs[contract], selector)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf(
+				"%w: selector %s is not a function of the %s ABI",
+				ErrContractDrift,
+				hexutil.Encode(selector),
+				contract,
+			)
+		}
+	}
+	if !hasSelector(code, selector) {
+		return fmt.Errorf(
+			"%w: implementation %s of %s has no function with selector %s",
+			ErrContractDrift,
+			implementation.Hex(),
+			target.Hex(),
+			hexutil.Encode(selector),
+		)
+	}
+	return nil
+}
+
+// getRegistryContract returns the name of the registry contract deployed at the address
+func getRegistryContract(chainID *big.Int, address gethcommon.Address) string {
+	chainMetadata, ok := ChainMetadataMap[chainID.Int64()]
+	if !ok {
+		return ""
+	}
+	switch {
+	case strings.EqualFold(chainMetadata.ELRewardsCoordinatorAddress, address.Hex()):
+		return ContractRewardsCoordinator
+	case strings.EqualFold(chainMetadata.ELDelegationManagerAddress, address.Hex()):
+		return ContractDelegationManager
+	default:
+		return ""
+	}
+}
+
+func hasABIMethod(contractABI string, selector []byte) (bool, error) {
+	parsed, err := abi.JSON(strings.NewReader(contractABI))
+	if err != nil {
+		return false, err
+	}
+	_, err = parsed.MethodById(selector)
+	return err == nil, nil
+}
+
+// hasSelector reports whether the bytecode pushes the selector onto the stack, which is how the
+// dispatcher of a Solidity contract compares the selector of the call. Selectors with
+// leading zero bytes are pushed with a shorter PUSH.
+func hasSelector(code []byte, selector []byte) bool {
+	want := new(big.Int).SetBytes(selector)
+	for i := 0; i < len(code); i++ {
+		op := code[i]
+		// PUSH1 to PUSH32
+		if op < 0x60 || op > 0x7f {
+			continue
+		}
+		size := int(op-0x60) + 1
+		end := i + 1 + size
+		if end > len(code) {
+			return false
+		}
+		if size <= 4 && new(big.Int).SetBytes(code[i+1:end]).Cmp(want) == 0 {
+			return true
+		}
+		i = end - 1
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCodeReader struct {
+	code    map[gethcommon.Address][]byte
+	storage map[gethcommon.Address]gethcommon.Hash
+}
+
+func (r *fakeCodeReader) CodeAt(
+	ctx context.Context,
+	contract gethcommon.Address,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	return r.code[contract], nil
+}
+
+func (r *fakeCodeReader) StorageAt(
+	ctx context.Context,
+	account gethcommon.Address,
+	key gethcommon.Hash,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	if key != implementationSlot {
+		return make([]byte, 32), nil
+	}
+	value := r.storage[account]
+	return value.Bytes(), nil
+}
+
+// dispatcherCode returns bytecode comparing the calldata selector with the given selectors
+func dispatcherCode(selectors ...[]byte) []byte {
+	// PUSH1 0xe0 CALLDATALOAD SHR
+	code := []byte{0x60, 0xe0, 0x35, 0x1c}
+	for _, selector := range selectors {
+		// DUP1 PUSH4 <selector> EQ
+		code = append(code, 0x80, 0x63)
+		code = append(code, selector...)
+		code = append(code, 0x14)
+	}
+	return code
+}
+
+func TestHasSelector(t *testing.T) {
+	selector := []byte{0x3a, 0x98, 0xef, 0x39}
+	assert.True(t, hasSelector(dispatcherCode(selector), selector))
+	assert.False(t, hasSelector(dispatcherCode([]byte{0x01, 0x02, 0x03, 0x04}), selector))
+	// Selectors with leading zero bytes are pushed with PUSH3
+	assert.True(t, hasSelector([]byte{0x62, 0x01, 0x02, 0x03, 0x14}, []byte{0x00, 0x01, 0x02, 0x03}))
+	// Bytes within the data of a longer PUSH aren't opcodes
+	assert.False(t, hasSelector([]byte{0x64, 0x00, 0x63, 0x01, 0x02, 0x03}, []byte{0x63, 0x01, 0x02, 0x03}))
+}
+
+func TestCodeCheckTxManager(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	SetVerifyContractCode()
+	defer verifyContractCode.Store(false)
+
+	rewardsCoordinator := gethcommon.HexToAddress(ChainMetadataMap[HoleskyChainId].ELRewardsCoordinatorAddress)
+	implementation := gethcommon.HexToAddress("0x1234")
+	setClaimerFor := crypto.Keccak256([]byte("setClaimerFor(address)"))[:4]
+	unknown := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	tests := []struct {
+		name    string
+		code    []byte
+		data    []byte
+		wantErr bool
+	}{
+		{
+			name: "implementation has the function",
+			code: dispatcherCode(setClaimerFor),
+			data: setClaimerFor,
+		},
+		{
+			name:    "implementation was upgraded without the function",
+			code:    dispatcherCode(unknown),
+			data:    setClaimerFor,
+			wantErr: true,
+		},
+		{
+			name:    "function is not in the ABI of the contract",
+			code:    dispatcherCode(unknown),
+			data:    unknown,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &fakeCodeReader{
+				code: map[gethcommon.Address][]byte{
+					rewardsCoordinator: {0x60, 0x80},
+					implementation:     tt.code,
+				},
+				storage: map[gethcommon.Address]gethcommon.Hash{
+					rewardsCoordinator: gethcommon.BytesToHash(implementation.Bytes()),
+				},
+			}
+			inner := &fakeTxManager{}
+			txMgr := withCodeCheck(inner, reader, big.NewInt(HoleskyChainId), logger)
+			tx := gethtypes.NewTx(&gethtypes.DynamicFeeTx{To: &rewardsCoordinator, Value: big.NewInt(0), Data: tt.data})
+
+			_, err := txMgr.Send(context.Background(), tx, true)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrContractDrift))
+				assert.Equal(t, 0, inner.sent)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, inner.sent)
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync/atomic"
+
+	eigensdkLogger "github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	ContractRewardsCoordinator = "RewardsCoordinator"
+	ContractAllocationManager  = "AllocationManager"
+	ContractDelegationManager  = "DelegationManager"
+)
+
+// FeatureRewardsSplits are the operator rewards splits of the RewardsCoordinator
+const FeatureRewardsSplits = "rewards-splits"
+
+var ErrIncompatibleContracts = errors.New("deployed contracts are not compatible with this version of the CLI")
+
+type CompatibilityStatus string
+
+const (
+	CompatibilitySupported CompatibilityStatus = "supported"
+	CompatibilityUntested  CompatibilityStatus = "untested"
+	CompatibilityBreaking  CompatibilityStatus = "breaking"
+)
+
+// ContractCompatibility describes which deployed versions of a contract a range of CLI releases
+// works with. Ranges use the semver range syntax, e.g. ">=1.0.0 <2.0.0".
+type ContractCompatibility struct {
+	Contract string
+	// CLIVersions is the range of CLI releases the entry applies to
+	CLIVersions string
+	// Supported is the range of contract versions the releases were tested against
+	Supported string
+	// Breaking is the range of contract versions known to break the releases
+	Breaking string
+	// Features are the optional features of the contract deployed on the chain
+	Features []string
+}
+
+// CompatibilityMatrix lists the contract versions CLI releases work with, per chain ID. The
+// bindings of the CLI are generated from the rewards v2 release of the contracts, which predates
+// version(), so contracts deployed before they exposed it are read as version 0.0.0. The slashing
+// release (1.0.0) changed registerAsOperator and queueWithdrawals of the DelegationManager and
+// added the AllocationManager, which this release doesn't support yet. Versions which are neither
+// supported nor breaking only get a warning.
+var CompatibilityMatrix = map[int64][]ContractCompatibility{
+	MainnetChainId: {
+		{Contract: ContractRewardsCoordinator, Supported: "0.0.0"},
+		{Contract: ContractDelegationManager, Supported: "0.0.0", Breaking: ">=1.0.0"},
+		{Contract: ContractAllocationManager, Breaking: ">=1.0.0"},
+	},
+	HoleskyChainId: {
+		{
+			Contract:  ContractRewardsCoordinator,
+			Supported: "0.0.0",
+			Features:  []string{FeatureRewardsSplits},
+		},
+		{Contract: ContractDelegationManager, Supported: "0.0.0", Breaking: ">=1.0.0"},
+		{Contract: ContractAllocationManager, Breaking: ">=1.0.0"},
+	},
+}
+
+// CompatibilityResult is the outcome of checking one deployed contract
+type CompatibilityResult struct {
+	Contract string
+	Address  string
+	Version  string
+	Status   CompatibilityStatus
+}
+
+var (
+	skipCompatibilityCheck atomic.Bool
+
+	versionSelector = crypto.Keccak256([]byte("version()"))[:4]
+)
+
+// SetSkipCompatibilityCheck turns CheckCompatibility into a no-op for the rest of the run
+func SetSkipCompatibilityCheck() {
+	skipCompatibilityCheck.Store(true)
+}
+
+// CheckCompatibility checks the versions of the contracts deployed on the chain against the
+// compatibility matrix of this release. Untested versions are logged as a warning, known breaking
+// versions make the command fail unless the check is skipped with --skip-compatibility-check.
+// The check itself failing, e.g. because of the RPC, fails the command as well, since the
+// deployed versions are unknown then.
+func CheckCompatibility(
+	cCtx *cli.Context,
+	caller bind.ContractCaller,
+	chainID *big.Int,
+	logger eigensdkLogger.Logger,
+) error {
+	if skipCompatibilityCheck.Load() || chainID == nil {
+		return nil
+	}
+	logger = WithLogModule(logger, LogModuleCompat)
+	cliVersion := ""
+	if cCtx.App != nil {
+		cliVersion = cCtx.App.Version
+	}
+	entries, ok := CompatibilityMatrix[chainID.Int64()]
+	if !ok {
+		logger.Debugf("No compatibility matrix for chain ID %s", chainID)
+		return nil
+	}
+
+	results, err := checkCompatibility(cCtx.Context, caller, chainID.Int64(), entries, cliVersion)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to check the compatibility of the deployed contracts, use --skip-compatibility-check "+
+				"to run anyway: %w",
+			err,
+		)
+	}
+	breaking := make([]string, 0)
+	for _, result := range results {
+		switch result.Status {
+		case CompatibilityUntested:
+			logger.Warnf(
+				"%s %s at %s was not tested with this version of the CLI",
+				result.Contract,
+				result.Version,
+				result.Address,
+			)
+		case CompatibilityBreaking:
+			breaking = append(breaking, fmt.Sprintf("%s %s at %s", result.Contract, result.Version, result.Address))
+		default:
+			logger.Debugf("%s %s at %s is supported", result.Contract, result.Version, result.Address)
+		}
+	}
+	if len(breaking) > 0 {
+		return fmt.Errorf(
+			"%w: %s. Upgrade the CLI, or use --skip-compatibility-check at your own risk",
+			ErrIncompatibleContracts,
+			strings.Join(breaking, ", "),
+		)
+	}
+	return nil
+}
+
+func checkCompatibility(
+	ctx context.Context,
+	caller bind.ContractCaller,
+	chainID int64,
+	entries []ContractCompatibility,
+	cliVersion string,
+) ([]CompatibilityResult, error) {
+	results := make([]CompatibilityResult, 0)
+	for _, entry := range entries {
+		applies, err := appliesTo(entry.CLIVersions, cliVersion)
+		if err != nil {
+			return nil, err
+		}
+		if !applies {
+			continue
+		}
+		address := getContractAddress(chainID, entry.Contract)
+		if IsEmptyString(address) {
+			continue
+		}
+		code, err := caller.CodeAt(ctx, gethcommon.HexToAddress(address), nil)
+		if err != nil {
+			return nil, err
+		}
+		// Contracts which aren't deployed on the chain yet can't be incompatible
+		if len(code) == 0 {
+			continue
+		}
+		version, err := getContractVersion(ctx, caller, gethcommon.HexToAddress(address))
+		if err != nil {
+			return nil, err
+		}
+
+		result := CompatibilityResult{
+			Contract: entry.Contract,
+			Address:  address,
+			Version:  version.String(),
+			Status:   CompatibilityUntested,
+		}
+		breaking, err := inRange(entry.Breaking, version)
+		if err != nil {
+			return nil, err
+		}
+		supported, err := inRange(entry.Supported, version)
+		if err != nil {
+			return nil, err
+		}
+		if breaking {
+			result.Status = CompatibilityBreaking
+		} else if supported {
+			result.Status = CompatibilitySupported
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// getContractVersion calls version() on the contract. Contracts which don't implement it revert
+// and are older than the versioned releases, so they are reported as 0.0.0. Any other error is
+// returned, so an unreachable RPC can't pass as a compatible contract.
+func getContractVersion(
+	ctx context.Context,
+	caller ethereum.ContractCaller,
+	address gethcommon.Address,
+) (semver.Version, error) {
+	output, err := caller.CallContract(ctx, ethereum.CallMsg{To: &address, Data: versionSelector}, nil)
+	if err != nil {
+		if strings.Contains(err.Error(), "execution reverted") {
+			return semver.Version{}, nil
+		}
+		return semver.Version{}, fmt.Errorf("failed to get version of contract %s: %w", address.Hex(), err)
+	}
+	if len(output) == 0 {
+		return semver.Version{}, nil
+	}
+	stringType, err := abi.NewType("string", "", nil)
+	if err != nil {
+		return semver.Version{}, err
+	}
+	values, err := abi.Arguments{{Type: stringType}}.Unpack(output)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("invalid version of contract %s: %w", address.Hex(), err)
+	}
+	version, ok := values[0].(string)
+	if !ok {
+		return semver.Version{}, fmt.Errorf("invalid version of contract %s", address.Hex())
+	}
+	parsed, err := semver.ParseTolerant(version)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("invalid contract version %s: %w", version, err)
+	}
+	return parsed, nil
+}
+
+// SupportsFeature reports whether the matrix lists the feature for the contracts deployed on the
+// chain, for the running version of the CLI
+func SupportsFeature(cCtx *cli.Context, chainID *big.Int, feature string) bool {
+	if chainID == nil {
+		return false
+	}
+	cliVersion := ""
+	if cCtx != nil && cCtx.App != nil {
+		cliVersion = cCtx.App.Version
+	}
+	for _, entry := range CompatibilityMatrix[chainID.Int64()] {
+		applies, err := appliesTo(entry.CLIVersions, cliVersion)
+		if err != nil || !applies {
+			continue
+		}
+		for _, f := range entry.Features {
+			if f == feature {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// appliesTo reports whether an entry for the range of CLI releases applies to the running
+// version. Versions which aren't semver, like development builds, match every range.
+func appliesTo(cliVersions string, cliVersion string) (bool, error) {
+	if IsEmptyString(cliVersions) {
+		return true, nil
+	}
+	version, err := semver.ParseTolerant(cliVersion)
+	if err != nil {
+		return true, nil
+	}
+	return inRange(cliVersions, version)
+}
+
+// inRange reports whether the version is in the range. An empty range matches no version.
+func inRange(versionRange string, version semver.Version) (bool, error) {
+	if IsEmptyString(versionRange) {
+		return false, nil
+	}
+	r, err := semver.ParseRange(versionRange)
+	if err != nil {
+		return false, fmt.Errorf("invalid version range %s: %w", versionRange, err)
+	}
+	return r(version), nil
+}
+
+func getContractAddress(chainID int64, contract string) string {
+	chainMetadata, ok := ChainMetadataMap[chainID]
+	if !ok {
+		return ""
+	}
+	switch contract {
+	case ContractRewardsCoordinator:
+		return chainMetadata.ELRewardsCoordinatorAddress
+	case ContractDelegationManager:
+		return chainMetadata.ELDelegationManagerAddress
+	case ContractAllocationManager:
+		return chainMetadata.ELAllocationManagerAddress
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,170 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+)
+
+type fakeContractCaller struct {
+	versions map[gethcommon.Address]string
+	deployed map[gethcommon.Address]bool
+	err      error
+}
+
+func (c *fakeContractCaller) CodeAt(
+	ctx context.Context,
+	contract gethcommon.Address,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	if c.deployed[contract] {
+		return []byte{0x60}, nil
+	}
+	return nil, nil
+}
+
+func (c *fakeContractCaller) CallContract(
+	ctx context.Context,
+	call ethereum.CallMsg,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	version, ok := c.versions[*call.To]
+	if !ok {
+		return nil, errors.New("execution reverted")
+	}
+	stringType, _ := abi.NewType("string", "", nil)
+	return abi.Arguments{{Type: stringType}}.Pack(version)
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	rewardsCoordinator := gethcommon.HexToAddress(ChainMetadataMap[HoleskyChainId].ELRewardsCoordinatorAddress)
+	allocationManager := gethcommon.HexToAddress(ChainMetadataMap[HoleskyChainId].ELAllocationManagerAddress)
+	entries := []ContractCompatibility{
+		{Contract: ContractRewardsCoordinator, CLIVersions: ">=0.10.0", Supported: "<1.3.0", Breaking: ">=2.0.0"},
+		{Contract: ContractAllocationManager, CLIVersions: ">=0.11.0", Supported: "<1.0.0", Breaking: ">=1.0.0"},
+	}
+
+	tests := []struct {
+		name       string
+		caller     *fakeContractCaller
+		cliVersion string
+		want       []CompatibilityResult
+	}{
+		{
+			name: "contracts without version are supported",
+			caller: &fakeContractCaller{
+				deployed: map[gethcommon.Address]bool{rewardsCoordinator: true},
+			},
+			cliVersion: "0.11.0",
+			want: []CompatibilityResult{
+				{
+					Contract: ContractRewardsCoordinator,
+					Address:  rewardsCoordinator.Hex(),
+					Version:  "0.0.0",
+					Status:   CompatibilitySupported,
+				},
+			},
+		},
+		{
+			name: "untested and breaking versions",
+			caller: &fakeContractCaller{
+				deployed: map[gethcommon.Address]bool{rewardsCoordinator: true, allocationManager: true},
+				versions: map[gethcommon.Address]string{rewardsCoordinator: "v1.4.0", allocationManager: "v1.0.0"},
+			},
+			cliVersion: "development",
+			want: []CompatibilityResult{
+				{
+					Contract: ContractRewardsCoordinator,
+					Address:  rewardsCoordinator.Hex(),
+					Version:  "1.4.0",
+					Status:   CompatibilityUntested,
+				},
+				{
+					Contract: ContractAllocationManager,
+					Address:  allocationManager.Hex(),
+					Version:  "1.0.0",
+					Status:   CompatibilityBreaking,
+				},
+			},
+		},
+		{
+			name: "entries for other CLI releases are ignored",
+			caller: &fakeContractCaller{
+				deployed: map[gethcommon.Address]bool{rewardsCoordinator: true, allocationManager: true},
+				versions: map[gethcommon.Address]string{allocationManager: "v1.0.0"},
+			},
+			cliVersion: "v0.10.2",
+			want: []CompatibilityResult{
+				{
+					Contract: ContractRewardsCoordinator,
+					Address:  rewardsCoordinator.Hex(),
+					Version:  "0.0.0",
+					Status:   CompatibilitySupported,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := checkCompatibility(context.Background(), tt.caller, HoleskyChainId, entries, tt.cliVersion)
+			assert.NoError(t, err)
+			for i := range results {
+				results[i].Address = gethcommon.HexToAddress(results[i].Address).Hex()
+			}
+			assert.Equal(t, tt.want, results)
+		})
+	}
+}
+
+func TestCheckCompatibilityRefusesBreakingVersions(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	delegationManager := gethcommon.HexToAddress(ChainMetadataMap[HoleskyChainId].ELDelegationManagerAddress)
+	caller := &fakeContractCaller{
+		deployed: map[gethcommon.Address]bool{delegationManager: true},
+		versions: map[gethcommon.Address]string{delegationManager: "v1.0.0"},
+	}
+	cCtx := cli.NewContext(&cli.App{Version: "0.11.0"}, nil, nil)
+
+	err := CheckCompatibility(cCtx, caller, big.NewInt(HoleskyChainId), logger)
+	assert.True(t, errors.Is(err, ErrIncompatibleContracts))
+
+	SetSkipCompatibilityCheck()
+	defer skipCompatibilityCheck.Store(false)
+	assert.NoError(t, CheckCompatibility(cCtx, caller, big.NewInt(HoleskyChainId), logger))
+}
+
+func TestCheckCompatibilityFailsOnRPCErrors(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	rewardsCoordinator := gethcommon.HexToAddress(ChainMetadataMap[HoleskyChainId].ELRewardsCoordinatorAddress)
+	caller := &fakeContractCaller{
+		deployed: map[gethcommon.Address]bool{rewardsCoordinator: true},
+		err:      errors.New("429 Too Many Requests"),
+	}
+	cCtx := cli.NewContext(&cli.App{Version: "0.11.0"}, nil, nil)
+
+	err := CheckCompatibility(cCtx, caller, big.NewInt(HoleskyChainId), logger)
+	assert.ErrorContains(t, err, "429 Too Many Requests")
+	assert.False(t, errors.Is(err, ErrIncompatibleContracts))
+}
+
+func TestSupportsFeature(t *testing.T) {
+	cCtx := cli.NewContext(&cli.App{Version: "0.11.0"}, nil, nil)
+	assert.True(t, SupportsFeature(cCtx, big.NewInt(HoleskyChainId), FeatureRewardsSplits))
+	assert.False(t, SupportsFeature(cCtx, big.NewInt(MainnetChainId), FeatureRewardsSplits))
+	assert.False(t, SupportsFeature(cCtx, big.NewInt(AnvilChainId), FeatureRewardsSplits))
+	assert.False(t, SupportsFeature(cCtx, nil, FeatureRewardsSplits))
+}
@@ -10,6 +10,8 @@ const (
 	OutputType_Calldata OutputType = "calldata"
 	OutputType_Pretty   OutputType = "pretty"
 	OutputType_Json     OutputType = "json"
+	OutputType_Csv      OutputType = "csv"
+	OutputType_Markdown OutputType = "markdown"
 
 	MainnetChainId           = 1
 	HoleskyChainId           = 17000
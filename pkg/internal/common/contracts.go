@@ -1,6 +1,7 @@
 package common
 
 import (
+	"context"
 	"errors"
 	"math/big"
 
@@ -26,23 +27,23 @@ func GetELWriter(
 	chainId *big.Int,
 	logger eigensdkLogger.Logger,
 ) (*elcontracts.ChainWriter, error) {
-	if signerConfig == nil {
-		return nil, errors.New("signer is required for broadcasting")
+	if err := checkReadOnly(); err != nil {
+		return nil, err
 	}
 	logger.Debug("Getting Writer from config")
-	keyWallet, sender, err := getWallet(
-		*signerConfig,
-		signerAddress.String(),
-		ethClient,
-		prompter,
-		*chainId,
-		logger,
-	)
+	txMgr, err := getTxManager(signerAddress, signerConfig, ethClient, prompter, chainId, logger)
 	if err != nil {
-		return nil, eigenSdkUtils.WrapError("failed to get wallet", err)
+		return nil, err
 	}
+	return newELWriter(contractConfig, ethClient, txMgr, logger)
+}
 
-	txMgr := txmgr.NewSimpleTxManager(keyWallet, ethClient, logger, sender)
+func newELWriter(
+	contractConfig elcontracts.Config,
+	ethClient *ethclient.Client,
+	txMgr txmgr.TxManager,
+	logger eigensdkLogger.Logger,
+) (*elcontracts.ChainWriter, error) {
 	noopMetrics := eigenMetrics.NewNoopMetrics()
 	eLWriter, err := elcontracts.NewWriterFromConfig(
 		contractConfig,
@@ -57,3 +58,47 @@ func GetELWriter(
 
 	return eLWriter, nil
 }
+
+func getTxManager(
+	signerAddress gethcommon.Address,
+	signerConfig *types.SignerConfig,
+	ethClient *ethclient.Client,
+	prompter utils.Prompter,
+	chainId *big.Int,
+	logger eigensdkLogger.Logger,
+) (txmgr.TxManager, error) {
+	if err := checkReadOnly(); err != nil {
+		return nil, err
+	}
+	if err := CheckAllowedNetwork(context.Background(), ethClient); err != nil {
+		return nil, err
+	}
+	if signerConfig == nil {
+		return nil, errors.New("signer is required for broadcasting")
+	}
+	keyWallet, sender, err := getWallet(
+		*signerConfig,
+		signerAddress.String(),
+		ethClient,
+		prompter,
+		*chainId,
+		logger,
+	)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get wallet", err)
+	}
+
+	logger = WithLogModule(logger, LogModuleTx)
+	txMgr := withHistory(
+		txmgr.NewSimpleTxManager(keyWallet, ethClient, logger, sender),
+		ethClient,
+		signerAddress,
+		chainId,
+		logger,
+	)
+	txMgr = withHooks(txMgr, signerAddress, chainId, logger)
+	txMgr = withCodeCheck(txMgr, ethClient, chainId, logger)
+	// The revert trace is the outermost, so a reverted transaction is recorded in the history and
+	// passed to the post-receipt hook before it fails the command
+	return withRevertTrace(txMgr, ethClient.Client(), ethClient, signerAddress, logger), nil
+}
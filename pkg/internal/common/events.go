@@ -0,0 +1,186 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// ContractERC20 is the name of the ERC20 events in the registry, e.g. the token transfers of a claim
+const ContractERC20 = "ERC20"
+
+const erc20EventsABI = `[{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},` +
+	`{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"value","type":"uint256"}],` +
+	`"name":"Transfer","type":"event"}]`
+
+var (
+	parsedABIsOnce sync.Once
+	parsedABIs     map[string]*abi.ABI
+)
+
+// getParsedABIs returns the ABIs of the registry and of the ERC20 events, parsed once per run
+func getParsedABIs() map[string]*abi.ABI {
+	parsedABIsOnce.Do(func() {
+		parsedABIs = make(map[string]*abi.ABI)
+		sources := map[string]string{ContractERC20: erc20EventsABI}
+		for contract, contractABI := range contractABIs {
+			sources[contract] = contractABI
+		}
+		for contract, contractABI := range sources {
+			parsed, err := abi.JSON(strings.NewReader(contractABI))
+			if err != nil {
+				continue
+			}
+			parsedABIs[contract] = &parsed
+		}
+	})
+	return parsedABIs
+}
+
+// DecodedEvent is an event of a receipt, with its arguments by name
+type DecodedEvent struct {
+	Contract string            `json:"contract"`
+	Name     string            `json:"name"`
+	Address  string            `json:"address"`
+	LogIndex uint              `json:"logIndex"`
+	Args     map[string]string `json:"args"`
+	// argNames are the names of the arguments in the order of the ABI
+	argNames []string
+}
+
+// DecodeReceiptEvents decodes the logs of a receipt with the ABIs of the registry. Logs of events
+// which are not in the registry are returned with the topic of the event as name.
+func DecodeReceiptEvents(receipt *gethtypes.Receipt) []DecodedEvent {
+	events := make([]DecodedEvent, 0, len(receipt.Logs))
+	for _, log := range receipt.Logs {
+		events = append(events, decodeLog(log))
+	}
+	return events
+}
+
+func decodeLog(log *gethtypes.Log) DecodedEvent {
+	decoded := DecodedEvent{Address: log.Address.Hex(), LogIndex: log.Index, Args: make(map[string]string)}
+	if len(log.Topics) == 0 {
+		decoded.Name = "anonymous"
+		return decoded
+	}
+	decoded.Name = log.Topics[0].Hex()
+
+	// Contracts are tried in a fixed order, so an event in several ABIs is always named the same
+	abis := getParsedABIs()
+	contracts := make([]string, 0, len(abis))
+	for contract := range abis {
+		contracts = append(contracts, contract)
+	}
+	sort.Strings(contracts)
+	for _, contract := range contracts {
+		event, err := abis[contract].EventByID(log.Topics[0])
+		if err != nil {
+			continue
+		}
+		values := make(map[string]interface{})
+		indexed := make(abi.Arguments, 0, len(event.Inputs))
+		for _, input := range event.Inputs {
+			if input.Indexed {
+				indexed = append(indexed, input)
+			}
+		}
+		if len(log.Topics)-1 != len(indexed) {
+			// Same signature, but other arguments indexed
+			continue
+		}
+		if err := abi.ParseTopicsIntoMap(values, indexed, log.Topics[1:]); err != nil {
+			continue
+		}
+		if err := event.Inputs.NonIndexed().UnpackIntoMap(values, log.Data); err != nil {
+			continue
+		}
+		decoded.Contract = contract
+		decoded.Name = event.Name
+		for _, input := range event.Inputs {
+			decoded.argNames = append(decoded.argNames, input.Name)
+			decoded.Args[input.Name] = formatEventValue(values[input.Name])
+		}
+		return decoded
+	}
+	return decoded
+}
+
+func formatEventValue(value interface{}) string {
+	switch v := value.(type) {
+	case gethcommon.Address:
+		return v.Hex()
+	case *big.Int:
+		return v.String()
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	case []byte:
+		return hexutil.Encode(v)
+	case string:
+		return v
+	}
+	if reflect.ValueOf(value).Kind() == reflect.Struct {
+		if out, err := json.Marshal(value); err == nil {
+			return string(out)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// PrintReceiptEvents prints the events of a receipt as on-chain confirmation of what the
+// transaction did, as a table or as JSON if the output type is json
+func PrintReceiptEvents(receipt *gethtypes.Receipt, outputType string) {
+	printReceiptEvents(os.Stdout, receipt, outputType)
+}
+
+func printReceiptEvents(w io.Writer, receipt *gethtypes.Receipt, outputType string) {
+	if receipt == nil || len(receipt.Logs) == 0 {
+		return
+	}
+	events := DecodeReceiptEvents(receipt)
+	if outputType == string(OutputType_Json) {
+		out, err := json.MarshalIndent(map[string][]DecodedEvent{"events": events}, "", "  ")
+		if err != nil {
+			return
+		}
+		_, _ = fmt.Fprintln(w, string(out))
+		return
+	}
+
+	table := NewTableWithHeaders("Log", "Event", "Contract", "Argument", "Value")
+	for _, event := range events {
+		name := event.Name
+		if event.Contract != "" {
+			name = fmt.Sprintf("%s.%s", event.Contract, event.Name)
+		}
+		if len(event.argNames) == 0 {
+			table.AddRow(fmt.Sprintf("%d", event.LogIndex), name, event.Address, "", "")
+			continue
+		}
+		for i, arg := range event.argNames {
+			value := RedactAmount(event.Args[arg])
+			if i == 0 {
+				table.AddRow(fmt.Sprintf("%d", event.LogIndex), name, event.Address, arg, value)
+			} else {
+				table.AddRow("", "", "", arg, value)
+			}
+		}
+	}
+	_, _ = fmt.Fprintln(w, "Events emitted by the transaction:")
+	if outputType == string(OutputType_Markdown) {
+		_, _ = fmt.Fprint(w, table.Markdown())
+		return
+	}
+	table.Render(w)
+}
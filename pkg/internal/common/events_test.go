@@ -0,0 +1,94 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRewardsClaimedLog(t *testing.T) *gethtypes.Log {
+	event := getParsedABIs()[ContractRewardsCoordinator].Events["RewardsClaimed"]
+	data, err := event.Inputs.NonIndexed().Pack(
+		[32]byte{0x01},
+		gethcommon.HexToAddress("0x0000000000000000000000000000000000000004"),
+		big.NewInt(1000),
+	)
+	assert.NoError(t, err)
+	return &gethtypes.Log{
+		Address: gethcommon.HexToAddress("0x00000000000000000000000000000000000000aa"),
+		Topics: []gethcommon.Hash{
+			event.ID,
+			gethcommon.HexToHash("0x01"),
+			gethcommon.HexToHash("0x02"),
+			gethcommon.HexToHash("0x03"),
+		},
+		Data:  data,
+		Index: 3,
+	}
+}
+
+func TestDecodeReceiptEvents(t *testing.T) {
+	transfer := getParsedABIs()[ContractERC20].Events["Transfer"]
+	transferData, err := transfer.Inputs.NonIndexed().Pack(big.NewInt(1000))
+	assert.NoError(t, err)
+	receipt := &gethtypes.Receipt{Logs: []*gethtypes.Log{
+		newRewardsClaimedLog(t),
+		{
+			Topics: []gethcommon.Hash{transfer.ID, gethcommon.HexToHash("0x0a"), gethcommon.HexToHash("0x03")},
+			Data:   transferData,
+			Index:  4,
+		},
+		{Topics: []gethcommon.Hash{gethcommon.HexToHash("0xff")}, Index: 5},
+	}}
+
+	events := DecodeReceiptEvents(receipt)
+	assert.Len(t, events, 3)
+
+	assert.Equal(t, ContractRewardsCoordinator, events[0].Contract)
+	assert.Equal(t, "RewardsClaimed", events[0].Name)
+	assert.Equal(t, uint(3), events[0].LogIndex)
+	assert.Equal(t, "0x0000000000000000000000000000000000000001", events[0].Args["earner"])
+	assert.Equal(t, "0x0000000000000000000000000000000000000003", events[0].Args["recipient"])
+	assert.Equal(t, "0x0000000000000000000000000000000000000004", events[0].Args["token"])
+	assert.Equal(t, "1000", events[0].Args["claimedAmount"])
+	assert.Equal(
+		t,
+		"0x0100000000000000000000000000000000000000000000000000000000000000",
+		events[0].Args["root"],
+	)
+
+	assert.Equal(t, ContractERC20, events[1].Contract)
+	assert.Equal(t, "Transfer", events[1].Name)
+	assert.Equal(t, "1000", events[1].Args["value"])
+
+	assert.Empty(t, events[2].Contract)
+	assert.Equal(t, gethcommon.HexToHash("0xff").Hex(), events[2].Name)
+	assert.Empty(t, events[2].Args)
+}
+
+func TestPrintReceiptEvents(t *testing.T) {
+	receipt := &gethtypes.Receipt{Logs: []*gethtypes.Log{newRewardsClaimedLog(t)}}
+
+	var out bytes.Buffer
+	printReceiptEvents(&out, receipt, string(OutputType_Pretty))
+	assert.Contains(t, out.String(), "RewardsCoordinator.RewardsClaimed")
+	assert.Contains(t, out.String(), "claimedAmount")
+
+	out.Reset()
+	printReceiptEvents(&out, receipt, string(OutputType_Json))
+	var decoded struct {
+		Events []DecodedEvent `json:"events"`
+	}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Len(t, decoded.Events, 1)
+	assert.Equal(t, "1000", decoded.Events[0].Args["claimedAmount"])
+
+	out.Reset()
+	printReceiptEvents(&out, &gethtypes.Receipt{}, string(OutputType_Pretty))
+	assert.Empty(t, out.String())
+}
@@ -0,0 +1,134 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	fileLockTimeout       = 30 * time.Second
+	fileLockRetryInterval = 50 * time.Millisecond
+)
+
+var ErrLockTimeout = errors.New("timed out waiting for file lock")
+
+// ReadFileLocked reads a file while holding a shared advisory lock on it. Writes replace the file
+// with a rename, so a read never sees a partial write of another invocation of the CLI.
+func ReadFileLocked(path string) ([]byte, error) {
+	path = filepath.Clean(path)
+	// A file which was never written with a lock has no lock file, and the directory may not
+	// be writable, so the lock file isn't created here
+	lock, err := os.Open(getLockPath(path))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if lock != nil {
+		defer lock.Close()
+		if err := lockFile(lock, false); err != nil {
+			return nil, err
+		}
+		defer func() { _ = unlockFile(lock) }()
+	}
+	return os.ReadFile(path)
+}
+
+// WriteFileLocked replaces the content of a file while holding an exclusive advisory lock on it
+func WriteFileLocked(data []byte, path string, perm os.FileMode) error {
+	return UpdateFileLocked(path, perm, func([]byte) ([]byte, error) {
+		return data, nil
+	})
+}
+
+// UpdateFileLocked reads, changes and writes back a file under one exclusive advisory lock, so
+// concurrent invocations of the CLI, e.g. parallel cron jobs, don't lose each other's updates.
+// The file is created if it doesn't exist, in which case update gets no data. The lock is held on
+// a .lock file next to it, since the file itself is replaced: the new content is written to a
+// temporary file, synced and renamed over the file, so a crash never leaves a truncated file.
+func UpdateFileLocked(path string, perm os.FileMode, update func(data []byte) ([]byte, error)) error {
+	path = filepath.Clean(path)
+	if err := ensureDir(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("error creating directory: %v", err)
+	}
+	lock, err := os.OpenFile(getLockPath(path), os.O_RDWR|os.O_CREATE, perm)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+
+	if err := lockFile(lock, true); err != nil {
+		return err
+	}
+	defer func() { _ = unlockFile(lock) }()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	data, err = update(data)
+	if err != nil {
+		return err
+	}
+	return replaceFile(path, data, perm)
+}
+
+// replaceFile atomically replaces the content of path with data
+func replaceFile(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	tmpPath := file.Name()
+	renamed := false
+	defer func() {
+		if !renamed {
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Chmod(perm); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	renamed = true
+	// The rename is only durable once the directory is synced
+	return syncDir(dir)
+}
+
+func getLockPath(path string) string {
+	return path + ".lock"
+}
+
+func lockFile(file *os.File, exclusive bool) error {
+	deadline := time.Now().Add(fileLockTimeout)
+	for {
+		locked, err := tryLockFile(file, exclusive)
+		if err != nil {
+			return fmt.Errorf("failed to lock %s: %w", file.Name(), err)
+		}
+		if locked {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w: %s", ErrLockTimeout, file.Name())
+		}
+		time.Sleep(fileLockRetryInterval)
+	}
+}
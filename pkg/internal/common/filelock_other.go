@@ -0,0 +1,37 @@
+//go:build !unix
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+var lockWarning sync.Once
+
+// Advisory locks are only supported on unix systems. Elsewhere files are accessed without locks,
+// so parallel runs of the CLI can lose each other's updates, which is printed once per run.
+func tryLockFile(file *os.File, exclusive bool) (bool, error) {
+	if exclusive {
+		lockWarning.Do(func() {
+			fmt.Fprintf(
+				os.Stderr,
+				"Warning: file locks are not supported on this platform, "+
+					"parallel runs of the CLI can lose updates of %s\n",
+				strings.TrimSuffix(file.Name(), ".lock"),
+			)
+		})
+	}
+	return true, nil
+}
+
+func unlockFile(file *os.File) error {
+	return nil
+}
+
+// Directories can't be synced on every platform, the rename is as durable as the platform makes it
+func syncDir(dir string) error {
+	return nil
+}
@@ -0,0 +1,72 @@
+package common
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateFileLocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "counter")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := UpdateFileLocked(path, 0o600, func(data []byte) ([]byte, error) {
+				count := 0
+				if len(data) > 0 {
+					count, _ = strconv.Atoi(string(data))
+				}
+				return []byte(strconv.Itoa(count + 1)), nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	data, err := ReadFileLocked(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "20", string(data))
+
+	err = WriteFileLocked([]byte("1"), path, 0o600)
+	assert.NoError(t, err)
+	data, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", string(data))
+}
+
+func TestUpdateFileLockedReplacesFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	assert.NoError(t, WriteFileLocked([]byte("before"), path, 0o600))
+
+	// A failed update leaves the file as it was
+	err := UpdateFileLocked(path, 0o600, func(data []byte) ([]byte, error) {
+		return nil, errors.New("failed")
+	})
+	assert.EqualError(t, err, "failed")
+	data, err := ReadFileLocked(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "before", string(data))
+
+	assert.NoError(t, WriteFileLocked([]byte("after"), path, 0o600))
+	data, err = ReadFileLocked(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "after", string(data))
+
+	// Only the file and its lock file are left, no temporary files
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"state.json", "state.json.lock"}, names)
+}
@@ -16,3 +16,11 @@ func GetSignerFlags() []cli.Flag {
 		&Web3SignerUrlFlag,
 	}
 }
+
+func GetPlanFlags() []cli.Flag {
+	return []cli.Flag{
+		&PlanFlag,
+		&ApplyFlag,
+		&PlanHashFlag,
+	}
+}
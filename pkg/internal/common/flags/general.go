@@ -37,7 +37,7 @@ var (
 		Name:    "output-type",
 		Aliases: []string{"ot"},
 		Value:   "pretty",
-		Usage:   "Output format of the command. One of 'pretty', 'json' or 'calldata'",
+		Usage:   "Output format of the command. One of 'pretty', 'json', 'csv', 'markdown' or 'calldata'. Commands only support the formats which fit their output",
 		EnvVars: []string{"OUTPUT_TYPE"},
 	}
 
@@ -72,10 +72,35 @@ var (
 	VerboseFlag = cli.BoolFlag{
 		Name:    "verbose",
 		Aliases: []string{"v"},
-		Usage:   "Enable verbose logging",
+		Usage:   "Enable debug logging of the command group, e.g. rewards. Use the global --vv and --vvv for more",
 		EnvVars: []string{"VERBOSE"},
 	}
 
+	// GlobalVerboseFlag is the app level --verbose. It has no -v alias, which is the version flag of the app.
+	GlobalVerboseFlag = cli.BoolFlag{
+		Name:    "verbose",
+		Usage:   "Enable debug logging of the command group, e.g. rewards. Use --vv and --vvv for more",
+		EnvVars: []string{"VERBOSE"},
+	}
+
+	VeryVerboseFlag = cli.BoolFlag{
+		Name:    "vv",
+		Usage:   "Enable debug logging of every module",
+		EnvVars: []string{"EIGENLAYER_VERY_VERBOSE"},
+	}
+
+	TraceFlag = cli.BoolFlag{
+		Name:    "vvv",
+		Usage:   "Enable debug logging of every module with the source location of every message",
+		EnvVars: []string{"EIGENLAYER_TRACE"},
+	}
+
+	LogFilterFlag = cli.StringFlag{
+		Name:    "log-filter",
+		Usage:   "Comma separated log levels by module, e.g. 'rewards=debug,rpc=warn'. Takes precedence over the verbosity",
+		EnvVars: []string{"EIGENLAYER_LOG_FILTER"},
+	}
+
 	SilentFlag = cli.BoolFlag{
 		Name:    "silent",
 		Aliases: []string{"s"},
@@ -104,4 +129,28 @@ var (
 		Usage:   "Input file for batch rewards claim",
 		EnvVars: []string{"BATCH_CLAIM_FILE"},
 	}
+
+	ResumeFlag = cli.StringFlag{
+		Name:    "resume",
+		Usage:   "State file of a batch operation. Progress is written to it, and a run interrupted before can be resumed from it, skipping completed items",
+		EnvVars: []string{"RESUME_STATE_FILE"},
+	}
+
+	PlanFlag = cli.StringFlag{
+		Name:    "plan",
+		Usage:   "Write the transactions of the command to a plan file for review instead of sending them",
+		EnvVars: []string{"PLAN_FILE"},
+	}
+
+	ApplyFlag = cli.StringFlag{
+		Name:    "apply",
+		Usage:   "Send the transactions of a previously reviewed plan file. Requires --plan-hash",
+		EnvVars: []string{"APPLY_FILE"},
+	}
+
+	PlanHashFlag = cli.StringFlag{
+		Name:    "plan-hash",
+		Usage:   "Hash of the reviewed plan, printed when the plan was written. --apply only sends a plan with this hash",
+		EnvVars: []string{"PLAN_HASH"},
+	}
 )
@@ -0,0 +1,78 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// heartbeatSubPath is the directory of the heartbeats of daemon mode inside the home directory
+const heartbeatSubPath = ".eigenlayer/heartbeats"
+
+// Heartbeat is written by long running commands after every round of work, so a healthcheck
+// can tell a stuck daemon from a working one without talking to it
+type Heartbeat struct {
+	Command   string    `json:"command"`
+	PID       int       `json:"pid"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// GetHeartbeatDir returns the directory every daemon writes its heartbeat in
+func GetHeartbeatDir() string {
+	homePath, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homePath, heartbeatSubPath)
+}
+
+// GetHeartbeatPath returns the heartbeat file of a daemon. The key tells apart daemons of the same
+// command on one host, e.g. the monitors of two operators, so they don't share a heartbeat.
+func GetHeartbeatPath(command string, key string) string {
+	dir := GetHeartbeatDir()
+	if dir == "" {
+		return ""
+	}
+	name := strings.ReplaceAll(command, " ", "-")
+	if key != "" {
+		sum := sha256.Sum256([]byte(key))
+		name += "-" + hex.EncodeToString(sum[:6])
+	}
+	return filepath.Join(dir, name+".json")
+}
+
+// WriteHeartbeat records that the command is alive now
+func WriteHeartbeat(path string, command string, now time.Time) error {
+	data, err := json.Marshal(Heartbeat{Command: command, PID: os.Getpid(), Timestamp: now.UTC()})
+	if err != nil {
+		return err
+	}
+	return WriteFileLocked(data, path, 0o600)
+}
+
+// RemoveHeartbeat removes the heartbeat of a daemon which stopped cleanly, so the healthcheck
+// doesn't report it as stuck
+func RemoveHeartbeat(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func ReadHeartbeat(path string) (*Heartbeat, error) {
+	data, err := ReadFileLocked(path)
+	if err != nil {
+		return nil, err
+	}
+	var heartbeat Heartbeat
+	if err := json.Unmarshal(data, &heartbeat); err != nil {
+		return nil, fmt.Errorf("failed to parse heartbeat %s: %w", path, err)
+	}
+	return &heartbeat, nil
+}
@@ -0,0 +1,25 @@
+package common
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHeartbeatPath(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	first := GetHeartbeatPath("operator monitor", "holesky/0x1")
+	second := GetHeartbeatPath("operator monitor", "holesky/0x2")
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, first, GetHeartbeatPath("operator monitor", "holesky/0x1"))
+	assert.Equal(t, GetHeartbeatDir(), filepath.Dir(first))
+	assert.Regexp(t, `operator-monitor-[0-9a-f]{12}\.json$`, first)
+	assert.Equal(t, filepath.Join(GetHeartbeatDir(), "scheduler.json"), GetHeartbeatPath("scheduler", ""))
+
+	assert.NoError(t, WriteHeartbeat(first, "operator monitor", time.Now()))
+	assert.NoError(t, RemoveHeartbeat(first))
+	assert.NoFileExists(t, first)
+	assert.NoError(t, RemoveHeartbeat(first))
+}
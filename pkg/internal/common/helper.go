@@ -44,6 +44,7 @@ var ChainMetadataMap = map[int64]types.ChainMetadata{
 		ELDelegationManagerAddress:  "0x39053D51B77DC0d36036Fc1fCc8Cb819df8Ef37A",
 		ELAVSDirectoryAddress:       "0x135dda560e946695d6f155dacafc6f1f25c1f5af",
 		ELRewardsCoordinatorAddress: "0x7750d328b314EfFa365A0402CcfD489B80B0adda",
+		ELAllocationManagerAddress:  "0x948a420b8CC1d6BFd0B6087C2E7c344a2CD0bc39",
 		WebAppUrl:                   "https://app.eigenlayer.xyz/operator",
 		ProofStoreBaseURL:           "https://eigenlabs-rewards-mainnet-ethereum.s3.amazonaws.com",
 	},
@@ -52,6 +53,7 @@ var ChainMetadataMap = map[int64]types.ChainMetadata{
 		ELDelegationManagerAddress:  "0xA44151489861Fe9e3055d95adC98FbD462B948e7",
 		ELAVSDirectoryAddress:       "0x055733000064333CaDDbC92763c58BF0192fFeBf",
 		ELRewardsCoordinatorAddress: "0xAcc1fb458a1317E886dB376Fc8141540537E68fE",
+		ELAllocationManagerAddress:  "0x78469728304326CBc65f8f95FA756B0B73164462",
 		WebAppUrl:                   "https://holesky.eigenlayer.xyz/operator",
 		ProofStoreBaseURL:           "https://eigenlabs-rewards-testnet-holesky.s3.amazonaws.com",
 	},
@@ -264,7 +266,7 @@ func ValidateAndReturnConfig(
 	logger.Debugf("ELAVSDirectoryAddress: %s", operatorCfg.ELAVSDirectoryAddress)
 	logger.Debugf("ELRewardsCoordinatorAddress: %s", operatorCfg.ELRewardsCoordinatorAddress)
 
-	ethClient, err := ethclient.Dial(operatorCfg.EthRPCUrl)
+	ethClient, err := DialEthClient(operatorCfg.EthRPCUrl)
 	if err != nil {
 		return nil, err
 	}
@@ -455,27 +457,81 @@ func IsEmptyString(s string) bool {
 	return len(strings.TrimSpace(s)) == 0
 }
 
+// GetLogger returns the logger of the command. -v logs debug messages of the command group, e.g.
+// rewards, -vv of every module and -vvv adds the source location. --log-filter sets the level of
+// single modules, taking precedence over the verbosity. The RPC requests of the run are logged
+// with the levels of the last logger returned.
 func GetLogger(cCtx *cli.Context) eigensdkLogger.Logger {
-	verbose := cCtx.Bool(flags.VerboseFlag.Name)
-
 	if cCtx.Bool(flags.SilentFlag.Name) {
 		return eigensdkLogger.NewTextSLogger(io.Discard, nil)
 	}
 
-	loggerOptions := &eigensdkLogger.SLoggerOptions{
-		Level: slog.LevelInfo,
+	verbosity := getVerbosity(cCtx)
+	logger := eigensdkLogger.NewTextSLogger(os.Stdout, &eigensdkLogger.SLoggerOptions{
+		Level:     slog.LevelDebug,
+		AddSource: verbosity >= verbositySource,
+	})
+
+	filter, err := ParseLogFilter(cCtx.String(flags.LogFilterFlag.Name))
+	if err != nil {
+		logger.Warnf("Ignoring log filter: %s", err)
+		filter = nil
+	}
+	module := getCommandModule(cCtx)
+	var levelLogger eigensdkLogger.Logger = &moduleLogger{
+		logger: logger,
+		module: module,
+		levels: newLogLevels(verbosity, module, filter),
+	}
+	if IsRedacted() {
+		levelLogger = &redactingLogger{logger: levelLogger}
 	}
-	if verbose {
-		loggerOptions = &eigensdkLogger.SLoggerOptions{
-			Level:     slog.LevelDebug,
-			AddSource: true,
+	setRPCLogger(levelLogger)
+	return levelLogger
+}
+
+func getVerbosity(cCtx *cli.Context) int {
+	switch {
+	case cCtx.Bool(flags.TraceFlag.Name):
+		return verbositySource
+	case cCtx.Bool(flags.VeryVerboseFlag.Name):
+		return verbosityDebug
+	case isVerbose(cCtx):
+		return verbosityCommandDebug
+	}
+	return 0
+}
+
+// isVerbose returns whether -v is given to the command or --verbose to the app. A command flag
+// hides the app flag of the same name, so the contexts of the lineage are checked one by one.
+func isVerbose(cCtx *cli.Context) bool {
+	for _, c := range cCtx.Lineage() {
+		if c.IsSet(flags.VerboseFlag.Name) {
+			return c.Bool(flags.VerboseFlag.Name)
 		}
 	}
-	logger := eigensdkLogger.NewTextSLogger(os.Stdout, loggerOptions)
-	return logger
+	return false
+}
+
+// getCommandModule returns the command group of the command, e.g. rewards for 'rewards claim'
+func getCommandModule(cCtx *cli.Context) string {
+	if cCtx.Command == nil {
+		return ""
+	}
+	// The help name is the full path of the command, starting with the name of the app
+	path := strings.Fields(cCtx.Command.HelpName)
+	if len(path) < 2 {
+		return cCtx.Command.Name
+	}
+	return path[1]
 }
 
 func noopSigner(addr common.Address, tx *gethtypes.Transaction) (*gethtypes.Transaction, error) {
+	// Unsigned transactions are built through the signer too, so this also stops
+	// calldata from being generated in read-only mode
+	if err := checkReadOnly(); err != nil {
+		return nil, err
+	}
 	return tx, nil
 }
 
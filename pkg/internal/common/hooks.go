@@ -0,0 +1,217 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	eigensdkLogger "github.com/Layr-Labs/eigensdk-go/logging"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+type HookEvent string
+
+const (
+	HookEventPreBroadcast HookEvent = "pre-broadcast"
+	HookEventPostReceipt  HookEvent = "post-receipt"
+	HookEventOnError      HookEvent = "on-error"
+)
+
+var ErrHookRejected = errors.New("pre-broadcast hook rejected the transaction")
+
+// Hooks are user scripts run at points of a command. Every script gets the HookContext as JSON on
+// stdin. A pre-broadcast script exiting with a non-zero code stops the transaction from being sent,
+// so it can be used for custom approvals. Failures of the other hooks are only logged.
+type Hooks struct {
+	PreBroadcast string `yaml:"pre-broadcast"`
+	PostReceipt  string `yaml:"post-receipt"`
+	OnError      string `yaml:"on-error"`
+}
+
+// HookContext is what a hook script gets on stdin
+type HookContext struct {
+	Event       HookEvent        `json:"event"`
+	Command     string           `json:"command"`
+	Timestamp   time.Time        `json:"timestamp"`
+	ChainID     string           `json:"chainId,omitempty"`
+	Transaction *HookTransaction `json:"transaction,omitempty"`
+	Receipt     *HookReceipt     `json:"receipt,omitempty"`
+	Error       string           `json:"error,omitempty"`
+}
+
+type HookTransaction struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Value string `json:"value"`
+	Data  string `json:"data"`
+}
+
+type HookReceipt struct {
+	TxHash      string `json:"txHash"`
+	BlockNumber string `json:"blockNumber"`
+	Status      uint64 `json:"status"`
+	GasUsed     uint64 `json:"gasUsed"`
+}
+
+var (
+	activeHooks Hooks
+	hookCommand string
+)
+
+// SetHooks sets the hooks of the current run
+func SetHooks(hooks Hooks) {
+	activeHooks = hooks
+}
+
+func GetHooks() Hooks {
+	return activeHooks
+}
+
+// SetHookCommand sets the name of the command passed to the hooks, e.g. 'rewards claim'
+func SetHookCommand(command string) {
+	hookCommand = command
+}
+
+// RunErrorHook runs the on-error hook with the error the command failed with
+func RunErrorHook(ctx context.Context, cmdErr error) {
+	if IsEmptyString(activeHooks.OnError) || cmdErr == nil {
+		return
+	}
+	err := runHook(ctx, activeHooks.OnError, &HookContext{Event: HookEventOnError, Error: cmdErr.Error()})
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "on-error hook failed: %s\n", err)
+	}
+}
+
+func runHook(ctx context.Context, script string, hookCtx *HookContext) error {
+	hookCtx.Command = hookCommand
+	hookCtx.Timestamp = time.Now().UTC()
+	input, err := json.Marshal(hookCtx)
+	if err != nil {
+		return err
+	}
+
+	// #nosec G204 -- running the configured script is the purpose of hooks
+	cmd := exec.CommandContext(ctx, filepath.Clean(script))
+	cmd.Stdin = bytes.NewReader(input)
+	// The output of hooks must not mix with the output of the command, which may be parsed
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "EIGENLAYER_HOOK_EVENT="+string(hookCtx.Event))
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s hook %s: %w", hookCtx.Event, script, err)
+	}
+	return nil
+}
+
+// hookTxManager runs the pre-broadcast and post-receipt hooks around every transaction sent
+type hookTxManager struct {
+	txmgr.TxManager
+	hooks   Hooks
+	from    gethcommon.Address
+	chainID *big.Int
+	logger  eigensdkLogger.Logger
+}
+
+func withHooks(
+	txMgr txmgr.TxManager,
+	from gethcommon.Address,
+	chainID *big.Int,
+	logger eigensdkLogger.Logger,
+) txmgr.TxManager {
+	hooks := GetHooks()
+	if IsEmptyString(hooks.PreBroadcast) && IsEmptyString(hooks.PostReceipt) {
+		return txMgr
+	}
+	return &hookTxManager{TxManager: txMgr, hooks: hooks, from: from, chainID: chainID, logger: logger}
+}
+
+func (m *hookTxManager) Send(
+	ctx context.Context,
+	tx *gethtypes.Transaction,
+	waitForReceipt bool,
+) (*gethtypes.Receipt, error) {
+	if err := runPreBroadcastHook(ctx, m.hooks, m.from, m.chainID, tx, m.logger); err != nil {
+		return nil, err
+	}
+
+	receipt, err := m.TxManager.Send(ctx, tx, waitForReceipt)
+	if err != nil || IsEmptyString(m.hooks.PostReceipt) || receipt == nil {
+		return receipt, err
+	}
+
+	m.logger.Debugf("Running post-receipt hook %s", m.hooks.PostReceipt)
+	blockNumber := ""
+	if receipt.BlockNumber != nil {
+		blockNumber = receipt.BlockNumber.String()
+	}
+	err = runHook(ctx, m.hooks.PostReceipt, &HookContext{
+		Event:       HookEventPostReceipt,
+		ChainID:     m.chainID.String(),
+		Transaction: newHookTransaction(m.from, tx),
+		Receipt: &HookReceipt{
+			TxHash:      receipt.TxHash.Hex(),
+			BlockNumber: blockNumber,
+			Status:      receipt.Status,
+			GasUsed:     receipt.GasUsed,
+		},
+	})
+	if err != nil {
+		// The transaction is already on-chain, so the command doesn't fail because of the hook
+		m.logger.Warnf("Post-receipt hook failed: %s", err)
+	}
+	return receipt, nil
+}
+
+// runPreBroadcastHook runs the pre-broadcast hook, if one is set, and fails if it rejects the
+// transaction
+func runPreBroadcastHook(
+	ctx context.Context,
+	hooks Hooks,
+	from gethcommon.Address,
+	chainID *big.Int,
+	tx *gethtypes.Transaction,
+	logger eigensdkLogger.Logger,
+) error {
+	if IsEmptyString(hooks.PreBroadcast) {
+		return nil
+	}
+	logger.Debugf("Running pre-broadcast hook %s", hooks.PreBroadcast)
+	err := runHook(ctx, hooks.PreBroadcast, &HookContext{
+		Event:       HookEventPreBroadcast,
+		ChainID:     chainID.String(),
+		Transaction: newHookTransaction(from, tx),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrHookRejected, err)
+	}
+	return nil
+}
+
+func newHookTransaction(from gethcommon.Address, tx *gethtypes.Transaction) *HookTransaction {
+	to := ""
+	if tx.To() != nil {
+		to = tx.To().Hex()
+	}
+	value := "0"
+	if tx.Value() != nil {
+		value = tx.Value().String()
+	}
+	return &HookTransaction{
+		From:  from.Hex(),
+		To:    to,
+		Value: value,
+		Data:  hexutil.Encode(tx.Data()),
+	}
+}
@@ -0,0 +1,83 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTxManager struct {
+	sent int
+}
+
+func (m *fakeTxManager) GetNoSendTxOpts() (*bind.TransactOpts, error) {
+	return GetNoSendTxOpts(gethcommon.Address{}), nil
+}
+
+func (m *fakeTxManager) Send(
+	ctx context.Context,
+	tx *gethtypes.Transaction,
+	waitForReceipt bool,
+) (*gethtypes.Receipt, error) {
+	m.sent++
+	return &gethtypes.Receipt{TxHash: tx.Hash(), Status: gethtypes.ReceiptStatusSuccessful}, nil
+}
+
+func writeHookScript(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(path, []byte("#!/bin/sh\n"+content+"\n"), 0o700)
+	assert.NoError(t, err)
+	return path
+}
+
+func TestHookTxManager(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	defer SetHooks(Hooks{})
+	from := gethcommon.HexToAddress("0x1")
+	to := gethcommon.HexToAddress("0x2")
+	tx := gethtypes.NewTx(&gethtypes.DynamicFeeTx{To: &to, Value: big.NewInt(0), Data: []byte{0x01}})
+
+	outputFile := filepath.Join(t.TempDir(), "receipt.json")
+	SetHooks(Hooks{
+		PreBroadcast: writeHookScript(t, "approve.sh", "exit 0"),
+		PostReceipt:  writeHookScript(t, "notify.sh", "cat > "+outputFile),
+	})
+	SetHookCommand("rewards claim")
+	inner := &fakeTxManager{}
+	_, err := withHooks(inner, from, big.NewInt(17000), logger).Send(context.Background(), tx, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, inner.sent)
+
+	data, err := os.ReadFile(outputFile)
+	assert.NoError(t, err)
+	var hookCtx HookContext
+	assert.NoError(t, json.Unmarshal(data, &hookCtx))
+	assert.Equal(t, HookEventPostReceipt, hookCtx.Event)
+	assert.Equal(t, "rewards claim", hookCtx.Command)
+	assert.Equal(t, "17000", hookCtx.ChainID)
+	assert.Equal(t, &HookTransaction{From: from.Hex(), To: to.Hex(), Value: "0", Data: "0x01"}, hookCtx.Transaction)
+	assert.Equal(t, tx.Hash().Hex(), hookCtx.Receipt.TxHash)
+
+	SetHooks(Hooks{PreBroadcast: writeHookScript(t, "reject.sh", "exit 1")})
+	inner = &fakeTxManager{}
+	_, err = withHooks(inner, from, big.NewInt(17000), logger).Send(context.Background(), tx, true)
+	assert.True(t, errors.Is(err, ErrHookRejected))
+	assert.Equal(t, 0, inner.sent)
+}
+
+func TestWithoutHooks(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	inner := &fakeTxManager{}
+	assert.Equal(t, inner, withHooks(inner, gethcommon.Address{}, big.NewInt(1), logger))
+}
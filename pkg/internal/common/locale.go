@@ -0,0 +1,127 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Locale is how numbers and timestamps are formatted in human-readable output. Machine output,
+// i.e. JSON and CSV, is never localized, so it can be parsed the same way everywhere.
+type Locale struct {
+	Name               string
+	ThousandsSeparator string
+	DecimalMark        string
+	DateLayout         string
+	TimeLayout         string
+}
+
+// locales are the supported locales by lower case name, with their name, thousands separator,
+// decimal mark, date layout and time layout
+var locales = map[string]Locale{
+	"en-us": {"en-US", ",", ".", "01/02/2006", "3:04:05 PM"},
+	"en-gb": {"en-GB", ",", ".", "02/01/2006", "15:04:05"},
+	"de-de": {"de-DE", ".", ",", "02.01.2006", "15:04:05"},
+	"de-ch": {"de-CH", "'", ".", "02.01.2006", "15:04:05"},
+	"fr-fr": {"fr-FR", " ", ",", "02/01/2006", "15:04:05"},
+	"es-es": {"es-ES", ".", ",", "02/01/2006", "15:04:05"},
+	"it-it": {"it-IT", ".", ",", "02/01/2006", "15:04:05"},
+	"nl-nl": {"nl-NL", ".", ",", "02-01-2006", "15:04:05"},
+	"pt-br": {"pt-BR", ".", ",", "02/01/2006", "15:04:05"},
+	"ja-jp": {"ja-JP", ",", ".", "2006/01/02", "15:04:05"},
+	"zh-cn": {"zh-CN", ",", ".", "2006/01/02", "15:04:05"},
+}
+
+var locale atomic.Pointer[Locale]
+
+// SetLocale sets the locale of human-readable output. Names like 'de-DE', 'de_DE' and
+// 'de_DE.UTF-8' are accepted. Without a locale, numbers have no thousands separators and
+// timestamps are formatted like 2006-01-02 15:04:05.
+func SetLocale(name string) error {
+	key, _, _ := strings.Cut(name, ".")
+	key = strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+	l, ok := locales[key]
+	if !ok {
+		return fmt.Errorf("unsupported locale %s, use one of %s", name, strings.Join(SupportedLocales(), ", "))
+	}
+	locale.Store(&l)
+	return nil
+}
+
+// SupportedLocales returns the names of the supported locales
+func SupportedLocales() []string {
+	names := make([]string, 0, len(locales))
+	for _, l := range locales {
+		names = append(names, l.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LocalizeNumber formats a decimal number like '-1234567.89' with the separators of the locale.
+// Strings which are not decimal numbers, e.g. empty cells, are returned as they are. Numbers are
+// masked with --redact, since they are amounts wherever they are localized.
+func LocalizeNumber(number string) string {
+	if IsRedacted() {
+		return RedactAmount(number)
+	}
+	l := locale.Load()
+	if l == nil || !isDecimal(number) {
+		return number
+	}
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(number, ".")
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(l.ThousandsSeparator)
+		}
+		b.WriteRune(digit)
+	}
+	if hasFraction {
+		b.WriteString(l.DecimalMark)
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// LocalizeTime formats a timestamp with the date and time layouts of the locale, or with the
+// given layout if no locale is set
+func LocalizeTime(t time.Time, layout string) string {
+	l := locale.Load()
+	if l == nil {
+		return t.Format(layout)
+	}
+	return t.Format(l.DateLayout + " " + l.TimeLayout)
+}
+
+// LocalizeDate formats the date of a timestamp with the date layout of the locale, or as
+// 2006-01-02 if no locale is set
+func LocalizeDate(t time.Time) string {
+	l := locale.Load()
+	if l == nil {
+		return t.Format(time.DateOnly)
+	}
+	return t.Format(l.DateLayout)
+}
+
+func isDecimal(number string) bool {
+	number = strings.TrimPrefix(number, "-")
+	whole, fraction, hasFraction := strings.Cut(number, ".")
+	if whole == "" || (hasFraction && fraction == "") {
+		return false
+	}
+	for _, digit := range whole + fraction {
+		if digit < '0' || digit > '9' {
+			return false
+		}
+	}
+	return true
+}
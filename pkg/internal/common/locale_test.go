@@ -0,0 +1,31 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalize(t *testing.T) {
+	defer locale.Store(nil)
+	ts := time.Date(2024, time.March, 5, 14, 7, 9, 0, time.UTC)
+
+	assert.Equal(t, "1234567.89", LocalizeNumber("1234567.89"))
+	assert.Equal(t, "2024-03-05T14:07:09Z", LocalizeTime(ts, time.RFC3339))
+	assert.Equal(t, "2024-03-05", LocalizeDate(ts))
+
+	assert.Error(t, SetLocale("xx-XX"))
+	assert.NoError(t, SetLocale("de_DE.UTF-8"))
+	assert.Equal(t, "1.234.567,89", LocalizeNumber("1234567.89"))
+	assert.Equal(t, "-123.456", LocalizeNumber("-123456"))
+	assert.Equal(t, "999", LocalizeNumber("999"))
+	assert.Equal(t, "", LocalizeNumber(""))
+	assert.Equal(t, "n/a", LocalizeNumber("n/a"))
+	assert.Equal(t, "05.03.2024 14:07:09", LocalizeTime(ts, time.RFC3339))
+	assert.Equal(t, "05.03.2024", LocalizeDate(ts))
+
+	assert.NoError(t, SetLocale("en-US"))
+	assert.Equal(t, "1,000,000", LocalizeNumber("1000000"))
+	assert.Equal(t, "03/05/2024 2:07:09 PM", LocalizeTime(ts, time.RFC3339))
+}
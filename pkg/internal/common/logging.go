@@ -0,0 +1,166 @@
+package common
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	eigensdkLogger "github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+// Modules of the CLI which log under their own name, next to the command groups like rewards
+// and operator
+const (
+	// LogModuleTx is sending transactions and waiting for their receipts
+	LogModuleTx = "tx"
+	// LogModuleCompat is checking the compatibility and the code of the deployed contracts
+	LogModuleCompat = "compat"
+	// LogModuleRPC is the requests to the RPC of the network
+	LogModuleRPC = "rpc"
+)
+
+const logModuleKey = "module"
+
+// Verbosity levels of -v, -vv and -vvv
+const (
+	verbosityCommandDebug = 1
+	verbosityDebug        = 2
+	verbositySource       = 3
+)
+
+// logLevels are the minimum levels logged by module
+type logLevels struct {
+	defaultLevel  slog.Level
+	commandModule string
+	commandLevel  slog.Level
+	modules       map[string]slog.Level
+}
+
+func newLogLevels(verbosity int, commandModule string, filter map[string]slog.Level) *logLevels {
+	levels := &logLevels{
+		defaultLevel:  slog.LevelInfo,
+		commandModule: commandModule,
+		commandLevel:  slog.LevelInfo,
+		modules:       filter,
+	}
+	if verbosity >= verbosityCommandDebug {
+		levels.commandLevel = slog.LevelDebug
+	}
+	if verbosity >= verbosityDebug {
+		levels.defaultLevel = slog.LevelDebug
+	}
+	return levels
+}
+
+func (l *logLevels) enabled(module string, level slog.Level) bool {
+	minLevel := l.defaultLevel
+	if module == l.commandModule {
+		minLevel = l.commandLevel
+	}
+	if moduleLevel, ok := l.modules[module]; ok {
+		minLevel = moduleLevel
+	}
+	return level >= minLevel
+}
+
+// ParseLogFilter parses a filter like 'rewards=debug,tx=warn' into the level of every module
+func ParseLogFilter(filter string) (map[string]slog.Level, error) {
+	levels := make(map[string]slog.Level)
+	for _, entry := range strings.Split(filter, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		module, levelName, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(module) == "" {
+			return nil, fmt.Errorf("invalid log filter %s, expected module=level", entry)
+		}
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(levelName))); err != nil {
+			return nil, fmt.Errorf("invalid level in log filter %s: %w", entry, err)
+		}
+		levels[strings.TrimSpace(module)] = level
+	}
+	return levels, nil
+}
+
+// WithLogModule returns a logger logging under the given module, so its level can be set with
+// --log-filter
+func WithLogModule(logger eigensdkLogger.Logger, module string) eigensdkLogger.Logger {
+	return logger.With(logModuleKey, module)
+}
+
+// moduleLogger drops the messages below the level of its module. The logger it wraps logs every
+// level, so the levels of modules can differ.
+type moduleLogger struct {
+	logger eigensdkLogger.Logger
+	module string
+	levels *logLevels
+}
+
+func (l *moduleLogger) Debug(msg string, tags ...any) {
+	if l.levels.enabled(l.module, slog.LevelDebug) {
+		l.logger.Debug(msg, tags...)
+	}
+}
+
+func (l *moduleLogger) Info(msg string, tags ...any) {
+	if l.levels.enabled(l.module, slog.LevelInfo) {
+		l.logger.Info(msg, tags...)
+	}
+}
+
+func (l *moduleLogger) Warn(msg string, tags ...any) {
+	if l.levels.enabled(l.module, slog.LevelWarn) {
+		l.logger.Warn(msg, tags...)
+	}
+}
+
+func (l *moduleLogger) Error(msg string, tags ...any) {
+	if l.levels.enabled(l.module, slog.LevelError) {
+		l.logger.Error(msg, tags...)
+	}
+}
+
+// Fatal is never filtered, since it exits
+func (l *moduleLogger) Fatal(msg string, tags ...any) {
+	l.logger.Fatal(msg, tags...)
+}
+
+func (l *moduleLogger) Debugf(template string, args ...interface{}) {
+	if l.levels.enabled(l.module, slog.LevelDebug) {
+		l.logger.Debugf(template, args...)
+	}
+}
+
+func (l *moduleLogger) Infof(template string, args ...interface{}) {
+	if l.levels.enabled(l.module, slog.LevelInfo) {
+		l.logger.Infof(template, args...)
+	}
+}
+
+func (l *moduleLogger) Warnf(template string, args ...interface{}) {
+	if l.levels.enabled(l.module, slog.LevelWarn) {
+		l.logger.Warnf(template, args...)
+	}
+}
+
+func (l *moduleLogger) Errorf(template string, args ...interface{}) {
+	if l.levels.enabled(l.module, slog.LevelError) {
+		l.logger.Errorf(template, args...)
+	}
+}
+
+func (l *moduleLogger) Fatalf(template string, args ...interface{}) {
+	l.logger.Fatalf(template, args...)
+}
+
+func (l *moduleLogger) With(tags ...any) eigensdkLogger.Logger {
+	module := l.module
+	for i := 0; i+1 < len(tags); i += 2 {
+		if key, ok := tags[i].(string); ok && key == logModuleKey {
+			module = fmt.Sprint(tags[i+1])
+		}
+	}
+	return &moduleLogger{logger: l.logger.With(tags...), module: module, levels: l.levels}
+}
@@ -0,0 +1,145 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+)
+
+func TestParseLogFilter(t *testing.T) {
+	levels, err := ParseLogFilter("rewards=debug, tx=warn,")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]slog.Level{"rewards": slog.LevelDebug, "tx": slog.LevelWarn}, levels)
+
+	_, err = ParseLogFilter("rewards")
+	assert.Error(t, err)
+	_, err = ParseLogFilter("rewards=loud")
+	assert.Error(t, err)
+}
+
+func TestModuleLogger(t *testing.T) {
+	tests := []struct {
+		name      string
+		verbosity int
+		filter    map[string]slog.Level
+		// logged are the messages expected of the command module and the tx module
+		logged []string
+	}{
+		{name: "default", logged: []string{"rewards info", "tx info"}},
+		{name: "-v", verbosity: 1, logged: []string{"rewards debug", "rewards info", "tx info"}},
+		{name: "-vv", verbosity: 2, logged: []string{"rewards debug", "rewards info", "tx debug", "tx info"}},
+		{
+			name:      "filter",
+			verbosity: 2,
+			filter:    map[string]slog.Level{"tx": slog.LevelWarn, "rewards": slog.LevelInfo},
+			logged:    []string{"rewards info"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			base := logging.NewJsonSLogger(&out, &logging.SLoggerOptions{Level: slog.LevelDebug})
+			logger := &moduleLogger{logger: base, module: "rewards", levels: newLogLevels(tt.verbosity, "rewards", tt.filter)}
+			txLogger := WithLogModule(logger, LogModuleTx)
+
+			logger.Debugf("rewards %s", "debug")
+			logger.Info("rewards info")
+			txLogger.Debug("tx debug")
+			txLogger.Infof("tx %s", "info")
+
+			for _, message := range []string{"rewards debug", "rewards info", "tx debug", "tx info"} {
+				assert.Equal(t, contains(tt.logged, message), bytes.Contains(out.Bytes(), []byte(message)), message)
+			}
+		})
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLoggingTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		// The request body is passed on unchanged
+		assert.Contains(t, string(body), "eth_chainId")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x4268"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	base := logging.NewJsonSLogger(&out, &logging.SLoggerOptions{Level: slog.LevelDebug})
+	setRPCLogger(&moduleLogger{logger: base, levels: newLogLevels(2, "rewards", nil)})
+	defer func() { rpcLogger = nil }()
+
+	client, err := DialEthClient(server.URL)
+	assert.NoError(t, err)
+	defer client.Close()
+	chainID, err := client.ChainID(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(17000), chainID.Int64())
+	assert.Contains(t, out.String(), `"module":"rpc"`)
+	assert.Contains(t, out.String(), `"method":"eth_chainId"`)
+
+	// rpc=warn drops the requests which succeed
+	out.Reset()
+	filter := map[string]slog.Level{LogModuleRPC: slog.LevelWarn}
+	setRPCLogger(&moduleLogger{logger: base, levels: newLogLevels(2, "rewards", filter)})
+	_, err = client.ChainID(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, out.String())
+}
+
+func TestGetRPCMethods(t *testing.T) {
+	assert.Equal(t, "eth_call", getRPCMethods([]byte(`{"method":"eth_call"}`)))
+	batch := []byte(`[{"method":"eth_call"},{"method":"eth_blockNumber"}]`)
+	assert.Equal(t, "eth_call,eth_blockNumber", getRPCMethods(batch))
+	assert.Equal(t, "unknown", getRPCMethods([]byte(`not json`)))
+}
+
+func TestIsVerbose(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{args: []string{"eigenlayer", "rewards", "show"}, want: false},
+		{args: []string{"eigenlayer", "rewards", "show", "-v"}, want: true},
+		{args: []string{"eigenlayer", "--verbose", "rewards", "show"}, want: true},
+	}
+	for _, tt := range tests {
+		var got bool
+		app := &cli.App{
+			Name:  "eigenlayer",
+			Flags: []cli.Flag{&flags.GlobalVerboseFlag},
+			Commands: []*cli.Command{{
+				Name: "rewards",
+				Subcommands: []*cli.Command{{
+					Name:  "show",
+					Flags: []cli.Flag{&flags.VerboseFlag},
+					Action: func(cCtx *cli.Context) error {
+						got = isVerbose(cCtx)
+						return nil
+					},
+				}},
+			}},
+		}
+		assert.NoError(t, app.Run(tt.args))
+		assert.Equal(t, tt.want, got, "%v", tt.args)
+	}
+}
@@ -0,0 +1,86 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// runMeta is what a command records about the data it acted on, e.g. the verification of a
+// downloaded rewards snapshot. It's written to the meta file and added to the JSON stats, so
+// pipelines can keep it next to the output of the command.
+var runMeta = struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]interface{}
+}{entries: make(map[string]interface{})}
+
+// SetMetaFile sets the file the run metadata is written to when the command completed
+func SetMetaFile(path string) {
+	runMeta.mu.Lock()
+	defer runMeta.mu.Unlock()
+	runMeta.path = path
+}
+
+// resetRunMeta drops the meta file and the entries recorded by a previous run
+func resetRunMeta() {
+	runMeta.mu.Lock()
+	defer runMeta.mu.Unlock()
+	runMeta.path = ""
+	runMeta.entries = make(map[string]interface{})
+}
+
+// SetRunMeta records an entry of the run metadata. The value has to marshal to JSON.
+func SetRunMeta(key string, value interface{}) {
+	runMeta.mu.Lock()
+	defer runMeta.mu.Unlock()
+	runMeta.entries[key] = value
+}
+
+// GetRunMeta returns the entries of the run metadata recorded so far
+func GetRunMeta() map[string]interface{} {
+	runMeta.mu.Lock()
+	defer runMeta.mu.Unlock()
+	entries := make(map[string]interface{}, len(runMeta.entries))
+	for key, value := range runMeta.entries {
+		entries[key] = value
+	}
+	return entries
+}
+
+// WriteRunMeta writes the run metadata to the meta file, if one is set. The file is written even
+// if nothing was recorded, so pipelines can tell the command ran.
+func WriteRunMeta() error {
+	runMeta.mu.Lock()
+	path := runMeta.path
+	runMeta.mu.Unlock()
+	if IsEmptyString(path) {
+		return nil
+	}
+
+	meta := GetRunMeta()
+	meta["command"] = hookCommand
+	out, err := json.MarshalIndent(map[string]interface{}{"meta": meta}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run metadata: %w", err)
+	}
+	return WriteToFile(out, path)
+}
+
+// withRunMeta adds the run metadata to the fields of v, which has to marshal to a JSON object
+func withRunMeta(v interface{}) (map[string]interface{}, error) {
+	out, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(out, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range GetRunMeta() {
+		if _, ok := fields[key]; !ok {
+			fields[key] = value
+		}
+	}
+	return fields, nil
+}
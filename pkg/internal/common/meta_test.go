@@ -0,0 +1,51 @@
+package common
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteRunMeta(t *testing.T) {
+	defer func() {
+		SetMetaFile("")
+		runMeta.entries = make(map[string]interface{})
+	}()
+
+	// Without a meta file nothing is written
+	assert.NoError(t, WriteRunMeta())
+
+	path := filepath.Join(t.TempDir(), "meta", "run.json")
+	SetMetaFile(path)
+	SetHookCommand("rewards claim")
+	defer SetHookCommand("")
+	SetRunMeta("snapshotVerification", map[string]interface{}{"verified": true, "method": "checksum"})
+	assert.NoError(t, WriteRunMeta())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var written struct {
+		Meta map[string]interface{} `json:"meta"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, "rewards claim", written.Meta["command"])
+	assert.Equal(t, map[string]interface{}{"verified": true, "method": "checksum"}, written.Meta["snapshotVerification"])
+}
+
+func TestWithRunMeta(t *testing.T) {
+	defer func() {
+		runMeta.entries = make(map[string]interface{})
+	}()
+	SetRunMeta("snapshotVerification", "ok")
+	// Fields of the value take precedence over the run metadata
+	SetRunMeta("rpcCalls", 100)
+
+	fields, err := withRunMeta(&RunStats{Command: "rewards show", RPCCalls: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "rewards show", fields["command"])
+	assert.Equal(t, 2.0, fields["rpcCalls"])
+	assert.Equal(t, "ok", fields["snapshotVerification"])
+}
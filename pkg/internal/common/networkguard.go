@@ -0,0 +1,66 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+var ErrNetworkDenied = errors.New("network is not allowed")
+
+// networkGuard is the set of chains transactions may be sent to, and where the restriction comes
+// from, e.g. a profile
+type networkGuard struct {
+	mu       sync.Mutex
+	chainIDs []*big.Int
+	source   string
+}
+
+var allowedNetworks networkGuard
+
+// SetAllowedChainIDs restricts sending transactions to RPCs of the given chains. The source, e.g.
+// 'profile holesky-operator', is named in the error when a transaction is refused.
+func SetAllowedChainIDs(chainIDs []*big.Int, source string) {
+	allowedNetworks.mu.Lock()
+	defer allowedNetworks.mu.Unlock()
+	allowedNetworks.chainIDs = chainIDs
+	allowedNetworks.source = source
+}
+
+type chainIDClient interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// CheckAllowedNetwork asks the RPC for its chain ID and fails if the chain is not allowed. The
+// configured network is not trusted, since an RPC URL of another network is exactly the mistake
+// this guards against.
+func CheckAllowedNetwork(ctx context.Context, client chainIDClient) error {
+	allowedNetworks.mu.Lock()
+	chainIDs, source := allowedNetworks.chainIDs, allowedNetworks.source
+	allowedNetworks.mu.Unlock()
+	if len(chainIDs) == 0 {
+		return nil
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the chain ID of the RPC to check the allowed networks: %w", err)
+	}
+	allowed := make([]string, 0, len(chainIDs))
+	for _, allowedChainID := range chainIDs {
+		if allowedChainID.Cmp(chainID) == 0 {
+			return nil
+		}
+		allowed = append(allowed, allowedChainID.String())
+	}
+	return fmt.Errorf(
+		"%w by %s: the RPC is on chain %s, allowed chains are %s",
+		ErrNetworkDenied,
+		source,
+		chainID,
+		strings.Join(allowed, ", "),
+	)
+}
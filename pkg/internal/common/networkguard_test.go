@@ -0,0 +1,31 @@
+package common
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeChainIDClient struct {
+	chainID *big.Int
+}
+
+func (c *fakeChainIDClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.chainID, nil
+}
+
+func TestCheckAllowedNetwork(t *testing.T) {
+	defer SetAllowedChainIDs(nil, "")
+	mainnet := &fakeChainIDClient{chainID: big.NewInt(1)}
+	holesky := &fakeChainIDClient{chainID: big.NewInt(17000)}
+
+	assert.NoError(t, CheckAllowedNetwork(context.Background(), mainnet))
+
+	SetAllowedChainIDs([]*big.Int{big.NewInt(17000)}, "profile test")
+	assert.NoError(t, CheckAllowedNetwork(context.Background(), holesky))
+	err := CheckAllowedNetwork(context.Background(), mainnet)
+	assert.ErrorIs(t, err, ErrNetworkDenied)
+	assert.EqualError(t, err, "network is not allowed by profile test: the RPC is on chain 1, allowed chains are 17000")
+}
@@ -0,0 +1,387 @@
+package common
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/types"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	eigensdkLogger "github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	txPlanVersion = 1
+
+	// ReceiptStatusPlanned is the status of the receipt returned for a transaction which was added
+	// to a plan instead of being sent. It is neither successful nor failed, so code which doesn't
+	// know about plans never takes a planned transaction for a sent one.
+	ReceiptStatusPlanned = math.MaxUint64
+)
+
+var ErrInvalidPlan = errors.New("invalid plan")
+
+// TxPlan is a reviewable description of the transactions a write command would send. It is
+// written with --plan and sent with --apply, so one person can prepare a change and another one
+// can review it before anything is signed.
+type TxPlan struct {
+	Version      int               `json:"version"`
+	Command      string            `json:"command"`
+	ChainID      string            `json:"chainId"`
+	CreatedAt    time.Time         `json:"createdAt"`
+	Params       map[string]string `json:"params,omitempty"`
+	Transactions []PlannedTx       `json:"transactions"`
+}
+
+// PlannedTx is a single transaction of a plan
+type PlannedTx struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Value string `json:"value"`
+	Data  string `json:"data"`
+}
+
+// PlanCheck is a check of the command applying a plan on the transactions of the plan, e.g. on
+// the decoded calldata. It runs after the plan is read and before anything is signed.
+type PlanCheck func(plan *TxPlan) error
+
+// PlanReceiptHandler is called by the command applying a plan with the receipt of every
+// transaction of the plan which succeeded, e.g. to record it
+type PlanReceiptHandler func(tx PlannedTx, receipt *gethtypes.Receipt)
+
+// PlanConfig holds the plan related flags of a write command
+type PlanConfig struct {
+	PlanFile  string
+	ApplyFile string
+	// PlanHash is the hash of the plan the reviewers approved, which the applied plan must have
+	PlanHash string
+}
+
+func (c *PlanConfig) IsPlan() bool {
+	return c != nil && !IsEmptyString(c.PlanFile)
+}
+
+func (c *PlanConfig) IsApply() bool {
+	return c != nil && !IsEmptyString(c.ApplyFile)
+}
+
+func ReadPlanConfig(cCtx *cli.Context) (*PlanConfig, error) {
+	config := &PlanConfig{
+		PlanFile:  cCtx.String(flags.PlanFlag.Name),
+		ApplyFile: cCtx.String(flags.ApplyFlag.Name),
+		PlanHash:  strings.TrimPrefix(cCtx.String(flags.PlanHashFlag.Name), "0x"),
+	}
+	if config.IsPlan() && config.IsApply() {
+		return nil, errors.New("--plan and --apply can't be used together")
+	}
+	if config.IsPlan() && cCtx.Bool(flags.BroadcastFlag.Name) {
+		return nil, errors.New("--plan and --broadcast can't be used together")
+	}
+	if config.IsApply() && IsEmptyString(config.PlanHash) {
+		return nil, errors.New("--apply requires the --plan-hash of the reviewed plan")
+	}
+	return config, nil
+}
+
+func NewTxPlan(command string, chainID *big.Int, params map[string]string) *TxPlan {
+	return &TxPlan{
+		Version:      txPlanVersion,
+		Command:      command,
+		ChainID:      chainID.String(),
+		CreatedAt:    time.Now().UTC(),
+		Params:       params,
+		Transactions: make([]PlannedTx, 0),
+	}
+}
+
+func ReadTxPlan(path string) (*TxPlan, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	var plan TxPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPlan, err)
+	}
+	if plan.Version != txPlanVersion {
+		return nil, fmt.Errorf("%w: unsupported plan version %d", ErrInvalidPlan, plan.Version)
+	}
+	if len(plan.Transactions) == 0 {
+		return nil, fmt.Errorf("%w: plan has no transactions", ErrInvalidPlan)
+	}
+	return &plan, nil
+}
+
+// Hash returns the hash of the plan, which reviewers can use to make sure the plan they approved
+// is the plan that gets applied
+func (p *TxPlan) Hash() (string, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func (p *TxPlan) Write(path string, logger eigensdkLogger.Logger) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := WriteToFile(data, path); err != nil {
+		return err
+	}
+	hash, err := p.Hash()
+	if err != nil {
+		return err
+	}
+	logger.Infof("%s Plan with %d transaction(s) written to file: %s", utils.EmojiCheckMark, len(p.Transactions), path)
+	logger.Infof("Plan hash: %s", hash)
+	fmt.Println("Review the plan and send it with --apply and --plan-hash")
+	return nil
+}
+
+func (p *TxPlan) add(from gethcommon.Address, tx *gethtypes.Transaction) {
+	to := ""
+	if tx.To() != nil {
+		to = tx.To().Hex()
+	}
+	value := "0"
+	if tx.Value() != nil {
+		value = tx.Value().String()
+	}
+	p.Transactions = append(p.Transactions, PlannedTx{
+		From:  from.Hex(),
+		To:    to,
+		Value: value,
+		Data:  hexutil.Encode(tx.Data()),
+	})
+}
+
+// planTxManager records transactions into a plan instead of sending them
+type planTxManager struct {
+	from gethcommon.Address
+	plan *TxPlan
+}
+
+func (m *planTxManager) GetNoSendTxOpts() (*bind.TransactOpts, error) {
+	return GetNoSendTxOpts(m.from), nil
+}
+
+func (m *planTxManager) Send(
+	ctx context.Context,
+	tx *gethtypes.Transaction,
+	waitForReceipt bool,
+) (*gethtypes.Receipt, error) {
+	m.plan.add(m.from, tx)
+	return &gethtypes.Receipt{TxHash: tx.Hash(), Status: ReceiptStatusPlanned}, nil
+}
+
+// GetPlanELWriter returns a writer which adds the transactions of a command to the plan instead
+// of sending them. No signer is needed to prepare a plan.
+func GetPlanELWriter(
+	from gethcommon.Address,
+	ethClient *ethclient.Client,
+	contractConfig elcontracts.Config,
+	plan *TxPlan,
+	logger eigensdkLogger.Logger,
+) (*elcontracts.ChainWriter, error) {
+	if err := checkReadOnly(); err != nil {
+		return nil, err
+	}
+	logger.Debug("Getting plan Writer from config")
+	return newELWriter(contractConfig, ethClient, &planTxManager{from: from, plan: plan}, logger)
+}
+
+// GetELWriterWithPlan returns the writer of a write command. With --plan the writer adds the
+// transactions to the returned plan, otherwise it signs and sends them and the plan is nil.
+func GetELWriterWithPlan(
+	planConfig *PlanConfig,
+	command string,
+	params map[string]string,
+	signerAddress gethcommon.Address,
+	signerConfig *types.SignerConfig,
+	ethClient *ethclient.Client,
+	contractConfig elcontracts.Config,
+	prompter utils.Prompter,
+	chainId *big.Int,
+	logger eigensdkLogger.Logger,
+) (*elcontracts.ChainWriter, *TxPlan, error) {
+	if !planConfig.IsPlan() {
+		elWriter, err := GetELWriter(signerAddress, signerConfig, ethClient, contractConfig, prompter, chainId, logger)
+		return elWriter, nil, err
+	}
+	plan := NewTxPlan(command, chainId, params)
+	elWriter, err := GetPlanELWriter(signerAddress, ethClient, contractConfig, plan, logger)
+	return elWriter, plan, err
+}
+
+// GetTxManagerWithPlan returns the tx manager of a write command which sends transactions built
+// with contract bindings rather than the EL writer. With --plan the transactions are added to the
+// returned plan, otherwise they are signed and sent and the plan is nil.
+func GetTxManagerWithPlan(
+	planConfig *PlanConfig,
+	command string,
+	params map[string]string,
+	signerAddress gethcommon.Address,
+	signerConfig *types.SignerConfig,
+	ethClient *ethclient.Client,
+	prompter utils.Prompter,
+	chainId *big.Int,
+	logger eigensdkLogger.Logger,
+) (txmgr.TxManager, *TxPlan, error) {
+	if !planConfig.IsPlan() {
+		txMgr, err := getTxManager(signerAddress, signerConfig, ethClient, prompter, chainId, logger)
+		return txMgr, nil, err
+	}
+	if err := checkReadOnly(); err != nil {
+		return nil, nil, err
+	}
+	plan := NewTxPlan(command, chainId, params)
+	return &planTxManager{from: signerAddress, plan: plan}, plan, nil
+}
+
+// ApplyTxPlan sends the transactions of a reviewed plan. The plan has to have the hash the reviewers
+// approved, be for the same command, chain and signer it is applied with, and pass the checks of
+// the command.
+func ApplyTxPlan(
+	ctx context.Context,
+	planConfig *PlanConfig,
+	command string,
+	signerAddress gethcommon.Address,
+	signerConfig *types.SignerConfig,
+	ethClient *ethclient.Client,
+	prompter utils.Prompter,
+	chainID *big.Int,
+	logger eigensdkLogger.Logger,
+	checks ...PlanCheck,
+) error {
+	return ApplyTxPlanWithReceipts(
+		ctx,
+		planConfig,
+		command,
+		signerAddress,
+		signerConfig,
+		ethClient,
+		prompter,
+		chainID,
+		logger,
+		checks,
+		nil,
+	)
+}
+
+// ApplyTxPlanWithReceipts is ApplyTxPlan which also hands the receipt of every successful
+// transaction to onReceipt, if set
+func ApplyTxPlanWithReceipts(
+	ctx context.Context,
+	planConfig *PlanConfig,
+	command string,
+	signerAddress gethcommon.Address,
+	signerConfig *types.SignerConfig,
+	ethClient *ethclient.Client,
+	prompter utils.Prompter,
+	chainID *big.Int,
+	logger eigensdkLogger.Logger,
+	checks []PlanCheck,
+	onReceipt PlanReceiptHandler,
+) error {
+	if err := checkReadOnly(); err != nil {
+		return err
+	}
+	plan, err := ReadTxPlan(planConfig.ApplyFile)
+	if err != nil {
+		return err
+	}
+	hash, err := plan.Hash()
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(hash, planConfig.PlanHash) {
+		return fmt.Errorf("%w: plan hash is %s, not the reviewed %s", ErrInvalidPlan, hash, planConfig.PlanHash)
+	}
+	if plan.Command != command {
+		return fmt.Errorf("%w: plan is for command '%s', not '%s'", ErrInvalidPlan, plan.Command, command)
+	}
+	if plan.ChainID != chainID.String() {
+		return fmt.Errorf("%w: plan is for chain ID %s, not %s", ErrInvalidPlan, plan.ChainID, chainID.String())
+	}
+	rpcChainID, err := ethClient.ChainID(ctx)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get chain ID from RPC", err)
+	}
+	if rpcChainID.Cmp(chainID) != 0 {
+		return fmt.Errorf("%w: RPC is connected to chain ID %s, not %s", ErrInvalidPlan, rpcChainID, chainID)
+	}
+	for _, tx := range plan.Transactions {
+		if gethcommon.HexToAddress(tx.From) != signerAddress {
+			return fmt.Errorf("%w: transaction from %s can't be sent by %s", ErrInvalidPlan, tx.From, signerAddress.Hex())
+		}
+		if !gethcommon.IsHexAddress(tx.To) {
+			return fmt.Errorf("%w: invalid transaction target %s", ErrInvalidPlan, tx.To)
+		}
+	}
+	for _, check := range checks {
+		if err := check(plan); err != nil {
+			return err
+		}
+	}
+
+	logger.Infof("Applying plan %s with %d transaction(s) created at %s", hash, len(plan.Transactions), plan.CreatedAt)
+
+	txMgr, err := getTxManager(signerAddress, signerConfig, ethClient, prompter, chainID, logger)
+	if err != nil {
+		return err
+	}
+	for i, plannedTx := range plan.Transactions {
+		to := gethcommon.HexToAddress(plannedTx.To)
+		value, ok := new(big.Int).SetString(plannedTx.Value, 10)
+		if !ok {
+			return fmt.Errorf("%w: invalid value %s", ErrInvalidPlan, plannedTx.Value)
+		}
+		tx := gethtypes.NewTx(&gethtypes.DynamicFeeTx{
+			ChainID: chainID,
+			To:      &to,
+			Value:   value,
+			Data:    gethcommon.FromHex(plannedTx.Data),
+		})
+
+		logger.Infof("Sending transaction %d of %d to %s", i+1, len(plan.Transactions), plannedTx.To)
+		receipt, err := txMgr.Send(ctx, tx, true)
+		if err != nil {
+			return eigenSdkUtils.WrapError(fmt.Sprintf("failed to send transaction %d of the plan", i+1), err)
+		}
+		if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+			return fmt.Errorf("transaction %d of the plan reverted: %s", i+1, receipt.TxHash.Hex())
+		}
+		PrintTransactionInfo(receipt.TxHash.String(), chainID)
+		PrintReceiptEvents(receipt, string(OutputType_Pretty))
+		if onReceipt != nil {
+			onReceipt(plannedTx, receipt)
+		}
+	}
+	logger.Infof("%s Plan applied successfully", utils.EmojiCheckMark)
+	return nil
+}
@@ -0,0 +1,81 @@
+package common
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxPlanRoundTrip(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	from := gethcommon.HexToAddress("0x1")
+	to := gethcommon.HexToAddress("0x2")
+
+	plan := NewTxPlan("rewards set-claimer", big.NewInt(17000), map[string]string{"claimer": to.Hex()})
+	txMgr := &planTxManager{from: from, plan: plan}
+	receipt, err := txMgr.Send(context.Background(), gethtypes.NewTx(&gethtypes.DynamicFeeTx{
+		To:    &to,
+		Value: big.NewInt(0),
+		Data:  []byte{0xa0, 0x16, 0x9d, 0xdd},
+	}), true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(ReceiptStatusPlanned), receipt.Status)
+
+	planFile := filepath.Join(t.TempDir(), "plan.json")
+	err = plan.Write(planFile, logger)
+	assert.NoError(t, err)
+
+	readPlan, err := ReadTxPlan(planFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "rewards set-claimer", readPlan.Command)
+	assert.Equal(t, "17000", readPlan.ChainID)
+	assert.Equal(t, []PlannedTx{{From: from.Hex(), To: to.Hex(), Value: "0", Data: "0xa0169ddd"}}, readPlan.Transactions)
+
+	// the hash shown when writing the plan has to match the hash shown when applying it
+	expectedHash, err := plan.Hash()
+	assert.NoError(t, err)
+	readHash, err := readPlan.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, expectedHash, readHash)
+}
+
+func TestReadTxPlanWithoutTransactions(t *testing.T) {
+	planFile := filepath.Join(t.TempDir(), "plan.json")
+	err := os.WriteFile(planFile, []byte(`{"version": 1, "command": "rewards claim", "transactions": []}`), 0o600)
+	assert.NoError(t, err)
+
+	_, err = ReadTxPlan(planFile)
+	assert.ErrorIs(t, err, ErrInvalidPlan)
+}
+
+func TestApplyTxPlanRequiresReviewedHash(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	from := gethcommon.HexToAddress("0x1")
+	plan := NewTxPlan("rewards set-claimer", big.NewInt(17000), nil)
+	plan.Transactions = append(plan.Transactions, PlannedTx{From: from.Hex(), To: from.Hex(), Value: "0", Data: "0x"})
+	planFile := filepath.Join(t.TempDir(), "plan.json")
+	assert.NoError(t, plan.Write(planFile, logger))
+
+	// The hash is checked before anything is read from the chain
+	err := ApplyTxPlan(
+		context.Background(),
+		&PlanConfig{ApplyFile: planFile, PlanHash: "1234"},
+		"rewards set-claimer",
+		from,
+		nil,
+		nil,
+		nil,
+		big.NewInt(17000),
+		logger,
+	)
+	assert.ErrorIs(t, err, ErrInvalidPlan)
+	assert.ErrorContains(t, err, "not the reviewed 1234")
+}
@@ -0,0 +1,27 @@
+package common
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+var ErrReadOnly = errors.New("read-only mode: transactions can't be constructed or sent")
+
+var readOnly atomic.Bool
+
+// SetReadOnly makes every later attempt to construct, sign or send a transaction fail. There is
+// no way to turn it off again within the same run.
+func SetReadOnly() {
+	readOnly.Store(true)
+}
+
+func IsReadOnly() bool {
+	return readOnly.Load()
+}
+
+func checkReadOnly() error {
+	if IsReadOnly() {
+		return ErrReadOnly
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"sync/atomic"
+
+	eigensdkLogger "github.com/Layr-Labs/eigensdk-go/logging"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// redactedAmount replaces amounts in redacted output
+const redactedAmount = "[redacted]"
+
+// Characters of an address kept by redaction, after the 0x prefix and at the end
+const (
+	redactKeepPrefix = 4
+	redactKeepSuffix = 4
+)
+
+// addressPattern matches addresses, but not the first 40 characters of longer hex strings like
+// transaction hashes
+var addressPattern = regexp.MustCompile(`\b0x[0-9a-fA-F]{40}\b`)
+
+var redact atomic.Bool
+
+// SetRedact masks addresses and amounts in human-readable output and logs, so it can be shared
+// without leaking financial details. JSON and CSV output is left as it is, since it's meant for
+// machines.
+func SetRedact() {
+	redact.Store(true)
+}
+
+func IsRedacted() bool {
+	return redact.Load()
+}
+
+// RedactAddress masks an address like 0x1234...abcd if redaction is on
+func RedactAddress(address string) string {
+	if !IsRedacted() || len(address) < 2+redactKeepPrefix+redactKeepSuffix {
+		return address
+	}
+	return address[:2+redactKeepPrefix] + "..." + address[len(address)-redactKeepSuffix:]
+}
+
+// RedactAmount masks a decimal amount if redaction is on. Other values are returned as they are.
+func RedactAmount(amount string) string {
+	if !IsRedacted() || !isDecimal(amount) {
+		return amount
+	}
+	return redactedAmount
+}
+
+// RedactText masks the addresses in a text if redaction is on. Amounts can't be told from other
+// numbers in free text, so they are left to the callers formatting them.
+func RedactText(text string) string {
+	if !IsRedacted() {
+		return text
+	}
+	return addressPattern.ReplaceAllStringFunc(text, RedactAddress)
+}
+
+// redactValue masks a value logged as a tag or as an argument of a template. Amounts are big
+// numbers everywhere in the CLI, so these are masked too.
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *big.Int, *big.Float:
+		return redactedAmount
+	case gethcommon.Address:
+		return RedactAddress(v.Hex())
+	case *gethcommon.Address:
+		if v == nil {
+			return v
+		}
+		return RedactAddress(v.Hex())
+	case string:
+		return RedactText(v)
+	case error:
+		return RedactText(v.Error())
+	case fmt.Stringer:
+		return RedactText(v.String())
+	}
+	return value
+}
+
+func redactValues(values []interface{}) []interface{} {
+	redacted := make([]interface{}, len(values))
+	for i, value := range values {
+		redacted[i] = redactValue(value)
+	}
+	return redacted
+}
+
+// redactArgs masks the arguments of a template. Amounts are replaced with a value printing as
+// masked with any verb, since they are usually formatted with %d or %s.
+func redactArgs(args []interface{}) []interface{} {
+	redacted := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch arg.(type) {
+		case *big.Int, *big.Float:
+			redacted[i] = redactedArg{}
+		default:
+			redacted[i] = redactValue(arg)
+		}
+	}
+	return redacted
+}
+
+type redactedArg struct{}
+
+func (redactedArg) Format(f fmt.State, verb rune) {
+	_, _ = io.WriteString(f, redactedAmount)
+}
+
+// redactingLogger masks the addresses and amounts in the messages and tags it logs
+type redactingLogger struct {
+	logger eigensdkLogger.Logger
+}
+
+func (l *redactingLogger) Debug(msg string, tags ...any) {
+	l.logger.Debug(RedactText(msg), redactValues(tags)...)
+}
+
+func (l *redactingLogger) Info(msg string, tags ...any) {
+	l.logger.Info(RedactText(msg), redactValues(tags)...)
+}
+
+func (l *redactingLogger) Warn(msg string, tags ...any) {
+	l.logger.Warn(RedactText(msg), redactValues(tags)...)
+}
+
+func (l *redactingLogger) Error(msg string, tags ...any) {
+	l.logger.Error(RedactText(msg), redactValues(tags)...)
+}
+
+func (l *redactingLogger) Fatal(msg string, tags ...any) {
+	l.logger.Fatal(RedactText(msg), redactValues(tags)...)
+}
+
+func (l *redactingLogger) Debugf(template string, args ...interface{}) {
+	l.logger.Debugf("%s", RedactText(fmt.Sprintf(template, redactArgs(args)...)))
+}
+
+func (l *redactingLogger) Infof(template string, args ...interface{}) {
+	l.logger.Infof("%s", RedactText(fmt.Sprintf(template, redactArgs(args)...)))
+}
+
+func (l *redactingLogger) Warnf(template string, args ...interface{}) {
+	l.logger.Warnf("%s", RedactText(fmt.Sprintf(template, redactArgs(args)...)))
+}
+
+func (l *redactingLogger) Errorf(template string, args ...interface{}) {
+	l.logger.Errorf("%s", RedactText(fmt.Sprintf(template, redactArgs(args)...)))
+}
+
+func (l *redactingLogger) Fatalf(template string, args ...interface{}) {
+	l.logger.Fatalf("%s", RedactText(fmt.Sprintf(template, redactArgs(args)...)))
+}
+
+func (l *redactingLogger) With(tags ...any) eigensdkLogger.Logger {
+	return &redactingLogger{logger: l.logger.With(redactValues(tags)...)}
+}
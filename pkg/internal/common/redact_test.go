@@ -0,0 +1,58 @@
+package common
+
+import (
+	"bytes"
+	"log/slog"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testRedactAddress = "0x2222AAC0C980Cc029624b7ff55B88Bc6F63C538f"
+	testRedactTxHash  = "0x3fc3b6ad9ab2e8e4ad6aa8e0c9d1f46b1ef9ffae2a5b4b9e0ff4c2a9d6f1a7b2"
+)
+
+func TestRedact(t *testing.T) {
+	defer redact.Store(false)
+
+	// Nothing is masked unless redaction is on
+	assert.Equal(t, testRedactAddress, RedactText(testRedactAddress))
+	assert.Equal(t, "1234", RedactAmount("1234"))
+	assert.Equal(t, "1234", LocalizeNumber("1234"))
+
+	SetRedact()
+	assert.Equal(t, "0x2222...538f", RedactAddress(testRedactAddress))
+	text := "earner " + testRedactAddress + " in " + testRedactTxHash
+	assert.Equal(t, "earner 0x2222...538f in "+testRedactTxHash, RedactText(text))
+	assert.Equal(t, redactedAmount, RedactAmount("1234.5"))
+	assert.Equal(t, "n/a", RedactAmount("n/a"))
+	assert.Equal(t, redactedAmount, LocalizeNumber("1234"))
+
+	table := NewTableWithHeaders("Address", "Amount")
+	table.AddRow(testRedactAddress, LocalizeNumber("100"))
+	assert.Contains(t, table.Markdown(), "| 0x2222...538f | [redacted] |")
+}
+
+func TestRedactingLogger(t *testing.T) {
+	defer redact.Store(false)
+	SetRedact()
+
+	var out bytes.Buffer
+	base := logging.NewJsonSLogger(&out, &logging.SLoggerOptions{Level: slog.LevelDebug})
+	logger := &redactingLogger{logger: base}
+	logger.Infof("Claiming %d of %s for %s", big.NewInt(12345), "token", gethcommon.HexToAddress(testRedactAddress))
+	logger.With("earner", testRedactAddress).Info("claimed", "amount", big.NewInt(67890))
+
+	logged := out.String()
+	assert.Contains(t, logged, "Claiming [redacted] of token for 0x2222...538f")
+	assert.Contains(t, logged, "0x2222...538f")
+	assert.False(t, strings.Contains(logged, testRedactAddress))
+	assert.False(t, strings.Contains(logged, "12345"))
+	assert.False(t, strings.Contains(logged, "67890"))
+}
@@ -0,0 +1,339 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	eigensdkLogger "github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+var ErrTxReverted = errors.New("transaction reverted")
+
+var (
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// panicReasons are the reasons of the Solidity panic codes
+var panicReasons = map[uint64]string{
+	0x01: "assertion failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to an uninitialized function",
+}
+
+// CallFrame is a call of a trace, in the format of the callTracer of debug_traceCall
+type CallFrame struct {
+	Type   string        `json:"type"`
+	From   string        `json:"from"`
+	To     string        `json:"to"`
+	Input  hexutil.Bytes `json:"input"`
+	Output hexutil.Bytes `json:"output,omitempty"`
+	Error  string        `json:"error,omitempty"`
+	Calls  []CallFrame   `json:"calls,omitempty"`
+}
+
+// RevertTrace is the outcome of re-simulating a reverted transaction
+type RevertTrace struct {
+	// Reason is the decoded revert reason, e.g. 'Error("not the claimer")' or a custom error
+	Reason string
+	// Trace is the call trace, or nil if the RPC supports neither debug_traceCall nor trace_call
+	Trace *CallFrame
+	// Method is the RPC method the transaction was re-simulated with
+	Method string
+}
+
+// rpcCaller is the raw RPC client, used for the tracing methods which have no typed client
+type rpcCaller interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+type contractCaller interface {
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// TraceRevert re-simulates a transaction on the state of the given block, or the latest block if
+// nil. It tries debug_traceCall, then trace_call, and falls back to eth_call, which only returns
+// the revert reason.
+func TraceRevert(
+	ctx context.Context,
+	rpc rpcCaller,
+	caller contractCaller,
+	from gethcommon.Address,
+	tx *gethtypes.Transaction,
+	blockNumber *big.Int,
+) (*RevertTrace, error) {
+	call := map[string]interface{}{
+		"from":  from,
+		"to":    tx.To(),
+		"gas":   hexutil.Uint64(tx.Gas()),
+		"value": (*hexutil.Big)(tx.Value()),
+		"data":  hexutil.Bytes(tx.Data()),
+	}
+	block := "latest"
+	if blockNumber != nil {
+		block = hexutil.EncodeBig(blockNumber)
+	}
+
+	var frame CallFrame
+	err := rpc.CallContext(ctx, &frame, "debug_traceCall", call, block, map[string]string{"tracer": "callTracer"})
+	if err == nil {
+		return &RevertTrace{Reason: getTraceReason(&frame), Trace: &frame, Method: "debug_traceCall"}, nil
+	}
+
+	var result traceCallResult
+	err = rpc.CallContext(ctx, &result, "trace_call", call, []string{"trace"}, block)
+	if err == nil && len(result.Trace) > 0 {
+		trace := result.toCallFrame()
+		return &RevertTrace{Reason: getTraceReason(trace), Trace: trace, Method: "trace_call"}, nil
+	}
+
+	msg := ethereum.CallMsg{From: from, To: tx.To(), Gas: tx.Gas(), Value: tx.Value(), Data: tx.Data()}
+	_, err = caller.CallContract(ctx, msg, blockNumber)
+	if err == nil {
+		return nil, errors.New("transaction doesn't revert when re-simulated")
+	}
+	var dataErr interface{ ErrorData() interface{} }
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if revertData, decodeErr := hexutil.Decode(data); decodeErr == nil {
+				return &RevertTrace{Reason: DecodeRevert(revertData), Method: "eth_call"}, nil
+			}
+		}
+	}
+	return &RevertTrace{Reason: err.Error(), Method: "eth_call"}, nil
+}
+
+// getTraceReason returns the reason of the innermost failed call, where the revert started
+func getTraceReason(frame *CallFrame) string {
+	for i := range frame.Calls {
+		if frame.Calls[i].Error != "" {
+			return getTraceReason(&frame.Calls[i])
+		}
+	}
+	if len(frame.Output) > 0 {
+		return DecodeRevert(frame.Output)
+	}
+	return frame.Error
+}
+
+// DecodeRevert decodes the revert data of a call: Error(string), Panic(uint256) and the custom
+// errors of the ABIs in the registry. Unknown errors are returned as hex.
+func DecodeRevert(data []byte) string {
+	if len(data) < 4 {
+		return "reverted without a reason"
+	}
+	selector := data[:4]
+	switch {
+	case bytes.Equal(selector, errorSelector):
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			return fmt.Sprintf("Error(%q)", reason)
+		}
+	case bytes.Equal(selector, panicSelector) && len(data) == 36:
+		code := new(big.Int).SetBytes(data[4:])
+		if reason, ok := panicReasons[code.Uint64()]; ok {
+			return fmt.Sprintf("Panic(0x%x): %s", code, reason)
+		}
+		return fmt.Sprintf("Panic(0x%x)", code)
+	}
+	for _, contractABI := range contractABIs {
+		parsed, err := abi.JSON(strings.NewReader(contractABI))
+		if err != nil {
+			continue
+		}
+		for _, abiError := range parsed.Errors {
+			if !bytes.Equal(abiError.ID[:4], selector) {
+				continue
+			}
+			args, err := abiError.Unpack(data)
+			if err != nil {
+				return abiError.Name
+			}
+			return fmt.Sprintf("%s%v", abiError.Name, args)
+		}
+	}
+	return hexutil.Encode(data)
+}
+
+// getMethodName returns the name of the function called with the input, if its ABI is in the
+// registry, or else the selector
+func getMethodName(input []byte) string {
+	if len(input) < 4 {
+		return ""
+	}
+	for _, contractABI := range contractABIs {
+		parsed, err := abi.JSON(strings.NewReader(contractABI))
+		if err != nil {
+			continue
+		}
+		if method, err := parsed.MethodById(input[:4]); err == nil {
+			return method.Name
+		}
+	}
+	return hexutil.Encode(input[:4])
+}
+
+// PrintRevertTrace prints the revert reason and the call trace as a tree
+func PrintRevertTrace(w io.Writer, trace *RevertTrace) {
+	_, _ = fmt.Fprintf(w, "Revert reason: %s (re-simulated with %s)\n", trace.Reason, trace.Method)
+	if trace.Trace != nil {
+		_, _ = fmt.Fprintln(w, "Call trace:")
+		printCallFrame(w, trace.Trace, 1)
+	}
+}
+
+func printCallFrame(w io.Writer, frame *CallFrame, depth int) {
+	line := fmt.Sprintf("%s%s %s -> %s", strings.Repeat("  ", depth), frame.Type, frame.From, frame.To)
+	if name := getMethodName(frame.Input); name != "" {
+		line += " " + name
+	}
+	if frame.Error != "" {
+		line += fmt.Sprintf(" [%s", frame.Error)
+		if len(frame.Output) > 0 {
+			line += ": " + DecodeRevert(frame.Output)
+		}
+		line += "]"
+	}
+	_, _ = fmt.Fprintln(w, line)
+	for i := range frame.Calls {
+		printCallFrame(w, &frame.Calls[i], depth+1)
+	}
+}
+
+// traceCallResult is the result of trace_call, a flat list of calls with their position in the
+// call tree
+type traceCallResult struct {
+	Trace []struct {
+		Action struct {
+			CallType string        `json:"callType"`
+			From     string        `json:"from"`
+			To       string        `json:"to"`
+			Input    hexutil.Bytes `json:"input"`
+		} `json:"action"`
+		Result *struct {
+			Output hexutil.Bytes `json:"output"`
+		} `json:"result"`
+		Error        string `json:"error"`
+		TraceAddress []int  `json:"traceAddress"`
+	} `json:"trace"`
+}
+
+// toCallFrame builds the call tree. Calls are listed depth first, so the parent of every call is
+// already in the tree.
+func (r *traceCallResult) toCallFrame() *CallFrame {
+	var root *CallFrame
+	for _, trace := range r.Trace {
+		frame := CallFrame{
+			Type:  strings.ToUpper(trace.Action.CallType),
+			From:  trace.Action.From,
+			To:    trace.Action.To,
+			Input: trace.Action.Input,
+			Error: trace.Error,
+		}
+		if trace.Result != nil {
+			frame.Output = trace.Result.Output
+		}
+		if len(trace.TraceAddress) == 0 {
+			root = &frame
+			continue
+		}
+		if root == nil {
+			continue
+		}
+		parent := root
+		for _, index := range trace.TraceAddress[:len(trace.TraceAddress)-1] {
+			if index >= len(parent.Calls) {
+				break
+			}
+			parent = &parent.Calls[index]
+		}
+		parent.Calls = append(parent.Calls, frame)
+	}
+	return root
+}
+
+// revertTraceTxManager re-simulates every transaction which reverts and prints why it reverted.
+// A transaction which reverted on-chain fails with ErrTxReverted and the reason, so callers can't
+// take its receipt for a success.
+type revertTraceTxManager struct {
+	txmgr.TxManager
+	rpc    rpcCaller
+	caller contractCaller
+	from   gethcommon.Address
+	logger eigensdkLogger.Logger
+}
+
+func withRevertTrace(
+	txMgr txmgr.TxManager,
+	rpc rpcCaller,
+	caller contractCaller,
+	from gethcommon.Address,
+	logger eigensdkLogger.Logger,
+) txmgr.TxManager {
+	return &revertTraceTxManager{TxManager: txMgr, rpc: rpc, caller: caller, from: from, logger: logger}
+}
+
+func (m *revertTraceTxManager) Send(
+	ctx context.Context,
+	tx *gethtypes.Transaction,
+	waitForReceipt bool,
+) (*gethtypes.Receipt, error) {
+	receipt, err := m.TxManager.Send(ctx, tx, waitForReceipt)
+	switch {
+	case err != nil && strings.Contains(err.Error(), "execution reverted"):
+		// Reverted when the tx manager estimated its gas, before it was sent. The writers of the SDK
+		// estimate the gas of their calls before, so this only catches a state change in between.
+		if trace := m.trace(ctx, tx, nil); trace != nil {
+			return receipt, fmt.Errorf("%w: %s", err, trace.Reason)
+		}
+	case err == nil && receipt != nil && receipt.Status == gethtypes.ReceiptStatusFailed:
+		// The state before the block of the transaction, so transactions before it in the same
+		// block are not applied
+		var parent *big.Int
+		if receipt.BlockNumber != nil && receipt.BlockNumber.Sign() > 0 {
+			parent = new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+		}
+		if trace := m.trace(ctx, tx, parent); trace != nil {
+			return receipt, fmt.Errorf("%w: %s: %s", ErrTxReverted, receipt.TxHash.Hex(), trace.Reason)
+		}
+		return receipt, fmt.Errorf("%w: %s", ErrTxReverted, receipt.TxHash.Hex())
+	}
+	return receipt, err
+}
+
+func (m *revertTraceTxManager) trace(
+	ctx context.Context,
+	tx *gethtypes.Transaction,
+	blockNumber *big.Int,
+) *RevertTrace {
+	if tx.To() == nil {
+		return nil
+	}
+	trace, err := TraceRevert(ctx, m.rpc, m.caller, m.from, tx, blockNumber)
+	if err != nil {
+		m.logger.Debugf("Failed to re-simulate reverted transaction %s: %s", tx.Hash().Hex(), err)
+		return nil
+	}
+	m.logger.Errorf("Transaction %s reverted: %s", tx.Hash().Hex(), trace.Reason)
+	// The trace goes to stderr, so it never mixes with the output of the command, which may be parsed
+	PrintRevertTrace(os.Stderr, trace)
+	return trace
+}
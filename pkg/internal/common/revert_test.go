@@ -0,0 +1,154 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTraceRPC answers the tracing methods with canned JSON, and fails the others
+type fakeTraceRPC map[string]string
+
+func (f fakeTraceRPC) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	response, ok := f[method]
+	if !ok {
+		return errors.New("the method " + method + " does not exist/is not available")
+	}
+	return json.Unmarshal([]byte(response), result)
+}
+
+type revertDataError struct {
+	data string
+}
+
+func (e *revertDataError) Error() string {
+	return "execution reverted"
+}
+
+func (e *revertDataError) ErrorData() interface{} {
+	return e.data
+}
+
+type fakeRevertCaller struct {
+	err error
+}
+
+func (f *fakeRevertCaller) CallContract(
+	ctx context.Context,
+	msg ethereum.CallMsg,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	return nil, f.err
+}
+
+func encodeErrorString(t *testing.T, reason string) []byte {
+	stringType, err := abi.NewType("string", "", nil)
+	assert.NoError(t, err)
+	packed, err := abi.Arguments{{Type: stringType}}.Pack(reason)
+	assert.NoError(t, err)
+	return append(append([]byte{}, errorSelector...), packed...)
+}
+
+func TestDecodeRevert(t *testing.T) {
+	assert.Equal(t, `Error("not the claimer")`, DecodeRevert(encodeErrorString(t, "not the claimer")))
+
+	panicData := append(append([]byte{}, panicSelector...), gethcommon.LeftPadBytes([]byte{0x11}, 32)...)
+	assert.Equal(t, "Panic(0x11): arithmetic overflow or underflow", DecodeRevert(panicData))
+
+	assert.Equal(t, "reverted without a reason", DecodeRevert(nil))
+	assert.Equal(t, "0xdeadbeef", DecodeRevert([]byte{0xde, 0xad, 0xbe, 0xef}))
+}
+
+func TestTraceRevert(t *testing.T) {
+	to := gethcommon.HexToAddress("0x2")
+	tx := gethtypes.NewTx(&gethtypes.DynamicFeeTx{To: &to, Gas: 100_000, Data: []byte{0x01, 0x02, 0x03, 0x04}})
+	from := gethcommon.HexToAddress("0x1")
+	revertData := hexutil.Encode(encodeErrorString(t, "not the claimer"))
+
+	rpc := fakeTraceRPC{"debug_traceCall": `{
+		"type": "CALL", "from": "0x1", "to": "0x2", "input": "0x01020304", "error": "execution reverted",
+		"calls": [{"type": "DELEGATECALL", "from": "0x2", "to": "0x3", "input": "0x01020304",
+			"error": "execution reverted", "output": "` + revertData + `"}]
+	}`}
+	trace, err := TraceRevert(context.Background(), rpc, &fakeRevertCaller{}, from, tx, big.NewInt(10))
+	assert.NoError(t, err)
+	assert.Equal(t, "debug_traceCall", trace.Method)
+	assert.Equal(t, `Error("not the claimer")`, trace.Reason)
+	assert.Len(t, trace.Trace.Calls, 1)
+
+	rpc = fakeTraceRPC{"trace_call": `{"trace": [
+		{"action": {"callType": "call", "from": "0x1", "to": "0x2", "input": "0x01020304"},
+			"error": "Reverted", "traceAddress": []},
+		{"action": {"callType": "delegatecall", "from": "0x2", "to": "0x3", "input": "0x01020304"},
+			"result": {"output": "` + revertData + `"}, "error": "Reverted", "traceAddress": [0]}
+	]}`}
+	trace, err = TraceRevert(context.Background(), rpc, &fakeRevertCaller{}, from, tx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "trace_call", trace.Method)
+	assert.Equal(t, `Error("not the claimer")`, trace.Reason)
+	assert.Equal(t, "DELEGATECALL", trace.Trace.Calls[0].Type)
+
+	var out bytes.Buffer
+	PrintRevertTrace(&out, trace)
+	assert.Contains(t, out.String(), `DELEGATECALL 0x2 -> 0x3 0x01020304 [Reverted: Error("not the claimer")]`)
+
+	caller := &fakeRevertCaller{err: &revertDataError{data: revertData}}
+	trace, err = TraceRevert(context.Background(), fakeTraceRPC{}, caller, from, tx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "eth_call", trace.Method)
+	assert.Equal(t, `Error("not the claimer")`, trace.Reason)
+	assert.Nil(t, trace.Trace)
+
+	_, err = TraceRevert(context.Background(), fakeTraceRPC{}, &fakeRevertCaller{}, from, tx, nil)
+	assert.Error(t, err)
+}
+
+// revertedTxManager mines every transaction as reverted
+type revertedTxManager struct {
+	fakeTxManager
+}
+
+func (m *revertedTxManager) Send(
+	ctx context.Context,
+	tx *gethtypes.Transaction,
+	waitForReceipt bool,
+) (*gethtypes.Receipt, error) {
+	return &gethtypes.Receipt{TxHash: tx.Hash(), Status: gethtypes.ReceiptStatusFailed, BlockNumber: big.NewInt(10)}, nil
+}
+
+func TestRevertTraceTxManager(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	to := gethcommon.HexToAddress("0x2")
+	tx := gethtypes.NewTx(&gethtypes.DynamicFeeTx{To: &to, Gas: 100_000, Data: []byte{0x01, 0x02, 0x03, 0x04}})
+	from := gethcommon.HexToAddress("0x1")
+	revertData := hexutil.Encode(encodeErrorString(t, "not the claimer"))
+	caller := &fakeRevertCaller{err: &revertDataError{data: revertData}}
+
+	txMgr := withRevertTrace(&revertedTxManager{}, fakeTraceRPC{}, caller, from, logger)
+	receipt, err := txMgr.Send(context.Background(), tx, true)
+	assert.ErrorIs(t, err, ErrTxReverted)
+	assert.ErrorContains(t, err, `Error("not the claimer")`)
+	assert.Equal(t, tx.Hash(), receipt.TxHash)
+
+	// Without a reason, the reverted transaction still fails
+	txMgr = withRevertTrace(&revertedTxManager{}, fakeTraceRPC{}, &fakeRevertCaller{}, from, logger)
+	_, err = txMgr.Send(context.Background(), tx, true)
+	assert.ErrorIs(t, err, ErrTxReverted)
+
+	txMgr = withRevertTrace(&fakeTxManager{}, fakeTraceRPC{}, caller, from, logger)
+	_, err = txMgr.Send(context.Background(), tx, true)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,95 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	eigensdkLogger "github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	rpcLoggerMu sync.RWMutex
+	rpcLogger   eigensdkLogger.Logger
+)
+
+// setRPCLogger sets the logger of the RPC requests of the run, which logs under LogModuleRPC
+func setRPCLogger(logger eigensdkLogger.Logger) {
+	rpcLoggerMu.Lock()
+	defer rpcLoggerMu.Unlock()
+	rpcLogger = WithLogModule(logger, LogModuleRPC)
+}
+
+func getRPCLogger() eigensdkLogger.Logger {
+	rpcLoggerMu.RLock()
+	defer rpcLoggerMu.RUnlock()
+	return rpcLogger
+}
+
+// DialEthClient connects to the RPC at url like ethclient.Dial. Over HTTP every request is logged
+// at debug level under the rpc module, so its level can be set with --log-filter rpc=...
+func DialEthClient(url string) (*ethclient.Client, error) {
+	httpClient := &http.Client{Transport: &loggingTransport{base: http.DefaultTransport}}
+	client, err := rpc.DialOptions(context.Background(), url, rpc.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
+
+// loggingTransport logs the JSON-RPC methods of every request and how long it took. The URL isn't
+// logged, since it often holds the API key of the provider.
+type loggingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	logger := getRPCLogger()
+	if logger == nil || req.Body == nil {
+		return t.base.RoundTrip(req)
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	method := getRPCMethods(body)
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		logger.Warn("RPC request failed", "method", method, "duration", time.Since(start), "error", err)
+		return nil, err
+	}
+	logger.Debug("RPC request", "method", method, "status", resp.StatusCode, "duration", time.Since(start))
+	return resp, nil
+}
+
+// getRPCMethods returns the method of a JSON-RPC request, or the methods of a batch
+func getRPCMethods(body []byte) string {
+	type request struct {
+		Method string `json:"method"`
+	}
+	var batch []request
+	if err := json.Unmarshal(body, &batch); err == nil {
+		methods := make([]string, len(batch))
+		for i, r := range batch {
+			methods[i] = r.Method
+		}
+		return strings.Join(methods, ",")
+	}
+	var single request
+	if err := json.Unmarshal(body, &single); err != nil {
+		return "unknown"
+	}
+	return single.Method
+}
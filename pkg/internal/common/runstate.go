@@ -0,0 +1,21 @@
+package common
+
+// ResetRunState turns off everything the global flags and the profile of a previous run turned
+// on. The CLI runs a single command per process, but a process embedding it, e.g. the scheduler,
+// runs many and must not carry the read-only mode, the hooks or the allowed networks of one run
+// over to the next.
+func ResetRunState() {
+	readOnly.Store(false)
+	skipCompatibilityCheck.Store(false)
+	verifyContractCode.Store(false)
+	confirmations.Store(0)
+	rebroadcastOnReorg.Store(false)
+	redact.Store(false)
+	locale.Store(nil)
+	timezone.Store(nil)
+	SetAllowedChainIDs(nil, "")
+	SetHooks(Hooks{})
+	SetHookCommand("")
+	disableStats()
+	resetRunMeta()
+}
@@ -0,0 +1,225 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RunStats is what a run of a command cost, to help tuning concurrency, caching and the choice
+// of RPC. HTTP traffic is grouped by host only, since RPC URLs often hold API keys in the path.
+type RunStats struct {
+	Command    string      `json:"command"`
+	DurationMs int64       `json:"durationMs"`
+	Phases     []PhaseStat `json:"phases"`
+	RPCCalls   int         `json:"rpcCalls"`
+	Hosts      []HostStat  `json:"hosts"`
+}
+
+type PhaseStat struct {
+	Name       string `json:"name"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+type HostStat struct {
+	Host            string `json:"host"`
+	Requests        int    `json:"requests"`
+	BytesDownloaded int64  `json:"bytesDownloaded"`
+}
+
+type statsCollector struct {
+	mu       sync.Mutex
+	format   OutputType
+	start    time.Time
+	phases   []PhaseStat
+	rpcCalls int
+	hosts    map[string]*HostStat
+}
+
+var stats *statsCollector
+
+// EnableStats collects the stats of the run, which PrintRunStats prints in the given format
+// when the command completed. All HTTP traffic of the process is counted, which includes the
+// calls to the RPC, since the eth client uses the default transport.
+func EnableStats(format OutputType) error {
+	if format != OutputType_Pretty && format != OutputType_Json {
+		return fmt.Errorf("unsupported stats format %s, use 'pretty' or 'json'", format)
+	}
+	stats = &statsCollector{format: format, start: time.Now(), hosts: make(map[string]*HostStat)}
+	// The transport stays installed when the stats are reset, so it's only added once
+	if _, ok := http.DefaultTransport.(*statsTransport); !ok {
+		http.DefaultTransport = &statsTransport{next: http.DefaultTransport}
+	}
+	return nil
+}
+
+// disableStats stops collecting the stats of the run
+func disableStats() {
+	stats = nil
+}
+
+// StartPhase starts timing a phase of the command and returns the function ending it
+func StartPhase(name string) func() {
+	if stats == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		stats.mu.Lock()
+		defer stats.mu.Unlock()
+		stats.phases = append(stats.phases, PhaseStat{Name: name, DurationMs: time.Since(start).Milliseconds()})
+	}
+}
+
+// GetRunStats returns the stats collected so far, or nil if stats are not enabled
+func GetRunStats() *RunStats {
+	if stats == nil {
+		return nil
+	}
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+	runStats := &RunStats{
+		Command:    hookCommand,
+		DurationMs: time.Since(stats.start).Milliseconds(),
+		Phases:     append([]PhaseStat{}, stats.phases...),
+		RPCCalls:   stats.rpcCalls,
+		Hosts:      make([]HostStat, 0, len(stats.hosts)),
+	}
+	for _, host := range stats.hosts {
+		runStats.Hosts = append(runStats.Hosts, *host)
+	}
+	sort.Slice(runStats.Hosts, func(i, j int) bool {
+		return runStats.Hosts[i].Host < runStats.Hosts[j].Host
+	})
+	return runStats
+}
+
+// PrintRunStats prints the stats of the run, if enabled. They go to stderr, so they never mix
+// with the output of the command, which may be parsed.
+func PrintRunStats(w io.Writer) {
+	runStats := GetRunStats()
+	if runStats == nil {
+		return
+	}
+
+	if stats.format == OutputType_Json {
+		meta, err := withRunMeta(runStats)
+		if err != nil {
+			return
+		}
+		out, err := json.Marshal(map[string]interface{}{"meta": meta})
+		if err != nil {
+			return
+		}
+		_, _ = fmt.Fprintln(w, string(out))
+		return
+	}
+
+	_, _ = fmt.Fprintf(
+		w,
+		"\n%s took %s with %d RPC calls\n",
+		runStats.Command,
+		formatMs(runStats.DurationMs),
+		runStats.RPCCalls,
+	)
+	if len(runStats.Phases) > 0 {
+		phases := NewTable(TableColumn{Header: "Phase"}, TableColumn{Header: "Duration", Align: AlignRight})
+		for _, phase := range runStats.Phases {
+			phases.AddRow(phase.Name, formatMs(phase.DurationMs))
+		}
+		phases.Render(w)
+	}
+	if len(runStats.Hosts) > 0 {
+		hosts := NewTable(
+			TableColumn{Header: "Host"},
+			TableColumn{Header: "Requests", Align: AlignRight},
+			TableColumn{Header: "Downloaded", Align: AlignRight},
+		)
+		for _, host := range runStats.Hosts {
+			hosts.AddRow(host.Host, strconv.Itoa(host.Requests), formatBytes(host.BytesDownloaded))
+		}
+		hosts.Render(w)
+	}
+}
+
+func formatMs(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value := float64(bytes)
+	for _, suffix := range []string{"KiB", "MiB", "GiB"} {
+		value /= unit
+		if value < unit || suffix == "GiB" {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+	}
+	return ""
+}
+
+func (c *statsCollector) addRequest(host string, rpc bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if rpc {
+		c.rpcCalls++
+	}
+	c.host(host).Requests++
+}
+
+func (c *statsCollector) addBytes(host string, n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.host(host).BytesDownloaded += int64(n)
+}
+
+func (c *statsCollector) host(host string) *HostStat {
+	hostStat, ok := c.hosts[host]
+	if !ok {
+		hostStat = &HostStat{Host: host}
+		c.hosts[host] = hostStat
+	}
+	return hostStat
+}
+
+// statsTransport counts the requests and the downloaded bytes by host. JSON POST requests are
+// counted as RPC calls, a batch of calls counting once.
+type statsTransport struct {
+	next http.RoundTripper
+}
+
+func (t *statsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	collector := stats
+	if collector == nil {
+		return t.next.RoundTrip(req)
+	}
+	rpc := req.Method == http.MethodPost && strings.HasPrefix(req.Header.Get("Content-Type"), "application/json")
+	collector.addRequest(req.URL.Host, rpc)
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &countingBody{ReadCloser: resp.Body, collector: collector, host: req.URL.Host}
+	return resp, nil
+}
+
+type countingBody struct {
+	io.ReadCloser
+	collector *statsCollector
+	host      string
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.collector.addBytes(b.host, n)
+	return n, err
+}
@@ -0,0 +1,62 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunStats(t *testing.T) {
+	defer func(transport http.RoundTripper) {
+		http.DefaultTransport = transport
+		stats = nil
+	}(http.DefaultTransport)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("0123456789"))
+	}))
+	defer server.Close()
+
+	assert.Error(t, EnableStats(OutputType_Csv))
+	assert.Nil(t, GetRunStats())
+	assert.NoError(t, EnableStats(OutputType_Json))
+	SetHookCommand("rewards show")
+	defer SetHookCommand("")
+
+	endPhase := StartPhase("proof data download")
+	resp, err := http.Get(server.URL)
+	assert.NoError(t, err)
+	_, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	endPhase()
+
+	resp, err = http.Post(server.URL, "application/json", strings.NewReader(`{"method":"eth_chainId"}`))
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	runStats := GetRunStats()
+	assert.Equal(t, "rewards show", runStats.Command)
+	assert.Equal(t, 1, runStats.RPCCalls)
+	assert.Len(t, runStats.Phases, 1)
+	assert.Equal(t, "proof data download", runStats.Phases[0].Name)
+	assert.Len(t, runStats.Hosts, 1)
+	assert.Equal(t, 2, runStats.Hosts[0].Requests)
+	assert.Equal(t, int64(10), runStats.Hosts[0].BytesDownloaded)
+
+	var out bytes.Buffer
+	PrintRunStats(&out)
+	var printed map[string]RunStats
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &printed))
+	assert.Equal(t, 1, printed["meta"].RPCCalls)
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "2.0 MiB", formatBytes(2*1024*1024))
+}
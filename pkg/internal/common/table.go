@@ -0,0 +1,207 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignRight
+)
+
+// TableColumn describes a column of a Table. Columns are as wide as their widest value, up to
+// MaxWidth if it is set. Longer values are truncated, or wrapped over several lines with Wrap.
+type TableColumn struct {
+	Header   string
+	Align    Alignment
+	MaxWidth int
+	Wrap     bool
+}
+
+// Table renders rows of values as a box drawn table for terminals, or as a GitHub flavored
+// markdown table. It is the one place tables of the CLI are drawn, so every command prints
+// them the same way.
+type Table struct {
+	columns []TableColumn
+	rows    [][]string
+}
+
+func NewTable(columns ...TableColumn) *Table {
+	return &Table{columns: columns}
+}
+
+// NewTableWithHeaders returns a table of left aligned columns without a width limit
+func NewTableWithHeaders(headers ...string) *Table {
+	columns := make([]TableColumn, len(headers))
+	for i, header := range headers {
+		columns[i] = TableColumn{Header: header}
+	}
+	return NewTable(columns...)
+}
+
+// AddRow adds a row of values. Missing values are left empty and extra values are dropped.
+// Addresses in the values are masked with --redact.
+func (t *Table) AddRow(values ...string) {
+	row := make([]string, len(t.columns))
+	copy(row, values)
+	for i, value := range row {
+		row[i] = RedactText(value)
+	}
+	t.rows = append(t.rows, row)
+}
+
+// Print renders the table to stdout
+func (t *Table) Print() {
+	t.Render(os.Stdout)
+}
+
+// PrintMarkdown renders the table to stdout as markdown
+func (t *Table) PrintMarkdown() {
+	t.RenderMarkdown(os.Stdout)
+}
+
+// Markdown returns the table as a GitHub flavored markdown table
+func (t *Table) Markdown() string {
+	var b strings.Builder
+	t.RenderMarkdown(&b)
+	return b.String()
+}
+
+// Render writes the table with box drawn borders
+func (t *Table) Render(w io.Writer) {
+	widths := t.getWidths()
+	separator := getSeparator(widths)
+
+	header := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		header[i] = column.Header
+	}
+
+	fmt.Fprintln(w, separator)
+	t.renderRow(w, header, widths, true)
+	fmt.Fprintln(w, separator)
+	for _, row := range t.rows {
+		t.renderRow(w, row, widths, false)
+	}
+	fmt.Fprintln(w, separator)
+}
+
+// RenderMarkdown writes the table as a GitHub flavored markdown table. Values are neither
+// truncated nor wrapped, since the markdown viewer takes care of the layout.
+func (t *Table) RenderMarkdown(w io.Writer) {
+	header := make([]string, len(t.columns))
+	alignments := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		header[i] = escapeMarkdownCell(column.Header)
+		alignments[i] = "---"
+		if column.Align == AlignRight {
+			alignments[i] = "---:"
+		}
+	}
+	fmt.Fprintf(w, "| %s |\n", strings.Join(header, " | "))
+	fmt.Fprintf(w, "| %s |\n", strings.Join(alignments, " | "))
+	for _, row := range t.rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cells[i] = escapeMarkdownCell(value)
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+	}
+}
+
+func (t *Table) getWidths() []int {
+	widths := make([]int, len(t.columns))
+	for i, column := range t.columns {
+		widths[i] = utf8.RuneCountInString(column.Header)
+		for _, row := range t.rows {
+			if width := utf8.RuneCountInString(row[i]); width > widths[i] {
+				widths[i] = width
+			}
+		}
+		if column.MaxWidth > 0 && widths[i] > column.MaxWidth {
+			widths[i] = column.MaxWidth
+		}
+	}
+	return widths
+}
+
+func (t *Table) renderRow(w io.Writer, row []string, widths []int, isHeader bool) {
+	cells := make([][]string, len(row))
+	height := 1
+	for i, value := range row {
+		cells[i] = fitCell(value, widths[i], t.columns[i].Wrap)
+		if len(cells[i]) > height {
+			height = len(cells[i])
+		}
+	}
+
+	for line := 0; line < height; line++ {
+		var b strings.Builder
+		for i, cell := range cells {
+			value := ""
+			if line < len(cell) {
+				value = cell[line]
+			}
+			padding := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(value))
+			b.WriteString("| ")
+			// Headers are always left aligned, like the rest of the CLI output
+			if t.columns[i].Align == AlignRight && !isHeader {
+				b.WriteString(padding + value)
+			} else {
+				b.WriteString(value + padding)
+			}
+			b.WriteString(" ")
+		}
+		b.WriteString("|")
+		fmt.Fprintln(w, b.String())
+	}
+}
+
+// fitCell splits a value into the lines of a cell of the given width
+func fitCell(value string, width int, wrap bool) []string {
+	runes := []rune(value)
+	if len(runes) <= width {
+		return []string{value}
+	}
+	if !wrap {
+		if width <= 1 {
+			return []string{string(runes[:width])}
+		}
+		return []string{string(runes[:width-1]) + "…"}
+	}
+
+	lines := make([]string, 0)
+	for len(runes) > width {
+		// Break at the last space of the line if there is one, otherwise in the middle of the word
+		end := width
+		for i := width; i > 0; i-- {
+			if runes[i] == ' ' {
+				end = i
+				break
+			}
+		}
+		lines = append(lines, strings.TrimRight(string(runes[:end]), " "))
+		runes = []rune(strings.TrimLeft(string(runes[end:]), " "))
+	}
+	return append(lines, string(runes))
+}
+
+func getSeparator(widths []int) string {
+	var b strings.Builder
+	for _, width := range widths {
+		b.WriteString("+" + strings.Repeat("-", width+2))
+	}
+	b.WriteString("+")
+	return b.String()
+}
+
+func escapeMarkdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", "<br>")
+}
@@ -0,0 +1,58 @@
+package common
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableRender(t *testing.T) {
+	table := NewTable(
+		TableColumn{Header: "Token Name", MaxWidth: 10},
+		TableColumn{Header: "Amount", Align: AlignRight},
+	)
+	table.AddRow("Wrapped Ether Staked", "100")
+	table.AddRow("EIGEN", "25")
+
+	var out bytes.Buffer
+	table.Render(&out)
+	expected := "" +
+		"+------------+--------+\n" +
+		"| Token Name | Amount |\n" +
+		"+------------+--------+\n" +
+		"| Wrapped E… |    100 |\n" +
+		"| EIGEN      |     25 |\n" +
+		"+------------+--------+\n"
+	assert.Equal(t, expected, out.String())
+}
+
+func TestTableWrap(t *testing.T) {
+	table := NewTable(TableColumn{Header: "Error", MaxWidth: 12, Wrap: true}, TableColumn{Header: "Code"})
+	table.AddRow("execution reverted: not allowed", "3")
+
+	var out bytes.Buffer
+	table.Render(&out)
+	expected := "" +
+		"+--------------+------+\n" +
+		"| Error        | Code |\n" +
+		"+--------------+------+\n" +
+		"| execution    | 3    |\n" +
+		"| reverted:    |      |\n" +
+		"| not allowed  |      |\n" +
+		"+--------------+------+\n"
+	assert.Equal(t, expected, out.String())
+}
+
+func TestTableRenderMarkdown(t *testing.T) {
+	table := NewTable(TableColumn{Header: "Token"}, TableColumn{Header: "Amount", Align: AlignRight})
+	table.AddRow("A|B", "1")
+
+	var out bytes.Buffer
+	table.RenderMarkdown(&out)
+	expected := "" +
+		"| Token | Amount |\n" +
+		"| --- | ---: |\n" +
+		"| A\\|B | 1 |\n"
+	assert.Equal(t, expected, out.String())
+}
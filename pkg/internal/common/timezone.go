@@ -0,0 +1,56 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// TimezoneLocal and TimezoneUTC are the names of the time zones which are not in the tz database
+const (
+	TimezoneLocal = "local"
+	TimezoneUTC   = "utc"
+)
+
+var timezone atomic.Pointer[time.Location]
+
+// SetTimezone sets the time zone timestamps are displayed in. It's 'local', 'utc' or a name of the
+// tz database, e.g. 'Europe/Berlin'.
+func SetTimezone(name string) error {
+	var location *time.Location
+	switch strings.ToLower(name) {
+	case TimezoneLocal:
+		location = time.Local
+	case TimezoneUTC:
+		location = time.UTC
+	default:
+		var err error
+		location, err = time.LoadLocation(name)
+		if err != nil {
+			return fmt.Errorf("unknown time zone %s, use 'local', 'utc' or a name like 'Europe/Berlin': %w", name, err)
+		}
+	}
+	timezone.Store(location)
+	return nil
+}
+
+// GetTimezone returns the time zone timestamps are displayed in, the local one by default
+func GetTimezone() *time.Location {
+	if location := timezone.Load(); location != nil {
+		return location
+	}
+	return time.Local
+}
+
+// FormatTime formats a timestamp for human-readable output, in the time zone and locale of the
+// output and with the zone, e.g. '2024-03-05 15:07:09 CET'
+func FormatTime(t time.Time) string {
+	t = t.In(GetTimezone())
+	return LocalizeTime(t, "2006-01-02 15:04:05") + " " + t.Format("MST")
+}
+
+// FormatUnixTime formats a Unix timestamp of a contract, like FormatTime
+func FormatUnixTime(seconds int64) string {
+	return FormatTime(time.Unix(seconds, 0))
+}
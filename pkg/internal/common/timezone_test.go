@@ -0,0 +1,24 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatTime(t *testing.T) {
+	defer timezone.Store(nil)
+	ts := time.Date(2024, time.March, 5, 14, 7, 9, 0, time.UTC)
+
+	assert.Error(t, SetTimezone("Mars/Olympus_Mons"))
+	assert.NoError(t, SetTimezone("UTC"))
+	assert.Equal(t, "2024-03-05 14:07:09 UTC", FormatTime(ts))
+	assert.Equal(t, "2024-03-05 14:07:09 UTC", FormatUnixTime(ts.Unix()))
+
+	assert.NoError(t, SetTimezone("Europe/Berlin"))
+	assert.Equal(t, "2024-03-05 15:07:09 CET", FormatTime(ts))
+
+	assert.NoError(t, SetTimezone("local"))
+	assert.Equal(t, time.Local, GetTimezone())
+}
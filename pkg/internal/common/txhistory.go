@@ -0,0 +1,505 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	eigensdkLogger "github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// txHistorySubPath is the location of the tx history inside the home directory
+	txHistorySubPath = ".eigenlayer/tx-history.json"
+	// maxTxHistoryEntries bounds the history. The oldest entries are dropped first.
+	maxTxHistoryEntries = 1000
+	// rebroadcastTimeout is how long a re-broadcast transaction may take to be included again
+	rebroadcastTimeout = 5 * time.Minute
+)
+
+type TxStatus string
+
+const (
+	TxStatusPending   TxStatus = "pending"
+	TxStatusConfirmed TxStatus = "confirmed"
+	TxStatusReverted  TxStatus = "reverted"
+	// TxStatusReorged is a transaction whose confirmation disappeared after a reorg
+	TxStatusReorged TxStatus = "reorged"
+	// TxStatusRebroadcast is a reorged transaction which was sent again
+	TxStatusRebroadcast TxStatus = "rebroadcast"
+)
+
+var ErrTxReorged = errors.New("confirmation of the transaction disappeared after a chain reorg")
+
+// TxHistoryEntry is a transaction sent by the CLI. The signed transaction is kept, so it can be
+// re-broadcast if its confirmation disappears after a reorg.
+type TxHistoryEntry struct {
+	TxHash      string    `json:"txHash"`
+	ChainID     string    `json:"chainId"`
+	Command     string    `json:"command,omitempty"`
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	Nonce       uint64    `json:"nonce"`
+	RawTx       string    `json:"rawTx,omitempty"`
+	Status      TxStatus  `json:"status"`
+	BlockNumber uint64    `json:"blockNumber,omitempty"`
+	BlockHash   string    `json:"blockHash,omitempty"`
+	Reorgs      int       `json:"reorgs,omitempty"`
+	SentAt      time.Time `json:"sentAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+var (
+	confirmations      atomic.Uint64
+	rebroadcastOnReorg atomic.Bool
+	// receiptPollInterval is how often receipts are checked while waiting for confirmations
+	receiptPollInterval = 2 * time.Second
+)
+
+// SetConfirmations makes every transaction wait for the given number of blocks on top of the
+// block it was included in. The receipt is re-checked while waiting, so a reorg is detected
+// instead of reporting a success which later vanishes.
+func SetConfirmations(blocks uint64) {
+	confirmations.Store(blocks)
+}
+
+// SetRebroadcastOnReorg makes transactions whose confirmation disappeared be sent again
+func SetRebroadcastOnReorg() {
+	rebroadcastOnReorg.Store(true)
+}
+
+func GetTxHistoryPath() string {
+	homePath, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homePath, txHistorySubPath)
+}
+
+// ReadTxHistory returns the entries of the tx history at path, oldest first
+func ReadTxHistory(path string) ([]TxHistoryEntry, error) {
+	data, err := ReadFileLocked(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []TxHistoryEntry
+	if len(data) == 0 {
+		return entries, nil
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse tx history %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// UpdateTxHistory changes the tx history file under its lock, so parallel runs of the CLI don't
+// lose entries
+func UpdateTxHistory(path string, update func(entries []TxHistoryEntry) ([]TxHistoryEntry, error)) error {
+	return UpdateFileLocked(path, 0o600, func(data []byte) ([]byte, error) {
+		var entries []TxHistoryEntry
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &entries); err != nil {
+				return nil, fmt.Errorf("failed to parse tx history %s: %w", path, err)
+			}
+		}
+		entries, err := update(entries)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) > maxTxHistoryEntries {
+			entries = entries[len(entries)-maxTxHistoryEntries:]
+		}
+		return json.MarshalIndent(entries, "", "  ")
+	})
+}
+
+// putTxHistoryEntry adds the entry to the history, or replaces the entry of the same transaction
+func putTxHistoryEntry(path string, entry TxHistoryEntry) error {
+	return UpdateTxHistory(path, func(entries []TxHistoryEntry) ([]TxHistoryEntry, error) {
+		for i := range entries {
+			if entries[i].TxHash == entry.TxHash && entries[i].ChainID == entry.ChainID {
+				entries[i] = entry
+				return entries, nil
+			}
+		}
+		return append(entries, entry), nil
+	})
+}
+
+// receiptClient is the part of the eth client needed to follow the confirmation of a transaction
+type receiptClient interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error)
+	TransactionReceipt(ctx context.Context, txHash gethcommon.Hash) (*gethtypes.Receipt, error)
+	TransactionByHash(ctx context.Context, txHash gethcommon.Hash) (*gethtypes.Transaction, bool, error)
+	SendTransaction(ctx context.Context, tx *gethtypes.Transaction) error
+}
+
+// historyTxManager records every transaction sent in the tx history, and waits for the configured
+// number of confirmations, following the transaction through reorgs
+type historyTxManager struct {
+	txmgr.TxManager
+	client  receiptClient
+	path    string
+	from    gethcommon.Address
+	chainID *big.Int
+	logger  eigensdkLogger.Logger
+}
+
+func withHistory(
+	txMgr txmgr.TxManager,
+	client receiptClient,
+	from gethcommon.Address,
+	chainID *big.Int,
+	logger eigensdkLogger.Logger,
+) txmgr.TxManager {
+	return &historyTxManager{
+		TxManager: txMgr,
+		client:    client,
+		path:      GetTxHistoryPath(),
+		from:      from,
+		chainID:   chainID,
+		logger:    logger,
+	}
+}
+
+func (m *historyTxManager) Send(
+	ctx context.Context,
+	tx *gethtypes.Transaction,
+	waitForReceipt bool,
+) (*gethtypes.Receipt, error) {
+	receipt, err := m.TxManager.Send(ctx, tx, waitForReceipt)
+	if err != nil || receipt == nil {
+		return receipt, err
+	}
+
+	// The transaction passed in is not signed yet, so the signed one is taken from the node
+	signedTx, _, err := m.client.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		m.logger.Warnf("Failed to get transaction %s, it can't be re-broadcast: %s", receipt.TxHash.Hex(), err)
+		signedTx = nil
+	}
+	entry := m.newEntry(tx, signedTx, receipt)
+	m.record(entry)
+
+	if !waitForReceipt {
+		return receipt, nil
+	}
+	receipt, err = waitForConfirmations(ctx, m.client, signedTx, receipt, &entry, m.logger)
+	m.record(entry)
+	return receipt, err
+}
+
+func (m *historyTxManager) newEntry(
+	tx *gethtypes.Transaction,
+	signedTx *gethtypes.Transaction,
+	receipt *gethtypes.Receipt,
+) TxHistoryEntry {
+	now := time.Now().UTC()
+	entry := TxHistoryEntry{
+		TxHash:    receipt.TxHash.Hex(),
+		ChainID:   m.chainID.String(),
+		Command:   hookCommand,
+		From:      m.from.Hex(),
+		Nonce:     tx.Nonce(),
+		Status:    TxStatusPending,
+		SentAt:    now,
+		UpdatedAt: now,
+	}
+	if tx.To() != nil {
+		entry.To = tx.To().Hex()
+	}
+	if signedTx != nil {
+		entry.Nonce = signedTx.Nonce()
+		if raw, err := signedTx.MarshalBinary(); err == nil {
+			entry.RawTx = hexutil.Encode(raw)
+		}
+	}
+	setReceipt(&entry, receipt)
+	return entry
+}
+
+// record writes the entry to the history. Failing to do so doesn't fail the transaction, which is
+// already sent.
+func (m *historyTxManager) record(entry TxHistoryEntry) {
+	if m.path == "" {
+		return
+	}
+	if err := putTxHistoryEntry(m.path, entry); err != nil {
+		m.logger.Warnf("Failed to record transaction %s in the tx history: %s", entry.TxHash, err)
+	}
+}
+
+func setReceipt(entry *TxHistoryEntry, receipt *gethtypes.Receipt) {
+	entry.UpdatedAt = time.Now().UTC()
+	if receipt.BlockNumber == nil {
+		return
+	}
+	entry.BlockNumber = receipt.BlockNumber.Uint64()
+	entry.BlockHash = receipt.BlockHash.Hex()
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		entry.Status = TxStatusReverted
+	} else if entry.Status != TxStatusRebroadcast {
+		// A re-broadcast transaction keeps its status once included, so it stays visible in the history
+		entry.Status = TxStatusConfirmed
+	}
+}
+
+// waitForConfirmations waits until the block of the receipt has the configured number of blocks on
+// top of it. If the transaction moved to another block meanwhile, the new receipt is followed. If
+// it is not included anymore, it is re-broadcast when enabled, or ErrTxReorged is returned.
+func waitForConfirmations(
+	ctx context.Context,
+	client receiptClient,
+	signedTx *gethtypes.Transaction,
+	receipt *gethtypes.Receipt,
+	entry *TxHistoryEntry,
+	logger eigensdkLogger.Logger,
+) (*gethtypes.Receipt, error) {
+	depth := confirmations.Load()
+	if depth == 0 || receipt.BlockNumber == nil {
+		return receipt, nil
+	}
+	logger.Infof("Waiting for %d confirmations of transaction %s", depth, receipt.TxHash.Hex())
+
+	for {
+		current, err := getCanonicalReceipt(ctx, client, receipt.TxHash)
+		if err != nil {
+			return nil, err
+		}
+		if current == nil {
+			entry.Reorgs++
+			entry.Status = TxStatusReorged
+			entry.UpdatedAt = time.Now().UTC()
+			logger.Warnf(
+				"Transaction %s is not included in block %d anymore after a reorg",
+				receipt.TxHash.Hex(),
+				receipt.BlockNumber.Uint64(),
+			)
+			if !rebroadcastOnReorg.Load() || signedTx == nil {
+				return nil, fmt.Errorf("%w: %s", ErrTxReorged, receipt.TxHash.Hex())
+			}
+			current, err = rebroadcast(ctx, client, signedTx, logger)
+			if err != nil {
+				return nil, err
+			}
+			entry.Status = TxStatusRebroadcast
+		}
+		if current.BlockHash != receipt.BlockHash {
+			logger.Infof("Transaction %s is included in block %d now", receipt.TxHash.Hex(), current.BlockNumber)
+			receipt = current
+			setReceipt(entry, receipt)
+		}
+
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if head >= receipt.BlockNumber.Uint64()+depth {
+			return receipt, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(receiptPollInterval):
+		}
+	}
+}
+
+// getCanonicalReceipt returns the receipt of the transaction if its block is part of the canonical
+// chain, or nil if the transaction is not included anymore
+func getCanonicalReceipt(
+	ctx context.Context,
+	client receiptClient,
+	txHash gethcommon.Hash,
+) (*gethtypes.Receipt, error) {
+	receipt, err := client.TransactionReceipt(ctx, txHash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Nodes may still serve the receipt of a block which was reorged out for a while
+	header, err := client.HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if header.Hash() != receipt.BlockHash {
+		return nil, nil
+	}
+	return receipt, nil
+}
+
+// rebroadcast sends the signed transaction again and waits until it is included
+func rebroadcast(
+	ctx context.Context,
+	client receiptClient,
+	signedTx *gethtypes.Transaction,
+	logger eigensdkLogger.Logger,
+) (*gethtypes.Receipt, error) {
+	logger.Infof("Re-broadcasting transaction %s", signedTx.Hash().Hex())
+	// The transaction may still be in the mempool of the node, in which case sending fails
+	if err := client.SendTransaction(ctx, signedTx); err != nil {
+		logger.Warnf("Re-broadcasting transaction %s failed: %s", signedTx.Hash().Hex(), err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, rebroadcastTimeout)
+	defer cancel()
+	for {
+		receipt, err := getCanonicalReceipt(ctx, client, signedTx.Hash())
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf(
+				"%w: re-broadcast transaction %s was not included: %s",
+				ErrTxReorged,
+				signedTx.Hash().Hex(),
+				ctx.Err(),
+			)
+		case <-time.After(receiptPollInterval):
+		}
+	}
+}
+
+// CheckTxHistory re-checks the confirmations recorded in the history for the chain, and marks the
+// entries which are not included anymore as reorged. With rebroadcast, these are sent again, with
+// the same guards as any other transaction sent by the CLI. The RPC is not called with the lock of
+// the history held, so other runs of the CLI can record their transactions meanwhile.
+func CheckTxHistory(
+	ctx context.Context,
+	client receiptClient,
+	path string,
+	chainID *big.Int,
+	rebroadcastReorged bool,
+	logger eigensdkLogger.Logger,
+) ([]TxHistoryEntry, error) {
+	if rebroadcastReorged {
+		if err := checkReadOnly(); err != nil {
+			return nil, err
+		}
+		if err := CheckAllowedNetwork(ctx, client); err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := ReadTxHistory(path)
+	if err != nil {
+		return nil, err
+	}
+	checked := make([]TxHistoryEntry, 0)
+	// read holds the entries the check changed, by tx hash, as they were read. Every change of an
+	// entry sets its UpdatedAt.
+	read := make(map[string]TxHistoryEntry)
+	changed := make(map[string]TxHistoryEntry)
+	for _, entry := range entries {
+		if entry.ChainID != chainID.String() {
+			continue
+		}
+		before := entry
+		if err := checkTxHistoryEntry(ctx, client, &entry, chainID, rebroadcastReorged, logger); err != nil {
+			return nil, err
+		}
+		checked = append(checked, entry)
+		if !entry.UpdatedAt.Equal(before.UpdatedAt) {
+			read[entry.TxHash] = before
+			changed[entry.TxHash] = entry
+		}
+	}
+	if len(changed) == 0 {
+		return checked, nil
+	}
+
+	err = UpdateTxHistory(path, func(entries []TxHistoryEntry) ([]TxHistoryEntry, error) {
+		for i := range entries {
+			entry, ok := changed[entries[i].TxHash]
+			if !ok || entries[i].ChainID != chainID.String() {
+				continue
+			}
+			// Entries updated by another run since they were read are left as they are
+			if entries[i].UpdatedAt.Equal(read[entry.TxHash].UpdatedAt) {
+				entries[i] = entry
+			}
+		}
+		return entries, nil
+	})
+	return checked, err
+}
+
+func checkTxHistoryEntry(
+	ctx context.Context,
+	client receiptClient,
+	entry *TxHistoryEntry,
+	chainID *big.Int,
+	rebroadcastReorged bool,
+	logger eigensdkLogger.Logger,
+) error {
+	receipt, err := getCanonicalReceipt(ctx, client, gethcommon.HexToHash(entry.TxHash))
+	if err != nil {
+		return err
+	}
+	if receipt != nil {
+		if receipt.BlockHash.Hex() != entry.BlockHash {
+			setReceipt(entry, receipt)
+		}
+		return nil
+	}
+
+	// Pending entries were never confirmed, so there is nothing which vanished
+	if entry.Status == TxStatusPending {
+		return nil
+	}
+	if entry.Status != TxStatusReorged {
+		logger.Warnf("Transaction %s is not included in block %d anymore", entry.TxHash, entry.BlockNumber)
+		entry.Reorgs++
+		entry.Status = TxStatusReorged
+		entry.UpdatedAt = time.Now().UTC()
+	}
+	if !rebroadcastReorged || entry.RawTx == "" {
+		return nil
+	}
+
+	raw, err := hexutil.Decode(entry.RawTx)
+	if err != nil {
+		return fmt.Errorf("invalid raw transaction of %s: %w", entry.TxHash, err)
+	}
+	signedTx := new(gethtypes.Transaction)
+	if err := signedTx.UnmarshalBinary(raw); err != nil {
+		return fmt.Errorf("invalid raw transaction of %s: %w", entry.TxHash, err)
+	}
+	err = runPreBroadcastHook(ctx, GetHooks(), gethcommon.HexToAddress(entry.From), chainID, signedTx, logger)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Re-broadcasting transaction %s", entry.TxHash)
+	if err := client.SendTransaction(ctx, signedTx); err != nil {
+		// The nonce may have been used by another transaction meanwhile, which is for the user to resolve
+		logger.Warnf("Re-broadcasting transaction %s failed: %s", entry.TxHash, err)
+		return nil
+	}
+	entry.Status = TxStatusRebroadcast
+	entry.UpdatedAt = time.Now().UTC()
+	return nil
+}
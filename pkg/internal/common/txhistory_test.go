@@ -0,0 +1,216 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeReceiptClient is a chain of headers by number with the receipts included in them
+type fakeReceiptClient struct {
+	head     uint64
+	headers  map[uint64]*gethtypes.Header
+	receipts map[gethcommon.Hash]*gethtypes.Receipt
+	// onSend is called when a transaction is sent, to include it in the chain
+	onSend func(tx *gethtypes.Transaction)
+	sent   int
+}
+
+func newFakeReceiptClient(head uint64) *fakeReceiptClient {
+	return &fakeReceiptClient{
+		head:     head,
+		headers:  make(map[uint64]*gethtypes.Header),
+		receipts: make(map[gethcommon.Hash]*gethtypes.Receipt),
+	}
+}
+
+// include adds a block at number with the transaction in it, replacing the block there before
+func (c *fakeReceiptClient) include(number uint64, fork int64, txHash gethcommon.Hash) *gethtypes.Receipt {
+	header := &gethtypes.Header{Number: new(big.Int).SetUint64(number), Difficulty: big.NewInt(fork)}
+	c.headers[number] = header
+	receipt := &gethtypes.Receipt{
+		TxHash:      txHash,
+		BlockNumber: header.Number,
+		BlockHash:   header.Hash(),
+		Status:      gethtypes.ReceiptStatusSuccessful,
+	}
+	c.receipts[txHash] = receipt
+	return receipt
+}
+
+// reorg replaces the block at number with an empty one of another fork
+func (c *fakeReceiptClient) reorg(number uint64, txHash gethcommon.Hash) {
+	c.headers[number] = &gethtypes.Header{Number: new(big.Int).SetUint64(number), Difficulty: big.NewInt(99)}
+	delete(c.receipts, txHash)
+}
+
+func (c *fakeReceiptClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(17000), nil
+}
+
+func (c *fakeReceiptClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.head, nil
+}
+
+func (c *fakeReceiptClient) HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error) {
+	header, ok := c.headers[number.Uint64()]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return header, nil
+}
+
+func (c *fakeReceiptClient) TransactionReceipt(
+	ctx context.Context,
+	txHash gethcommon.Hash,
+) (*gethtypes.Receipt, error) {
+	receipt, ok := c.receipts[txHash]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return receipt, nil
+}
+
+func (c *fakeReceiptClient) TransactionByHash(
+	ctx context.Context,
+	txHash gethcommon.Hash,
+) (*gethtypes.Transaction, bool, error) {
+	return nil, false, ethereum.NotFound
+}
+
+func (c *fakeReceiptClient) SendTransaction(ctx context.Context, tx *gethtypes.Transaction) error {
+	c.sent++
+	if c.onSend != nil {
+		c.onSend(tx)
+	}
+	return nil
+}
+
+func TestWaitForConfirmations(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	defer SetConfirmations(0)
+	defer rebroadcastOnReorg.Store(false)
+	defer func(interval time.Duration) { receiptPollInterval = interval }(receiptPollInterval)
+	receiptPollInterval = time.Millisecond
+	SetConfirmations(5)
+
+	to := gethcommon.HexToAddress("0x2")
+	tx := gethtypes.NewTx(&gethtypes.DynamicFeeTx{To: &to, Nonce: 3, Value: big.NewInt(0)})
+
+	t.Run("confirmed", func(t *testing.T) {
+		client := newFakeReceiptClient(20)
+		receipt := client.include(10, 1, tx.Hash())
+		entry := TxHistoryEntry{Status: TxStatusConfirmed}
+		got, err := waitForConfirmations(context.Background(), client, tx, receipt, &entry, logger)
+		assert.NoError(t, err)
+		assert.Equal(t, receipt, got)
+		assert.Equal(t, TxStatusConfirmed, entry.Status)
+	})
+
+	t.Run("reorged", func(t *testing.T) {
+		client := newFakeReceiptClient(20)
+		receipt := client.include(10, 1, tx.Hash())
+		client.reorg(10, tx.Hash())
+		entry := TxHistoryEntry{Status: TxStatusConfirmed}
+		_, err := waitForConfirmations(context.Background(), client, tx, receipt, &entry, logger)
+		assert.True(t, errors.Is(err, ErrTxReorged))
+		assert.Equal(t, TxStatusReorged, entry.Status)
+		assert.Equal(t, 1, entry.Reorgs)
+		assert.Equal(t, 0, client.sent)
+	})
+
+	t.Run("rebroadcast", func(t *testing.T) {
+		SetRebroadcastOnReorg()
+		client := newFakeReceiptClient(20)
+		receipt := client.include(10, 1, tx.Hash())
+		client.reorg(10, tx.Hash())
+		client.onSend = func(tx *gethtypes.Transaction) {
+			client.include(12, 2, tx.Hash())
+		}
+		entry := TxHistoryEntry{Status: TxStatusConfirmed}
+		got, err := waitForConfirmations(context.Background(), client, tx, receipt, &entry, logger)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(12), got.BlockNumber.Uint64())
+		assert.Equal(t, TxStatusRebroadcast, entry.Status)
+		assert.Equal(t, uint64(12), entry.BlockNumber)
+		assert.Equal(t, 1, client.sent)
+	})
+}
+
+func TestCheckTxHistory(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	path := filepath.Join(t.TempDir(), "tx-history.json")
+
+	kept := gethcommon.HexToHash("0x1")
+	reorged := gethcommon.HexToHash("0x2")
+	client := newFakeReceiptClient(20)
+	keptReceipt := client.include(10, 1, kept)
+	reorgedReceipt := client.include(11, 1, reorged)
+	client.reorg(11, reorged)
+
+	entries := []TxHistoryEntry{
+		{TxHash: kept.Hex(), ChainID: "17000", Status: TxStatusConfirmed},
+		{TxHash: reorged.Hex(), ChainID: "17000", Status: TxStatusConfirmed},
+		{TxHash: reorged.Hex(), ChainID: "1", Status: TxStatusConfirmed},
+	}
+	setReceipt(&entries[0], keptReceipt)
+	setReceipt(&entries[1], reorgedReceipt)
+	for _, entry := range entries {
+		assert.NoError(t, putTxHistoryEntry(path, entry))
+	}
+
+	checked, err := CheckTxHistory(context.Background(), client, path, big.NewInt(17000), false, logger)
+	assert.NoError(t, err)
+	assert.Len(t, checked, 2)
+	assert.Equal(t, TxStatusConfirmed, checked[0].Status)
+	assert.Equal(t, TxStatusReorged, checked[1].Status)
+
+	saved, err := ReadTxHistory(path)
+	assert.NoError(t, err)
+	assert.Len(t, saved, 3)
+	assert.Equal(t, TxStatusReorged, saved[1].Status)
+	assert.Equal(t, 1, saved[1].Reorgs)
+	assert.Equal(t, TxStatusConfirmed, saved[2].Status)
+
+	// Checking again doesn't count the same reorg twice
+	_, err = CheckTxHistory(context.Background(), client, path, big.NewInt(17000), false, logger)
+	assert.NoError(t, err)
+	saved, err = ReadTxHistory(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, saved[1].Reorgs)
+}
+
+func TestCheckTxHistoryRebroadcastGuards(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	path := filepath.Join(t.TempDir(), "tx-history.json")
+	client := newFakeReceiptClient(20)
+	assert.NoError(t, putTxHistoryEntry(path, TxHistoryEntry{TxHash: "0x1", ChainID: "17000", Status: TxStatusReorged}))
+
+	SetReadOnly()
+	_, err := CheckTxHistory(context.Background(), client, path, big.NewInt(17000), true, logger)
+	readOnly.Store(false)
+	assert.ErrorIs(t, err, ErrReadOnly)
+
+	SetAllowedChainIDs([]*big.Int{big.NewInt(1)}, "profile mainnet")
+	_, err = CheckTxHistory(context.Background(), client, path, big.NewInt(17000), true, logger)
+	SetAllowedChainIDs(nil, "")
+	assert.ErrorIs(t, err, ErrNetworkDenied)
+
+	// Without re-broadcasting, the history can be checked in read-only mode
+	SetReadOnly()
+	defer readOnly.Store(false)
+	_, err = CheckTxHistory(context.Background(), client, path, big.NewInt(17000), false, logger)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, client.sent)
+}
@@ -1,6 +1,8 @@
 package erc20
 
 import (
+	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -10,11 +12,15 @@ import (
 )
 
 const (
-	UnknownTokenName = "Unknown"
+	UnknownTokenName   = "Unknown"
+	UnknownTokenSymbol = "UNKNOWN"
+
+	// DefaultDecimals is used for tokens which don't implement decimals()
+	DefaultDecimals = 18
 )
 
 // ABI is a simplified ABI for the ERC20 token standard
-var ABI = `[{"constant":true,"inputs":[],"name":"name","outputs":[{"name":"","type":"string"}],"type":"function"}]`
+var ABI = `[{"constant":true,"inputs":[],"name":"name","outputs":[{"name":"","type":"string"}],"type":"function"},{"constant":true,"inputs":[],"name":"symbol","outputs":[{"name":"","type":"string"}],"type":"function"},{"constant":true,"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"type":"function"}]`
 
 // ERC20 is the Go binding of the ERC20 contract
 type ERC20 struct {
@@ -36,6 +42,26 @@ func (c *Caller) Name(opts *bind.CallOpts) (string, error) {
 	return out[0].(string), nil
 }
 
+// Symbol is a free data retrieval call binding the contract method 0x95d89b41.
+func (c *Caller) Symbol(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "symbol")
+	if err != nil {
+		return "", err
+	}
+	return out[0].(string), nil
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+func (c *Caller) Decimals(opts *bind.CallOpts) (uint8, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "decimals")
+	if err != nil {
+		return 0, err
+	}
+	return out[0].(uint8), nil
+}
+
 // NewERC20 creates a new instance of ERC20, bound to a specific deployed contract.
 func NewERC20(address common.Address, backend bind.ContractBackend) (*ERC20, error) {
 	contract, err := bindERC20(address, backend, backend, backend)
@@ -72,3 +98,47 @@ func GetTokenName(tokenAddress common.Address, client *ethclient.Client) string
 
 	return name
 }
+
+func GetTokenSymbol(tokenAddress common.Address, client *ethclient.Client) string {
+	erc20Client, err := NewERC20(tokenAddress, client)
+	if err != nil {
+		return UnknownTokenSymbol
+	}
+
+	symbol, err := erc20Client.Symbol(&bind.CallOpts{})
+	if err != nil {
+		return UnknownTokenSymbol
+	}
+
+	return symbol
+}
+
+func GetTokenDecimals(tokenAddress common.Address, client *ethclient.Client) (uint8, error) {
+	erc20Client, err := NewERC20(tokenAddress, client)
+	if err != nil {
+		return 0, err
+	}
+
+	return erc20Client.Decimals(&bind.CallOpts{})
+}
+
+// FormatUnits formats an amount in the token's smallest unit as a decimal string using the
+// token decimals, e.g. 1500000000000000000 with 18 decimals is formatted as "1.5"
+func FormatUnits(amount *big.Int, decimals uint8) string {
+	if decimals == 0 {
+		return amount.String()
+	}
+	abs := new(big.Int).Abs(amount)
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole, fraction := new(big.Int).QuoRem(abs, divisor, new(big.Int))
+
+	formatted := whole.String()
+	if fraction.Sign() != 0 {
+		fractionStr := fmt.Sprintf("%0*s", int(decimals), fraction.String())
+		formatted += "." + strings.TrimRight(fractionStr, "0")
+	}
+	if amount.Sign() < 0 {
+		formatted = "-" + formatted
+	}
+	return formatted
+}
@@ -0,0 +1,60 @@
+package erc20
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   *big.Int
+		decimals uint8
+		expected string
+	}{
+		{
+			name:     "whole amount",
+			amount:   big.NewInt(2_000_000_000_000_000_000),
+			decimals: 18,
+			expected: "2",
+		},
+		{
+			name:     "fractional amount",
+			amount:   big.NewInt(1_500_000_000_000_000_000),
+			decimals: 18,
+			expected: "1.5",
+		},
+		{
+			name:     "amount smaller than one unit",
+			amount:   big.NewInt(1234),
+			decimals: 6,
+			expected: "0.001234",
+		},
+		{
+			name:     "zero decimals",
+			amount:   big.NewInt(42),
+			decimals: 0,
+			expected: "42",
+		},
+		{
+			name:     "zero amount",
+			amount:   big.NewInt(0),
+			decimals: 18,
+			expected: "0",
+		},
+		{
+			name:     "negative amount",
+			amount:   big.NewInt(-1_250_000),
+			decimals: 6,
+			expected: "-1.25",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FormatUnits(tt.amount, tt.decimals))
+		})
+	}
+}
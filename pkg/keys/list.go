@@ -36,46 +36,25 @@ It will only list keys created in the default folder (./operator_keys/)
 				return err
 			}
 			keyStorePath := filepath.Clean(filepath.Join(homePath, OperatorKeystoreSubFolder))
-			files, err := os.ReadDir(keyStorePath)
+			keys, err := ListKeys(keyStorePath)
 			if err != nil {
 				return err
 			}
 
-			for _, file := range files {
-				keySplits := strings.Split(file.Name(), ".")
-				fileName := keySplits[0]
-				keyType := keySplits[1]
-				fmt.Println("Key Name: " + fileName)
-				switch keyType {
+			for _, key := range keys {
+				fmt.Println("Key Name: " + key.Name)
+				switch key.Type {
 				case KeyTypeECDSA:
 					fmt.Println("Key Type: ECDSA")
-					keyFilePath := filepath.Join(keyStorePath, file.Name())
-					address, err := GetAddress(filepath.Clean(keyFilePath))
-					if err != nil {
-						return err
-					}
-					fmt.Println("Address: 0x" + address)
-					fmt.Println("Key location: " + keyFilePath)
-					fmt.Println("====================================================================================")
-					fmt.Println()
+					fmt.Println("Address: " + key.Address)
 				case KeyTypeBLS:
 					fmt.Println("Key Type: BLS")
-					keyFilePath := filepath.Join(keyStorePath, file.Name())
-					pubKey, err := GetPubKey(filepath.Clean(keyFilePath))
-					if err != nil {
-						return err
-					}
-					fmt.Println("Public Key: " + pubKey)
-					operatorIdStr, err := GetOperatorIdFromBLSPubKey(pubKey)
-					if err != nil {
-						return err
-					}
-					fmt.Println("Operator Id: 0x" + operatorIdStr)
-					fmt.Println("Key location: " + keyFilePath)
-					fmt.Println("====================================================================================")
-					fmt.Println()
+					fmt.Println("Public Key: " + key.PublicKey)
+					fmt.Println("Operator Id: " + key.OperatorID)
 				}
-
+				fmt.Println("Key location: " + key.Path)
+				fmt.Println("====================================================================================")
+				fmt.Println()
 			}
 			return nil
 		},
@@ -83,6 +62,59 @@ It will only list keys created in the default folder (./operator_keys/)
 	return listCmd
 }
 
+// KeyInfo is a key of the keystore created by the create and import commands
+type KeyInfo struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Address    string `json:"address,omitempty"`
+	PublicKey  string `json:"publicKey,omitempty"`
+	OperatorID string `json:"operatorId,omitempty"`
+	Path       string `json:"path"`
+}
+
+// ListKeys returns the ECDSA and BLS keys in the keystore directory. Other files are skipped.
+func ListKeys(keyStorePath string) ([]KeyInfo, error) {
+	files, err := os.ReadDir(keyStorePath)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]KeyInfo, 0, len(files))
+	for _, file := range files {
+		// Keys are named like <name>.ecdsa.key.json
+		keySplits := strings.Split(file.Name(), ".")
+		if len(keySplits) < 2 {
+			continue
+		}
+		fileName, keyType := keySplits[0], keySplits[1]
+		keyFilePath := filepath.Join(keyStorePath, file.Name())
+		key := KeyInfo{Name: fileName, Type: keyType, Path: keyFilePath}
+		switch keyType {
+		case KeyTypeECDSA:
+			address, err := GetAddress(filepath.Clean(keyFilePath))
+			if err != nil {
+				return nil, err
+			}
+			key.Address = "0x" + address
+		case KeyTypeBLS:
+			pubKey, err := GetPubKey(filepath.Clean(keyFilePath))
+			if err != nil {
+				return nil, err
+			}
+			key.PublicKey = pubKey
+			operatorIdStr, err := GetOperatorIdFromBLSPubKey(pubKey)
+			if err != nil {
+				return nil, err
+			}
+			key.OperatorID = "0x" + operatorIdStr
+		default:
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
 func GetPubKey(keyStoreFile string) (string, error) {
 	keyJson, err := os.ReadFile(keyStoreFile)
 	if err != nil {
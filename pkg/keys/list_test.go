@@ -1,6 +1,8 @@
 package keys
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,3 +29,39 @@ func TestGetOperatorIdFromPubKey(t *testing.T) {
 		assert.Equal(t, operatorIds[i], id, "operator id from pubkey for %s should eq", key)
 	}
 }
+
+func TestListKeys(t *testing.T) {
+	keyStorePath := t.TempDir()
+	pubKey := "E([498211989701534593628498974128726712526336918939770789545660245177948853517," +
+		"19434346619705907282579203143605058653932187676054178921788041096426532277474])"
+	files := map[string]string{
+		"operator.ecdsa.key.json": `{"address":"1111111111111111111111111111111111111111"}`,
+		"operator.bls.key.json":   `{"pubKey":"` + pubKey + `"}`,
+		"notes.txt":               "not a key",
+		"README":                  "not a key",
+	}
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(keyStorePath, name), []byte(content), 0o600))
+	}
+
+	keys, err := ListKeys(keyStorePath)
+	assert.NoError(t, err)
+	assert.Equal(t, []KeyInfo{
+		{
+			Name:       "operator",
+			Type:       KeyTypeBLS,
+			PublicKey:  pubKey,
+			OperatorID: "0x5a76fe9014f9cd296a69ac589c2bbd2c6a354c5e4c0c79ee35c5b8202b8523a2",
+			Path:       filepath.Join(keyStorePath, "operator.bls.key.json"),
+		},
+		{
+			Name:    "operator",
+			Type:    KeyTypeECDSA,
+			Address: "0x1111111111111111111111111111111111111111",
+			Path:    filepath.Join(keyStorePath, "operator.ecdsa.key.json"),
+		},
+	}, keys)
+
+	_, err = ListKeys(filepath.Join(keyStorePath, "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -3,6 +3,7 @@ package operator
 import "errors"
 
 var (
-	ErrInvalidNumberOfArgs = errors.New("invalid number of arguments")
-	ErrInvalidYamlFile     = errors.New("invalid yaml file")
+	ErrInvalidNumberOfArgs  = errors.New("invalid number of arguments")
+	ErrInvalidYamlFile      = errors.New("invalid yaml file")
+	ErrRegistrationReverted = errors.New("registration transaction reverted")
 )
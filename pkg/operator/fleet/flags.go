@@ -0,0 +1,20 @@
+package fleet
+
+import "github.com/urfave/cli/v2"
+
+var (
+	OperatorsFileFlag = cli.StringFlag{
+		Name:     "operators-file",
+		Aliases:  []string{"of"},
+		Usage:    "CSV file of the operators, with an address column and optional name and avs columns",
+		Required: true,
+		EnvVars:  []string{"OPERATOR_FLEET_OPERATORS_FILE"},
+	}
+
+	ConcurrencyFlag = cli.IntFlag{
+		Name:    "concurrency",
+		Usage:   "Number of operators whose status is read at the same time",
+		Value:   DefaultConcurrency,
+		EnvVars: []string{"OPERATOR_FLEET_CONCURRENCY"},
+	}
+)
@@ -0,0 +1,288 @@
+package fleet
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operator/monitor"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/rewards"
+
+	eigensdkTypes "github.com/Layr-Labs/eigensdk-go/types"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultConcurrency is the number of operators whose status is read at the same time
+const DefaultConcurrency = 16
+
+// Columns of the operators file
+const (
+	addressColumn = "address"
+	nameColumn    = "name"
+	avsColumn     = "avs"
+)
+
+var columnAliases = map[string]string{
+	"operator":         addressColumn,
+	"operator_address": addressColumn,
+	"avs_address":      avsColumn,
+}
+
+var ErrOperatorsFailed = errors.New("failed to read the status of some operators")
+
+// Operator is an operator of the fleet, read from a row of the operators file
+type Operator struct {
+	Address gethcommon.Address
+	Name    string
+	// AVS is the AVS whose rewards split is read. The zero address skips the AVS split.
+	AVS gethcommon.Address
+}
+
+// Status is the status of an operator of the fleet. Values which could not be read are left
+// empty and the reason is added to Errors, so one failing call doesn't hide the rest.
+type Status struct {
+	Operator         string         `json:"operator"`
+	Name             string         `json:"name,omitempty"`
+	Registered       *bool          `json:"registered"`
+	Allocation       monitor.Status `json:"allocation"`
+	PISplit          *uint16        `json:"piSplit,omitempty"`
+	AVS              string         `json:"avs,omitempty"`
+	AVSSplit         *uint16        `json:"avsSplit,omitempty"`
+	RewardsDate      string         `json:"rewardsDate,omitempty"`
+	UnclaimedRewards []TokenRewards `json:"unclaimedRewards"`
+	Errors           []string       `json:"errors"`
+}
+
+type TokenRewards struct {
+	Token     string `json:"token"`
+	Unclaimed string `json:"unclaimed"`
+}
+
+type chainReader interface {
+	IsOperatorRegistered(ctx context.Context, operator eigensdkTypes.Operator) (bool, error)
+	GetOperatorPISplit(ctx context.Context, operator gethcommon.Address) (uint16, error)
+	GetOperatorAVSSplit(ctx context.Context, operator gethcommon.Address, avs gethcommon.Address) (uint16, error)
+}
+
+type allocationsReader interface {
+	GetAllocations(ctx context.Context, operator gethcommon.Address) (*monitor.Allocations, error)
+}
+
+type rewardsReader interface {
+	GetLatestSnapshot(ctx context.Context, earnerAddress gethcommon.Address) (*rewards.RewardsSnapshot, error)
+}
+
+// Options are how the status of the operators is collected
+type Options struct {
+	Concurrency int
+	// Splits are only read on networks whose rewards coordinator supports them
+	Splits bool
+}
+
+// Sources are where the status of the operators is read from. The rewards reader should share
+// the downloaded snapshot between operators, like rewards.SnapshotReader does.
+type Sources struct {
+	Chain       chainReader
+	Allocations allocationsReader
+	Rewards     rewardsReader
+}
+
+// ReadOperatorsFile reads the operators of the fleet from a CSV file. The header is optional and
+// names the address, name and avs columns. Without a header the columns are taken in this order.
+// Empty lines and lines starting with # are skipped.
+func ReadOperatorsFile(path string) ([]Operator, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readOperators(file)
+}
+
+func readOperators(r io.Reader) ([]Operator, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns := map[string]int{addressColumn: 0, nameColumn: 1, avsColumn: 2}
+	operators := make([]Operator, 0)
+	seen := make(map[gethcommon.Address]int)
+	first := true
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		if first {
+			first = false
+			if !gethcommon.IsHexAddress(strings.TrimSpace(record[0])) {
+				columns, err = parseHeader(record)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+
+		operator, err := parseOperator(record, columns)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if previous, ok := seen[operator.Address]; ok {
+			return nil, fmt.Errorf("line %d: operator %s is already listed on line %d", line, operator.Address, previous)
+		}
+		seen[operator.Address] = line
+		operators = append(operators, operator)
+	}
+	return operators, nil
+}
+
+func parseHeader(header []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if alias, ok := columnAliases[name]; ok {
+			name = alias
+		}
+		columns[name] = i
+	}
+	if _, ok := columns[addressColumn]; !ok {
+		return nil, fmt.Errorf("the header of the operators file has no %s column", addressColumn)
+	}
+	return columns, nil
+}
+
+func parseOperator(record []string, columns map[string]int) (Operator, error) {
+	get := func(column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	address := get(addressColumn)
+	if !gethcommon.IsHexAddress(address) {
+		return Operator{}, fmt.Errorf("invalid operator address %q", address)
+	}
+	operator := Operator{Address: gethcommon.HexToAddress(address), Name: get(nameColumn)}
+	if avs := get(avsColumn); avs != "" {
+		if !gethcommon.IsHexAddress(avs) {
+			return Operator{}, fmt.Errorf("invalid AVS address %q", avs)
+		}
+		operator.AVS = gethcommon.HexToAddress(avs)
+	}
+	return operator, nil
+}
+
+// Collect reads the status of the operators concurrently. The statuses are in the order of the
+// operators.
+func Collect(ctx context.Context, operators []Operator, opts Options, src Sources) []Status {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	statuses := make([]Status, len(operators))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, operator := range operators {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, operator Operator) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			statuses[i] = getStatus(ctx, operator, opts, src)
+		}(i, operator)
+	}
+	wg.Wait()
+	return statuses
+}
+
+func getStatus(ctx context.Context, operator Operator, opts Options, src Sources) Status {
+	status := Status{
+		Operator:         operator.Address.Hex(),
+		Name:             operator.Name,
+		UnclaimedRewards: make([]TokenRewards, 0),
+		Errors:           make([]string, 0),
+	}
+	fail := func(section string, err error) {
+		status.Errors = append(status.Errors, fmt.Sprintf("%s: %s", section, err))
+	}
+
+	registered, err := src.Chain.IsOperatorRegistered(ctx, eigensdkTypes.Operator{Address: operator.Address.Hex()})
+	if err != nil {
+		fail("registration", err)
+	} else {
+		status.Registered = &registered
+	}
+
+	// Skipped on networks whose AllocationManager doesn't support allocations yet
+	allocation := monitor.NewAllocationCheck(src.Allocations, operator.Address).Run(ctx)
+	status.Allocation = allocation.Status
+	if allocation.Status == monitor.StatusError {
+		fail("allocation", errors.New(allocation.Message))
+	}
+
+	if opts.Splits {
+		piSplit, err := src.Chain.GetOperatorPISplit(ctx, operator.Address)
+		if err != nil {
+			fail("pi split", err)
+		} else {
+			status.PISplit = &piSplit
+		}
+		if operator.AVS != (gethcommon.Address{}) {
+			status.AVS = operator.AVS.Hex()
+			avsSplit, err := src.Chain.GetOperatorAVSSplit(ctx, operator.Address, operator.AVS)
+			if err != nil {
+				fail("avs split", err)
+			} else {
+				status.AVSSplit = &avsSplit
+			}
+		}
+	}
+
+	snapshot, err := src.Rewards.GetLatestSnapshot(ctx, operator.Address)
+	switch {
+	case errors.Is(err, rewards.ErrEarnerNotFound):
+	case err != nil:
+		fail("rewards", err)
+	default:
+		status.RewardsDate = snapshot.Date
+		for token, unclaimed := range snapshot.Unclaimed {
+			status.UnclaimedRewards = append(status.UnclaimedRewards, TokenRewards{
+				Token:     token.Hex(),
+				Unclaimed: unclaimed.String(),
+			})
+		}
+		sort.Slice(status.UnclaimedRewards, func(i, j int) bool {
+			return status.UnclaimedRewards[i].Token < status.UnclaimedRewards[j].Token
+		})
+	}
+	return status
+}
+
+// Failed returns the number of operators whose status could not be read completely
+func Failed(statuses []Status) int {
+	failed := 0
+	for _, status := range statuses {
+		if len(status.Errors) > 0 {
+			failed++
+		}
+	}
+	return failed
+}
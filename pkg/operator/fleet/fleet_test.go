@@ -0,0 +1,181 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operator/monitor"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/rewards"
+
+	eigensdkTypes "github.com/Layr-Labs/eigensdk-go/types"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testOperator1 = gethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+	testOperator2 = gethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+	testAVS       = gethcommon.HexToAddress("0x3333333333333333333333333333333333333333")
+	testToken     = gethcommon.HexToAddress("0x4444444444444444444444444444444444444444")
+)
+
+func TestReadOperators(t *testing.T) {
+	operators, err := readOperators(strings.NewReader(`# fleet of the provider
+Name,Operator_Address,AVS
+operator-1,` + testOperator1.Hex() + `,` + testAVS.Hex() + `
+operator-2, ` + strings.ToLower(testOperator2.Hex()) + `,
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Operator{
+		{Address: testOperator1, Name: "operator-1", AVS: testAVS},
+		{Address: testOperator2, Name: "operator-2"},
+	}, operators)
+
+	// Without a header the columns are address, name and avs
+	operators, err = readOperators(strings.NewReader(testOperator1.Hex() + "\n" + testOperator2.Hex() + ",operator-2\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []Operator{{Address: testOperator1}, {Address: testOperator2, Name: "operator-2"}}, operators)
+
+	_, err = readOperators(strings.NewReader("name\noperator-1\n"))
+	assert.ErrorContains(t, err, "no address column")
+	_, err = readOperators(strings.NewReader("address\n" + testOperator1.Hex() + "\n0x1234\n"))
+	assert.ErrorContains(t, err, "line 3: invalid operator address")
+	_, err = readOperators(strings.NewReader(testOperator1.Hex() + "\n" + testOperator1.Hex() + "\n"))
+	assert.ErrorContains(t, err, "already listed on line 1")
+}
+
+type fakeChainReader struct {
+	mu       sync.Mutex
+	calls    int
+	inflight int
+	peak     int
+}
+
+func (r *fakeChainReader) IsOperatorRegistered(ctx context.Context, operator eigensdkTypes.Operator) (bool, error) {
+	r.mu.Lock()
+	r.calls++
+	r.inflight++
+	r.peak = max(r.peak, r.inflight)
+	r.mu.Unlock()
+	// Give the other workers the chance to run at the same time
+	time.Sleep(time.Millisecond)
+
+	r.mu.Lock()
+	r.inflight--
+	r.mu.Unlock()
+	return operator.Address == testOperator1.Hex(), nil
+}
+
+func (r *fakeChainReader) GetOperatorPISplit(ctx context.Context, operator gethcommon.Address) (uint16, error) {
+	return 1000, nil
+}
+
+func (r *fakeChainReader) GetOperatorAVSSplit(
+	ctx context.Context,
+	operator gethcommon.Address,
+	avs gethcommon.Address,
+) (uint16, error) {
+	return 0, errors.New("execution reverted")
+}
+
+type fakeAllocationsReader struct{}
+
+func (r fakeAllocationsReader) GetAllocations(
+	ctx context.Context,
+	operator gethcommon.Address,
+) (*monitor.Allocations, error) {
+	if operator != testOperator1 {
+		return nil, monitor.ErrAllocationsUnsupported
+	}
+	return &monitor.Allocations{}, nil
+}
+
+type fakeRewardsReader struct{}
+
+func (r fakeRewardsReader) GetLatestSnapshot(
+	ctx context.Context,
+	earnerAddress gethcommon.Address,
+) (*rewards.RewardsSnapshot, error) {
+	if earnerAddress != testOperator1 {
+		return nil, rewards.ErrEarnerNotFound
+	}
+	return &rewards.RewardsSnapshot{
+		Date:      "2024-08-01",
+		Unclaimed: map[gethcommon.Address]*big.Int{testToken: big.NewInt(250)},
+	}, nil
+}
+
+func TestCollect(t *testing.T) {
+	chain := &fakeChainReader{}
+	statuses := Collect(context.Background(), []Operator{
+		{Address: testOperator1, Name: "operator-1", AVS: testAVS},
+		{Address: testOperator2},
+	}, Options{Concurrency: 2, Splits: true}, Sources{
+		Chain:       chain,
+		Allocations: fakeAllocationsReader{},
+		Rewards:     fakeRewardsReader{},
+	})
+
+	assert.Len(t, statuses, 2)
+	first := statuses[0]
+	assert.Equal(t, testOperator1.Hex(), first.Operator)
+	assert.True(t, *first.Registered)
+	assert.Equal(t, monitor.StatusOK, first.Allocation)
+	assert.Equal(t, uint16(1000), *first.PISplit)
+	assert.Nil(t, first.AVSSplit)
+	assert.Equal(t, []TokenRewards{{Token: testToken.Hex(), Unclaimed: "250"}}, first.UnclaimedRewards)
+	assert.Equal(t, []string{"avs split: execution reverted"}, first.Errors)
+
+	second := statuses[1]
+	assert.False(t, *second.Registered)
+	assert.Equal(t, monitor.StatusSkipped, second.Allocation)
+	assert.Empty(t, second.AVS)
+	assert.Empty(t, second.UnclaimedRewards)
+	assert.Empty(t, second.Errors)
+	assert.Equal(t, 1, Failed(statuses))
+
+	rows := Rows(statuses)
+	assert.Equal(t, []Row{
+		{
+			Operator:    testOperator1.Hex(),
+			Name:        "operator-1",
+			Registered:  "true",
+			Allocation:  "ok",
+			PISplit:     "1000",
+			AVS:         testAVS.Hex(),
+			RewardsDate: "2024-08-01",
+			Token:       testToken.Hex(),
+			Unclaimed:   "250",
+			Errors:      "avs split: execution reverted",
+		},
+		{Operator: testOperator2.Hex(), Registered: "false", Allocation: "skipped", PISplit: "1000"},
+	}, rows)
+	assert.Equal(t, "2 operators, 1 registered, 1 with errors", Summary(statuses))
+}
+
+func TestCollectConcurrency(t *testing.T) {
+	operators := make([]Operator, 50)
+	for i := range operators {
+		operators[i] = Operator{Address: gethcommon.BigToAddress(big.NewInt(int64(i + 1)))}
+	}
+	chain := &fakeChainReader{}
+	statuses := Collect(context.Background(), operators, Options{Concurrency: 4}, Sources{
+		Chain:       chain,
+		Allocations: fakeAllocationsReader{},
+		Rewards:     fakeRewardsReader{},
+	})
+
+	assert.Equal(t, 50, chain.calls)
+	assert.LessOrEqual(t, chain.peak, 4)
+	for i, status := range statuses {
+		assert.Equal(t, operators[i].Address.Hex(), status.Operator)
+		// Splits are not read unless enabled
+		assert.Nil(t, status.PISplit)
+	}
+}
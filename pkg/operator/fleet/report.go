@@ -0,0 +1,116 @@
+package fleet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+)
+
+// maxErrorsWidth wraps the errors of an operator, so the table stays readable
+const maxErrorsWidth = 50
+
+// Row is a row of the CSV report. An operator has one row per token with unclaimed rewards, or a
+// single row without a token if it has none.
+type Row struct {
+	Operator    string `csv:"operator"`
+	Name        string `csv:"name"`
+	Registered  string `csv:"registered"`
+	Allocation  string `csv:"allocation"`
+	PISplit     string `csv:"pi_split"`
+	AVS         string `csv:"avs"`
+	AVSSplit    string `csv:"avs_split"`
+	RewardsDate string `csv:"rewards_date"`
+	Token       string `csv:"token"`
+	Unclaimed   string `csv:"unclaimed"`
+	Errors      string `csv:"errors"`
+}
+
+// Rows flattens the statuses into the rows of the CSV report
+func Rows(statuses []Status) []Row {
+	rows := make([]Row, 0, len(statuses))
+	for _, status := range statuses {
+		row := Row{
+			Operator:    status.Operator,
+			Name:        status.Name,
+			Allocation:  string(status.Allocation),
+			AVS:         status.AVS,
+			RewardsDate: status.RewardsDate,
+			Errors:      strings.Join(status.Errors, "; "),
+		}
+		if status.Registered != nil {
+			row.Registered = strconv.FormatBool(*status.Registered)
+		}
+		if status.PISplit != nil {
+			row.PISplit = strconv.Itoa(int(*status.PISplit))
+		}
+		if status.AVSSplit != nil {
+			row.AVSSplit = strconv.Itoa(int(*status.AVSSplit))
+		}
+
+		if len(status.UnclaimedRewards) == 0 {
+			rows = append(rows, row)
+			continue
+		}
+		for _, tokenRewards := range status.UnclaimedRewards {
+			row.Token = tokenRewards.Token
+			row.Unclaimed = tokenRewards.Unclaimed
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// NewTable returns the table of the report. The operator columns are only filled in the first row
+// of an operator.
+func NewTable(statuses []Status) *common.Table {
+	table := common.NewTable(
+		common.TableColumn{Header: "Operator"},
+		common.TableColumn{Header: "Name"},
+		common.TableColumn{Header: "Registered"},
+		common.TableColumn{Header: "Allocation"},
+		common.TableColumn{Header: "PI Split", Align: common.AlignRight},
+		common.TableColumn{Header: "AVS Split", Align: common.AlignRight},
+		common.TableColumn{Header: "Token"},
+		common.TableColumn{Header: "Unclaimed (wei)", Align: common.AlignRight},
+		common.TableColumn{Header: "Errors", MaxWidth: maxErrorsWidth, Wrap: true},
+	)
+	previous := ""
+	for _, row := range Rows(statuses) {
+		unclaimed := common.LocalizeNumber(row.Unclaimed)
+		if row.Operator == previous {
+			table.AddRow("", "", "", "", "", "", row.Token, unclaimed, "")
+			continue
+		}
+		previous = row.Operator
+		table.AddRow(
+			row.Operator,
+			row.Name,
+			row.Registered,
+			row.Allocation,
+			row.PISplit,
+			row.AVSSplit,
+			row.Token,
+			unclaimed,
+			row.Errors,
+		)
+	}
+	return table
+}
+
+// Summary is a one line summary of the report
+func Summary(statuses []Status) string {
+	registered := 0
+	for _, status := range statuses {
+		if status.Registered != nil && *status.Registered {
+			registered++
+		}
+	}
+	return fmt.Sprintf(
+		"%d operators, %d registered, %d with errors",
+		len(statuses),
+		registered,
+		Failed(statuses),
+	)
+}
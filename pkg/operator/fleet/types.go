@@ -0,0 +1,20 @@
+package fleet
+
+import (
+	"math/big"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+type Config struct {
+	Network                   string
+	RPCUrl                    string
+	ChainID                   *big.Int
+	DelegationManagerAddress  gethcommon.Address
+	RewardsCoordinatorAddress gethcommon.Address
+	AllocationManagerAddress  gethcommon.Address
+	Operators                 []Operator
+	Concurrency               int
+	OutputType                string
+	OutputFile                string
+}
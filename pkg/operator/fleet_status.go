@@ -0,0 +1,213 @@
+package operator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operator/fleet"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operator/monitor"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/rewards"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+func FleetStatusCmd(p utils.Prompter) *cli.Command {
+	fleetStatusCmd := &cli.Command{
+		Name:      "fleet-status",
+		Usage:     "Export the status of many operators into one CSV or JSON report",
+		UsageText: "fleet-status --operators-file <csv>",
+		Description: `
+Command to gather the status of a fleet of operators, e.g. of a node-as-a-service provider, in one
+consolidated report. The status of the operators is read concurrently, --concurrency at a time.
+
+The operators are read from a CSV file with an address column and optional name and avs columns.
+The header is optional, without it the columns are taken in this order:
+
+address,name,avs
+0x1111111111111111111111111111111111111111,operator-1,0x2222222222222222222222222222222222222222
+0x3333333333333333333333333333333333333333,operator-2,
+
+For every operator the report has
+- registered: whether the operator is registered on EigenLayer
+- allocation: the result of the allocation check of 'operator monitor'. Skipped on networks whose
+  AllocationManager doesn't support allocations yet
+- pi split and avs split: the rewards splits of the operator for programmatic incentives and for
+  the AVS of its row. Splits are only read on networks whose rewards coordinator supports them
+- unclaimed rewards: unclaimed rewards per token in the latest active distribution root. The
+  snapshot of the root is downloaded once for the whole fleet
+
+With --output-type csv an operator has one row per token with unclaimed rewards. Values which could
+not be read are left empty and their errors are reported in the errors column. The report is written
+even if some operators fail, and the command then exits with an error.
+		`,
+		After: telemetry.AfterRunAction(),
+		Flags: getFleetStatusFlags(),
+		Action: func(cCtx *cli.Context) error {
+			return FleetStatus(cCtx)
+		},
+	}
+
+	return fleetStatusCmd
+}
+
+func getFleetStatusFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputTypeFlag,
+		&flags.OutputFileFlag,
+		&flags.VerboseFlag,
+		&rewards.RewardsCoordinatorAddressFlag,
+		&fleet.OperatorsFileFlag,
+		&fleet.ConcurrencyFlag,
+	}
+
+	sort.Sort(cli.FlagsByName(baseFlags))
+	return baseFlags
+}
+
+func FleetStatus(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateFleetStatusConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate fleet status config", err)
+	}
+	cCtx.App.Metadata["network"] = config.ChainID.String()
+
+	ethClient, err := common.DialEthClient(config.RPCUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	elReader, err := elcontracts.NewReaderFromConfig(elcontracts.Config{
+		DelegationManagerAddress:  config.DelegationManagerAddress,
+		RewardsCoordinatorAddress: config.RewardsCoordinatorAddress,
+	}, ethClient, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create EL reader", err)
+	}
+	allocationReader, err := monitor.NewAllocationReader(ethClient, config.AllocationManagerAddress)
+	if err != nil {
+		return err
+	}
+	snapshotReader, err := rewards.NewSnapshotReader(ethClient, config.Network, config.RewardsCoordinatorAddress, logger)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof(
+		"Reading the status of %d operators, %d at a time",
+		len(config.Operators),
+		config.Concurrency,
+	)
+	statuses := fleet.Collect(ctx, config.Operators, fleet.Options{
+		Concurrency: config.Concurrency,
+		Splits:      common.SupportsFeature(cCtx, config.ChainID, common.FeatureRewardsSplits),
+	}, fleet.Sources{
+		Chain:       elReader,
+		Allocations: allocationReader,
+		Rewards:     snapshotReader,
+	})
+
+	err = handleFleetStatusOutput(config, statuses)
+	if err != nil {
+		return err
+	}
+	if failed := fleet.Failed(statuses); failed > 0 {
+		return fmt.Errorf("%w: %d of %d operators", fleet.ErrOperatorsFailed, failed, len(statuses))
+	}
+	return nil
+}
+
+func handleFleetStatusOutput(config *fleet.Config, statuses []fleet.Status) error {
+	switch config.OutputType {
+	case string(common.OutputType_Json):
+		out, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return err
+		}
+		if !common.IsEmptyString(config.OutputFile) {
+			return common.WriteToFile(out, config.OutputFile)
+		}
+		fmt.Println(string(out))
+	case string(common.OutputType_Csv):
+		if common.IsEmptyString(config.OutputFile) {
+			return errors.New("output file is required for csv output type")
+		}
+		return common.WriteToCSV(fleet.Rows(statuses), config.OutputFile)
+	default:
+		if !common.IsEmptyString(config.OutputFile) {
+			fmt.Println("output file not supported for pretty output type")
+			fmt.Println()
+		}
+		fleet.NewTable(statuses).Print()
+		fmt.Println(fleet.Summary(statuses))
+	}
+	return nil
+}
+
+func readAndValidateFleetStatusConfig(cCtx *cli.Context, logger logging.Logger) (*fleet.Config, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+	concurrency := cCtx.Int(fleet.ConcurrencyFlag.Name)
+
+	switch outputType {
+	case string(common.OutputType_Pretty), string(common.OutputType_Json), string(common.OutputType_Csv):
+	default:
+		return nil, fmt.Errorf("unsupported output type %s, use 'pretty', 'json' or 'csv'", outputType)
+	}
+	if concurrency <= 0 {
+		return nil, errors.New("concurrency must be positive")
+	}
+
+	operators, err := fleet.ReadOperatorsFile(cCtx.String(fleet.OperatorsFileFlag.Name))
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to read operators file", err)
+	}
+	if len(operators) == 0 {
+		return nil, errors.New("operators file has no operators")
+	}
+	logger.Debugf("Read %d operators", len(operators))
+
+	chainID := utils.NetworkNameToChainId(network)
+	logger.Debugf("Using chain ID: %s", chainID.String())
+
+	chainMetadata, ok := common.ChainMetadataMap[chainID.Int64()]
+	if !ok {
+		return nil, fmt.Errorf("network %s is not supported", network)
+	}
+
+	rewardsCoordinatorAddress := cCtx.String(rewards.RewardsCoordinatorAddressFlag.Name)
+	if common.IsEmptyString(rewardsCoordinatorAddress) {
+		rewardsCoordinatorAddress = chainMetadata.ELRewardsCoordinatorAddress
+	}
+	logger.Debugf("Using Rewards Coordinator address: %s", rewardsCoordinatorAddress)
+
+	return &fleet.Config{
+		Network:                   network,
+		RPCUrl:                    rpcUrl,
+		ChainID:                   chainID,
+		DelegationManagerAddress:  gethcommon.HexToAddress(chainMetadata.ELDelegationManagerAddress),
+		RewardsCoordinatorAddress: gethcommon.HexToAddress(rewardsCoordinatorAddress),
+		AllocationManagerAddress:  gethcommon.HexToAddress(chainMetadata.ELAllocationManagerAddress),
+		Operators:                 operators,
+		Concurrency:               concurrency,
+		OutputType:                outputType,
+		OutputFile:                cCtx.String(flags.OutputFileFlag.Name),
+	}, nil
+}
@@ -22,7 +22,6 @@ import (
 
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/urfave/cli/v2"
 )
@@ -69,7 +68,7 @@ Use the --expiry flag to override the default expiration of 3600 seconds.
 			)
 			logger.Info("%s validating operator config:  %s", utils.EmojiWait, operatorCfg.Operator.Address)
 
-			ethClient, err := ethclient.Dial(operatorCfg.EthRPCUrl)
+			ethClient, err := common.DialEthClient(operatorCfg.EthRPCUrl)
 			if err != nil {
 				return err
 			}
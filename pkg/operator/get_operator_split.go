@@ -14,7 +14,6 @@ import (
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
 	gethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/urfave/cli/v2"
 )
 
@@ -61,7 +60,7 @@ func GetOperatorSplit(cCtx *cli.Context, isProgrammaticIncentive bool) error {
 
 	cCtx.App.Metadata["network"] = config.ChainID.String()
 
-	ethClient, err := ethclient.Dial(config.RPCUrl)
+	ethClient, err := common.DialEthClient(config.RPCUrl)
 	if err != nil {
 		return eigenSdkUtils.WrapError("failed to create new eth client", err)
 	}
@@ -0,0 +1,301 @@
+package operator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operator/monitor"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/rewards"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+func MonitorCmd(p utils.Prompter) *cli.Command {
+	monitorCmd := &cli.Command{
+		Name:      "monitor",
+		Usage:     "Periodically check the health of an operator and expose the results as Prometheus metrics",
+		UsageText: "monitor",
+		Description: `
+Command to monitor an operator. It is meant to run as a sidecar next to the AVS nodes of the operator.
+
+Every interval the following checks are run:
+- registration: the operator is registered on EigenLayer
+- allocation: the operator set an allocation delay and allocates to every operator set it is registered
+  to. Reported as skipped on networks whose AllocationManager doesn't support allocations yet
+- metadata_uri: the metadata URI of the operator is reachable and holds valid operator metadata
+- unclaimed_rewards: unclaimed rewards of the operator in the latest active distribution root
+- claimer: watchdog on the claimers of the earners set with --earner-addresses (the operator by default).
+  Fails if a claimer is changed after the monitor started, or to an address not in --expected-claimers
+- accrual: compares the rewards of the same earners in consecutive distribution roots. Fails if a token
+  of --accrual-tokens (by default every token of the previous root) accrued nothing, which usually means
+  the operator fell out of an operator set or quorum. New roots are compared as soon as they are submitted
+
+When a check starts failing, an alert is logged and, with --alert-webhook-url, the result is posted to
+the webhook.
+
+The results are served as Prometheus metrics on /metrics and as JSON on /health, which responds
+with status 503 while any check does not pass.
+
+With --grafana the metrics and failed checks of the last --grafana-retention are kept in memory
+and served on /grafana in the format of the Grafana JSON datasource plugin, so dashboards can be
+built on the monitor without a Prometheus server. The history is lost when the monitor restarts:
+- every metric, e.g. check_up{check="registration"} or unclaimed_rewards{token="0x..."}, is a
+  time series
+- the checks target is a table of the results of the last round of checks
+- checks which start failing are annotations
+
+With --once the checks are run a single time and the command exits with
+- 0 if all checks passed
+- 2 if a check found a problem with the operator
+- 3 if a check could not run, e.g. because the RPC is not reachable
+		`,
+		After: telemetry.AfterRunAction(),
+		Flags: getMonitorFlags(),
+		Action: func(cCtx *cli.Context) error {
+			return Monitor(cCtx)
+		},
+	}
+
+	return monitorCmd
+}
+
+func getMonitorFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OperatorAddressFlag,
+		&flags.VerboseFlag,
+		&rewards.RewardsCoordinatorAddressFlag,
+		&monitor.IntervalFlag,
+		&monitor.MetricsAddressFlag,
+		&monitor.OnceFlag,
+		&monitor.MetadataURLFlag,
+		&monitor.EarnerAddressesFlag,
+		&monitor.ExpectedClaimersFlag,
+		&monitor.AccrualTokensFlag,
+		&monitor.AlertWebhookURLFlag,
+		&monitor.GrafanaFlag,
+		&monitor.GrafanaRetentionFlag,
+	}
+
+	sort.Sort(cli.FlagsByName(baseFlags))
+	return baseFlags
+}
+
+func Monitor(cCtx *cli.Context) error {
+	ctx, stop := signal.NotifyContext(cCtx.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateMonitorConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate monitor config", err)
+	}
+	cCtx.App.Metadata["network"] = config.ChainID.String()
+
+	ethClient, err := common.DialEthClient(config.RPCUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	m, err := newMonitor(config, ethClient, logger)
+	if err != nil {
+		return err
+	}
+
+	if config.Once {
+		results := m.RunOnce(ctx)
+		if code := monitor.ExitCode(results); code != monitor.ExitCodeOK {
+			return cli.Exit(fmt.Sprintf("%s operator monitor checks did not pass", utils.EmojiCrossMark), code)
+		}
+		logger.Infof("%s All operator monitor checks passed", utils.EmojiCheckMark)
+		return nil
+	}
+
+	serveErr := make(chan error, 1)
+	if !common.IsEmptyString(config.MetricsAddress) {
+		go func() {
+			serveErr <- monitor.Serve(ctx, config.MetricsAddress, m, logger)
+		}()
+	}
+
+	logger.Infof("Monitoring operator %s every %s", config.OperatorAddress.Hex(), config.Interval)
+	heartbeatPath := common.GetHeartbeatPath(
+		"operator monitor",
+		fmt.Sprintf("%s/%s", config.Network, config.OperatorAddress.Hex()),
+	)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		m.WithHeartbeat(heartbeatPath).Run(ctx, config.Interval)
+	}()
+
+	select {
+	case <-ctx.Done():
+		logger.Info("Stopping operator monitor")
+		// Wait for the heartbeat to be removed
+		<-stopped
+		return nil
+	case err := <-serveErr:
+		return eigenSdkUtils.WrapError("failed to serve metrics", err)
+	}
+}
+
+func newMonitor(config *monitor.Config, ethClient *ethclient.Client, logger logging.Logger) (*monitor.Monitor, error) {
+	elReader, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
+		DelegationManagerAddress:  config.DelegationManagerAddress,
+		RewardsCoordinatorAddress: config.RewardsCoordinatorAddress,
+	}, ethClient, nil, logger, nil)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to create EL clients", err)
+	}
+
+	snapshotReader, err := rewards.NewSnapshotReader(ethClient, config.Network, config.RewardsCoordinatorAddress, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	allocationReader, err := monitor.NewAllocationReader(ethClient, config.AllocationManagerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := monitor.NewMetrics()
+	checks := []monitor.Check{
+		monitor.NewRegistrationCheck(elReader, config.OperatorAddress),
+		monitor.NewAllocationCheck(allocationReader, config.OperatorAddress),
+		monitor.NewMetadataCheck(
+			contractBindings.DelegationManager,
+			ethClient,
+			config.OperatorAddress,
+			config.MetadataURL,
+		),
+		monitor.NewUnclaimedRewardsCheck(snapshotReader, config.OperatorAddress, metrics),
+		monitor.NewClaimerCheck(
+			contractBindings.RewardsCoordinator,
+			ethClient,
+			config.EarnerAddresses,
+			config.ExpectedClaimers,
+		),
+		monitor.NewAccrualCheck(snapshotReader, config.EarnerAddresses, config.AccrualTokens, metrics),
+	}
+	m := monitor.New(checks, metrics, logger)
+	if !common.IsEmptyString(config.AlertWebhookURL) {
+		m = m.WithAlerter(monitor.NewWebhookAlerter(config.AlertWebhookURL))
+	}
+	if config.Grafana {
+		m = m.WithGrafana(config.GrafanaRetention)
+	}
+	return m, nil
+}
+
+func readAndValidateMonitorConfig(cCtx *cli.Context, logger logging.Logger) (*monitor.Config, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	metadataURL := cCtx.String(monitor.MetadataURLFlag.Name)
+	interval := cCtx.Duration(monitor.IntervalFlag.Name)
+	metricsAddress := cCtx.String(monitor.MetricsAddressFlag.Name)
+	once := cCtx.Bool(monitor.OnceFlag.Name)
+
+	operatorAddressString := cCtx.String(flags.OperatorAddressFlag.Name)
+	if !gethcommon.IsHexAddress(operatorAddressString) {
+		return nil, errors.New("operator address must be a valid address")
+	}
+	operatorAddress := gethcommon.HexToAddress(operatorAddressString)
+	logger.Infof("Using operator address: %s", operatorAddress.String())
+
+	if interval <= 0 {
+		return nil, errors.New("interval must be positive")
+	}
+
+	grafana := cCtx.Bool(monitor.GrafanaFlag.Name)
+	grafanaRetention := cCtx.Duration(monitor.GrafanaRetentionFlag.Name)
+	if grafana && common.IsEmptyString(metricsAddress) {
+		return nil, errors.New("grafana needs a metrics address to serve on")
+	}
+	if grafana && grafanaRetention <= 0 {
+		return nil, errors.New("grafana retention must be positive")
+	}
+
+	earnerAddresses, err := parseAddresses(cCtx.String(monitor.EarnerAddressesFlag.Name))
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("invalid earner addresses", err)
+	}
+	if len(earnerAddresses) == 0 {
+		earnerAddresses = []gethcommon.Address{operatorAddress}
+	}
+	expectedClaimers, err := parseAddresses(cCtx.String(monitor.ExpectedClaimersFlag.Name))
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("invalid expected claimers", err)
+	}
+	accrualTokens, err := parseAddresses(cCtx.String(monitor.AccrualTokensFlag.Name))
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("invalid accrual tokens", err)
+	}
+
+	chainID := utils.NetworkNameToChainId(network)
+	logger.Debugf("Using chain ID: %s", chainID.String())
+
+	chainMetadata, ok := common.ChainMetadataMap[chainID.Int64()]
+	if !ok {
+		return nil, fmt.Errorf("network %s is not supported", network)
+	}
+
+	rewardsCoordinatorAddress := cCtx.String(rewards.RewardsCoordinatorAddressFlag.Name)
+	if common.IsEmptyString(rewardsCoordinatorAddress) {
+		rewardsCoordinatorAddress = chainMetadata.ELRewardsCoordinatorAddress
+	}
+	logger.Debugf("Using Rewards Coordinator address: %s", rewardsCoordinatorAddress)
+
+	return &monitor.Config{
+		Network:                   network,
+		RPCUrl:                    rpcUrl,
+		ChainID:                   chainID,
+		OperatorAddress:           operatorAddress,
+		MetadataURL:               metadataURL,
+		DelegationManagerAddress:  gethcommon.HexToAddress(chainMetadata.ELDelegationManagerAddress),
+		RewardsCoordinatorAddress: gethcommon.HexToAddress(rewardsCoordinatorAddress),
+		AllocationManagerAddress:  gethcommon.HexToAddress(chainMetadata.ELAllocationManagerAddress),
+		Interval:                  interval,
+		MetricsAddress:            metricsAddress,
+		Once:                      once,
+		EarnerAddresses:           earnerAddresses,
+		ExpectedClaimers:          expectedClaimers,
+		AccrualTokens:             accrualTokens,
+		AlertWebhookURL:           cCtx.String(monitor.AlertWebhookURLFlag.Name),
+		Grafana:                   grafana,
+		GrafanaRetention:          grafanaRetention,
+	}, nil
+}
+
+// parseAddresses parses a comma separated list of addresses
+func parseAddresses(list string) ([]gethcommon.Address, error) {
+	addresses := make([]gethcommon.Address, 0)
+	for _, value := range strings.Split(list, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if !gethcommon.IsHexAddress(value) {
+			return nil, fmt.Errorf("%s is not a valid address", value)
+		}
+		addresses = append(addresses, gethcommon.HexToAddress(value))
+	}
+	return addresses, nil
+}
@@ -0,0 +1,49 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const alertRequestTimeout = 10 * time.Second
+
+// Alerter is notified when a check starts failing, or fails for a new reason
+type Alerter interface {
+	Alert(ctx context.Context, result Result) error
+}
+
+// webhookAlerter posts the result of the failing check as JSON to a URL, e.g. of an incident
+// management tool or a chat integration
+type webhookAlerter struct {
+	client *http.Client
+	url    string
+}
+
+func NewWebhookAlerter(url string) Alerter {
+	return &webhookAlerter{client: &http.Client{Timeout: alertRequestTimeout}, url: url}
+}
+
+func (a *webhookAlerter) Alert(ctx context.Context, result Result) error {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+const AllocationCheckName = "allocation"
+
+// allocationManagerABI is the part of the AllocationManager of the slashing release read by the
+// allocation check. The bindings of the CLI predate the slashing release, so they don't have it.
+const allocationManagerABI = `[
+	{
+		"type": "function",
+		"name": "getAllocationDelay",
+		"stateMutability": "view",
+		"inputs": [{"name": "operator", "type": "address"}],
+		"outputs": [{"name": "isSet", "type": "bool"}, {"name": "delay", "type": "uint32"}]
+	},
+	{
+		"type": "function",
+		"name": "getRegisteredSets",
+		"stateMutability": "view",
+		"inputs": [{"name": "operator", "type": "address"}],
+		"outputs": [{
+			"name": "operatorSets",
+			"type": "tuple[]",
+			"components": [{"name": "avs", "type": "address"}, {"name": "id", "type": "uint32"}]
+		}]
+	},
+	{
+		"type": "function",
+		"name": "getAllocatedSets",
+		"stateMutability": "view",
+		"inputs": [{"name": "operator", "type": "address"}],
+		"outputs": [{
+			"name": "operatorSets",
+			"type": "tuple[]",
+			"components": [{"name": "avs", "type": "address"}, {"name": "id", "type": "uint32"}]
+		}]
+	}
+]`
+
+// ErrAllocationsUnsupported is returned when the network has no AllocationManager, or one which
+// predates the slashing release
+var ErrAllocationsUnsupported = errors.New("allocations are not supported by the contracts deployed on this network")
+
+type OperatorSet struct {
+	Avs gethcommon.Address
+	Id  uint32
+}
+
+func (s OperatorSet) String() string {
+	return fmt.Sprintf("%s/%d", s.Avs.Hex(), s.Id)
+}
+
+type Allocations struct {
+	DelaySet       bool
+	Delay          uint32
+	RegisteredSets []OperatorSet
+	AllocatedSets  []OperatorSet
+}
+
+// Unallocated returns the operator sets the operator is registered to without allocating to them
+func (a *Allocations) Unallocated() []OperatorSet {
+	allocated := make(map[OperatorSet]bool)
+	for _, set := range a.AllocatedSets {
+		allocated[set] = true
+	}
+	unallocated := make([]OperatorSet, 0)
+	for _, set := range a.RegisteredSets {
+		if !allocated[set] {
+			unallocated = append(unallocated, set)
+		}
+	}
+	return unallocated
+}
+
+// AllocationReader reads the allocations of operators from the AllocationManager
+type AllocationReader struct {
+	caller  ethereum.ContractCaller
+	address gethcommon.Address
+	abi     abi.ABI
+}
+
+// NewAllocationReader creates a reader of the AllocationManager at the address. Without an
+// address every read returns ErrAllocationsUnsupported.
+func NewAllocationReader(caller ethereum.ContractCaller, address gethcommon.Address) (*AllocationReader, error) {
+	parsed, err := abi.JSON(strings.NewReader(allocationManagerABI))
+	if err != nil {
+		return nil, err
+	}
+	return &AllocationReader{caller: caller, address: address, abi: parsed}, nil
+}
+
+func (r *AllocationReader) GetAllocations(ctx context.Context, operator gethcommon.Address) (*Allocations, error) {
+	if r.address == (gethcommon.Address{}) {
+		return nil, ErrAllocationsUnsupported
+	}
+	delay, err := r.call(ctx, "getAllocationDelay", operator)
+	if err != nil {
+		return nil, err
+	}
+	registered, err := r.call(ctx, "getRegisteredSets", operator)
+	if err != nil {
+		return nil, err
+	}
+	allocated, err := r.call(ctx, "getAllocatedSets", operator)
+	if err != nil {
+		return nil, err
+	}
+	return &Allocations{
+		DelaySet:       *abi.ConvertType(delay[0], new(bool)).(*bool),
+		Delay:          *abi.ConvertType(delay[1], new(uint32)).(*uint32),
+		RegisteredSets: *abi.ConvertType(registered[0], new([]OperatorSet)).(*[]OperatorSet),
+		AllocatedSets:  *abi.ConvertType(allocated[0], new([]OperatorSet)).(*[]OperatorSet),
+	}, nil
+}
+
+// call calls the method on the AllocationManager. An address without code answers with no
+// output and a contract without the method reverts, both mean the network doesn't support
+// allocations yet.
+func (r *AllocationReader) call(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
+	data, err := r.abi.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	output, err := r.caller.CallContract(ctx, ethereum.CallMsg{To: &r.address, Data: data}, nil)
+	if err != nil {
+		if strings.Contains(err.Error(), "execution reverted") {
+			return nil, ErrAllocationsUnsupported
+		}
+		return nil, fmt.Errorf("failed to call %s of AllocationManager %s: %w", method, r.address.Hex(), err)
+	}
+	if len(output) == 0 {
+		return nil, ErrAllocationsUnsupported
+	}
+	values, err := r.abi.Unpack(method, output)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output of %s of AllocationManager %s: %w", method, r.address.Hex(), err)
+	}
+	return values, nil
+}
+
+type allocationReader interface {
+	GetAllocations(ctx context.Context, operator gethcommon.Address) (*Allocations, error)
+}
+
+// allocationCheck fails if the operator is registered to operator sets without an allocation
+// delay, or without allocating to every set it is registered to. Slashable stake is only
+// allocated once the delay is set, so an AVS sees the operator with no stake until then. The
+// check is skipped on networks where the AllocationManager isn't deployed yet.
+type allocationCheck struct {
+	reader          allocationReader
+	operatorAddress gethcommon.Address
+}
+
+func NewAllocationCheck(reader allocationReader, operatorAddress gethcommon.Address) Check {
+	return &allocationCheck{reader: reader, operatorAddress: operatorAddress}
+}
+
+func (c *allocationCheck) Name() string {
+	return AllocationCheckName
+}
+
+func (c *allocationCheck) Run(ctx context.Context) Result {
+	allocations, err := c.reader.GetAllocations(ctx, c.operatorAddress)
+	if errors.Is(err, ErrAllocationsUnsupported) {
+		return Result{Status: StatusSkipped, Message: err.Error()}
+	}
+	if err != nil {
+		return Result{Status: StatusError, Message: err.Error()}
+	}
+	if len(allocations.RegisteredSets) == 0 {
+		return Result{Status: StatusOK, Message: "operator is not registered to any operator set"}
+	}
+	if !allocations.DelaySet {
+		return Result{
+			Status: StatusFailed,
+			Message: fmt.Sprintf(
+				"operator is registered to %d operator sets without an allocation delay",
+				len(allocations.RegisteredSets),
+			),
+		}
+	}
+
+	unallocated := make([]string, 0)
+	for _, set := range allocations.Unallocated() {
+		unallocated = append(unallocated, set.String())
+	}
+	if len(unallocated) > 0 {
+		return Result{
+			Status:  StatusFailed,
+			Message: fmt.Sprintf("operator has no allocation to operator sets %s", strings.Join(unallocated, ", ")),
+		}
+	}
+	return Result{
+		Status: StatusOK,
+		Message: fmt.Sprintf(
+			"operator allocates to the %d operator sets it is registered to",
+			len(allocations.RegisteredSets),
+		),
+	}
+}
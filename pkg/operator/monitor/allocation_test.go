@@ -0,0 +1,160 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAllocationManager answers the calls of the AllocationManager ABI with the outputs by method
+type fakeAllocationManager struct {
+	abi     abi.ABI
+	outputs map[string][]interface{}
+	err     error
+}
+
+func (f *fakeAllocationManager) CallContract(
+	ctx context.Context,
+	call ethereum.CallMsg,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	method, err := f.abi.MethodById(call.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	outputs, ok := f.outputs[method.Name]
+	if !ok {
+		return nil, nil
+	}
+	return method.Outputs.Pack(outputs...)
+}
+
+type abiOperatorSet struct {
+	Avs gethcommon.Address `json:"avs"`
+	Id  uint32             `json:"id"`
+}
+
+func TestAllocationReader(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress("0x1")
+	allocationManager := gethcommon.HexToAddress("0x2")
+	avs := gethcommon.HexToAddress("0x3")
+	reader, err := NewAllocationReader(nil, allocationManager)
+	assert.NoError(t, err)
+
+	caller := &fakeAllocationManager{
+		abi: reader.abi,
+		outputs: map[string][]interface{}{
+			"getAllocationDelay": {true, uint32(75)},
+			"getRegisteredSets":  {[]abiOperatorSet{{Avs: avs, Id: 1}, {Avs: avs, Id: 2}}},
+			"getAllocatedSets":   {[]abiOperatorSet{{Avs: avs, Id: 1}}},
+		},
+	}
+	reader.caller = caller
+	allocations, err := reader.GetAllocations(context.Background(), operatorAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, &Allocations{
+		DelaySet:       true,
+		Delay:          75,
+		RegisteredSets: []OperatorSet{{Avs: avs, Id: 1}, {Avs: avs, Id: 2}},
+		AllocatedSets:  []OperatorSet{{Avs: avs, Id: 1}},
+	}, allocations)
+
+	// An AllocationManager which predates the slashing release
+	caller.err = errors.New("execution reverted")
+	_, err = reader.GetAllocations(context.Background(), operatorAddress)
+	assert.ErrorIs(t, err, ErrAllocationsUnsupported)
+
+	caller.err = errors.New("rpc down")
+	_, err = reader.GetAllocations(context.Background(), operatorAddress)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrAllocationsUnsupported)
+
+	// No AllocationManager deployed at the address
+	caller.err = nil
+	caller.outputs = map[string][]interface{}{}
+	_, err = reader.GetAllocations(context.Background(), operatorAddress)
+	assert.ErrorIs(t, err, ErrAllocationsUnsupported)
+
+	// No AllocationManager on the network
+	reader, err = NewAllocationReader(caller, gethcommon.Address{})
+	assert.NoError(t, err)
+	_, err = reader.GetAllocations(context.Background(), operatorAddress)
+	assert.ErrorIs(t, err, ErrAllocationsUnsupported)
+}
+
+type fakeAllocationReader struct {
+	allocations *Allocations
+	err         error
+}
+
+func (f *fakeAllocationReader) GetAllocations(
+	ctx context.Context,
+	operator gethcommon.Address,
+) (*Allocations, error) {
+	return f.allocations, f.err
+}
+
+func TestAllocationCheck(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress("0x1")
+	avs := gethcommon.HexToAddress("0x3")
+	sets := []OperatorSet{{Avs: avs, Id: 1}, {Avs: avs, Id: 2}}
+
+	tests := []struct {
+		name   string
+		reader *fakeAllocationReader
+		status Status
+	}{
+		{
+			name:   "not registered to operator sets",
+			reader: &fakeAllocationReader{allocations: &Allocations{}},
+			status: StatusOK,
+		},
+		{
+			name: "allocated to every set",
+			reader: &fakeAllocationReader{
+				allocations: &Allocations{DelaySet: true, RegisteredSets: sets, AllocatedSets: sets},
+			},
+			status: StatusOK,
+		},
+		{
+			name: "allocation delay not set",
+			reader: &fakeAllocationReader{
+				allocations: &Allocations{RegisteredSets: sets, AllocatedSets: sets},
+			},
+			status: StatusFailed,
+		},
+		{
+			name: "set without allocation",
+			reader: &fakeAllocationReader{
+				allocations: &Allocations{DelaySet: true, RegisteredSets: sets, AllocatedSets: sets[:1]},
+			},
+			status: StatusFailed,
+		},
+		{
+			name:   "unsupported",
+			reader: &fakeAllocationReader{err: ErrAllocationsUnsupported},
+			status: StatusSkipped,
+		},
+		{
+			name:   "rpc error",
+			reader: &fakeAllocationReader{err: errors.New("rpc down")},
+			status: StatusError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := NewAllocationCheck(tt.reader, operatorAddress)
+			assert.Equal(t, AllocationCheckName, check.Name())
+			assert.Equal(t, tt.status, check.Run(context.Background()).Status)
+		})
+	}
+}
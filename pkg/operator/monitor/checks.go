@@ -0,0 +1,460 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/rewards"
+
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IRewardsCoordinator"
+	eigensdkTypes "github.com/Layr-Labs/eigensdk-go/types"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	RegistrationCheckName     = "registration"
+	MetadataCheckName         = "metadata_uri"
+	UnclaimedRewardsCheckName = "unclaimed_rewards"
+	ClaimerCheckName          = "claimer"
+	AccrualCheckName          = "accrual"
+
+	metadataRequestTimeout = 10 * time.Second
+	// metadataMaxSize is the limit enforced on the metadata by the EigenLayer web app
+	metadataMaxSize = 1 << 20
+
+	// logsChunkBlocks is the block range of a log query, which most RPC providers accept
+	logsChunkBlocks = 10000
+)
+
+type headReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// filterChunks runs filter on the blocks from start up to the head, logsChunkBlocks blocks at a
+// time. It returns the block to continue from next time, which is after the last chunk that was
+// read, even if a later chunk failed.
+func filterChunks(
+	ctx context.Context,
+	head headReader,
+	start uint64,
+	filter func(opts *bind.FilterOpts) error,
+) (uint64, error) {
+	latest, err := head.BlockNumber(ctx)
+	if err != nil {
+		return start, err
+	}
+	for from := start; from <= latest; from += logsChunkBlocks {
+		to := min(from+logsChunkBlocks-1, latest)
+		if err := filter(&bind.FilterOpts{Start: from, End: &to, Context: ctx}); err != nil {
+			return from, err
+		}
+	}
+	return max(start, latest+1), nil
+}
+
+type operatorRegistrationReader interface {
+	IsOperatorRegistered(ctx context.Context, operator eigensdkTypes.Operator) (bool, error)
+}
+
+// registrationCheck fails if the operator is not registered on EigenLayer
+type registrationCheck struct {
+	reader          operatorRegistrationReader
+	operatorAddress gethcommon.Address
+}
+
+func NewRegistrationCheck(reader operatorRegistrationReader, operatorAddress gethcommon.Address) Check {
+	return &registrationCheck{reader: reader, operatorAddress: operatorAddress}
+}
+
+func (c *registrationCheck) Name() string {
+	return RegistrationCheckName
+}
+
+func (c *registrationCheck) Run(ctx context.Context) Result {
+	registered, err := c.reader.IsOperatorRegistered(ctx, eigensdkTypes.Operator{Address: c.operatorAddress.Hex()})
+	if err != nil {
+		return Result{Status: StatusError, Message: err.Error()}
+	}
+	if !registered {
+		return Result{Status: StatusFailed, Message: "operator is not registered on EigenLayer"}
+	}
+	return Result{Status: StatusOK, Message: "operator is registered on EigenLayer"}
+}
+
+type metadataURIFilterer interface {
+	FilterOperatorMetadataURIUpdated(
+		opts *bind.FilterOpts,
+		operator []gethcommon.Address,
+	) (*delegationmanager.ContractDelegationManagerOperatorMetadataURIUpdatedIterator, error)
+}
+
+// metadataCheck fails if the metadata URI of the operator can't be fetched or isn't valid operator
+// metadata. Without a fixed URL the check follows the URI last set on-chain.
+type metadataCheck struct {
+	client          *http.Client
+	filterer        metadataURIFilterer
+	head            headReader
+	operatorAddress gethcommon.Address
+
+	url       string
+	fixedURL  bool
+	nextBlock uint64
+}
+
+func NewMetadataCheck(
+	filterer metadataURIFilterer,
+	head headReader,
+	operatorAddress gethcommon.Address,
+	url string,
+) Check {
+	return &metadataCheck{
+		client:          &http.Client{Timeout: metadataRequestTimeout},
+		filterer:        filterer,
+		head:            head,
+		operatorAddress: operatorAddress,
+		url:             url,
+		fixedURL:        url != "",
+	}
+}
+
+func (c *metadataCheck) Name() string {
+	return MetadataCheckName
+}
+
+func (c *metadataCheck) Run(ctx context.Context) Result {
+	if !c.fixedURL {
+		err := c.updateURL(ctx)
+		if err != nil {
+			return Result{Status: StatusError, Message: fmt.Sprintf("failed to get metadata URI: %s", err)}
+		}
+		if c.url == "" {
+			return Result{Status: StatusFailed, Message: "operator has no metadata URI set on-chain"}
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return Result{Status: StatusFailed, Message: fmt.Sprintf("invalid metadata URI %s: %s", c.url, err)}
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Result{Status: StatusFailed, Message: fmt.Sprintf("metadata URI %s is not reachable: %s", c.url, err)}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Result{
+			Status:  StatusFailed,
+			Message: fmt.Sprintf("metadata URI %s returned status %d", c.url, resp.StatusCode),
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, metadataMaxSize))
+	if err != nil {
+		return Result{Status: StatusFailed, Message: fmt.Sprintf("failed to read metadata from %s: %s", c.url, err)}
+	}
+	var metadata eigensdkTypes.OperatorMetadata
+	if err := json.Unmarshal(body, &metadata); err != nil {
+		return Result{Status: StatusFailed, Message: fmt.Sprintf("metadata at %s is not valid JSON: %s", c.url, err)}
+	}
+	if metadata.Name == "" {
+		return Result{Status: StatusFailed, Message: fmt.Sprintf("metadata at %s has no name", c.url)}
+	}
+	return Result{Status: StatusOK, Message: fmt.Sprintf("metadata URI %s is reachable", c.url)}
+}
+
+// updateURL picks up metadata URI updates of the operator since the last round
+func (c *metadataCheck) updateURL(ctx context.Context) error {
+	nextBlock, err := filterChunks(ctx, c.head, c.nextBlock, func(opts *bind.FilterOpts) error {
+		iter, err := c.filterer.FilterOperatorMetadataURIUpdated(opts, []gethcommon.Address{c.operatorAddress})
+		if err != nil {
+			return err
+		}
+		defer iter.Close()
+		for iter.Next() {
+			c.url = iter.Event.MetadataURI
+		}
+		return iter.Error()
+	})
+	c.nextBlock = nextBlock
+	return err
+}
+
+type snapshotReader interface {
+	GetLatestSnapshot(ctx context.Context, earnerAddress gethcommon.Address) (*rewards.RewardsSnapshot, error)
+}
+
+// unclaimedRewardsCheck publishes the unclaimed rewards of the operator in the latest active root
+type unclaimedRewardsCheck struct {
+	reader          snapshotReader
+	operatorAddress gethcommon.Address
+	metrics         *Metrics
+}
+
+func NewUnclaimedRewardsCheck(reader snapshotReader, operatorAddress gethcommon.Address, metrics *Metrics) Check {
+	return &unclaimedRewardsCheck{reader: reader, operatorAddress: operatorAddress, metrics: metrics}
+}
+
+func (c *unclaimedRewardsCheck) Name() string {
+	return UnclaimedRewardsCheckName
+}
+
+func (c *unclaimedRewardsCheck) Run(ctx context.Context) Result {
+	snapshot, err := c.reader.GetLatestSnapshot(ctx, c.operatorAddress)
+	if errors.Is(err, rewards.ErrEarnerNotFound) {
+		c.metrics.UnclaimedRewards.Reset()
+		return Result{Status: StatusOK, Message: "operator has no rewards in the latest active root"}
+	}
+	if err != nil {
+		return Result{Status: StatusError, Message: err.Error()}
+	}
+
+	c.metrics.UnclaimedRewards.Reset()
+	tokensWithRewards := 0
+	for token, amount := range snapshot.Unclaimed {
+		value, _ := new(big.Float).SetInt(amount).Float64()
+		c.metrics.UnclaimedRewards.WithLabelValues(token.Hex()).Set(value)
+		if amount.Sign() > 0 {
+			tokensWithRewards++
+		}
+	}
+	return Result{
+		Status: StatusOK,
+		Message: fmt.Sprintf(
+			"%d token(s) with unclaimed rewards in root %d (%s)",
+			tokensWithRewards,
+			snapshot.RootIndex,
+			snapshot.Date,
+		),
+	}
+}
+
+type claimerForSetFilterer interface {
+	FilterClaimerForSet(
+		opts *bind.FilterOpts,
+		earner []gethcommon.Address,
+		oldClaimer []gethcommon.Address,
+		claimer []gethcommon.Address,
+	) (*rewardscoordinator.ContractIRewardsCoordinatorClaimerForSetIterator, error)
+}
+
+// claimerCheck is a watchdog on the claimers of the monitored earners. A claimer can claim all
+// rewards of its earner, so an unexpected change is an early sign of a compromised key. The
+// claimers set when the check starts are the baseline. With expected claimers, every claimer has
+// to be one of them. Without, every change after the baseline fails the check until the monitor
+// is restarted.
+type claimerCheck struct {
+	filterer         claimerForSetFilterer
+	head             headReader
+	earners          []gethcommon.Address
+	expectedClaimers map[gethcommon.Address]bool
+
+	claimers  map[gethcommon.Address]gethcommon.Address
+	changes   []string
+	nextBlock uint64
+	baselined bool
+}
+
+func NewClaimerCheck(
+	filterer claimerForSetFilterer,
+	head headReader,
+	earners []gethcommon.Address,
+	expectedClaimers []gethcommon.Address,
+) Check {
+	expected := make(map[gethcommon.Address]bool, len(expectedClaimers))
+	for _, claimer := range expectedClaimers {
+		expected[claimer] = true
+	}
+	return &claimerCheck{
+		filterer:         filterer,
+		head:             head,
+		earners:          earners,
+		expectedClaimers: expected,
+		claimers:         make(map[gethcommon.Address]gethcommon.Address),
+	}
+}
+
+func (c *claimerCheck) Name() string {
+	return ClaimerCheckName
+}
+
+func (c *claimerCheck) Run(ctx context.Context) Result {
+	nextBlock, err := filterChunks(ctx, c.head, c.nextBlock, c.update)
+	c.nextBlock = nextBlock
+	if err != nil {
+		return Result{Status: StatusError, Message: fmt.Sprintf("failed to get claimer changes: %s", err)}
+	}
+	// The baseline is only complete once the claimer changes are read up to the head
+	c.baselined = true
+
+	if len(c.changes) > 0 {
+		return Result{Status: StatusFailed, Message: strings.Join(c.changes, "; ")}
+	}
+	unexpected := make([]string, 0)
+	for _, earner := range c.earners {
+		claimer, ok := c.claimers[earner]
+		// Without a claimer only the earner itself can claim
+		if !ok || claimer == (gethcommon.Address{}) || len(c.expectedClaimers) == 0 {
+			continue
+		}
+		if !c.expectedClaimers[claimer] {
+			unexpected = append(unexpected, fmt.Sprintf("claimer of %s is unexpected address %s", earner.Hex(), claimer.Hex()))
+		}
+	}
+	if len(unexpected) > 0 {
+		return Result{Status: StatusFailed, Message: strings.Join(unexpected, "; ")}
+	}
+	if len(c.expectedClaimers) > 0 {
+		return Result{Status: StatusOK, Message: fmt.Sprintf("claimers of %d earner(s) are expected", len(c.earners))}
+	}
+	return Result{Status: StatusOK, Message: fmt.Sprintf("claimers of %d earner(s) are unchanged", len(c.earners))}
+}
+
+// update picks up the claimer changes of the earners in the blocks of opts
+func (c *claimerCheck) update(opts *bind.FilterOpts) error {
+	iter, err := c.filterer.FilterClaimerForSet(opts, c.earners, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+	for iter.Next() {
+		event := iter.Event
+		c.claimers[event.Earner] = event.Claimer
+		if c.baselined && len(c.expectedClaimers) == 0 {
+			c.changes = append(c.changes, fmt.Sprintf(
+				"claimer of %s changed from %s to %s in block %d",
+				event.Earner.Hex(),
+				event.OldClaimer.Hex(),
+				event.Claimer.Hex(),
+				event.Raw.BlockNumber,
+			))
+		}
+	}
+	return iter.Error()
+}
+
+type upcomingSnapshotReader interface {
+	snapshotReader
+	GetUpcomingSnapshot(ctx context.Context, earnerAddress gethcommon.Address) (*rewards.RewardsSnapshot, error)
+}
+
+// accrualCheck compares the rewards of the earners in consecutive distribution roots and fails if
+// a token stopped accruing, which usually means the operator fell out of an operator set or quorum.
+// The latest submitted root is compared as soon as it is posted, so the alert comes before the root
+// is claimable. Roots don't break rewards down by AVS, so the expected rewards are set per token.
+type accrualCheck struct {
+	reader  upcomingSnapshotReader
+	earners []gethcommon.Address
+	tokens  []gethcommon.Address
+	metrics *Metrics
+
+	previous map[gethcommon.Address]*rewards.RewardsSnapshot
+	stalled  map[gethcommon.Address][]string
+}
+
+func NewAccrualCheck(
+	reader upcomingSnapshotReader,
+	earners []gethcommon.Address,
+	tokens []gethcommon.Address,
+	metrics *Metrics,
+) Check {
+	return &accrualCheck{
+		reader:   reader,
+		earners:  earners,
+		tokens:   tokens,
+		metrics:  metrics,
+		previous: make(map[gethcommon.Address]*rewards.RewardsSnapshot),
+		stalled:  make(map[gethcommon.Address][]string),
+	}
+}
+
+func (c *accrualCheck) Name() string {
+	return AccrualCheckName
+}
+
+func (c *accrualCheck) Run(ctx context.Context) Result {
+	for _, earner := range c.earners {
+		if err := c.update(ctx, earner); err != nil {
+			return Result{Status: StatusError, Message: err.Error()}
+		}
+	}
+
+	stalled := make([]string, 0)
+	for _, earner := range c.earners {
+		stalled = append(stalled, c.stalled[earner]...)
+	}
+	if len(stalled) > 0 {
+		return Result{Status: StatusFailed, Message: strings.Join(stalled, "; ")}
+	}
+	return Result{Status: StatusOK, Message: fmt.Sprintf("rewards of %d earner(s) are accruing", len(c.earners))}
+}
+
+// update compares the upcoming root with the root seen before, once per root
+func (c *accrualCheck) update(ctx context.Context, earner gethcommon.Address) error {
+	upcoming, err := c.reader.GetUpcomingSnapshot(ctx, earner)
+	if errors.Is(err, rewards.ErrEarnerNotFound) {
+		c.stalled[earner] = []string{fmt.Sprintf("earner %s has no rewards in the latest root", earner.Hex())}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	previous, ok := c.previous[earner]
+	if !ok {
+		// Without an earlier round the latest active root is the baseline
+		previous, err = c.reader.GetLatestSnapshot(ctx, earner)
+		if errors.Is(err, rewards.ErrEarnerNotFound) {
+			c.previous[earner] = upcoming
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	c.previous[earner] = upcoming
+	if previous.RootIndex == upcoming.RootIndex {
+		return nil
+	}
+
+	tokens := c.tokens
+	if len(tokens) == 0 {
+		for token := range previous.Total {
+			tokens = append(tokens, token)
+		}
+	}
+	stalled := make([]string, 0)
+	for _, token := range tokens {
+		accrued := new(big.Int)
+		if total, ok := upcoming.Total[token]; ok {
+			accrued.Set(total)
+		}
+		if total, ok := previous.Total[token]; ok {
+			accrued.Sub(accrued, total)
+		}
+		value, _ := new(big.Float).SetInt(accrued).Float64()
+		c.metrics.RewardsAccrued.WithLabelValues(earner.Hex(), token.Hex()).Set(value)
+		if accrued.Sign() <= 0 {
+			stalled = append(stalled, fmt.Sprintf(
+				"no %s accrued by %s between root %d (%s) and root %d (%s)",
+				token.Hex(),
+				earner.Hex(),
+				previous.RootIndex,
+				previous.Date,
+				upcoming.RootIndex,
+				upcoming.Date,
+			))
+		}
+	}
+	c.stalled[earner] = stalled
+	return nil
+}
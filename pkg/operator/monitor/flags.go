@@ -0,0 +1,79 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	IntervalFlag = cli.DurationFlag{
+		Name:    "interval",
+		Aliases: []string{"i"},
+		Usage:   "Time between two rounds of checks",
+		Value:   5 * time.Minute,
+		EnvVars: []string{"OPERATOR_MONITOR_INTERVAL"},
+	}
+
+	MetricsAddressFlag = cli.StringFlag{
+		Name:    "metrics-address",
+		Aliases: []string{"ma"},
+		Usage:   "Address to serve the Prometheus metrics and the health endpoint on. Set to empty to disable",
+		Value:   ":9091",
+		EnvVars: []string{"OPERATOR_MONITOR_METRICS_ADDRESS"},
+	}
+
+	OnceFlag = cli.BoolFlag{
+		Name:    "once",
+		Usage:   "Run the checks once and exit with a non zero exit code if any check failed",
+		EnvVars: []string{"OPERATOR_MONITOR_ONCE"},
+	}
+
+	MetadataURLFlag = cli.StringFlag{
+		Name:    "metadata-url",
+		Aliases: []string{"mu"},
+		Usage:   "Metadata URL of the operator. Defaults to the URL last set on-chain by the operator",
+		EnvVars: []string{"OPERATOR_MONITOR_METADATA_URL"},
+	}
+
+	EarnerAddressesFlag = cli.StringFlag{
+		Name:    "earner-addresses",
+		Aliases: []string{"ea"},
+		Usage:   "Comma separated earner addresses watched by the claimer and accrual checks. Defaults to the operator address",
+		EnvVars: []string{"OPERATOR_MONITOR_EARNER_ADDRESSES"},
+	}
+
+	ExpectedClaimersFlag = cli.StringFlag{
+		Name:    "expected-claimers",
+		Aliases: []string{"ec"},
+		Usage:   "Comma separated claimer addresses the watched earners may use. If not set, any claimer change is an alert",
+		EnvVars: []string{"OPERATOR_MONITOR_EXPECTED_CLAIMERS"},
+	}
+
+	AccrualTokensFlag = cli.StringFlag{
+		Name:    "accrual-tokens",
+		Aliases: []string{"at"},
+		Usage:   "Comma separated tokens the watched earners are expected to accrue in every root. Defaults to the tokens of the previous root",
+		EnvVars: []string{"OPERATOR_MONITOR_ACCRUAL_TOKENS"},
+	}
+
+	AlertWebhookURLFlag = cli.StringFlag{
+		Name:    "alert-webhook-url",
+		Aliases: []string{"awu"},
+		Usage:   "URL to post the result of a check to as JSON when the check starts failing",
+		EnvVars: []string{"OPERATOR_MONITOR_ALERT_WEBHOOK_URL"},
+	}
+
+	GrafanaFlag = cli.BoolFlag{
+		Name:    "grafana",
+		Usage:   "Serve the metrics and failed checks as a Grafana JSON datasource on /grafana of --metrics-address",
+		EnvVars: []string{"OPERATOR_MONITOR_GRAFANA"},
+	}
+
+	GrafanaRetentionFlag = cli.DurationFlag{
+		Name:    "grafana-retention",
+		Usage:   "How long the history served to Grafana is kept. It is kept in memory only and lost on restart",
+		Value:   7 * 24 * time.Hour,
+		EnvVars: []string{"OPERATOR_MONITOR_GRAFANA_RETENTION"},
+	}
+)
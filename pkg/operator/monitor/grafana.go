@@ -0,0 +1,288 @@
+package monitor
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// GrafanaPath is where the Grafana JSON datasource is served
+	GrafanaPath = "/grafana"
+
+	// checksTarget is the table of the results of the last round of checks
+	checksTarget = "checks"
+)
+
+// history keeps the metrics and the failed checks of every round for the retention period, so
+// Grafana can graph them without a Prometheus server in between
+type history struct {
+	retention time.Duration
+
+	mu     sync.RWMutex
+	rounds []round
+	// failing holds the message of every check failing in the last round
+	failing map[string]string
+}
+
+type round struct {
+	at     time.Time
+	values map[string]float64
+	// failures are the checks which started failing in the round, or fail for a new reason
+	failures []Result
+}
+
+func newHistory(retention time.Duration) *history {
+	return &history{retention: retention, failing: make(map[string]string)}
+}
+
+func (h *history) add(at time.Time, values map[string]float64, results []Result) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	failures := make([]Result, 0)
+	for _, result := range results {
+		if result.Status != StatusFailed && result.Status != StatusError {
+			delete(h.failing, result.Check)
+			continue
+		}
+		if message, ok := h.failing[result.Check]; ok && message == result.Message {
+			continue
+		}
+		h.failing[result.Check] = result.Message
+		failures = append(failures, result)
+	}
+
+	h.rounds = append(h.rounds, round{at: at, values: values, failures: failures})
+	expired := 0
+	for expired < len(h.rounds) && at.Sub(h.rounds[expired].at) > h.retention {
+		expired++
+	}
+	h.rounds = h.rounds[expired:]
+}
+
+// series returns the names of all series in the history
+func (h *history) series() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, r := range h.rounds {
+		for name := range r.values {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// datapoints returns the values of a series between from and to as [value, unix ms] pairs. If
+// there are more than maxDataPoints, every n-th value is kept.
+func (h *history) datapoints(name string, from, to time.Time, maxDataPoints int) [][2]float64 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	points := make([][2]float64, 0)
+	for _, r := range h.rounds {
+		value, ok := r.values[name]
+		if !ok || r.at.Before(from) || r.at.After(to) {
+			continue
+		}
+		points = append(points, [2]float64{value, float64(r.at.UnixMilli())})
+	}
+	if maxDataPoints <= 0 || len(points) <= maxDataPoints {
+		return points
+	}
+	stride := (len(points) + maxDataPoints - 1) / maxDataPoints
+	sampled := make([][2]float64, 0, maxDataPoints)
+	for i := 0; i < len(points); i += stride {
+		sampled = append(sampled, points[i])
+	}
+	return sampled
+}
+
+func (h *history) failures(from, to time.Time) []Result {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	failures := make([]Result, 0)
+	for _, r := range h.rounds {
+		if r.at.Before(from) || r.at.After(to) {
+			continue
+		}
+		failures = append(failures, r.failures...)
+	}
+	return failures
+}
+
+// values returns the current value of every gauge and counter, named like the Prometheus series
+// without the namespace, e.g. unclaimed_rewards{token="0x..."}
+func (m *Metrics) values() map[string]float64 {
+	values := make(map[string]float64)
+	families, err := m.registry.Gather()
+	if err != nil {
+		return values
+	}
+	for _, family := range families {
+		name := strings.TrimPrefix(family.GetName(), metricsNamespace+"_")
+		for _, metric := range family.GetMetric() {
+			labels := make([]string, 0, len(metric.GetLabel()))
+			for _, label := range metric.GetLabel() {
+				labels = append(labels, label.GetName()+"=\""+label.GetValue()+"\"")
+			}
+			series := name
+			if len(labels) > 0 {
+				series += "{" + strings.Join(labels, ",") + "}"
+			}
+			switch {
+			case metric.GetGauge() != nil:
+				values[series] = metric.GetGauge().GetValue()
+			case metric.GetCounter() != nil:
+				values[series] = metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return values
+}
+
+type grafanaRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+type grafanaQuery struct {
+	Range   grafanaRange `json:"range"`
+	Targets []struct {
+		RefID  string `json:"refId"`
+		Target string `json:"target"`
+	} `json:"targets"`
+	MaxDataPoints int `json:"maxDataPoints"`
+}
+
+type grafanaTimeSeries struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+type grafanaColumn struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+type grafanaTable struct {
+	Type    string          `json:"type"`
+	Columns []grafanaColumn `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+type grafanaAnnotationQuery struct {
+	Range      grafanaRange    `json:"range"`
+	Annotation json.RawMessage `json:"annotation"`
+}
+
+type grafanaAnnotation struct {
+	Annotation json.RawMessage `json:"annotation,omitempty"`
+	Time       int64           `json:"time"`
+	Title      string          `json:"title"`
+	Text       string          `json:"text"`
+	Tags       []string        `json:"tags"`
+}
+
+// newGrafanaHandler serves the history of the monitor in the format of the Grafana JSON
+// datasource plugin (simpod-json-datasource). The metrics, e.g. check_up{check="registration"},
+// are time series, the checks target is a table of the last results and failed checks are
+// served as annotations.
+func newGrafanaHandler(m *Monitor) http.Handler {
+	mux := http.NewServeMux()
+	// Grafana tests the connection of the datasource with its root
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	// /metrics is the endpoint of the current version of the plugin, /search the one of older versions
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		metrics := make([]map[string]string, 0)
+		for _, name := range append([]string{checksTarget}, m.history.series()...) {
+			metrics = append(metrics, map[string]string{"label": name, "value": name})
+		}
+		writeJSON(w, metrics)
+	})
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, append([]string{checksTarget}, m.history.series()...))
+	})
+	mux.HandleFunc("/query", func(w http.ResponseWriter, r *http.Request) {
+		var query grafanaQuery
+		if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		response := make([]interface{}, 0, len(query.Targets))
+		for _, target := range query.Targets {
+			if target.Target == "" {
+				continue
+			}
+			if target.Target == checksTarget {
+				response = append(response, newChecksTable(m.Results()))
+				continue
+			}
+			response = append(response, grafanaTimeSeries{
+				Target:     target.Target,
+				Datapoints: m.history.datapoints(target.Target, query.Range.From, query.Range.To, query.MaxDataPoints),
+			})
+		}
+		writeJSON(w, response)
+	})
+	mux.HandleFunc("/annotations", func(w http.ResponseWriter, r *http.Request) {
+		var query grafanaAnnotationQuery
+		if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		annotations := make([]grafanaAnnotation, 0)
+		for _, failure := range m.history.failures(query.Range.From, query.Range.To) {
+			annotations = append(annotations, grafanaAnnotation{
+				Annotation: query.Annotation,
+				Time:       failure.CheckedAt.UnixMilli(),
+				Title:      "check " + failure.Check + " " + string(failure.Status),
+				Text:       failure.Message,
+				Tags:       []string{failure.Check, string(failure.Status)},
+			})
+		}
+		writeJSON(w, annotations)
+	})
+	return mux
+}
+
+func newChecksTable(results []Result) grafanaTable {
+	table := grafanaTable{
+		Type: "table",
+		Columns: []grafanaColumn{
+			{Text: "Checked At", Type: "time"},
+			{Text: "Check", Type: "string"},
+			{Text: "Status", Type: "string"},
+			{Text: "Message", Type: "string"},
+		},
+		Rows: make([][]interface{}, 0, len(results)),
+	}
+	for _, result := range results {
+		table.Rows = append(table.Rows, []interface{}{
+			result.CheckedAt.UnixMilli(),
+			result.Check,
+			string(result.Status),
+			result.Message,
+		})
+	}
+	return table
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
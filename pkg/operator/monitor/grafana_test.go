@@ -0,0 +1,105 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func postGrafana(t *testing.T, server *httptest.Server, path string, body string, v interface{}) {
+	resp, err := http.Post(server.URL+path, "application/json", strings.NewReader(body))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+}
+
+func TestGrafana(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	reader := &fakeRegistrationReader{registered: true}
+	m := New([]Check{
+		NewRegistrationCheck(reader, gethcommon.HexToAddress("0x1")),
+		&fakeCheck{results: []Result{{Status: StatusSkipped}, {Status: StatusSkipped}, {Status: StatusSkipped}}},
+	}, NewMetrics(), logger).WithGrafana(time.Hour)
+
+	m.RunOnce(context.Background())
+	reader.registered = false
+	m.RunOnce(context.Background())
+	m.RunOnce(context.Background())
+
+	server := httptest.NewServer(newGrafanaHandler(m))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var metrics []map[string]string
+	postGrafana(t, server, "/metrics", "{}", &metrics)
+	assert.Contains(t, metrics, map[string]string{"label": checksTarget, "value": checksTarget})
+	assert.Contains(t, metrics, map[string]string{
+		"label": `check_up{check="registration"}`,
+		"value": `check_up{check="registration"}`,
+	})
+
+	from := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339Nano)
+	to := time.Now().Add(time.Minute).UTC().Format(time.RFC3339Nano)
+	var series []grafanaTimeSeries
+	postGrafana(t, server, "/query", `{
+		"range": {"from": "`+from+`", "to": "`+to+`"},
+		"targets": [{"refId": "A", "target": "check_up{check=\"registration\"}"}],
+		"maxDataPoints": 100
+	}`, &series)
+	assert.Len(t, series, 1)
+	assert.Len(t, series[0].Datapoints, 3)
+	assert.Equal(t, []float64{1, 0, 0}, []float64{
+		series[0].Datapoints[0][0],
+		series[0].Datapoints[1][0],
+		series[0].Datapoints[2][0],
+	})
+
+	var tables []grafanaTable
+	postGrafana(t, server, "/query", `{
+		"range": {"from": "`+from+`", "to": "`+to+`"},
+		"targets": [{"refId": "A", "target": "checks"}]
+	}`, &tables)
+	assert.Len(t, tables, 1)
+	assert.Equal(t, "table", tables[0].Type)
+	assert.Len(t, tables[0].Rows, 2)
+
+	// The registration check failed twice with the same message, which is a single annotation
+	var annotations []grafanaAnnotation
+	postGrafana(t, server, "/annotations", `{"range": {"from": "`+from+`", "to": "`+to+`"}}`, &annotations)
+	assert.Len(t, annotations, 1)
+	assert.Equal(t, []string{RegistrationCheckName, string(StatusFailed)}, annotations[0].Tags)
+}
+
+func TestHistory(t *testing.T) {
+	start := time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)
+	h := newHistory(time.Hour)
+	for i := 0; i < 120; i++ {
+		h.add(start.Add(time.Duration(i)*time.Minute), map[string]float64{"up": float64(i)}, nil)
+	}
+
+	// Rounds older than the retention are dropped
+	points := h.datapoints("up", start, start.Add(3*time.Hour), 0)
+	assert.Len(t, points, 61)
+	assert.Equal(t, float64(59), points[0][0])
+
+	// Points are thinned out to the maximum number of points
+	points = h.datapoints("up", start, start.Add(3*time.Hour), 20)
+	assert.LessOrEqual(t, len(points), 20)
+	assert.Equal(t, float64(59), points[0][0])
+	assert.Equal(t, []string{"up"}, h.series())
+}
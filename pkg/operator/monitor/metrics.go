@@ -0,0 +1,116 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const metricsNamespace = "eigenlayer_operator_monitor"
+
+// Metrics are the Prometheus metrics published by the monitor
+type Metrics struct {
+	registry *prometheus.Registry
+
+	checkUp       *prometheus.GaugeVec
+	checkLastRun  *prometheus.GaugeVec
+	checkFailures *prometheus.CounterVec
+
+	UnclaimedRewards *prometheus.GaugeVec
+	RewardsAccrued   *prometheus.GaugeVec
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		checkUp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "check_up",
+			Help:      "1 if the last run of the check passed, 0 otherwise",
+		}, []string{"check"}),
+		checkLastRun: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "check_last_run_timestamp_seconds",
+			Help:      "Unix timestamp of the last run of the check",
+		}, []string{"check"}),
+		checkFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "check_failures_total",
+			Help:      "Number of runs of the check which did not pass",
+		}, []string{"check", "status"}),
+		UnclaimedRewards: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "unclaimed_rewards",
+			Help:      "Unclaimed rewards of the operator in the latest active distribution root, in wei",
+		}, []string{"token"}),
+		RewardsAccrued: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "rewards_accrued",
+			Help:      "Rewards accrued by the earner between the two latest distribution roots, in wei",
+		}, []string{"earner", "token"}),
+	}
+	m.registry.MustRegister(m.checkUp, m.checkLastRun, m.checkFailures, m.UnclaimedRewards, m.RewardsAccrued)
+	return m
+}
+
+func (m *Metrics) observe(result Result) {
+	if result.Status == StatusSkipped {
+		return
+	}
+	m.checkLastRun.WithLabelValues(result.Check).Set(float64(result.CheckedAt.Unix()))
+	if result.Status == StatusOK {
+		m.checkUp.WithLabelValues(result.Check).Set(1)
+		return
+	}
+	m.checkUp.WithLabelValues(result.Check).Set(0)
+	m.checkFailures.WithLabelValues(result.Check, string(result.Status)).Inc()
+}
+
+// Serve serves the metrics on /metrics and the results of the last round of checks on /health
+// until the context is cancelled. /health responds with 503 while any check does not pass, so
+// it can be used as a liveness or readiness probe of the sidecar. With Grafana enabled, the
+// history of the monitor is served as a Grafana JSON datasource on /grafana.
+func Serve(ctx context.Context, address string, m *Monitor, logger logging.Logger) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.metrics.registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		results := m.Results()
+		w.Header().Set("Content-Type", "application/json")
+		if ExitCode(results) != ExitCodeOK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(results)
+	})
+	if m.history != nil {
+		mux.Handle(GrafanaPath+"/", http.StripPrefix(GrafanaPath, newGrafanaHandler(m)))
+	}
+
+	server := &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Infof("Serving metrics on %s/metrics", address)
+	if m.history != nil {
+		logger.Infof("Serving the Grafana JSON datasource on %s%s", address, GrafanaPath)
+	}
+	err := server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,201 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+type Status string
+
+const (
+	// StatusOK means the check ran and the operator is healthy
+	StatusOK Status = "ok"
+	// StatusFailed means the check ran and found a problem with the operator
+	StatusFailed Status = "failed"
+	// StatusError means the check could not run, e.g. because the RPC is down
+	StatusError Status = "error"
+	// StatusSkipped means the check is not supported in this setup
+	StatusSkipped Status = "skipped"
+)
+
+// Exit codes of a single round of checks. 1 is left to the CLI for invalid input.
+const (
+	ExitCodeOK     = 0
+	ExitCodeFailed = 2
+	ExitCodeError  = 3
+)
+
+// Result is the outcome of a single check
+type Result struct {
+	Check     string    `json:"check"`
+	Status    Status    `json:"status"`
+	Message   string    `json:"message"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Check is a single health check of the operator
+type Check interface {
+	Name() string
+	Run(ctx context.Context) Result
+}
+
+// Monitor runs a set of checks periodically and publishes their results as metrics. Alerters are
+// notified when a check starts failing or fails for a new reason.
+type Monitor struct {
+	checks   []Check
+	metrics  *Metrics
+	alerters []Alerter
+	logger   logging.Logger
+	// heartbeatPath is written after every round of checks of Run, if it is set
+	heartbeatPath string
+	// history is served to Grafana, if it is set
+	history *history
+
+	mu      sync.RWMutex
+	results []Result
+	// failures holds the message of every check failing in the last round
+	failures map[string]string
+}
+
+func New(checks []Check, metrics *Metrics, logger logging.Logger) *Monitor {
+	return &Monitor{
+		checks:   checks,
+		metrics:  metrics,
+		logger:   logger,
+		failures: make(map[string]string),
+	}
+}
+
+// WithAlerter adds an alerter to the monitor
+func (m *Monitor) WithAlerter(alerter Alerter) *Monitor {
+	m.alerters = append(m.alerters, alerter)
+	return m
+}
+
+// WithHeartbeat makes the monitor write a heartbeat to path after every round of checks. The
+// heartbeat is removed when the monitor stops.
+func (m *Monitor) WithHeartbeat(path string) *Monitor {
+	m.heartbeatPath = path
+	return m
+}
+
+// WithGrafana makes the monitor keep the metrics and failed checks of every round for the
+// retention period, and serve them in the format of the Grafana JSON datasource
+func (m *Monitor) WithGrafana(retention time.Duration) *Monitor {
+	m.history = newHistory(retention)
+	return m
+}
+
+// Run runs the checks every interval until the context is cancelled
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.RunOnce(ctx)
+		m.writeHeartbeat()
+		select {
+		case <-ctx.Done():
+			m.removeHeartbeat()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce runs every check once and returns their results
+func (m *Monitor) RunOnce(ctx context.Context) []Result {
+	results := make([]Result, 0, len(m.checks))
+	for _, check := range m.checks {
+		result := check.Run(ctx)
+		result.Check = check.Name()
+		result.CheckedAt = time.Now().UTC()
+		m.log(result)
+		m.metrics.observe(result)
+		m.alert(ctx, result)
+		results = append(results, result)
+	}
+
+	m.mu.Lock()
+	m.results = results
+	m.mu.Unlock()
+	if m.history != nil {
+		m.history.add(time.Now().UTC(), m.metrics.values(), results)
+	}
+	return results
+}
+
+// Results returns the results of the last round of checks
+func (m *Monitor) Results() []Result {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.results
+}
+
+func (m *Monitor) writeHeartbeat() {
+	if m.heartbeatPath == "" {
+		return
+	}
+	if err := common.WriteHeartbeat(m.heartbeatPath, "operator monitor", time.Now()); err != nil {
+		m.logger.Warnf("Failed to write heartbeat: %s", err)
+	}
+}
+
+func (m *Monitor) removeHeartbeat() {
+	if m.heartbeatPath == "" {
+		return
+	}
+	if err := common.RemoveHeartbeat(m.heartbeatPath); err != nil {
+		m.logger.Warnf("Failed to remove heartbeat: %s", err)
+	}
+}
+
+// alert notifies the alerters if the check started failing or fails with a new message, so
+// a lasting problem is only reported once
+func (m *Monitor) alert(ctx context.Context, result Result) {
+	if result.Status != StatusFailed {
+		delete(m.failures, result.Check)
+		return
+	}
+	if message, ok := m.failures[result.Check]; ok && message == result.Message {
+		return
+	}
+	m.failures[result.Check] = result.Message
+
+	m.logger.Errorf("ALERT: check %s failed: %s", result.Check, result.Message)
+	for _, alerter := range m.alerters {
+		if err := alerter.Alert(ctx, result); err != nil {
+			m.logger.Warnf("Failed to send alert for check %s: %s", result.Check, err)
+		}
+	}
+}
+
+func (m *Monitor) log(result Result) {
+	switch result.Status {
+	case StatusOK:
+		m.logger.Infof("Check %s passed: %s", result.Check, result.Message)
+	case StatusSkipped:
+		m.logger.Debugf("Check %s skipped: %s", result.Check, result.Message)
+	default:
+		m.logger.Warnf("Check %s %s: %s", result.Check, result.Status, result.Message)
+	}
+}
+
+// ExitCode returns the exit code for a round of checks. A failed check takes precedence over
+// a check which could not run, since it is a known problem with the operator.
+func ExitCode(results []Result) int {
+	code := ExitCodeOK
+	for _, result := range results {
+		switch result.Status {
+		case StatusFailed:
+			return ExitCodeFailed
+		case StatusError:
+			code = ExitCodeError
+		}
+	}
+	return code
+}
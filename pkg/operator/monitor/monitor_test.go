@@ -0,0 +1,235 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/rewards"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigensdkTypes "github.com/Layr-Labs/eigensdk-go/types"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRegistrationReader struct {
+	registered bool
+	err        error
+}
+
+func (f *fakeRegistrationReader) IsOperatorRegistered(
+	ctx context.Context,
+	operator eigensdkTypes.Operator,
+) (bool, error) {
+	return f.registered, f.err
+}
+
+func TestRegistrationCheck(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress("0x1")
+
+	tests := []struct {
+		name   string
+		reader *fakeRegistrationReader
+		status Status
+	}{
+		{name: "registered", reader: &fakeRegistrationReader{registered: true}, status: StatusOK},
+		{name: "not registered", reader: &fakeRegistrationReader{registered: false}, status: StatusFailed},
+		{name: "rpc error", reader: &fakeRegistrationReader{err: errors.New("rpc down")}, status: StatusError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewRegistrationCheck(tt.reader, operatorAddress).Run(context.Background())
+			assert.Equal(t, tt.status, result.Status)
+		})
+	}
+}
+
+func TestMetadataCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/valid.json":
+			_, _ = w.Write([]byte(`{"name": "operator", "website": "https://example.com"}`))
+		case "/invalid.json":
+			_, _ = w.Write([]byte(`not json`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	operatorAddress := gethcommon.HexToAddress("0x1")
+	tests := []struct {
+		name   string
+		url    string
+		status Status
+	}{
+		{name: "valid metadata", url: server.URL + "/valid.json", status: StatusOK},
+		{name: "invalid metadata", url: server.URL + "/invalid.json", status: StatusFailed},
+		{name: "not found", url: server.URL + "/missing.json", status: StatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewMetadataCheck(nil, nil, operatorAddress, tt.url).Run(context.Background())
+			assert.Equal(t, tt.status, result.Status, result.Message)
+		})
+	}
+}
+
+func TestRunOnce(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	operatorAddress := gethcommon.HexToAddress("0x1")
+	m := New([]Check{
+		NewRegistrationCheck(&fakeRegistrationReader{registered: true}, operatorAddress),
+		&fakeCheck{results: []Result{{Status: StatusSkipped}}},
+	}, NewMetrics(), logger)
+
+	results := m.RunOnce(context.Background())
+	assert.Len(t, results, 2)
+	assert.Equal(t, RegistrationCheckName, results[0].Check)
+	assert.Equal(t, StatusSkipped, results[1].Status)
+	assert.Equal(t, results, m.Results())
+	assert.Equal(t, ExitCodeOK, ExitCode(results))
+}
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, ExitCodeOK, ExitCode([]Result{{Status: StatusOK}, {Status: StatusSkipped}}))
+	assert.Equal(t, ExitCodeError, ExitCode([]Result{{Status: StatusOK}, {Status: StatusError}}))
+	assert.Equal(t, ExitCodeFailed, ExitCode([]Result{{Status: StatusError}, {Status: StatusFailed}}))
+}
+
+type fakeCheck struct {
+	results []Result
+	runs    int
+}
+
+func (c *fakeCheck) Name() string {
+	return "fake"
+}
+
+func (c *fakeCheck) Run(ctx context.Context) Result {
+	result := c.results[c.runs]
+	c.runs++
+	return result
+}
+
+func TestAlerts(t *testing.T) {
+	alerts := make([]Result, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result Result
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&result))
+		alerts = append(alerts, result)
+	}))
+	defer server.Close()
+
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	check := &fakeCheck{results: []Result{
+		{Status: StatusOK},
+		{Status: StatusFailed, Message: "claimer changed"},
+		{Status: StatusFailed, Message: "claimer changed"},
+		{Status: StatusFailed, Message: "claimer changed again"},
+		{Status: StatusOK},
+		{Status: StatusFailed, Message: "claimer changed"},
+	}}
+	m := New([]Check{check}, NewMetrics(), logger).WithAlerter(NewWebhookAlerter(server.URL))
+	for range check.results {
+		m.RunOnce(context.Background())
+	}
+
+	// A lasting failure is only alerted once, a new reason or a new failure is alerted again
+	assert.Len(t, alerts, 3)
+	assert.Equal(t, "claimer changed", alerts[0].Message)
+	assert.Equal(t, "claimer changed again", alerts[1].Message)
+	assert.Equal(t, "fake", alerts[2].Check)
+}
+
+type fakeSnapshotReader struct {
+	active   *rewards.RewardsSnapshot
+	upcoming *rewards.RewardsSnapshot
+}
+
+func (f *fakeSnapshotReader) GetLatestSnapshot(
+	ctx context.Context,
+	earnerAddress gethcommon.Address,
+) (*rewards.RewardsSnapshot, error) {
+	return f.active, nil
+}
+
+func (f *fakeSnapshotReader) GetUpcomingSnapshot(
+	ctx context.Context,
+	earnerAddress gethcommon.Address,
+) (*rewards.RewardsSnapshot, error) {
+	return f.upcoming, nil
+}
+
+func TestAccrualCheck(t *testing.T) {
+	earner := gethcommon.HexToAddress("0x1")
+	eigen := gethcommon.HexToAddress("0xe1")
+	weth := gethcommon.HexToAddress("0xe2")
+	snapshot := func(rootIndex uint32, eigenTotal, wethTotal int64) *rewards.RewardsSnapshot {
+		return &rewards.RewardsSnapshot{
+			RootIndex: rootIndex,
+			Total:     map[gethcommon.Address]*big.Int{eigen: big.NewInt(eigenTotal), weth: big.NewInt(wethTotal)},
+		}
+	}
+
+	reader := &fakeSnapshotReader{active: snapshot(1, 100, 50), upcoming: snapshot(2, 150, 50)}
+	check := NewAccrualCheck(reader, []gethcommon.Address{earner}, nil, NewMetrics())
+	result := check.Run(context.Background())
+	assert.Equal(t, StatusFailed, result.Status)
+	assert.Contains(t, result.Message, weth.Hex())
+	assert.NotContains(t, result.Message, eigen.Hex())
+
+	// The result holds until the next root
+	assert.Equal(t, StatusFailed, check.Run(context.Background()).Status)
+
+	reader.upcoming = snapshot(3, 200, 60)
+	assert.Equal(t, StatusOK, check.Run(context.Background()).Status)
+
+	// Only the expected tokens are checked
+	reader.upcoming = snapshot(4, 250, 60)
+	check = NewAccrualCheck(reader, []gethcommon.Address{earner}, []gethcommon.Address{eigen}, NewMetrics())
+	reader.active = snapshot(3, 200, 60)
+	assert.Equal(t, StatusOK, check.Run(context.Background()).Status)
+}
+
+type fakeHeadReader uint64
+
+func (f fakeHeadReader) BlockNumber(ctx context.Context) (uint64, error) {
+	return uint64(f), nil
+}
+
+func TestFilterChunks(t *testing.T) {
+	ranges := make([][2]uint64, 0)
+	filter := func(opts *bind.FilterOpts) error {
+		ranges = append(ranges, [2]uint64{opts.Start, *opts.End})
+		if opts.Start >= 2*logsChunkBlocks {
+			return errors.New("mock error")
+		}
+		return nil
+	}
+
+	next, err := filterChunks(context.Background(), fakeHeadReader(logsChunkBlocks+5), 0, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(logsChunkBlocks+6), next)
+	assert.Equal(t, [][2]uint64{{0, logsChunkBlocks - 1}, {logsChunkBlocks, logsChunkBlocks + 5}}, ranges)
+
+	// Nothing to read before a new block
+	ranges = ranges[:0]
+	next, err = filterChunks(context.Background(), fakeHeadReader(logsChunkBlocks+5), next, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(logsChunkBlocks+6), next)
+	assert.Empty(t, ranges)
+
+	// A failed chunk is read again next time
+	next, err = filterChunks(context.Background(), fakeHeadReader(3*logsChunkBlocks), next, filter)
+	assert.Error(t, err)
+	assert.Equal(t, uint64(2*logsChunkBlocks+6), next)
+}
@@ -0,0 +1,28 @@
+package monitor
+
+import (
+	"math/big"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+type Config struct {
+	Network                   string
+	RPCUrl                    string
+	ChainID                   *big.Int
+	OperatorAddress           gethcommon.Address
+	MetadataURL               string
+	DelegationManagerAddress  gethcommon.Address
+	RewardsCoordinatorAddress gethcommon.Address
+	AllocationManagerAddress  gethcommon.Address
+	Interval                  time.Duration
+	MetricsAddress            string
+	Once                      bool
+	EarnerAddresses           []gethcommon.Address
+	ExpectedClaimers          []gethcommon.Address
+	AccrualTokens             []gethcommon.Address
+	AlertWebhookURL           string
+	Grafana                   bool
+	GrafanaRetention          time.Duration
+}
@@ -2,122 +2,283 @@ package operator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/types"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
 	elContracts "github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/logging"
 	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
 
 	gethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/urfave/cli/v2"
 )
 
+const registerCommandName = "operator register"
+
 func RegisterCmd(p utils.Prompter) *cli.Command {
 	registerCmd := &cli.Command{
 		Name:      "register",
 		Usage:     "Register the operator to EigenLayer contracts",
-		UsageText: "register <configuration-file>",
+		UsageText: "register <configuration-file> [<configuration-file>...]",
 		Description: `
 		Register command expects a yaml config file as an argument
 		to successfully register an operator address to eigenlayer
 
 		This will register operator to DelegationManager
+
+		Many operators can be registered at once by passing one config file per
+		operator. With --resume the progress is written to a state file, and an
+		interrupted run continues where it stopped, skipping registered operators
 		`,
 		After: telemetry.AfterRunAction(),
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&flags.VerboseFlag,
-		},
+			&flags.ResumeFlag,
+		}, flags.GetPlanFlags()...),
 		Action: func(cCtx *cli.Context) error {
 			logger := common.GetLogger(cCtx)
 
 			args := cCtx.Args()
-			if args.Len() != 1 {
-				return fmt.Errorf("%w: accepts 1 arg, received %d", ErrInvalidNumberOfArgs, args.Len())
+			if args.Len() < 1 {
+				return fmt.Errorf("%w: accepts at least 1 arg, received %d", ErrInvalidNumberOfArgs, args.Len())
 			}
 
-			configurationFilePath := args.Get(0)
-			operatorCfg, err := common.ValidateAndReturnConfig(configurationFilePath, logger)
+			planConfig, err := common.ReadPlanConfig(cCtx)
 			if err != nil {
 				return err
 			}
-			cCtx.App.Metadata["network"] = operatorCfg.ChainId.String()
-
-			logger.Infof(
-				"%s Operator configuration file validated successfully %s",
-				utils.EmojiCheckMark,
-				operatorCfg.Operator.Address,
-			)
-
-			ctx := context.Background()
 
-			ethClient, err := ethclient.Dial(operatorCfg.EthRPCUrl)
-			if err != nil {
+			resumeFile := cCtx.String(flags.ResumeFlag.Name)
+			if args.Len() == 1 && common.IsEmptyString(resumeFile) {
+				operatorCfg, err := readRegisterConfig(cCtx, args.Get(0), logger)
+				if err != nil {
+					return err
+				}
+				_, err = registerOperator(p, operatorCfg, planConfig, logger, nil)
 				return err
 			}
 
-			contractCfg := elcontracts.Config{
-				DelegationManagerAddress: gethcommon.HexToAddress(operatorCfg.ELDelegationManagerAddress),
-				AvsDirectoryAddress:      gethcommon.HexToAddress(operatorCfg.ELAVSDirectoryAddress),
+			// A plan is for one signer, while every operator of a batch signs its own registration
+			if planConfig.IsPlan() || planConfig.IsApply() {
+				return errors.New("--plan and --apply register one operator at a time")
 			}
+			return registerOperators(cCtx, p, args.Slice(), resumeFile, logger)
+		},
+	}
 
-			elWriter, err := common.GetELWriter(
-				gethcommon.HexToAddress(operatorCfg.Operator.Address),
-				&operatorCfg.SignerConfig,
-				ethClient,
-				contractCfg,
-				p,
-				&operatorCfg.ChainId,
-				logger,
-			)
+	return registerCmd
+}
 
-			if err != nil {
-				return eigenSdkUtils.WrapError("failed to get EL writer", err)
-			}
+func readRegisterConfig(
+	cCtx *cli.Context,
+	configurationFilePath string,
+	logger logging.Logger,
+) (*types.OperatorConfig, error) {
+	operatorCfg, err := common.ValidateAndReturnConfig(configurationFilePath, logger)
+	if err != nil {
+		return nil, err
+	}
+	cCtx.App.Metadata["network"] = operatorCfg.ChainId.String()
 
-			elReader, err := elContracts.NewReaderFromConfig(
-				contractCfg,
-				ethClient,
-				logger,
-			)
-			if err != nil {
-				return err
-			}
+	logger.Infof(
+		"%s Operator configuration file validated successfully %s",
+		utils.EmojiCheckMark,
+		operatorCfg.Operator.Address,
+	)
+	return operatorCfg, nil
+}
 
-			status, err := elReader.IsOperatorRegistered(ctx, operatorCfg.Operator)
+// registerOperators registers the operators of the config files one after the other. With a
+// state file every registration is recorded, so an interrupted run can be resumed.
+func registerOperators(
+	cCtx *cli.Context,
+	p utils.Prompter,
+	configurationFilePaths []string,
+	resumeFile string,
+	logger logging.Logger,
+) error {
+	var state *common.BatchState
+	if !common.IsEmptyString(resumeFile) {
+		input := make([]byte, 0)
+		for _, path := range configurationFilePaths {
+			data, err := os.ReadFile(filepath.Clean(path))
 			if err != nil {
 				return err
 			}
+			input = append(input, data...)
+		}
+		var err error
+		state, err = common.LoadBatchState(resumeFile, registerCommandName, common.HashBatchInput(input), nil)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to load batch state", err)
+		}
+	}
 
-			if !status {
-				receipt, err := elWriter.RegisterAsOperator(ctx, operatorCfg.Operator, true)
-				if err != nil {
-					return err
-				}
+	for idx, path := range configurationFilePaths {
+		operatorCfg, err := readRegisterConfig(cCtx, path, logger)
+		if err != nil {
+			return err
+		}
+		// Items are keyed by position too, since the same operator can be in a batch more than once
+		key := fmt.Sprintf("%d:%s", idx, gethcommon.HexToAddress(operatorCfg.Operator.Address).Hex())
+		if state != nil && state.IsCompleted(key) {
+			logger.Infof("Skipping registered operator %s", operatorCfg.Operator.Address)
+			continue
+		}
 
-				common.PrintRegistrationInfo(
-					receipt.TxHash.String(),
-					gethcommon.HexToAddress(operatorCfg.Operator.Address),
-					&operatorCfg.ChainId,
-				)
-			} else {
-				logger.Infof("%s Operator is already registered on EigenLayer", utils.EmojiCheckMark)
-				return nil
+		var beforeSend func() error
+		if state != nil {
+			beforeSend = func() error {
+				return state.MarkPending([]string{key})
 			}
-
-			logger.Infof(
-				"%s Operator is registered successfully to EigenLayer. There is a 30 minute delay between registration and operator details being shown in our webapp.",
-				utils.EmojiCheckMark,
+		}
+		// A pending registration which landed before the run was interrupted is found registered
+		// on chain, and completed with an empty transaction hash
+		txHash, err := registerOperator(p, operatorCfg, &common.PlanConfig{}, logger, beforeSend)
+		if err != nil && state != nil {
+			return eigenSdkUtils.WrapError(
+				fmt.Sprintf("failed to register operator, resume the batch with --resume %s", resumeFile),
+				err,
 			)
-			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if state != nil {
+			if err := state.MarkCompleted([]string{key}, txHash); err != nil {
+				return eigenSdkUtils.WrapError("failed to write batch state", err)
+			}
+		}
+	}
+
+	logger.Infof("%s All %d operators are registered", utils.EmojiCheckMark, len(configurationFilePaths))
+	return nil
+}
+
+// registerOperator registers the operator of the config, or adds its registration to the plan.
+// It returns the hash of the registration transaction, which is empty if the operator was
+// registered already. beforeSend, if set, runs before the registration is sent.
+func registerOperator(
+	p utils.Prompter,
+	operatorCfg *types.OperatorConfig,
+	planConfig *common.PlanConfig,
+	logger logging.Logger,
+	beforeSend func() error,
+) (string, error) {
+	ctx := context.Background()
+
+	ethClient, err := common.DialEthClient(operatorCfg.EthRPCUrl)
+	if err != nil {
+		return "", err
+	}
+
+	contractCfg := elcontracts.Config{
+		DelegationManagerAddress: gethcommon.HexToAddress(operatorCfg.ELDelegationManagerAddress),
+		AvsDirectoryAddress:      gethcommon.HexToAddress(operatorCfg.ELAVSDirectoryAddress),
+	}
+
+	if planConfig.IsApply() {
+		return "", common.ApplyTxPlan(
+			context.Background(),
+			planConfig,
+			registerCommandName,
+			gethcommon.HexToAddress(operatorCfg.Operator.Address),
+			&operatorCfg.SignerConfig,
+			ethClient,
+			p,
+			&operatorCfg.ChainId,
+			logger,
+		)
+	}
+
+	elWriter, txPlan, err := common.GetELWriterWithPlan(
+		planConfig,
+		registerCommandName,
+		map[string]string{
+			"operator":    operatorCfg.Operator.Address,
+			"metadataUri": operatorCfg.Operator.MetadataUrl,
 		},
+		gethcommon.HexToAddress(operatorCfg.Operator.Address),
+		&operatorCfg.SignerConfig,
+		ethClient,
+		contractCfg,
+		p,
+		&operatorCfg.ChainId,
+		logger,
+	)
+
+	if err != nil {
+		return "", eigenSdkUtils.WrapError("failed to get EL writer", err)
 	}
 
-	return registerCmd
+	elReader, err := elContracts.NewReaderFromConfig(
+		contractCfg,
+		ethClient,
+		logger,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	status, err := elReader.IsOperatorRegistered(ctx, operatorCfg.Operator)
+	if err != nil {
+		return "", err
+	}
+
+	if status {
+		logger.Infof("%s Operator is already registered on EigenLayer", utils.EmojiCheckMark)
+		return "", nil
+	}
+
+	if beforeSend != nil {
+		if err := beforeSend(); err != nil {
+			return "", eigenSdkUtils.WrapError("failed to write batch state", err)
+		}
+	}
+
+	receipt, err := elWriter.RegisterAsOperator(ctx, operatorCfg.Operator, true)
+	if err != nil {
+		return "", err
+	}
+
+	if txPlan != nil {
+		return "", txPlan.Write(planConfig.PlanFile, logger)
+	}
+	if err := checkRegistrationReceipt(receipt); err != nil {
+		common.PrintTransactionInfo(receipt.TxHash.String(), &operatorCfg.ChainId)
+		return "", err
+	}
+
+	common.PrintRegistrationInfo(
+		receipt.TxHash.String(),
+		gethcommon.HexToAddress(operatorCfg.Operator.Address),
+		&operatorCfg.ChainId,
+	)
+	common.PrintReceiptEvents(receipt, string(common.OutputType_Pretty))
+
+	logger.Infof(
+		"%s Operator is registered successfully to EigenLayer. There is a 30 minute delay between registration and operator details being shown in our webapp.",
+		utils.EmojiCheckMark,
+	)
+	return receipt.TxHash.Hex(), nil
+}
+
+// checkRegistrationReceipt fails for a reverted registration, so it's neither reported as
+// registered nor recorded as completed in a batch
+func checkRegistrationReceipt(receipt *gethtypes.Receipt) error {
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		return fmt.Errorf("%w: %s", ErrRegistrationReverted, receipt.TxHash.Hex())
+	}
+	return nil
 }
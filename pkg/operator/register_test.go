@@ -0,0 +1,20 @@
+package operator
+
+import (
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRegistrationReceipt(t *testing.T) {
+	txHash := gethcommon.HexToHash("0x1")
+	err := checkRegistrationReceipt(&gethtypes.Receipt{Status: gethtypes.ReceiptStatusSuccessful, TxHash: txHash})
+	assert.NoError(t, err)
+
+	err = checkRegistrationReceipt(&gethtypes.Receipt{Status: gethtypes.ReceiptStatusFailed, TxHash: txHash})
+	assert.ErrorIs(t, err, ErrRegistrationReverted)
+	assert.ErrorContains(t, err, txHash.Hex())
+}
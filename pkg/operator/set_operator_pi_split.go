@@ -39,6 +39,7 @@ func getSetOperatorPISplitFlags() []cli.Flag {
 	}
 
 	allFlags := append(baseFlags, flags.GetSignerFlags()...)
+	allFlags = append(allFlags, flags.GetPlanFlags()...)
 	sort.Sort(cli.FlagsByName(allFlags))
 	return allFlags
 }
@@ -16,10 +16,14 @@ import (
 	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/urfave/cli/v2"
 )
 
+const (
+	setOperatorSplitCommandName   = "operator set-rewards-split"
+	setOperatorPISplitCommandName = "operator set-pi-split"
+)
+
 func SetOperatorSplitCmd(p utils.Prompter) *cli.Command {
 	var operatorSplitCmd = &cli.Command{
 		Name:  "set-rewards-split",
@@ -49,14 +53,42 @@ func SetOperatorSplit(cCtx *cli.Context, p utils.Prompter, isProgrammaticIncenti
 
 	cCtx.App.Metadata["network"] = config.ChainID.String()
 
-	ethClient, err := ethclient.Dial(config.RPCUrl)
+	ethClient, err := common.DialEthClient(config.RPCUrl)
 	if err != nil {
 		return eigenSdkUtils.WrapError("failed to create new eth client", err)
 	}
 
-	if config.Broadcast {
+	commandName := setOperatorSplitCommandName
+	if isProgrammaticIncentive {
+		commandName = setOperatorPISplitCommandName
+	}
+
+	if config.PlanConfig.IsApply() {
+		return common.ApplyTxPlan(
+			ctx,
+			config.PlanConfig,
+			commandName,
+			config.OperatorAddress,
+			config.SignerConfig,
+			ethClient,
+			p,
+			config.ChainID,
+			logger,
+		)
+	}
 
-		eLWriter, err := common.GetELWriter(
+	if config.Broadcast || config.PlanConfig.IsPlan() {
+		params := map[string]string{
+			"operator": config.OperatorAddress.Hex(),
+			"split":    fmt.Sprintf("%d", config.Split),
+		}
+		if !isProgrammaticIncentive {
+			params["avs"] = config.AVSAddress.Hex()
+		}
+		eLWriter, txPlan, err := common.GetELWriterWithPlan(
+			config.PlanConfig,
+			commandName,
+			params,
 			config.OperatorAddress,
 			config.SignerConfig,
 			ethClient,
@@ -72,7 +104,9 @@ func SetOperatorSplit(cCtx *cli.Context, p utils.Prompter, isProgrammaticIncenti
 			return eigenSdkUtils.WrapError("failed to get EL writer", err)
 		}
 
-		logger.Infof("Broadcasting set operator transaction...")
+		if txPlan == nil {
+			logger.Infof("Broadcasting set operator transaction...")
+		}
 
 		var receipt *types.Receipt
 		if isProgrammaticIncentive {
@@ -85,8 +119,13 @@ func SetOperatorSplit(cCtx *cli.Context, p utils.Prompter, isProgrammaticIncenti
 			return eigenSdkUtils.WrapError("failed to process claim", err)
 		}
 
+		if txPlan != nil {
+			return txPlan.Write(config.PlanConfig.PlanFile, logger)
+		}
+
 		logger.Infof("Set operator transaction submitted successfully")
 		common.PrintTransactionInfo(receipt.TxHash.String(), config.ChainID)
+		common.PrintReceiptEvents(receipt, config.OutputType)
 	} else {
 		noSendTxOpts := common.GetNoSendTxOpts(config.OperatorAddress)
 		_, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
@@ -157,6 +196,7 @@ func getSetOperatorSplitFlags() []cli.Flag {
 	}
 
 	allFlags := append(baseFlags, flags.GetSignerFlags()...)
+	allFlags = append(allFlags, flags.GetPlanFlags()...)
 	sort.Sort(cli.FlagsByName(allFlags))
 	return allFlags
 }
@@ -174,9 +214,13 @@ func readAndValidateSetOperatorSplitConfig(
 	outputFile := cCtx.String(flags.OutputFileFlag.Name)
 	isSilent := cCtx.Bool(flags.SilentFlag.Name)
 
+	planConfig, err := common.ReadPlanConfig(cCtx)
+	if err != nil {
+		return nil, err
+	}
+
 	rewardsCoordinatorAddress := cCtx.String(rewards.RewardsCoordinatorAddressFlag.Name)
 
-	var err error
 	if common.IsEmptyString(rewardsCoordinatorAddress) {
 		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(utils.NetworkNameToChainId(network))
 		if err != nil {
@@ -218,5 +262,6 @@ func readAndValidateSetOperatorSplitConfig(
 		OutputType:                outputType,
 		OutputFile:                outputFile,
 		IsSilent:                  isSilent,
+		PlanConfig:                planConfig,
 	}, nil
 }
@@ -3,6 +3,7 @@ package split
 import (
 	"math/big"
 
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/types"
 	gethcommon "github.com/ethereum/go-ethereum/common"
 )
@@ -20,6 +21,7 @@ type SetOperatorAVSSplitConfig struct {
 	OutputType                string
 	OutputFile                string
 	IsSilent                  bool
+	PlanConfig                *common.PlanConfig
 }
 
 type GetOperatorAVSSplitConfig struct {
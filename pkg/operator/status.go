@@ -14,7 +14,6 @@ import (
 	eigensdkTypes "github.com/Layr-Labs/eigensdk-go/types"
 
 	gethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/urfave/cli/v2"
 )
@@ -59,7 +58,7 @@ func StatusCmd(p utils.Prompter) *cli.Command {
 				return fmt.Errorf("%w: with error %s", ErrInvalidYamlFile, err)
 			}
 
-			ethClient, err := ethclient.Dial(operatorCfg.EthRPCUrl)
+			ethClient, err := common.DialEthClient(operatorCfg.EthRPCUrl)
 			if err != nil {
 				return err
 			}
@@ -127,8 +126,8 @@ func StatusCmd(p utils.Prompter) *cli.Command {
 func printOperatorDetails(operator eigensdkTypes.Operator) {
 	fmt.Println()
 	fmt.Println("--------------------------- Operator Details ---------------------------")
-	fmt.Printf("Address: %s\n", operator.Address)
-	fmt.Printf("Delegation Approver Address: %s\n", operator.DelegationApproverAddress)
+	fmt.Printf("Address: %s\n", common.RedactText(operator.Address))
+	fmt.Printf("Delegation Approver Address: %s\n", common.RedactText(operator.DelegationApproverAddress))
 	fmt.Printf("Staker Opt Out Window Blocks: %d\n", operator.StakerOptOutWindowBlocks)
 	fmt.Println("------------------------------------------------------------------------")
 	fmt.Println()
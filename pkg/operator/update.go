@@ -13,11 +13,12 @@ import (
 	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
 
 	gethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/urfave/cli/v2"
 )
 
+const updateCommandName = "operator update"
+
 func UpdateCmd(p utils.Prompter) *cli.Command {
 	updateCmd := &cli.Command{
 		Name:      "update",
@@ -32,9 +33,9 @@ Updates the operator metadata onchain which includes
 Requires the same file used for registration as argument
 This command only updates above details. To update metadata URI, use eigenlayer operator update-metadata-uri command
 		`,
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&flags.VerboseFlag,
-		},
+		}, flags.GetPlanFlags()...),
 		After: telemetry.AfterRunAction(),
 		Action: func(cCtx *cli.Context) error {
 			logger := common.GetLogger(cCtx)
@@ -51,13 +52,18 @@ This command only updates above details. To update metadata URI, use eigenlayer
 			}
 			cCtx.App.Metadata["network"] = operatorCfg.ChainId.String()
 
+			planConfig, err := common.ReadPlanConfig(cCtx)
+			if err != nil {
+				return err
+			}
+
 			logger.Infof(
 				"%s Operator configuration file validated successfully %s",
 				utils.EmojiCheckMark,
 				operatorCfg.Operator.Address,
 			)
 
-			ethClient, err := ethclient.Dial(operatorCfg.EthRPCUrl)
+			ethClient, err := common.DialEthClient(operatorCfg.EthRPCUrl)
 			if err != nil {
 				return err
 			}
@@ -67,7 +73,28 @@ This command only updates above details. To update metadata URI, use eigenlayer
 				AvsDirectoryAddress:      gethcommon.HexToAddress(operatorCfg.ELAVSDirectoryAddress),
 			}
 
-			elWriter, err := common.GetELWriter(
+			if planConfig.IsApply() {
+				return common.ApplyTxPlan(
+					context.Background(),
+					planConfig,
+					updateCommandName,
+					gethcommon.HexToAddress(operatorCfg.Operator.Address),
+					&operatorCfg.SignerConfig,
+					ethClient,
+					p,
+					&operatorCfg.ChainId,
+					logger,
+				)
+			}
+
+			elWriter, txPlan, err := common.GetELWriterWithPlan(
+				planConfig,
+				updateCommandName,
+				map[string]string{
+					"operator":                 operatorCfg.Operator.Address,
+					"delegationApprover":       operatorCfg.Operator.DelegationApproverAddress,
+					"stakerOptOutWindowBlocks": fmt.Sprintf("%d", operatorCfg.Operator.StakerOptOutWindowBlocks),
+				},
 				gethcommon.HexToAddress(operatorCfg.Operator.Address),
 				&operatorCfg.SignerConfig,
 				ethClient,
@@ -85,6 +112,9 @@ This command only updates above details. To update metadata URI, use eigenlayer
 			if err != nil {
 				return err
 			}
+			if txPlan != nil {
+				return txPlan.Write(planConfig.PlanFile, logger)
+			}
 			logger.Infof(
 				"%s Operator details updated at: %s",
 				utils.EmojiCheckMark,
@@ -95,6 +125,7 @@ This command only updates above details. To update metadata URI, use eigenlayer
 				gethcommon.HexToAddress(operatorCfg.Operator.Address),
 				&operatorCfg.ChainId,
 			)
+			common.PrintReceiptEvents(receipt, string(common.OutputType_Pretty))
 
 			logger.Infof(
 				"%s Operator details updated successfully. There is a 30 minute delay between update and operator details being shown in our webapp.",
@@ -13,11 +13,12 @@ import (
 	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
 
 	gethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/urfave/cli/v2"
 )
 
+const updateMetadataURICommandName = "operator update-metadata-uri"
+
 func UpdateMetadataURICmd(p utils.Prompter) *cli.Command {
 	updateMetadataURICmd := &cli.Command{
 		Name:      "update-metadata-uri",
@@ -29,9 +30,9 @@ Updates the operator metadata uri onchain
 Requires the same file used for registration as argument
 		`,
 		After: telemetry.AfterRunAction(),
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&flags.VerboseFlag,
-		},
+		}, flags.GetPlanFlags()...),
 		Action: func(cCtx *cli.Context) error {
 			logger := common.GetLogger(cCtx)
 
@@ -47,13 +48,18 @@ Requires the same file used for registration as argument
 			}
 			cCtx.App.Metadata["network"] = operatorCfg.ChainId.String()
 
+			planConfig, err := common.ReadPlanConfig(cCtx)
+			if err != nil {
+				return err
+			}
+
 			logger.Infof(
 				"%s Operator configuration file validated successfully %s",
 				utils.EmojiCheckMark,
 				operatorCfg.Operator.Address,
 			)
 
-			ethClient, err := ethclient.Dial(operatorCfg.EthRPCUrl)
+			ethClient, err := common.DialEthClient(operatorCfg.EthRPCUrl)
 			if err != nil {
 				return err
 			}
@@ -63,7 +69,27 @@ Requires the same file used for registration as argument
 				AvsDirectoryAddress:      gethcommon.HexToAddress(operatorCfg.ELAVSDirectoryAddress),
 			}
 
-			elWriter, err := common.GetELWriter(
+			if planConfig.IsApply() {
+				return common.ApplyTxPlan(
+					context.Background(),
+					planConfig,
+					updateMetadataURICommandName,
+					gethcommon.HexToAddress(operatorCfg.Operator.Address),
+					&operatorCfg.SignerConfig,
+					ethClient,
+					p,
+					&operatorCfg.ChainId,
+					logger,
+				)
+			}
+
+			elWriter, txPlan, err := common.GetELWriterWithPlan(
+				planConfig,
+				updateMetadataURICommandName,
+				map[string]string{
+					"operator":    operatorCfg.Operator.Address,
+					"metadataUri": operatorCfg.Operator.MetadataUrl,
+				},
 				gethcommon.HexToAddress(operatorCfg.Operator.Address),
 				&operatorCfg.SignerConfig,
 				ethClient,
@@ -82,6 +108,9 @@ Requires the same file used for registration as argument
 				fmt.Printf("%s Error while updating operator metadata uri\n", utils.EmojiCrossMark)
 				return err
 			}
+			if txPlan != nil {
+				return txPlan.Write(planConfig.PlanFile, logger)
+			}
 			logger.Infof(
 				"%s Operator metadata uri updated at: %s",
 				utils.EmojiCheckMark,
@@ -93,6 +122,7 @@ Requires the same file used for registration as argument
 				gethcommon.HexToAddress(operatorCfg.Operator.Address),
 				&operatorCfg.ChainId,
 			)
+			common.PrintReceiptEvents(receipt, string(common.OutputType_Pretty))
 
 			logger.Infof(
 				"%s Operator metadata uri successfully. There is a 30 minute delay between update and operator metadata being shown in our webapp.",
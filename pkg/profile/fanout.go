@@ -0,0 +1,425 @@
+package profile
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	profileColumn = "profile"
+	errorColumn   = "error"
+	sectionColumn = "section"
+
+	// maxRecordWidth wraps long values, e.g. errors, so merged tables stay readable
+	maxRecordWidth = 66
+)
+
+var ErrProfilesFailed = errors.New("command failed for some profiles")
+
+// Result is the output of a command run for a single profile
+type Result struct {
+	Profile string
+	Records []map[string]interface{}
+	Err     error
+}
+
+// IsFanOut returns true if the command should be run for several profiles
+func IsFanOut(cCtx *cli.Context) bool {
+	return cCtx.Bool(AllProfilesFlag.Name) || !common.IsEmptyString(cCtx.String(ProfilesFlag.Name))
+}
+
+// FanOut runs the current command once per selected profile and prints the merged results,
+// with a profile column added to every record. Each profile runs in its own process so profiles
+// can't leak flag values into each other. Only read-only commands with json output support this.
+func FanOut(cCtx *cli.Context) error {
+	names, err := getFanOutProfiles(cCtx)
+	if err != nil {
+		return err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the eigenlayer executable: %w", err)
+	}
+	env := getChildEnv()
+	args := filterArgs(os.Args[1:])
+
+	results := make([]Result, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			records, err := runProfile(cCtx.Context, executable, env, args, cCtx.String(ProfilesFileFlag.Name), name)
+			results[i] = Result{Profile: name, Records: records, Err: err}
+		}(i, name)
+	}
+	wg.Wait()
+
+	records := MergeResults(results)
+	err = writeRecords(cCtx, records)
+	if err != nil {
+		return err
+	}
+
+	failed := make([]string, 0)
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result.Profile)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %s", ErrProfilesFailed, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// MergeResults flattens the results of all profiles into one list of records, keeping the
+// profile order. A failed profile is represented by a single record holding its error.
+func MergeResults(results []Result) []map[string]interface{} {
+	merged := make([]map[string]interface{}, 0)
+	for _, result := range results {
+		if result.Err != nil {
+			merged = append(merged, map[string]interface{}{
+				profileColumn: result.Profile,
+				errorColumn:   result.Err.Error(),
+			})
+			continue
+		}
+		for _, record := range result.Records {
+			withProfile := make(map[string]interface{}, len(record)+1)
+			for key, value := range record {
+				withProfile[key] = value
+			}
+			withProfile[profileColumn] = result.Profile
+			merged = append(merged, withProfile)
+		}
+	}
+	return merged
+}
+
+func getFanOutProfiles(cCtx *cli.Context) ([]string, error) {
+	config, err := Load(getProfilesFile(cCtx))
+	if err != nil {
+		return nil, err
+	}
+
+	if cCtx.Bool(AllProfilesFlag.Name) {
+		names := config.Names()
+		if len(names) == 0 {
+			return nil, errors.New("no profiles found in profiles file")
+		}
+		return names, nil
+	}
+
+	names := make([]string, 0)
+	for _, name := range strings.Split(cCtx.String(ProfilesFlag.Name), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, err := config.Get(name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func runProfile(
+	ctx context.Context,
+	executable string,
+	env []string,
+	args []string,
+	profilesFile string,
+	name string,
+) ([]map[string]interface{}, error) {
+	outputDir, err := os.MkdirTemp("", "eigenlayer-profile-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(outputDir)
+	outputFile := filepath.Join(outputDir, "output.json")
+
+	childArgs := []string{"--" + ProfileFlag.Name, name}
+	if profilesFile != "" {
+		childArgs = append(childArgs, "--"+ProfilesFileFlag.Name, profilesFile)
+	}
+	childArgs = append(childArgs, args...)
+	childArgs = append(childArgs,
+		"--"+flags.OutputTypeFlag.Name, string(common.OutputType_Json),
+		"--"+flags.OutputFileFlag.Name, outputFile,
+	)
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, executable, childArgs...)
+	cmd.Env = env
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return nil, errors.New(message)
+	}
+
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read output of profile %s: %w", name, err)
+	}
+	return parseRecords(data)
+}
+
+// parseRecords reads the json output of a command as a list of records. Commands print either
+// a list of objects or a single object. An object whose values are all lists of objects, e.g. the
+// rewards and errors of a partial result, is expanded into the records of all lists, with the key
+// of the list in a section column.
+func parseRecords(data []byte) ([]map[string]interface{}, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var records []map[string]interface{}
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, err
+		}
+		return records, nil
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	if sections, ok := getSections(record); ok {
+		return sections, nil
+	}
+	return []map[string]interface{}{record}, nil
+}
+
+// getSections expands an object whose values are all lists of objects, or null for an empty list,
+// in the order of its keys
+func getSections(record map[string]interface{}) ([]map[string]interface{}, bool) {
+	if len(record) == 0 {
+		return nil, false
+	}
+	keys := make([]string, 0, len(record))
+	for key, value := range record {
+		if value == nil {
+			continue
+		}
+		list, ok := value.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		for _, item := range list {
+			if _, ok := item.(map[string]interface{}); !ok {
+				return nil, false
+			}
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	records := make([]map[string]interface{}, 0)
+	for _, key := range keys {
+		for _, item := range record[key].([]interface{}) {
+			section := item.(map[string]interface{})
+			section[sectionColumn] = key
+			records = append(records, section)
+		}
+	}
+	return records, true
+}
+
+// filterArgs drops the profile selection and output flags from the command line, so each
+// profile run gets its own profile and writes json to its own file. The profiles file is passed
+// to each run separately.
+func filterArgs(args []string) []string {
+	withValue := map[string]bool{
+		ProfileFlag.Name:                true,
+		ProfilesFlag.Name:               true,
+		ProfilesFileFlag.Name:           true,
+		flags.OutputTypeFlag.Name:       true,
+		flags.OutputFileFlag.Name:       true,
+		flags.OutputTypeFlag.Aliases[0]: true,
+		flags.OutputFileFlag.Aliases[0]: true,
+	}
+	withoutValue := map[string]bool{
+		AllProfilesFlag.Name: true,
+	}
+
+	filtered := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			filtered = append(filtered, args[i:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			filtered = append(filtered, arg)
+			continue
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if withoutValue[name] {
+			continue
+		}
+		if withValue[name] {
+			if !hasValue {
+				i++
+			}
+			continue
+		}
+		filtered = append(filtered, arg)
+	}
+	return filtered
+}
+
+// getChildEnv returns the environment without the profile selection of this run
+func getChildEnv() []string {
+	skip := map[string]bool{
+		ProfileFlag.EnvVars[0]:     true,
+		ProfilesFlag.EnvVars[0]:    true,
+		AllProfilesFlag.EnvVars[0]: true,
+	}
+
+	env := make([]string, 0)
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+		if skip[name] {
+			continue
+		}
+		env = append(env, entry)
+	}
+	return env
+}
+
+func writeRecords(cCtx *cli.Context, records []map[string]interface{}) error {
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+	outputFile := cCtx.String(flags.OutputFileFlag.Name)
+
+	switch outputType {
+	case string(common.OutputType_Json):
+		out, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		if !common.IsEmptyString(outputFile) {
+			return common.WriteToFile(out, outputFile)
+		}
+		fmt.Println(string(out))
+	case string(common.OutputType_Csv):
+		if common.IsEmptyString(outputFile) {
+			return errors.New("output file is required for csv output type")
+		}
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		columns := getColumns(records)
+		if err := writer.Write(columns); err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := writer.Write(getRow(record, columns)); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+		return common.WriteToFile(buf.Bytes(), outputFile)
+	case string(common.OutputType_Markdown):
+		out := newRecordsTable(records).Markdown()
+		if !common.IsEmptyString(outputFile) {
+			return common.WriteToFile([]byte(out), outputFile)
+		}
+		fmt.Print(out)
+	default:
+		if !common.IsEmptyString(outputFile) {
+			fmt.Println("output file not supported for pretty output type")
+			fmt.Println()
+		}
+		fmt.Println()
+		printRecords(records)
+	}
+	return nil
+}
+
+// getColumns returns the union of the record keys, with the profile column first and the error
+// column last
+func getColumns(records []map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	columns := make([]string, 0)
+	hasError := false
+	for _, record := range records {
+		for key := range record {
+			if key == errorColumn {
+				hasError = true
+				continue
+			}
+			if key == profileColumn || seen[key] {
+				continue
+			}
+			seen[key] = true
+			columns = append(columns, key)
+		}
+	}
+	sort.Strings(columns)
+	columns = append([]string{profileColumn}, columns...)
+	if hasError {
+		columns = append(columns, errorColumn)
+	}
+	return columns
+}
+
+func getRow(record map[string]interface{}, columns []string) []string {
+	row := make([]string, len(columns))
+	for i, column := range columns {
+		value, ok := record[column]
+		if !ok || value == nil {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			row[i] = v
+		case map[string]interface{}, []interface{}:
+			out, err := json.Marshal(v)
+			if err != nil {
+				row[i] = fmt.Sprint(v)
+			} else {
+				row[i] = string(out)
+			}
+		default:
+			row[i] = fmt.Sprint(v)
+		}
+	}
+	return row
+}
+
+func printRecords(records []map[string]interface{}) {
+	newRecordsTable(records).Print()
+}
+
+func newRecordsTable(records []map[string]interface{}) *common.Table {
+	columns := getColumns(records)
+	tableColumns := make([]common.TableColumn, len(columns))
+	for i, column := range columns {
+		tableColumns[i] = common.TableColumn{Header: column, MaxWidth: maxRecordWidth, Wrap: true}
+	}
+	table := common.NewTable(tableColumns...)
+	for _, record := range records {
+		table.AddRow(getRow(record, columns)...)
+	}
+	return table
+}
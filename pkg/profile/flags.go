@@ -0,0 +1,45 @@
+package profile
+
+import "github.com/urfave/cli/v2"
+
+var (
+	ProfileFlag = cli.StringFlag{
+		Name:    "profile",
+		Usage:   "Name of the profile from the profiles file to use flag values from",
+		EnvVars: []string{"EIGENLAYER_PROFILE"},
+	}
+
+	ProfilesFileFlag = cli.StringFlag{
+		Name:    "profiles-file",
+		Usage:   "Path to the profiles file. Defaults to $HOME/" + DefaultProfilesSubPath,
+		EnvVars: []string{"EIGENLAYER_PROFILES_FILE"},
+	}
+
+	AllProfilesFlag = cli.BoolFlag{
+		Name:    "all-profiles",
+		Usage:   "Run the command for every profile in the profiles file and merge the results",
+		EnvVars: []string{"EIGENLAYER_ALL_PROFILES"},
+	}
+
+	ProfilesFlag = cli.StringFlag{
+		Name:    "profiles",
+		Usage:   "Comma separated list of profiles to run the command for. The results are merged",
+		EnvVars: []string{"EIGENLAYER_PROFILES"},
+	}
+)
+
+// GlobalFlags are the app level flags to select a profile
+func GlobalFlags() []cli.Flag {
+	return []cli.Flag{
+		&ProfileFlag,
+		&ProfilesFileFlag,
+	}
+}
+
+// FanOutFlags are added to read-only commands which can be run for several profiles at once
+func FanOutFlags() []cli.Flag {
+	return []cli.Flag{
+		&AllProfilesFlag,
+		&ProfilesFlag,
+	}
+}
@@ -0,0 +1,371 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultProfilesSubPath is the location of the profiles file inside the home directory
+	DefaultProfilesSubPath = ".eigenlayer/profiles.yaml"
+
+	// selectedKey is the app metadata key holding the selected profile
+	selectedKey = "profile"
+)
+
+var (
+	ErrProfileNotFound = errors.New("profile not found")
+	ErrUnknownFlag     = errors.New("unknown flag in profile")
+	ErrCommandDenied   = errors.New("command is not allowed by profile")
+	ErrRecipientDenied = errors.New("recipient is not allowed by profile")
+)
+
+// Profile is a named set of flag values, e.g. the operator, network and RPC one identity is operated with.
+// A read-only profile can't be used to construct or send transactions. If allowed commands are set, only
+// those commands can be run with the profile. An entry allows a command and all its subcommands, e.g.
+// 'rewards' allows every rewards command while 'rewards show' only allows showing rewards. If allowed
+// recipients are set, rewards can only be claimed to those addresses. If allowed networks are set,
+// transactions are only sent to RPCs of those networks, checked with the chain ID of the RPC.
+type Profile struct {
+	Name              string            `yaml:"-"`
+	ReadOnly          bool              `yaml:"read-only"`
+	AllowedCommands   []string          `yaml:"allowed-commands"`
+	AllowedRecipients []string          `yaml:"allowed-recipients"`
+	AllowedNetworks   []string          `yaml:"allowed-networks"`
+	Hooks             common.Hooks      `yaml:"hooks"`
+	Flags             map[string]string `yaml:"flags"`
+}
+
+// Config is the content of the profiles file
+//
+//	profiles:
+//	  operator-a:
+//	    read-only: false
+//	    allowed-commands:
+//	      - rewards show
+//	      - operator status
+//	    allowed-recipients:
+//	      - 0x...
+//	    allowed-networks:
+//	      - holesky
+//	    hooks:
+//	      pre-broadcast: /path/to/approve.sh
+//	      post-receipt: /path/to/notify.sh
+//	      on-error: /path/to/alert.sh
+//	    flags:
+//	      network: holesky
+//	      eth-rpc-url: https://...
+//	      earner-address: 0x...
+type Config struct {
+	Profiles map[string]*Profile `yaml:"profiles"`
+}
+
+// DefaultPath returns the default location of the profiles file
+func DefaultPath() string {
+	homePath, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homePath, DefaultProfilesSubPath)
+}
+
+// Load reads the profiles file at path
+func Load(path string) (*Config, error) {
+	data, err := common.ReadFileLocked(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles file %s: %w", path, err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles file %s: %w", path, err)
+	}
+	for name, profile := range config.Profiles {
+		if profile == nil {
+			profile = &Profile{}
+			config.Profiles[name] = profile
+		}
+		profile.Name = name
+		for _, recipient := range profile.AllowedRecipients {
+			if !gethcommon.IsHexAddress(recipient) {
+				return nil, fmt.Errorf("invalid allowed recipient %s in profile %s", recipient, name)
+			}
+		}
+		if _, err := profile.AllowedChainIDs(); err != nil {
+			return nil, err
+		}
+	}
+	return &config, nil
+}
+
+// Get returns the profile with the given name
+func (c *Config) Get(name string) (*Profile, error) {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	return profile, nil
+}
+
+// Names returns the names of all profiles in alphabetical order
+func (c *Config) Names() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BeforeRunAction loads the profile selected with --profile and makes its flag values the
+// defaults of the command being run
+func BeforeRunAction() cli.BeforeFunc {
+	return func(cCtx *cli.Context) error {
+		name := cCtx.String(ProfileFlag.Name)
+		if name == "" {
+			return nil
+		}
+
+		config, err := Load(getProfilesFile(cCtx))
+		if err != nil {
+			return err
+		}
+		profile, err := config.Get(name)
+		if err != nil {
+			return err
+		}
+		if profile.ReadOnly {
+			common.SetReadOnly()
+		}
+		common.SetHooks(profile.Hooks)
+		chainIDs, err := profile.AllowedChainIDs()
+		if err != nil {
+			return err
+		}
+		common.SetAllowedChainIDs(chainIDs, "profile "+profile.Name)
+		if len(profile.AllowedCommands) > 0 {
+			restrictCommands(cCtx.App.Commands, nil, profile)
+		}
+
+		if err := apply(cCtx.App.Commands, profile); err != nil {
+			return err
+		}
+		cCtx.App.Metadata[selectedKey] = profile
+		return nil
+	}
+}
+
+// Selected returns the profile selected with --profile, or nil if no profile is used
+func Selected(cCtx *cli.Context) *Profile {
+	if cCtx == nil || cCtx.App == nil {
+		return nil
+	}
+	profile, ok := cCtx.App.Metadata[selectedKey].(*Profile)
+	if !ok {
+		return nil
+	}
+	return profile
+}
+
+// CheckRecipient returns an error if the profile has allowed recipients and the address is not one of them.
+// It is safe to call on a nil profile.
+func (p *Profile) CheckRecipient(recipient gethcommon.Address) error {
+	if p == nil || len(p.AllowedRecipients) == 0 {
+		return nil
+	}
+	for _, allowed := range p.AllowedRecipients {
+		if gethcommon.HexToAddress(allowed) == recipient {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w %s: %s", ErrRecipientDenied, p.Name, recipient.Hex())
+}
+
+// AllowedChainIDs returns the chain IDs of the allowed networks, which are network names like
+// 'holesky' or chain IDs. It returns nil if all networks are allowed.
+func (p *Profile) AllowedChainIDs() ([]*big.Int, error) {
+	if len(p.AllowedNetworks) == 0 {
+		return nil, nil
+	}
+	chainIDs := make([]*big.Int, 0, len(p.AllowedNetworks))
+	for _, network := range p.AllowedNetworks {
+		chainID := utils.NetworkNameToChainId(network)
+		if chainID.Sign() < 0 {
+			var ok bool
+			chainID, ok = new(big.Int).SetString(network, 10)
+			if !ok || chainID.Sign() <= 0 {
+				return nil, fmt.Errorf("invalid allowed network %s in profile %s, use a network name or chain ID", network, p.Name)
+			}
+		}
+		chainIDs = append(chainIDs, chainID)
+	}
+	return chainIDs, nil
+}
+
+// apply makes the profile flag values the defaults of every command. The values are set on the
+// context of the command before it runs, so flags given on the command line and env vars set by
+// the user take precedence. Since the required flags are checked before that, the required flags
+// the profile provides are replaced with copies which aren't required.
+func apply(commands []*cli.Command, profile *Profile) error {
+	names := getFlagNames(commands)
+	for flagName := range profile.Flags {
+		if !names[flagName] {
+			return fmt.Errorf("%w: %s in profile %s", ErrUnknownFlag, flagName, profile.Name)
+		}
+	}
+	applyToCommands(commands, profile)
+	return nil
+}
+
+func applyToCommands(commands []*cli.Command, profile *Profile) {
+	for _, command := range commands {
+		applyToCommands(command.Subcommands, profile)
+
+		provided := make([]string, 0)
+		for i, flag := range command.Flags {
+			name := flag.Names()[0]
+			if _, ok := profile.Flags[name]; !ok {
+				continue
+			}
+			provided = append(provided, name)
+			command.Flags[i] = notRequired(flag)
+		}
+		if len(provided) == 0 {
+			continue
+		}
+		before := command.Before
+		command.Before = func(cCtx *cli.Context) error {
+			for _, name := range provided {
+				if cCtx.IsSet(name) {
+					continue
+				}
+				if err := cCtx.Set(name, profile.Flags[name]); err != nil {
+					return fmt.Errorf("invalid value for %s in profile %s: %w", name, profile.Name, err)
+				}
+			}
+			if before != nil {
+				return before(cCtx)
+			}
+			return nil
+		}
+	}
+}
+
+// notRequired returns a copy of a required flag which isn't required. The flags are shared between
+// commands, so they are copied instead of changed.
+func notRequired(flag cli.Flag) cli.Flag {
+	switch f := flag.(type) {
+	case *cli.StringFlag:
+		if f.Required {
+			copied := *f
+			copied.Required = false
+			return &copied
+		}
+	case *cli.Uint64Flag:
+		if f.Required {
+			copied := *f
+			copied.Required = false
+			return &copied
+		}
+	case *cli.IntFlag:
+		if f.Required {
+			copied := *f
+			copied.Required = false
+			return &copied
+		}
+	case *cli.BoolFlag:
+		if f.Required {
+			copied := *f
+			copied.Required = false
+			return &copied
+		}
+	case *cli.StringSliceFlag:
+		if f.Required {
+			copied := *f
+			copied.Required = false
+			return &copied
+		}
+	}
+	return flag
+}
+
+// restrictCommands guards the action of every command the profile doesn't allow, so the check
+// happens when the command is dispatched no matter how it was named on the command line
+func restrictCommands(commands []*cli.Command, parentPath []string, profile *Profile) {
+	for _, command := range commands {
+		path := append(append([]string{}, parentPath...), command.Name)
+		// A parent command can have its own action, so it is guarded like any other command
+		restrictCommands(command.Subcommands, path, profile)
+		// Hidden commands are run by the CLI itself, e.g. to send telemetry in the background
+		if command.Action == nil || command.Hidden || profile.IsAllowed(path) {
+			continue
+		}
+		fullName := strings.Join(path, " ")
+		command.Action = func(cCtx *cli.Context) error {
+			return fmt.Errorf("%w %s: %s", ErrCommandDenied, profile.Name, fullName)
+		}
+	}
+}
+
+// IsAllowed returns true if the command with the given path, e.g. [rewards show], can be run with the profile
+func (p *Profile) IsAllowed(path []string) bool {
+	if len(p.AllowedCommands) == 0 {
+		return true
+	}
+	// Help is always available so users can find out what they are allowed to run
+	if len(path) > 0 && path[len(path)-1] == "help" {
+		return true
+	}
+	for _, allowed := range p.AllowedCommands {
+		allowedPath := strings.Fields(allowed)
+		if len(allowedPath) == 0 || len(allowedPath) > len(path) {
+			continue
+		}
+		matches := true
+		for i, name := range allowedPath {
+			if path[i] != name {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// getFlagNames returns the names of all flags of the command tree
+func getFlagNames(commands []*cli.Command) map[string]bool {
+	names := make(map[string]bool)
+	for _, command := range commands {
+		for _, flag := range command.Flags {
+			names[flag.Names()[0]] = true
+		}
+		for name := range getFlagNames(command.Subcommands) {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+func getProfilesFile(cCtx *cli.Context) string {
+	path := cCtx.String(ProfilesFileFlag.Name)
+	if strings.TrimSpace(path) == "" {
+		return DefaultPath()
+	}
+	return path
+}
@@ -0,0 +1,192 @@
+package profile
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+)
+
+func TestLoad(t *testing.T) {
+	profilesFile := filepath.Join(t.TempDir(), "profiles.yaml")
+	content := `profiles:
+  operator-b:
+    flags:
+      network: mainnet
+  operator-a:
+    flags:
+      network: holesky
+      earner-address: "0x111"
+`
+	err := os.WriteFile(profilesFile, []byte(content), 0o600)
+	assert.NoError(t, err)
+
+	config, err := Load(profilesFile)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"operator-a", "operator-b"}, config.Names())
+
+	profile, err := config.Get("operator-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "operator-a", profile.Name)
+	assert.Equal(t, "0x111", profile.Flags["earner-address"])
+
+	_, err = config.Get("operator-c")
+	assert.True(t, errors.Is(err, ErrProfileNotFound))
+}
+
+func TestApply(t *testing.T) {
+	network := &cli.StringFlag{Name: "network", Required: true, EnvVars: []string{"TEST_PROFILE_NETWORK"}}
+	earner := &cli.StringFlag{Name: "earner-address", Required: true}
+	var gotNetwork, gotEarner string
+	show := &cli.Command{
+		Name:  "show",
+		Flags: []cli.Flag{network, earner},
+		Action: func(cCtx *cli.Context) error {
+			gotNetwork = cCtx.String("network")
+			gotEarner = cCtx.String("earner-address")
+			return nil
+		},
+	}
+	app := &cli.App{Name: "eigenlayer", Commands: []*cli.Command{{Name: "rewards", Subcommands: []*cli.Command{show}}}}
+	t.Setenv("TEST_PROFILE_NETWORK", "holesky")
+
+	err := apply(app.Commands, &Profile{
+		Name:  "operator-a",
+		Flags: map[string]string{"network": "mainnet", "earner-address": "0x111"},
+	})
+	assert.NoError(t, err)
+	// the shared flags stay required for other commands
+	assert.True(t, earner.Required)
+	assert.NotSame(t, earner, show.Flags[1])
+
+	err = app.Run([]string{"eigenlayer", "rewards", "show"})
+	assert.NoError(t, err)
+	// values set by the user take precedence over the profile
+	assert.Equal(t, "holesky", gotNetwork)
+	assert.Equal(t, "0x111", gotEarner)
+
+	err = app.Run([]string{"eigenlayer", "rewards", "show", "--earner-address", "0x222"})
+	assert.NoError(t, err)
+	assert.Equal(t, "0x222", gotEarner)
+
+	err = apply(app.Commands, &Profile{Name: "operator-a", Flags: map[string]string{"unknown": "value"}})
+	assert.True(t, errors.Is(err, ErrUnknownFlag))
+}
+
+func TestIsAllowed(t *testing.T) {
+	profile := &Profile{Name: "automation", AllowedCommands: []string{"rewards show", "operator"}}
+	assert.True(t, profile.IsAllowed([]string{"rewards", "show"}))
+	assert.True(t, profile.IsAllowed([]string{"operator", "status"}))
+	assert.True(t, profile.IsAllowed([]string{"rewards", "help"}))
+	assert.False(t, profile.IsAllowed([]string{"rewards", "claim"}))
+	assert.False(t, profile.IsAllowed([]string{"rewards"}))
+
+	assert.True(t, (&Profile{}).IsAllowed([]string{"rewards", "claim"}))
+}
+
+func TestRestrictCommands(t *testing.T) {
+	noop := func(cCtx *cli.Context) error { return nil }
+	show := &cli.Command{Name: "show", Action: noop}
+	claim := &cli.Command{Name: "claim", Action: noop}
+	rewards := &cli.Command{Name: "rewards", Action: noop, Subcommands: []*cli.Command{show, claim}}
+	keys := &cli.Command{Name: "keys", Action: noop, Subcommands: []*cli.Command{{Name: "list", Action: noop}}}
+	commands := []*cli.Command{rewards, keys}
+
+	restrictCommands(commands, nil, &Profile{Name: "automation", AllowedCommands: []string{"rewards show", "keys"}})
+	assert.NoError(t, show.Action(nil))
+	assert.True(t, errors.Is(claim.Action(nil), ErrCommandDenied))
+	// A parent with an action is only allowed if the parent itself is
+	assert.True(t, errors.Is(rewards.Action(nil), ErrCommandDenied))
+	assert.NoError(t, keys.Action(nil))
+	assert.NoError(t, keys.Subcommands[0].Action(nil))
+}
+
+func TestCheckRecipient(t *testing.T) {
+	allowed := gethcommon.HexToAddress("0x111")
+	profile := &Profile{Name: "automation", AllowedRecipients: []string{allowed.Hex()}}
+	assert.NoError(t, profile.CheckRecipient(allowed))
+	assert.True(t, errors.Is(profile.CheckRecipient(gethcommon.HexToAddress("0x222")), ErrRecipientDenied))
+
+	var noProfile *Profile
+	assert.NoError(t, noProfile.CheckRecipient(gethcommon.HexToAddress("0x222")))
+	assert.NoError(t, (&Profile{}).CheckRecipient(gethcommon.HexToAddress("0x222")))
+}
+
+func TestAllowedChainIDs(t *testing.T) {
+	profile := &Profile{Name: "test", AllowedNetworks: []string{"holesky", "31337", "11155111"}}
+	chainIDs, err := profile.AllowedChainIDs()
+	assert.NoError(t, err)
+	assert.Equal(t, []*big.Int{big.NewInt(17000), big.NewInt(31337), big.NewInt(11155111)}, chainIDs)
+
+	chainIDs, err = (&Profile{}).AllowedChainIDs()
+	assert.NoError(t, err)
+	assert.Nil(t, chainIDs)
+
+	_, err = (&Profile{Name: "test", AllowedNetworks: []string{"mainnett"}}).AllowedChainIDs()
+	assert.EqualError(t, err, "invalid allowed network mainnett in profile test, use a network name or chain ID")
+}
+
+func TestFilterArgs(t *testing.T) {
+	args := []string{
+		"--profile", "operator-a",
+		"--profiles-file", "profiles.yaml",
+		"rewards", "show",
+		"--all-profiles",
+		"--profiles=operator-a,operator-b",
+		"--ot", "pretty",
+		"-o", "out.json",
+		"--output-type=json",
+		"--claim-type", "unclaimed",
+	}
+	assert.Equal(t, []string{"rewards", "show", "--claim-type", "unclaimed"}, filterArgs(args))
+}
+
+func TestMergeResults(t *testing.T) {
+	records := MergeResults([]Result{
+		{
+			Profile: "operator-a",
+			Records: []map[string]interface{}{{"tokenAddress": "0x1", "amount": "10"}},
+		},
+		{
+			Profile: "operator-b",
+			Err:     errors.New("rpc unavailable"),
+		},
+	})
+
+	assert.Len(t, records, 2)
+	assert.Equal(t, "operator-a", records[0]["profile"])
+	assert.Equal(t, "10", records[0]["amount"])
+	assert.Equal(t, "operator-b", records[1]["profile"])
+	assert.Equal(t, "rpc unavailable", records[1]["error"])
+	assert.Equal(t, []string{"profile", "amount", "tokenAddress", "error"}, getColumns(records))
+}
+
+func TestParseRecords(t *testing.T) {
+	records, err := parseRecords([]byte(`[{"tokenAddress": "0x1"}]`))
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"tokenAddress": "0x1"}}, records)
+
+	records, err = parseRecords([]byte(`{"operator": "0x1", "splits": [{"avs": "0x2"}]}`))
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "0x1", records[0]["operator"])
+
+	records, err = parseRecords([]byte(`{
+		"rewards": [{"tokenAddress": "0x1", "amount": "10"}],
+		"errors": [{"address": "0x2", "error": "failed"}]
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"section": "errors", "address": "0x2", "error": "failed"},
+		{"section": "rewards", "tokenAddress": "0x1", "amount": "10"},
+	}, records)
+
+	records, err = parseRecords([]byte(`{"rewards": [{"tokenAddress": "0x1"}], "errors": null}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"section": "rewards", "tokenAddress": "0x1"}}, records)
+}
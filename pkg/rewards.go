@@ -14,6 +14,11 @@ func RewardsCmd(p utils.Prompter) *cli.Command {
 			rewards.ClaimCmd(p),
 			rewards.SetClaimerCmd(p),
 			rewards.ShowCmd(p),
+			rewards.TaxReportCmd(p),
+			rewards.SimulateCmd(p),
+			rewards.SubmitRootCmd(p),
+			rewards.DisableRootCmd(p),
+			rewards.ExportProofCmd(p),
 		},
 	}
 
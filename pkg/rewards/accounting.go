@@ -0,0 +1,172 @@
+package rewards
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/erc20"
+
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/claimgen"
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/distribution"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// accountingRow is one line of the accounting CSV. The columns follow what bookkeeping tools
+// usually expect when importing token income.
+type accountingRow struct {
+	Date         string `csv:"date"`
+	RootHash     string `csv:"root_hash"`
+	TokenSymbol  string `csv:"token_symbol"`
+	TokenAddress string `csv:"token_address"`
+	Decimals     uint8  `csv:"decimals"`
+	AmountWei    string `csv:"amount_wei"`
+	Amount       string `csv:"amount"`
+	ClaimTxHash  string `csv:"claim_tx_hash"`
+}
+
+// getSnapshotRoot computes the merkle root of the snapshot the earner's rewards were read from
+func getSnapshotRoot(
+	snapshot *distribution.Distribution,
+	earnerAddress gethcommon.Address,
+	tokenAddresses []gethcommon.Address,
+	rootIndex uint32,
+) (gethcommon.Hash, error) {
+	cg := claimgen.NewClaimgen(snapshot)
+	accounts, _, err := cg.GenerateClaimProofForEarner(earnerAddress, tokenAddresses, rootIndex)
+	if err != nil {
+		return gethcommon.Hash{}, eigenSdkUtils.WrapError("failed to generate claim proof for earner", err)
+	}
+	return gethcommon.BytesToHash(accounts.Root()), nil
+}
+
+// getClaimTxHashes returns the hash of the latest claim transaction for each token claimed by the
+// earner. The rewards of the root can only be claimed once it is active, so the claim events are
+// read from its activation block to the head.
+func getClaimTxHashes(
+	ctx context.Context,
+	ethClient *ethclient.Client,
+	rewardsCoordinatorAddress gethcommon.Address,
+	earnerAddress gethcommon.Address,
+	rootIndex uint32,
+	logger logging.Logger,
+) (map[gethcommon.Address]gethcommon.Hash, error) {
+	_, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
+		RewardsCoordinatorAddress: rewardsCoordinatorAddress,
+	}, ethClient, nil, logger, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := contractBindings.RewardsCoordinator.GetDistributionRootAtIndex(
+		&bind.CallOpts{Context: ctx},
+		big.NewInt(int64(rootIndex)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	start, err := common.BlockAtTimestamp(ctx, ethClient, uint64(root.ActivatedAt))
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get activation block of the root", err)
+	}
+	head, err := ethClient.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Logs are returned in chain order, so the last one seen for a token is the latest claim
+	txHashes := make(map[gethcommon.Address]gethcommon.Hash)
+	err = common.FilterBlockRange(ctx, start, head, func(opts *bind.FilterOpts) error {
+		iter, err := contractBindings.RewardsCoordinator.FilterRewardsClaimed(
+			opts,
+			[]gethcommon.Address{earnerAddress},
+			nil,
+			nil,
+		)
+		if err != nil {
+			return err
+		}
+		defer iter.Close()
+		for iter.Next() {
+			txHashes[iter.Event.Token] = iter.Event.Raw.TxHash
+		}
+		return iter.Error()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return txHashes, nil
+}
+
+func writeAccountingCSV(
+	ctx context.Context,
+	cfg *ShowConfig,
+	ethClient *ethclient.Client,
+	rewards map[gethcommon.Address]*big.Int,
+	claimDate string,
+	rootIndex uint32,
+	rootHash gethcommon.Hash,
+	logger logging.Logger,
+) error {
+	txHashes, err := getClaimTxHashes(
+		ctx,
+		ethClient,
+		cfg.RewardsCoordinatorAddress,
+		cfg.EarnerAddress,
+		rootIndex,
+		logger,
+	)
+	if err != nil {
+		// Claim transactions are informational, so don't fail the export if the RPC can't serve the logs
+		logger.Warnf("Failed to get claim transactions, claim tx hashes will be empty: %s", err)
+		txHashes = make(map[gethcommon.Address]gethcommon.Hash)
+	}
+
+	rows := make([]accountingRow, 0, len(rewards))
+	for address, amount := range rewards {
+		decimals, err := erc20.GetTokenDecimals(address, ethClient)
+		if err != nil {
+			logger.Warnf(
+				"Failed to get decimals for token %s, using %d: %s",
+				address.Hex(),
+				erc20.DefaultDecimals,
+				err,
+			)
+			decimals = erc20.DefaultDecimals
+		}
+
+		claimTxHash := ""
+		if txHash, ok := txHashes[address]; ok {
+			claimTxHash = txHash.Hex()
+		}
+
+		rows = append(rows, accountingRow{
+			Date:         claimDate,
+			RootHash:     rootHash.Hex(),
+			TokenSymbol:  erc20.GetTokenSymbol(address, ethClient),
+			TokenAddress: address.Hex(),
+			Decimals:     decimals,
+			AmountWei:    amount.String(),
+			Amount:       erc20.FormatUnits(amount, decimals),
+			ClaimTxHash:  claimTxHash,
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].TokenAddress < rows[j].TokenAddress
+	})
+
+	err = common.WriteToCSV(rows, cfg.AccountingCSV)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Accounting CSV written to file: %s", cfg.AccountingCSV)
+	return nil
+}
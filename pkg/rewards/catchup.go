@@ -0,0 +1,280 @@
+package rewards
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IRewardsCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// maxCatchUpRoots bounds how many older roots the catch-up report downloads the proof data of.
+// Rewards of even older roots are reported together with the oldest root looked at.
+const maxCatchUpRoots = 52
+
+// catchUpRootReader reads the posted distribution roots
+type catchUpRootReader interface {
+	GetDistributionRootAtIndex(
+		opts *bind.CallOpts,
+		index *big.Int,
+	) (rewardscoordinator.IRewardsCoordinatorDistributionRoot, error)
+}
+
+// catchUpPeriod is what an earner earned of each token in the period of a root, which is not
+// claimed yet
+type catchUpPeriod struct {
+	RootIndex      uint32
+	CalculationEnd time.Time
+	ActivatedAt    time.Time
+	// IncludesEarlier is set when the period also has the rewards of older roots, which were not
+	// looked at
+	IncludesEarlier bool
+	Amounts         map[gethcommon.Address]*big.Int
+}
+
+// catchUpReport splits a claim against the newest active root into the periods of the roots the
+// earner skipped. The amounts of the periods add up to the claimed amounts, since cumulative
+// amounts only grow from root to root.
+type catchUpReport struct {
+	Earner    gethcommon.Address
+	RootIndex uint32
+	Claimable map[gethcommon.Address]*big.Int
+	Periods   []catchUpPeriod
+}
+
+// reportCatchUp prints the catch-up report of the claim. It goes to stderr unless the output is
+// pretty, so it never mixes with JSON or calldata output.
+func reportCatchUp(
+	ctx context.Context,
+	config *ClaimConfig,
+	ethClient *ethclient.Client,
+	elReader elChainReader,
+	fetcher claimAmountsFetcher,
+	claim *rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim,
+	logger logging.Logger,
+) error {
+	_, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
+		RewardsCoordinatorAddress: config.RewardsCoordinatorAddress,
+	}, ethClient, nil, logger, nil)
+	if err != nil {
+		return err
+	}
+
+	tokens := make(map[gethcommon.Address]*big.Int, len(claim.TokenLeaves))
+	for _, leaf := range claim.TokenLeaves {
+		tokens[leaf.Token] = leaf.CumulativeEarnings
+	}
+	claimed, err := getClaimedRewards(ctx, elReader, claim.EarnerLeaf.Earner, tokens)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get claimed rewards", err)
+	}
+
+	report, err := getCatchUpReport(
+		ctx,
+		contractBindings.RewardsCoordinator,
+		fetcher,
+		claim,
+		claimed,
+		maxCatchUpRoots,
+	)
+	if err != nil {
+		return err
+	}
+
+	w := os.Stderr
+	if config.OutputType == string(common.OutputType_Pretty) {
+		w = os.Stdout
+	}
+	printCatchUpReport(w, report)
+	return nil
+}
+
+// getCatchUpReport walks back from the root of the claim, until the cumulative amounts of a root
+// are all claimed already
+func getCatchUpReport(
+	ctx context.Context,
+	roots catchUpRootReader,
+	fetcher claimAmountsFetcher,
+	claim *rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim,
+	claimed map[gethcommon.Address]*big.Int,
+	maxRoots int,
+) (*catchUpReport, error) {
+	earner := claim.EarnerLeaf.Earner
+	report := &catchUpReport{
+		Earner:    earner,
+		RootIndex: claim.RootIndex,
+		Claimable: make(map[gethcommon.Address]*big.Int),
+	}
+
+	later := make(map[gethcommon.Address]*big.Int)
+	for _, leaf := range claim.TokenLeaves {
+		later[leaf.Token] = leaf.CumulativeEarnings
+		report.Claimable[leaf.Token] = positiveSub(leaf.CumulativeEarnings, claimed[leaf.Token])
+	}
+	laterRoot, err := roots.GetDistributionRootAtIndex(&bind.CallOpts{Context: ctx}, big.NewInt(int64(claim.RootIndex)))
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get distribution root", err)
+	}
+	laterIndex := claim.RootIndex
+
+	looked := 0
+	for index := int64(claim.RootIndex) - 1; index >= 0; index-- {
+		if looked == maxRoots {
+			report.addPeriod(laterIndex, laterRoot, later, claimed, nil, true)
+			return report, nil
+		}
+		root, err := roots.GetDistributionRootAtIndex(&bind.CallOpts{Context: ctx}, big.NewInt(index))
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to get distribution root", err)
+		}
+		if root.Disabled {
+			continue
+		}
+		looked++
+
+		date := time.Unix(int64(root.RewardsCalculationEndTimestamp), 0).UTC().Format(time.DateOnly)
+		proofData, err := fetcher.FetchClaimAmountsForDate(ctx, date)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError(fmt.Sprintf("failed to fetch claim amounts of root %d", index), err)
+		}
+		earnerTokens, present := proofData.Distribution.GetTokensForEarner(earner)
+		amounts := make(map[gethcommon.Address]*big.Int, len(later))
+		covered := true
+		for token := range later {
+			amounts[token] = big.NewInt(0)
+			if present {
+				if amount, found := earnerTokens.Get(token); found {
+					amounts[token] = amount.Int
+				}
+			}
+			if positiveSub(amounts[token], claimed[token]).Sign() > 0 {
+				covered = false
+			}
+		}
+
+		report.addPeriod(laterIndex, laterRoot, later, claimed, amounts, false)
+		if covered {
+			return report, nil
+		}
+		later, laterRoot, laterIndex = amounts, root, uint32(index)
+	}
+	// The oldest root has everything earned before it
+	report.addPeriod(laterIndex, laterRoot, later, claimed, nil, false)
+	return report, nil
+}
+
+// addPeriod adds the period of a root with what was earned since the previous root and is not
+// claimed yet. Periods without unclaimed rewards are left out.
+func (r *catchUpReport) addPeriod(
+	index uint32,
+	root rewardscoordinator.IRewardsCoordinatorDistributionRoot,
+	amounts map[gethcommon.Address]*big.Int,
+	claimed map[gethcommon.Address]*big.Int,
+	previous map[gethcommon.Address]*big.Int,
+	includesEarlier bool,
+) {
+	period := catchUpPeriod{
+		RootIndex:       index,
+		CalculationEnd:  time.Unix(int64(root.RewardsCalculationEndTimestamp), 0),
+		ActivatedAt:     time.Unix(int64(root.ActivatedAt), 0),
+		IncludesEarlier: includesEarlier,
+		Amounts:         make(map[gethcommon.Address]*big.Int),
+	}
+	earned := false
+	for token, amount := range amounts {
+		floor := claimed[token]
+		if floor == nil || (previous != nil && previous[token].Cmp(floor) > 0) {
+			floor = previous[token]
+		}
+		period.Amounts[token] = positiveSub(amount, floor)
+		if period.Amounts[token].Sign() > 0 {
+			earned = true
+		}
+	}
+	if !earned {
+		return
+	}
+	// Periods are added newest first, but reported oldest first
+	r.Periods = append([]catchUpPeriod{period}, r.Periods...)
+}
+
+// positiveSub returns a - b, or 0 if b is larger. A nil b counts as 0.
+func positiveSub(a, b *big.Int) *big.Int {
+	if b == nil {
+		return new(big.Int).Set(a)
+	}
+	if a.Cmp(b) <= 0 {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Sub(a, b)
+}
+
+// printCatchUpReport prints the periods of the claim, and that their amounts add up to what the
+// single claim against the newest root pays out
+func printCatchUpReport(w io.Writer, report *catchUpReport) {
+	tokens := make([]gethcommon.Address, 0, len(report.Claimable))
+	for token := range report.Claimable {
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].Hex() < tokens[j].Hex()
+	})
+
+	table := common.NewTable(
+		common.TableColumn{Header: "Root"},
+		common.TableColumn{Header: "Calculated Until"},
+		common.TableColumn{Header: "Activated At"},
+		common.TableColumn{Header: "Token Address"},
+		common.TableColumn{Header: "Amount (Wei)", Align: common.AlignRight},
+	)
+	for _, period := range report.Periods {
+		root := fmt.Sprintf("%d", period.RootIndex)
+		if period.IncludesEarlier {
+			root = fmt.Sprintf("%d and earlier", period.RootIndex)
+		}
+		for _, token := range tokens {
+			amount, ok := period.Amounts[token]
+			if !ok || amount.Sign() == 0 {
+				continue
+			}
+			table.AddRow(
+				root,
+				common.FormatTime(period.CalculationEnd),
+				common.FormatTime(period.ActivatedAt),
+				token.Hex(),
+				common.LocalizeNumber(amount.String()),
+			)
+		}
+	}
+
+	_, _ = fmt.Fprintf(
+		w,
+		"\nEarner %s has unclaimed rewards of %d root period(s). A claim against root %d pays out all of them:\n",
+		report.Earner.Hex(),
+		len(report.Periods),
+		report.RootIndex,
+	)
+	table.Render(w)
+	for _, token := range tokens {
+		_, _ = fmt.Fprintf(
+			w,
+			"Total of %s: %s wei\n",
+			token.Hex(),
+			common.LocalizeNumber(report.Claimable[token].String()),
+		)
+	}
+	_, _ = fmt.Fprintln(w, "Rewards are cumulative, so a claim against the newest root loses nothing of skipped roots.")
+}
@@ -0,0 +1,95 @@
+package rewards
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/proofDataFetcher"
+
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IRewardsCoordinator"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCatchUpRoots []rewardscoordinator.IRewardsCoordinatorDistributionRoot
+
+func (f fakeCatchUpRoots) GetDistributionRootAtIndex(
+	opts *bind.CallOpts,
+	index *big.Int,
+) (rewardscoordinator.IRewardsCoordinatorDistributionRoot, error) {
+	return f[index.Int64()], nil
+}
+
+type fakeClaimAmountsFetcher map[string]*proofDataFetcher.RewardProofData
+
+func (f fakeClaimAmountsFetcher) FetchClaimAmountsForDate(
+	ctx context.Context,
+	date string,
+) (*proofDataFetcher.RewardProofData, error) {
+	proofData, ok := f[date]
+	if !ok {
+		return nil, fmt.Errorf("no claim amounts for %s", date)
+	}
+	return proofData, nil
+}
+
+func TestGetCatchUpReport(t *testing.T) {
+	earner := gethcommon.HexToAddress("0x1")
+	token := gethcommon.HexToAddress("0x2")
+	day := func(d int) uint32 {
+		return uint32(time.Date(2024, time.May, d, 0, 0, 0, 0, time.UTC).Unix())
+	}
+	roots := fakeCatchUpRoots{
+		{RewardsCalculationEndTimestamp: day(1), ActivatedAt: day(2)},
+		{RewardsCalculationEndTimestamp: day(8), ActivatedAt: day(9)},
+		{RewardsCalculationEndTimestamp: day(15), ActivatedAt: day(16), Disabled: true},
+		{RewardsCalculationEndTimestamp: day(22), ActivatedAt: day(23)},
+	}
+	fetcher := fakeClaimAmountsFetcher{}
+	for date, amount := range map[string]string{"2024-05-01": "100", "2024-05-08": "150"} {
+		snapshot, err := loadSnapshot(writeSnapshot(t, fmt.Sprintf(
+			`{"earner":"%s","token":"%s","cumulative_amount":"%s"}`,
+			earner.Hex(),
+			token.Hex(),
+			amount,
+		)))
+		assert.NoError(t, err)
+		fetcher[date] = &proofDataFetcher.RewardProofData{Distribution: snapshot}
+	}
+	claim := &rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim{
+		RootIndex:  3,
+		EarnerLeaf: rewardscoordinator.IRewardsCoordinatorEarnerTreeMerkleLeaf{Earner: earner},
+		TokenLeaves: []rewardscoordinator.IRewardsCoordinatorTokenTreeMerkleLeaf{
+			{Token: token, CumulativeEarnings: big.NewInt(300)},
+		},
+	}
+	claimed := map[gethcommon.Address]*big.Int{token: big.NewInt(100)}
+
+	report, err := getCatchUpReport(context.Background(), roots, fetcher, claim, claimed, maxCatchUpRoots)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(200), report.Claimable[token])
+	assert.Len(t, report.Periods, 2)
+	assert.Equal(t, uint32(1), report.Periods[0].RootIndex)
+	assert.Equal(t, big.NewInt(50), report.Periods[0].Amounts[token])
+	assert.Equal(t, uint32(3), report.Periods[1].RootIndex)
+	assert.Equal(t, big.NewInt(150), report.Periods[1].Amounts[token])
+	assert.False(t, report.Periods[0].IncludesEarlier)
+
+	// Older roots than the limit are reported with the oldest root looked at
+	report, err = getCatchUpReport(context.Background(), roots, fetcher, claim, claimed, 1)
+	assert.NoError(t, err)
+	assert.Len(t, report.Periods, 2)
+	assert.True(t, report.Periods[0].IncludesEarlier)
+	assert.Equal(t, big.NewInt(50), report.Periods[0].Amounts[token])
+
+	var out bytes.Buffer
+	printCatchUpReport(&out, report)
+	assert.Contains(t, out.String(), "1 and earlier")
+	assert.Contains(t, out.String(), "A claim against root 3 pays out all of them")
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"net/http"
 	"os"
 	"sort"
 	"strings"
@@ -14,6 +13,7 @@ import (
 
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/profile"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
 	"github.com/wealdtech/go-merkletree/v2"
@@ -47,8 +47,15 @@ type elChainReader interface {
 	) (rewardscoordinator.IRewardsCoordinatorDistributionRoot, error)
 	CurrRewardsCalculationEndTimestamp(ctx context.Context) (uint32, error)
 	GetCumulativeClaimed(ctx context.Context, earnerAddress, tokenAddress gethcommon.Address) (*big.Int, error)
+	CheckClaim(ctx context.Context, claim rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim) (bool, error)
 }
 
+const (
+	claimCommandName = "rewards claim"
+
+	defaultResumeBatchSize = 20
+)
+
 func ClaimCmd(p utils.Prompter) *cli.Command {
 	var claimCmd = &cli.Command{
 		Name:  "claim",
@@ -78,12 +85,20 @@ func getClaimFlags() []cli.Flag {
 		&RewardsCoordinatorAddressFlag,
 		&ClaimTimestampFlag,
 		&ProofStoreBaseURLFlag,
+		&ProofStoreSignerFlag,
 		&flags.VerboseFlag,
 		&flags.SilentFlag,
 		&flags.BatchClaimFile,
+		&flags.ResumeFlag,
+		&BatchSizeFlag,
+		&VerifyProofsFlag,
+		&TrustedBlockHashFlag,
+		&IgnoreClaimHistoryFlag,
+		&CatchUpFlag,
 	}
 
 	allFlags := append(baseFlags, flags.GetSignerFlags()...)
+	allFlags = append(allFlags, flags.GetPlanFlags()...)
 	sort.Sort(cli.FlagsByName(allFlags))
 	return allFlags
 }
@@ -92,7 +107,8 @@ func batchClaim(
 	ctx context.Context,
 	logger logging.Logger,
 	ethClient *ethclient.Client,
-	elReader *elcontracts.ChainReader,
+	elReader elChainReader,
+	verifier *proofVerifier,
 	config *ClaimConfig,
 	p utils.Prompter,
 	rootIndex uint32,
@@ -114,12 +130,35 @@ func batchClaim(
 		return eigenSdkUtils.WrapError("failed to parse YAML config", err)
 	}
 
+	var state *common.BatchState
+	if !common.IsEmptyString(config.ResumeFile) {
+		state, err = common.LoadBatchState(
+			config.ResumeFile,
+			claimCommandName,
+			common.HashBatchInput(yamlFile),
+			map[string]string{
+				"rootIndex": fmt.Sprintf("%d", rootIndex),
+				"recipient": config.RecipientAddress.Hex(),
+			},
+		)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to load batch state", err)
+		}
+	}
+
 	var elClaims []rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim
 	var claims []contractrewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim
 	var accounts []merkletree.MerkleTree
+	var keys []string
 
-	for _, claimConfig := range claimConfigs {
+	for idx, claimConfig := range claimConfigs {
 		earnerAddr := gethcommon.HexToAddress(claimConfig.EarnerAddress)
+		// Items are keyed by position too, since the same earner can be in a batch more than once
+		key := fmt.Sprintf("%d:%s", idx, earnerAddr.Hex())
+		if state != nil && state.IsCompleted(key) {
+			logger.Infof("Skipping completed claim for earner %s", earnerAddr.String())
+			continue
+		}
 
 		var tokenAddrs []gethcommon.Address
 
@@ -131,6 +170,24 @@ func batchClaim(
 			}
 		}
 
+		// The transaction of a pending claim may have landed before the run was interrupted
+		if state != nil && state.IsPending(key) {
+			claimed, err := isClaimed(ctx, elReader, proofData, earnerAddr, tokenAddrs)
+			if err != nil {
+				return eigenSdkUtils.WrapError(
+					fmt.Sprintf("failed to check pending claim for earner %s", earnerAddr.String()),
+					err,
+				)
+			}
+			if claimed {
+				logger.Infof("Pending claim for earner %s landed on chain, skipping it", earnerAddr.String())
+				if err := state.MarkCompleted([]string{key}, ""); err != nil {
+					return eigenSdkUtils.WrapError("failed to write batch state", err)
+				}
+				continue
+			}
+		}
+
 		elClaim, claim, account, err := generateClaimPayload(
 			ctx,
 			rootIndex,
@@ -149,17 +206,61 @@ func batchClaim(
 		elClaims = append(elClaims, *elClaim)
 		claims = append(claims, *claim)
 		accounts = append(accounts, *account)
+		keys = append(keys, key)
+	}
 
+	// All claims in the batch are against the same root, so verifying it once is enough
+	if verifier != nil && len(accounts) > 0 {
+		err = verifier.VerifyDistributionRoot(ctx, rootIndex, gethcommon.BytesToHash(accounts[0].Root()))
+		if err != nil {
+			return err
+		}
+		logger.Infof("Distribution root at index %d verified against storage proof", rootIndex)
 	}
 
-	return broadcastClaims(config, ethClient, logger, p, ctx, elClaims, claims, accounts)
+	if state == nil {
+		return broadcastClaims(config, ethClient, logger, p, ctx, elClaims, claims, accounts, nil)
+	}
+	if len(elClaims) == 0 {
+		logger.Infof("%s No claims left to process in the batch", utils.EmojiCheckMark)
+		return nil
+	}
+	batch := &claimBatch{keys: keys, state: state}
+	return broadcastClaims(config, ethClient, logger, p, ctx, elClaims, claims, accounts, batch)
+}
+
+// claimBatch is the progress of a resumable batch claim. keys are the state keys of the claims.
+type claimBatch struct {
+	keys  []string
+	state *common.BatchState
+}
+
+// isClaimed returns whether the earner has no tokens of the proof data left to claim, e.g. since
+// the pending claim of an interrupted batch landed on chain
+func isClaimed(
+	ctx context.Context,
+	elReader elChainReader,
+	proofData *proofDataFetcher.RewardProofData,
+	earnerAddress gethcommon.Address,
+	tokenAddresses []gethcommon.Address,
+) (bool, error) {
+	claimableTokensOrderMap, present := proofData.Distribution.GetTokensForEarner(earnerAddress)
+	if !present {
+		return false, nil
+	}
+	claimableTokensMap := getTokensToClaim(claimableTokensOrderMap, tokenAddresses)
+	claimableTokens, err := filterClaimableTokens(ctx, elReader, earnerAddress, claimableTokensMap)
+	if err != nil {
+		return false, err
+	}
+	return len(claimableTokens) == 0, nil
 }
 
 func generateClaimPayload(
 	ctx context.Context,
 	rootIndex uint32,
 	proofData *proofDataFetcher.RewardProofData,
-	elReader *elcontracts.ChainReader,
+	elReader elChainReader,
 	logger logging.Logger,
 	earnerAddress gethcommon.Address,
 	tokenAddresses []gethcommon.Address,
@@ -228,12 +329,31 @@ func Claim(cCtx *cli.Context, p utils.Prompter) error {
 
 	cCtx.App.Metadata["network"] = config.ChainID.String()
 
-	ethClient, err := ethclient.Dial(config.RPCUrl)
+	ethClient, err := common.DialEthClient(config.RPCUrl)
 	if err != nil {
 		return eigenSdkUtils.WrapError("failed to create new eth client", err)
 	}
 
-	elReader, err := elcontracts.NewReaderFromConfig(
+	if config.PlanConfig.IsApply() {
+		return common.ApplyTxPlanWithReceipts(
+			ctx,
+			config.PlanConfig,
+			claimCommandName,
+			config.ClaimerAddress,
+			config.SignerConfig,
+			ethClient,
+			p,
+			config.ChainID,
+			logger,
+			[]common.PlanCheck{
+				planRecipientCheck(cCtx, config.RewardsCoordinatorAddress),
+				planClaimHistoryCheck(config),
+			},
+			recordPlanClaims(config, logger),
+		)
+	}
+
+	chainReader, err := elcontracts.NewReaderFromConfig(
 		elcontracts.Config{
 			RewardsCoordinatorAddress: config.RewardsCoordinatorAddress,
 		},
@@ -244,27 +364,59 @@ func Claim(cCtx *cli.Context, p utils.Prompter) error {
 		return eigenSdkUtils.WrapError("failed to create new reader from config", err)
 	}
 
+	var elReader elChainReader = chainReader
+	var verifier *proofVerifier
+	if config.VerifyProofs {
+		verifier, err = newProofVerifier(ctx, config.RPCUrl, config.RewardsCoordinatorAddress, config.TrustedBlockHash)
+		if err != nil {
+			return err
+		}
+		logger.Infof("Verifying critical reads with storage proofs at block %d", verifier.header.Number.Uint64())
+		elReader = &verifiedELReader{elChainReader: chainReader, verifier: verifier}
+	}
+
+	snapshots, err := newRewardsSnapshotVerifier(
+		ethClient,
+		config.RewardsCoordinatorAddress,
+		config.ProofStoreSigner,
+		logger,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create snapshot verifier", err)
+	}
 	df := httpProofDataFetcher.NewHttpProofDataFetcher(
 		config.ProofStoreBaseURL,
 		config.Environment,
 		config.Network,
-		http.DefaultClient,
+		snapshots.HTTPClient(),
 	)
 
+	endPhase := common.StartPhase("distribution root")
 	claimDate, rootIndex, err := getClaimDistributionRoot(ctx, config.ClaimTimestamp, elReader, logger)
+	endPhase()
 	if err != nil {
 		return eigenSdkUtils.WrapError("failed to get claim distribution root", err)
 	}
 
+	endPhase = common.StartPhase("proof data download")
 	proofData, err := df.FetchClaimAmountsForDate(ctx, claimDate)
+	endPhase()
 	if err != nil {
 		return eigenSdkUtils.WrapError("failed to fetch claim amounts for date", err)
 	}
 
+	endPhase = common.StartPhase("snapshot verification")
+	_, err = snapshots.Verify(ctx, claimDate, rootIndex, proofData.Distribution, logger)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
 	if config.BatchClaimFile != "" {
-		return batchClaim(ctx, logger, ethClient, elReader, config, p, rootIndex, proofData)
+		return batchClaim(ctx, logger, ethClient, elReader, verifier, config, p, rootIndex, proofData)
 	}
 
+	endPhase = common.StartPhase("proof generation")
 	elClaim, claim, account, err := generateClaimPayload(
 		ctx,
 		rootIndex,
@@ -274,15 +426,34 @@ func Claim(cCtx *cli.Context, p utils.Prompter) error {
 		config.EarnerAddress,
 		config.TokenAddresses,
 	)
-
+	endPhase()
 	if err != nil {
 		return err
 	}
 
+	if verifier != nil {
+		err = verifier.VerifyDistributionRoot(ctx, rootIndex, gethcommon.BytesToHash(account.Root()))
+		if err != nil {
+			return err
+		}
+		logger.Infof("Distribution root at index %d verified against storage proof", rootIndex)
+	}
+
+	if config.CatchUp {
+		endPhase = common.StartPhase("catch-up report")
+		err = reportCatchUp(ctx, config, ethClient, elReader, df, elClaim, logger)
+		endPhase()
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to report catch-up claim", err)
+		}
+	}
+
 	elClaims := []rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim{*elClaim}
 	claims := []contractrewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim{*claim}
 	accounts := []merkletree.MerkleTree{*account}
-	err = broadcastClaims(config, ethClient, logger, p, ctx, elClaims, claims, accounts)
+	endPhase = common.StartPhase("broadcast")
+	err = broadcastClaims(config, ethClient, logger, p, ctx, elClaims, claims, accounts, nil)
+	endPhase()
 
 	return err
 }
@@ -296,12 +467,26 @@ func broadcastClaims(
 	elClaims []rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim,
 	claims []contractrewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim,
 	accounts []merkletree.MerkleTree,
+	batch *claimBatch,
 ) error {
 	if len(elClaims) == 0 {
 		return fmt.Errorf("at least one claim is required")
 	}
-	if config.Broadcast {
-		eLWriter, err := common.GetELWriter(
+	if config.Broadcast || config.PlanConfig.IsPlan() {
+		if !config.IgnoreClaimHistory {
+			if err := checkClaimHistory(getClaimHistoryPath(), config.ChainID, elClaims); err != nil {
+				return err
+			}
+		}
+
+		eLWriter, txPlan, err := common.GetELWriterWithPlan(
+			config.PlanConfig,
+			claimCommandName,
+			map[string]string{
+				"recipient": config.RecipientAddress.Hex(),
+				"rootIndex": fmt.Sprintf("%d", elClaims[0].RootIndex),
+				"claims":    fmt.Sprintf("%d", len(elClaims)),
+			},
 			config.ClaimerAddress,
 			config.SignerConfig,
 			ethClient,
@@ -317,22 +502,82 @@ func broadcastClaims(
 			return eigenSdkUtils.WrapError("failed to get EL writer", err)
 		}
 
-		logger.Infof("Broadcasting claim transaction...")
+		// The claims of a resumable batch are sent in transactions of --batch-size claims, every
+		// other claim in one transaction
+		size := len(elClaims)
+		if batch != nil {
+			size = config.BatchSize
+			if size <= 0 {
+				size = defaultResumeBatchSize
+			}
+		}
+		for start := 0; start < len(elClaims); start += size {
+			end := start + size
+			if end > len(elClaims) {
+				end = len(elClaims)
+			}
+			chunk := elClaims[start:end]
 
-		var receipt *types.Receipt
+			if txPlan == nil && batch != nil {
+				logger.Infof("Broadcasting claims %d to %d of %d...", start+1, end, len(elClaims))
+				if err := batch.state.MarkPending(batch.keys[start:end]); err != nil {
+					return eigenSdkUtils.WrapError("failed to write batch state", err)
+				}
+			} else if txPlan == nil {
+				logger.Infof("Broadcasting claim transaction...")
+			}
 
-		if len(elClaims) > 1 {
-			receipt, err = eLWriter.ProcessClaims(ctx, elClaims, config.RecipientAddress, true)
-		} else {
-			receipt, err = eLWriter.ProcessClaim(ctx, elClaims[0], config.RecipientAddress, true)
-		}
+			var receipt *types.Receipt
+			if len(chunk) > 1 {
+				receipt, err = eLWriter.ProcessClaims(ctx, chunk, config.RecipientAddress, true)
+			} else {
+				receipt, err = eLWriter.ProcessClaim(ctx, chunk[0], config.RecipientAddress, true)
+			}
+			if err != nil && batch != nil {
+				return eigenSdkUtils.WrapError(
+					fmt.Sprintf("failed to process claims, resume the batch with --resume %s", config.ResumeFile),
+					err,
+				)
+			}
+			if err != nil {
+				return eigenSdkUtils.WrapError("failed to process claim", err)
+			}
 
-		if err != nil {
-			return eigenSdkUtils.WrapError("failed to process claim", err)
+			if txPlan != nil {
+				continue
+			}
+
+			logger.Infof("Claim transaction submitted successfully")
+			common.PrintTransactionInfo(receipt.TxHash.String(), config.ChainID)
+
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				if batch != nil {
+					return fmt.Errorf(
+						"claim transaction %s reverted, resume the batch with --resume %s",
+						receipt.TxHash.Hex(),
+						config.ResumeFile,
+					)
+				}
+				continue
+			}
+			common.PrintReceiptEvents(receipt, config.OutputType)
+			err = recordClaims(getClaimHistoryPath(), config.ChainID, receipt.TxHash.Hex(), chunk, time.Now())
+			if err != nil {
+				logger.Warnf("Failed to record the claim in the claim history: %s", err)
+			}
+			if batch != nil {
+				if err := batch.state.MarkCompleted(batch.keys[start:end], receipt.TxHash.Hex()); err != nil {
+					return eigenSdkUtils.WrapError("failed to write batch state", err)
+				}
+			}
 		}
 
-		logger.Infof("Claim transaction submitted successfully")
-		common.PrintTransactionInfo(receipt.TxHash.String(), config.ChainID)
+		if txPlan != nil {
+			return txPlan.Write(config.PlanConfig.PlanFile, logger)
+		}
+		if batch != nil {
+			logger.Infof("%s All claims of the batch are processed", utils.EmojiCheckMark)
+		}
 	} else {
 		noSendTxOpts := common.GetNoSendTxOpts(config.ClaimerAddress)
 		_, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
@@ -553,8 +798,31 @@ func readAndValidateClaimConfig(cCtx *cli.Context, logger logging.Logger) (*Clai
 	rewardsCoordinatorAddress := cCtx.String(RewardsCoordinatorAddressFlag.Name)
 	isSilent := cCtx.Bool(flags.SilentFlag.Name)
 	batchClaimFile := cCtx.String(flags.BatchClaimFile.Name)
+	verifyProofs := cCtx.Bool(VerifyProofsFlag.Name)
+	trustedBlockHash := cCtx.String(TrustedBlockHashFlag.Name)
+	if !verifyProofs && !common.IsEmptyString(trustedBlockHash) {
+		return nil, errors.New("trusted block hash can only be used with --verify-proofs")
+	}
+
+	planConfig, err := common.ReadPlanConfig(cCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	resumeFile := cCtx.String(flags.ResumeFlag.Name)
+	batchSize := cCtx.Int(BatchSizeFlag.Name)
+	if !common.IsEmptyString(resumeFile) {
+		if common.IsEmptyString(batchClaimFile) {
+			return nil, errors.New("--resume can only be used with --batch-claim-file")
+		}
+		if !broadcast || planConfig.IsPlan() || planConfig.IsApply() {
+			return nil, errors.New("--resume can only be used with --broadcast")
+		}
+	}
+	if batchSize < 0 {
+		return nil, errors.New("batch size can't be negative")
+	}
 
-	var err error
 	if common.IsEmptyString(rewardsCoordinatorAddress) {
 		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(utils.NetworkNameToChainId(network))
 		if err != nil {
@@ -566,6 +834,16 @@ func readAndValidateClaimConfig(cCtx *cli.Context, logger logging.Logger) (*Clai
 	claimTimestamp := cCtx.String(ClaimTimestampFlag.Name)
 	logger.Debugf("Using claim timestamp from user: %s", claimTimestamp)
 
+	catchUp := cCtx.Bool(CatchUpFlag.Name)
+	if catchUp {
+		if claimTimestamp != LatestActiveTimestamp {
+			return nil, fmt.Errorf("--%s only works with the latest active root", CatchUpFlag.Name)
+		}
+		if batchClaimFile != "" {
+			return nil, fmt.Errorf("--%s can't be used with batch claims", CatchUpFlag.Name)
+		}
+	}
+
 	recipientAddress := gethcommon.HexToAddress(cCtx.String(RecipientAddressFlag.Name))
 	if recipientAddress == utils.ZeroAddress {
 		logger.Infof(
@@ -575,6 +853,9 @@ func readAndValidateClaimConfig(cCtx *cli.Context, logger logging.Logger) (*Clai
 		recipientAddress = earnerAddress
 	}
 	logger.Infof("Using rewards recipient address: %s", recipientAddress.String())
+	if err := profile.Selected(cCtx).CheckRecipient(recipientAddress); err != nil {
+		return nil, err
+	}
 
 	claimerAddress := gethcommon.HexToAddress(cCtx.String(ClaimerAddressFlag.Name))
 	if claimerAddress == utils.ZeroAddress {
@@ -602,6 +883,11 @@ func readAndValidateClaimConfig(cCtx *cli.Context, logger logging.Logger) (*Clai
 	}
 	logger.Debugf("Using Proof store base URL: %s", proofStoreBaseURL)
 
+	proofStoreSigner, err := readProofStoreSigner(cCtx)
+	if err != nil {
+		return nil, err
+	}
+
 	if common.IsEmptyString(environment) {
 		environment = getEnvFromNetwork(network)
 	}
@@ -631,6 +917,7 @@ func readAndValidateClaimConfig(cCtx *cli.Context, logger logging.Logger) (*Clai
 		RewardsCoordinatorAddress: gethcommon.HexToAddress(rewardsCoordinatorAddress),
 		ChainID:                   chainID,
 		ProofStoreBaseURL:         proofStoreBaseURL,
+		ProofStoreSigner:          proofStoreSigner,
 		Environment:               environment,
 		RecipientAddress:          recipientAddress,
 		SignerConfig:              signerConfig,
@@ -638,6 +925,13 @@ func readAndValidateClaimConfig(cCtx *cli.Context, logger logging.Logger) (*Clai
 		ClaimerAddress:            claimerAddress,
 		IsSilent:                  isSilent,
 		BatchClaimFile:            batchClaimFile,
+		VerifyProofs:              verifyProofs,
+		TrustedBlockHash:          trustedBlockHash,
+		PlanConfig:                planConfig,
+		ResumeFile:                resumeFile,
+		BatchSize:                 batchSize,
+		IgnoreClaimHistory:        cCtx.Bool(IgnoreClaimHistoryFlag.Name),
+		CatchUp:                   catchUp,
 	}, nil
 }
 
@@ -0,0 +1,191 @@
+package rewards
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IRewardsCoordinator"
+)
+
+const (
+	// claimHistorySubPath is the location of the claim history inside the home directory
+	claimHistorySubPath = ".eigenlayer/claim-history.json"
+	// maxClaimHistoryEntries bounds the history. The oldest entries are dropped first.
+	maxClaimHistoryEntries = 1000
+)
+
+var ErrCumulativeAmountDecreased = errors.New("cumulative amount is lower than in the last successful claim")
+
+// claimHistoryEntry is a successful claim of an earner, with the cumulative amount of every
+// claimed token in wei
+type claimHistoryEntry struct {
+	ChainID           string            `json:"chainId"`
+	Earner            string            `json:"earner"`
+	RootIndex         uint32            `json:"rootIndex"`
+	TxHash            string            `json:"txHash"`
+	ClaimedAt         time.Time         `json:"claimedAt"`
+	CumulativeAmounts map[string]string `json:"cumulativeAmounts"`
+}
+
+// decreasedAmount is a token of a claim whose cumulative amount is lower than in the last
+// successful claim of the earner. Cumulative amounts never decrease between roots, so it points
+// to corrupt proof data.
+type decreasedAmount struct {
+	Earner         string
+	Token          string
+	Previous       *big.Int
+	Current        *big.Int
+	PreviousTxHash string
+}
+
+func getClaimHistoryPath() string {
+	homePath, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homePath, claimHistorySubPath)
+}
+
+func readClaimHistory(path string) ([]claimHistoryEntry, error) {
+	data, err := common.ReadFileLocked(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseClaimHistory(path, data)
+}
+
+func parseClaimHistory(path string, data []byte) ([]claimHistoryEntry, error) {
+	var entries []claimHistoryEntry
+	if len(data) == 0 {
+		return entries, nil
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse claim history %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// recordClaims adds an entry for the earner of every claim to the claim history
+func recordClaims(
+	path string,
+	chainID *big.Int,
+	txHash string,
+	claims []rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim,
+	now time.Time,
+) error {
+	return common.UpdateFileLocked(path, 0o600, func(data []byte) ([]byte, error) {
+		entries, err := parseClaimHistory(path, data)
+		if err != nil {
+			return nil, err
+		}
+		for _, claim := range claims {
+			entry := claimHistoryEntry{
+				ChainID:           chainID.String(),
+				Earner:            claim.EarnerLeaf.Earner.Hex(),
+				RootIndex:         claim.RootIndex,
+				TxHash:            txHash,
+				ClaimedAt:         now.UTC(),
+				CumulativeAmounts: make(map[string]string, len(claim.TokenLeaves)),
+			}
+			for _, leaf := range claim.TokenLeaves {
+				entry.CumulativeAmounts[leaf.Token.Hex()] = leaf.CumulativeEarnings.String()
+			}
+			entries = append(entries, entry)
+		}
+		if len(entries) > maxClaimHistoryEntries {
+			entries = entries[len(entries)-maxClaimHistoryEntries:]
+		}
+		return json.MarshalIndent(entries, "", "  ")
+	})
+}
+
+// getDecreasedAmounts compares the cumulative amounts of the claims with the ones of the last
+// successful claim of every token in the history
+func getDecreasedAmounts(
+	history []claimHistoryEntry,
+	chainID *big.Int,
+	claims []rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim,
+) []decreasedAmount {
+	decreased := make([]decreasedAmount, 0)
+	for _, claim := range claims {
+		earner := claim.EarnerLeaf.Earner.Hex()
+		for _, leaf := range claim.TokenLeaves {
+			token := leaf.Token.Hex()
+			for i := len(history) - 1; i >= 0; i-- {
+				entry := history[i]
+				if entry.ChainID != chainID.String() || !strings.EqualFold(entry.Earner, earner) {
+					continue
+				}
+				amount, ok := entry.CumulativeAmounts[token]
+				if !ok {
+					continue
+				}
+				previous, ok := new(big.Int).SetString(amount, 10)
+				if ok && leaf.CumulativeEarnings.Cmp(previous) < 0 {
+					decreased = append(decreased, decreasedAmount{
+						Earner:         earner,
+						Token:          token,
+						Previous:       previous,
+						Current:        leaf.CumulativeEarnings,
+						PreviousTxHash: entry.TxHash,
+					})
+				}
+				break
+			}
+		}
+	}
+	sort.Slice(decreased, func(i, j int) bool {
+		if decreased[i].Earner != decreased[j].Earner {
+			return decreased[i].Earner < decreased[j].Earner
+		}
+		return decreased[i].Token < decreased[j].Token
+	})
+	return decreased
+}
+
+// checkClaimHistory fails if the cumulative amount of a token decreased since the last successful
+// claim recorded in the claim history at path
+func checkClaimHistory(
+	path string,
+	chainID *big.Int,
+	claims []rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim,
+) error {
+	history, err := readClaimHistory(path)
+	if err != nil {
+		return err
+	}
+	decreased := getDecreasedAmounts(history, chainID, claims)
+	if len(decreased) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	for _, d := range decreased {
+		fmt.Fprintf(
+			&b,
+			"\n- earner %s, token %s: %s wei, was %s wei in %s",
+			d.Earner,
+			d.Token,
+			d.Current,
+			d.Previous,
+			d.PreviousTxHash,
+		)
+	}
+	return fmt.Errorf(
+		"%w, the proof data may be corrupt:%s\nUse --%s to claim anyway",
+		ErrCumulativeAmountDecreased,
+		b.String(),
+		IgnoreClaimHistoryFlag.Name,
+	)
+}
@@ -0,0 +1,74 @@
+package rewards
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IRewardsCoordinator"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func newHistoryClaim(earner string, amounts map[string]int64) rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim {
+	claim := rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim{
+		EarnerLeaf: rewardscoordinator.IRewardsCoordinatorEarnerTreeMerkleLeaf{
+			Earner: gethcommon.HexToAddress(earner),
+		},
+	}
+	for token, amount := range amounts {
+		claim.TokenLeaves = append(claim.TokenLeaves, rewardscoordinator.IRewardsCoordinatorTokenTreeMerkleLeaf{
+			Token:              gethcommon.HexToAddress(token),
+			CumulativeEarnings: big.NewInt(amount),
+		})
+	}
+	return claim
+}
+
+func historyClaims(
+	claims ...rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim,
+) []rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim {
+	return claims
+}
+
+func TestCheckClaimHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "claim-history.json")
+	chainID := big.NewInt(1)
+	now := time.Now()
+
+	// Without history there is nothing to compare against
+	first := newHistoryClaim("0x1", map[string]int64{"0xa": 100, "0xb": 50})
+	assert.NoError(t, checkClaimHistory(path, chainID, historyClaims(first)))
+	assert.NoError(t, recordClaims(path, chainID, "0x01", historyClaims(first), now))
+
+	// A later claim of only one token keeps the amount of the other one
+	second := newHistoryClaim("0x1", map[string]int64{"0xa": 200})
+	assert.NoError(t, checkClaimHistory(path, chainID, historyClaims(second)))
+	assert.NoError(t, recordClaims(path, chainID, "0x02", historyClaims(second), now))
+
+	history, err := readClaimHistory(path)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+
+	decreased := getDecreasedAmounts(history, chainID, []rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim{
+		newHistoryClaim("0x1", map[string]int64{"0xa": 150, "0xb": 40}),
+		newHistoryClaim("0x2", map[string]int64{"0xa": 1}),
+	})
+	assert.Len(t, decreased, 2)
+	assert.Equal(t, gethcommon.HexToAddress("0xa").Hex(), decreased[0].Token)
+	assert.Equal(t, big.NewInt(200), decreased[0].Previous)
+	assert.Equal(t, "0x02", decreased[0].PreviousTxHash)
+	assert.Equal(t, gethcommon.HexToAddress("0xb").Hex(), decreased[1].Token)
+	assert.Equal(t, "0x01", decreased[1].PreviousTxHash)
+
+	// Claims on other chains are not compared
+	other := historyClaims(newHistoryClaim("0x1", map[string]int64{"0xa": 1}))
+	assert.Empty(t, getDecreasedAmounts(history, big.NewInt(17000), other))
+
+	err = checkClaimHistory(path, chainID, []rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim{
+		newHistoryClaim("0x1", map[string]int64{"0xa": 150}),
+	})
+	assert.ErrorIs(t, err, ErrCumulativeAmountDecreased)
+}
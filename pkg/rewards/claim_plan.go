@@ -0,0 +1,122 @@
+package rewards
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/profile"
+
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IRewardsCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/urfave/cli/v2"
+)
+
+// planRecipientCheck makes sure a claim plan written elsewhere only claims rewards from the
+// rewards coordinator, to a recipient the profile allows. The recipient is decoded from the
+// calldata of every transaction, since that is what gets signed.
+func planRecipientCheck(cCtx *cli.Context, rewardsCoordinatorAddress gethcommon.Address) common.PlanCheck {
+	return func(plan *common.TxPlan) error {
+		selected := profile.Selected(cCtx)
+		if selected == nil || len(selected.AllowedRecipients) == 0 {
+			return nil
+		}
+		for i, tx := range plan.Transactions {
+			_, recipient, err := decodeClaimTx(tx, rewardsCoordinatorAddress)
+			if err != nil {
+				return fmt.Errorf("%w: transaction %d: %s", common.ErrInvalidPlan, i+1, err)
+			}
+			if err := selected.CheckRecipient(recipient); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// planClaimHistoryCheck runs the claim history check on the claims of a plan, like on the claims
+// of a claim which is sent right away
+func planClaimHistoryCheck(config *ClaimConfig) common.PlanCheck {
+	return func(plan *common.TxPlan) error {
+		if config.IgnoreClaimHistory {
+			return nil
+		}
+		claims := make([]rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim, 0)
+		for i, tx := range plan.Transactions {
+			txClaims, _, err := decodeClaimTx(tx, config.RewardsCoordinatorAddress)
+			if err != nil {
+				return fmt.Errorf("%w: transaction %d: %s", common.ErrInvalidPlan, i+1, err)
+			}
+			claims = append(claims, txClaims...)
+		}
+		return checkClaimHistory(getClaimHistoryPath(), config.ChainID, claims)
+	}
+}
+
+// recordPlanClaims records the claims of every sent transaction of a plan in the claim history
+func recordPlanClaims(config *ClaimConfig, logger logging.Logger) common.PlanReceiptHandler {
+	return func(tx common.PlannedTx, receipt *types.Receipt) {
+		claims, _, err := decodeClaimTx(tx, config.RewardsCoordinatorAddress)
+		if err == nil {
+			err = recordClaims(getClaimHistoryPath(), config.ChainID, receipt.TxHash.Hex(), claims, time.Now())
+		}
+		if err != nil {
+			logger.Warnf("Failed to record the claim in the claim history: %s", err)
+		}
+	}
+}
+
+// decodeClaimTx returns the claims and the recipient of a processClaim or processClaims
+// transaction to the rewards coordinator
+func decodeClaimTx(
+	tx common.PlannedTx,
+	rewardsCoordinatorAddress gethcommon.Address,
+) ([]rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim, gethcommon.Address, error) {
+	if gethcommon.HexToAddress(tx.To) != rewardsCoordinatorAddress {
+		return nil, gethcommon.Address{}, fmt.Errorf("target %s is not the rewards coordinator", tx.To)
+	}
+	if value, ok := new(big.Int).SetString(tx.Value, 10); !ok || value.Sign() != 0 {
+		return nil, gethcommon.Address{}, fmt.Errorf("claims can't send value, got %s", tx.Value)
+	}
+	contractAbi, err := rewardscoordinator.ContractIRewardsCoordinatorMetaData.GetAbi()
+	if err != nil {
+		return nil, gethcommon.Address{}, err
+	}
+	data := gethcommon.FromHex(tx.Data)
+	if len(data) < 4 {
+		return nil, gethcommon.Address{}, errors.New("calldata is too short")
+	}
+	method, err := contractAbi.MethodById(data[:4])
+	if err != nil {
+		return nil, gethcommon.Address{}, err
+	}
+	if method.Name != "processClaim" && method.Name != "processClaims" {
+		return nil, gethcommon.Address{}, fmt.Errorf("%s is not a claim", method.Name)
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, gethcommon.Address{}, fmt.Errorf("invalid %s calldata: %s", method.Name, err)
+	}
+	recipient, ok := args[len(args)-1].(gethcommon.Address)
+	if !ok {
+		return nil, gethcommon.Address{}, fmt.Errorf("invalid recipient in %s calldata", method.Name)
+	}
+	var claims []rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim
+	if method.Name == "processClaim" {
+		claim := abi.ConvertType(args[0], new(rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim))
+		claims = []rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim{
+			*claim.(*rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim),
+		}
+	} else {
+		converted := abi.ConvertType(args[0], new([]rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim))
+		claims = *converted.(*[]rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim)
+	}
+	return claims, recipient, nil
+}
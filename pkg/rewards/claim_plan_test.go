@@ -0,0 +1,89 @@
+package rewards
+
+import (
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IRewardsCoordinator"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeClaimTx(t *testing.T) {
+	rewardsCoordinator := gethcommon.HexToAddress("0x1")
+	recipient := gethcommon.HexToAddress("0x2")
+	contractAbi, err := rewardscoordinator.ContractIRewardsCoordinatorMetaData.GetAbi()
+	assert.NoError(t, err)
+	claim := rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim{
+		EarnerLeaf: rewardscoordinator.IRewardsCoordinatorEarnerTreeMerkleLeaf{Earner: gethcommon.HexToAddress("0x3")},
+	}
+	processClaim, err := contractAbi.Pack("processClaim", claim, recipient)
+	assert.NoError(t, err)
+	processClaims, err := contractAbi.Pack(
+		"processClaims",
+		[]rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim{claim, claim},
+		recipient,
+	)
+	assert.NoError(t, err)
+	setClaimerFor, err := contractAbi.Pack("setClaimerFor", recipient)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		to      gethcommon.Address
+		value   string
+		data    []byte
+		claims  int
+		wantErr string
+	}{
+		{name: "processClaim", to: rewardsCoordinator, value: "0", data: processClaim, claims: 1},
+		{name: "processClaims", to: rewardsCoordinator, value: "0", data: processClaims, claims: 2},
+		{
+			name:    "other contract",
+			to:      gethcommon.HexToAddress("0x4"),
+			value:   "0",
+			data:    processClaim,
+			wantErr: "not the rewards coordinator",
+		},
+		{
+			name:    "value",
+			to:      rewardsCoordinator,
+			value:   "1",
+			data:    processClaim,
+			wantErr: "can't send value",
+		},
+		{
+			name:    "other function",
+			to:      rewardsCoordinator,
+			value:   "0",
+			data:    setClaimerFor,
+			wantErr: "setClaimerFor is not a claim",
+		},
+		{
+			name:    "truncated calldata",
+			to:      rewardsCoordinator,
+			value:   "0",
+			data:    processClaim[:36],
+			wantErr: "invalid processClaim calldata",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := common.PlannedTx{To: tt.to.Hex(), Value: tt.value, Data: hexutil.Encode(tt.data)}
+			claims, decoded, err := decodeClaimTx(tx, rewardsCoordinator)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, recipient, decoded)
+			assert.Len(t, claims, tt.claims)
+			for _, decodedClaim := range claims {
+				assert.Equal(t, claim.EarnerLeaf.Earner, decodedClaim.EarnerLeaf.Earner)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"flag"
+	"fmt"
 	"math/big"
 	"os"
 	"sort"
@@ -15,6 +16,7 @@ import (
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
 
 	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/distribution"
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/proofDataFetcher"
 
 	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IRewardsCoordinator"
 	"github.com/Layr-Labs/eigensdk-go/logging"
@@ -111,6 +113,13 @@ func (f *fakeELReader) GetCumulativeClaimed(
 	return claimed, nil
 }
 
+func (f *fakeELReader) CheckClaim(
+	ctx context.Context,
+	claim rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim,
+) (bool, error) {
+	return true, nil
+}
+
 func (f *fakeELReader) CurrRewardsCalculationEndTimestamp(ctx context.Context) (uint32, error) {
 	rootLen, err := f.GetDistributionRootsLength(ctx)
 	if err != nil {
@@ -418,3 +427,34 @@ func TestFilterClaimableTokens(t *testing.T) {
 func newBigInt(value int64) *distribution.BigInt {
 	return &distribution.BigInt{Int: big.NewInt(value)}
 }
+
+func TestIsClaimed(t *testing.T) {
+	earner := common.HexToAddress("0x1")
+	token := common.HexToAddress("0x2")
+	snapshot, err := loadSnapshot(writeSnapshot(t, fmt.Sprintf(
+		`{"earner":"%s","token":"%s","cumulative_amount":"100"}`,
+		earner.Hex(),
+		token.Hex(),
+	)))
+	assert.NoError(t, err)
+	proofData := &proofDataFetcher.RewardProofData{Distribution: snapshot}
+
+	tests := []struct {
+		name    string
+		claimed *big.Int
+		want    bool
+	}{
+		{name: "pending claim landed", claimed: big.NewInt(100), want: true},
+		{name: "pending claim not sent", claimed: big.NewInt(40), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newFakeELReader(time.Now(), map[common.Address]map[common.Address]*big.Int{
+				earner: {token: tt.claimed},
+			})
+			claimed, err := isClaimed(context.Background(), reader, proofData, earner, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, claimed)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package rewards
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+)
+
+// DustFilter decides which amounts are left out of human output. Machine output, i.e. JSON, always
+// has every amount, so scripts never miss a token.
+type DustFilter struct {
+	HideZero bool
+	// Threshold hides amounts below it, in wei. Nil hides nothing.
+	Threshold *big.Int
+}
+
+func readDustFilter(hideZero bool, threshold string) (DustFilter, error) {
+	filter := DustFilter{HideZero: hideZero}
+	if common.IsEmptyString(threshold) {
+		return filter, nil
+	}
+	value, ok := new(big.Int).SetString(threshold, 10)
+	if !ok || value.Sign() < 0 {
+		return filter, fmt.Errorf("dust threshold must be a non-negative amount in wei, got %s", threshold)
+	}
+	filter.Threshold = value
+	return filter, nil
+}
+
+// Hides returns true if the amount is left out of human output. Amounts which can't be parsed are
+// never hidden.
+func (f DustFilter) Hides(amount string) bool {
+	return f.hiddenBy(amount) != ""
+}
+
+// hiddenBy returns the name of the flag which hides the amount, or "" if it's shown. A zero amount
+// is hidden by --hide-zero if it's set, even if it's below the threshold too.
+func (f DustFilter) hiddenBy(amount string) string {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return ""
+	}
+	if f.HideZero && value.Sign() == 0 {
+		return HideZeroFlag.Name
+	}
+	if f.Threshold != nil && value.Cmp(f.Threshold) < 0 {
+		return DustThresholdFlag.Name
+	}
+	return ""
+}
+
+// hiddenRows counts the rows of a table a DustFilter hid, by the flag which hid them
+type hiddenRows struct {
+	zero      int
+	dust      int
+	threshold *big.Int
+}
+
+// hide returns true if the filter hides the amount, and counts it
+func (h *hiddenRows) hide(f DustFilter, amount string) bool {
+	switch f.hiddenBy(amount) {
+	case HideZeroFlag.Name:
+		h.zero++
+	case DustThresholdFlag.Name:
+		h.dust++
+		h.threshold = f.Threshold
+	default:
+		return false
+	}
+	return true
+}
+
+func (h hiddenRows) total() int {
+	return h.zero + h.dust
+}
+
+// hiddenNote tells how many rows were hidden and by which flag, so a short table isn't mistaken
+// for all rewards
+func hiddenNote(hidden hiddenRows) string {
+	notes := make([]string, 0, 2)
+	if hidden.zero > 0 {
+		notes = append(notes, fmt.Sprintf(
+			"%d token(s) with a zero amount hidden, use --%s=false to show them",
+			hidden.zero,
+			HideZeroFlag.Name,
+		))
+	}
+	if hidden.dust > 0 {
+		notes = append(notes, fmt.Sprintf(
+			"%d token(s) below the dust threshold of %s wei hidden, lower --%s to show them",
+			hidden.dust,
+			hidden.threshold,
+			DustThresholdFlag.Name,
+		))
+	}
+	return strings.Join(notes, "\n")
+}
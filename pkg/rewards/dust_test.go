@@ -0,0 +1,66 @@
+package rewards
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDustFilter(t *testing.T) {
+	filter, err := readDustFilter(true, "")
+	assert.NoError(t, err)
+	assert.True(t, filter.Hides("0"))
+	assert.False(t, filter.Hides("1"))
+	assert.False(t, filter.Hides("not a number"))
+
+	filter, err = readDustFilter(false, "1000")
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000), filter.Threshold)
+	assert.True(t, filter.Hides("0"))
+	assert.True(t, filter.Hides("999"))
+	assert.False(t, filter.Hides("1000"))
+
+	_, err = readDustFilter(true, "-1")
+	assert.Error(t, err)
+	_, err = readDustFilter(true, "1e18")
+	assert.Error(t, err)
+}
+
+func TestHiddenNote(t *testing.T) {
+	filter := DustFilter{HideZero: true, Threshold: big.NewInt(1000)}
+	var hidden hiddenRows
+	for _, amount := range []string{"0", "0", "999", "1000"} {
+		hidden.hide(filter, amount)
+	}
+	assert.Equal(t, 3, hidden.total())
+	assert.Equal(
+		t,
+		"2 token(s) with a zero amount hidden, use --hide-zero=false to show them\n"+
+			"1 token(s) below the dust threshold of 1000 wei hidden, lower --dust-threshold to show them",
+		hiddenNote(hidden),
+	)
+
+	// Without --hide-zero a zero amount is hidden by the threshold
+	hidden = hiddenRows{}
+	hidden.hide(DustFilter{Threshold: big.NewInt(1000)}, "0")
+	assert.Equal(
+		t,
+		"1 token(s) below the dust threshold of 1000 wei hidden, lower --dust-threshold to show them",
+		hiddenNote(hidden),
+	)
+}
+
+func TestNewSimulationTableKeepsErrors(t *testing.T) {
+	filter := DustFilter{HideZero: true}
+	table, hidden := newSimulationTable([]simulatedRewardJson{
+		{Address: "0x1", Total: "10", Claimed: "10", Claimable: "0"},
+		{Address: "0x2", Total: "5", Claimed: "10", Claimable: "0", Error: "decreased"},
+		{Address: "0x3", Total: "10", Claimed: "0", Claimable: "10"},
+	}, filter)
+	assert.Equal(t, 1, hidden.total())
+	markdown := table.Markdown()
+	assert.NotContains(t, markdown, "0x1")
+	assert.Contains(t, markdown, "0x2")
+	assert.Contains(t, markdown, "0x3")
+}
@@ -0,0 +1,429 @@
+package rewards
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	contractrewardscoordinator "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IRewardsCoordinator"
+
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/claimgen"
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/proofDataFetcher/httpProofDataFetcher"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	earnerProofVersion = 1
+
+	// Salts the rewards coordinator prefixes leaves with, so earner and token leaves can't be confused
+	earnerLeafSalt = 0
+	tokenLeafSalt  = 1
+)
+
+var ErrInvalidEarnerProof = errors.New("invalid earner proof")
+
+// earnerProofJson is the Merkle path of an earner in a distribution root. It has everything needed
+// to verify the inclusion of the earner's cumulative earnings in the root, without the CLI or the
+// proof store.
+type earnerProofJson struct {
+	Version            int             `json:"version"`
+	ChainID            string          `json:"chainId"`
+	RewardsCoordinator string          `json:"rewardsCoordinator"`
+	SnapshotDate       string          `json:"snapshotDate"`
+	RootIndex          uint32          `json:"rootIndex"`
+	Root               string          `json:"root"`
+	Verification       proofSchemeJson `json:"verification"`
+	EarnerLeaf         earnerLeafJson  `json:"earnerLeaf"`
+	TokenLeaves        []tokenLeafJson `json:"tokenLeaves"`
+}
+
+type proofSchemeJson struct {
+	EarnerLeafHash string `json:"earnerLeafHash"`
+	TokenLeafHash  string `json:"tokenLeafHash"`
+	Path           string `json:"path"`
+}
+
+type earnerLeafJson struct {
+	Earner          string   `json:"earner"`
+	EarnerTokenRoot string   `json:"earnerTokenRoot"`
+	LeafHash        string   `json:"leafHash"`
+	Index           uint32   `json:"index"`
+	Siblings        []string `json:"siblings"`
+}
+
+type tokenLeafJson struct {
+	Token              string   `json:"token"`
+	CumulativeEarnings string   `json:"cumulativeEarnings"`
+	LeafHash           string   `json:"leafHash"`
+	Index              uint32   `json:"index"`
+	Siblings           []string `json:"siblings"`
+}
+
+var earnerProofScheme = proofSchemeJson{
+	EarnerLeafHash: "keccak256(abi.encodePacked(uint8(0), earner, earnerTokenRoot))",
+	TokenLeafHash:  "keccak256(abi.encodePacked(uint8(1), token, cumulativeEarnings))",
+	Path: "starting from the leaf hash, for each sibling: if index is even, hash = keccak256(hash, sibling), " +
+		"otherwise hash = keccak256(sibling, hash); then index = index / 2. " +
+		"Token leaves lead to earnerTokenRoot, the earner leaf leads to root",
+}
+
+func ExportProofCmd(p utils.Prompter) *cli.Command {
+	exportProofCmd := &cli.Command{
+		Name:      "export-proof",
+		Usage:     "Export the Merkle proof of an earner as standalone JSON for third-party verification",
+		UsageText: "export-proof",
+		Description: `
+Command to export the full Merkle path of an earner in a distribution root: the earner leaf, the
+token leaves and the sibling hashes up to the root, together with how the leaves are hashed.
+Third parties can verify the inclusion of the earner's cumulative earnings with the exported JSON
+and the root posted on-chain, without the CLI or the proof store.
+
+The proof is verified before it is exported.
+
+Helpful flags
+- claim-timestamp: Root to export the proof for. Can be 'latest' or 'latest_active'
+- token-addresses: Only export these tokens. All tokens of the earner are exported by default
+		`,
+		After: telemetry.AfterRunAction(),
+		Flags: getExportProofFlags(),
+		Action: func(cCtx *cli.Context) error {
+			return ExportProof(cCtx)
+		},
+	}
+
+	return exportProofCmd
+}
+
+func getExportProofFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.VerboseFlag,
+		&EarnerAddressFlag,
+		&EnvironmentFlag,
+		&TokenAddressesFlag,
+		&RewardsCoordinatorAddressFlag,
+		&ClaimTimestampFlag,
+		&ProofStoreBaseURLFlag,
+		&ProofStoreSignerFlag,
+	}
+
+	sort.Sort(cli.FlagsByName(baseFlags))
+	return baseFlags
+}
+
+func ExportProof(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateExportProofConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate export proof config", err)
+	}
+
+	cCtx.App.Metadata["network"] = config.ChainID.String()
+
+	ethClient, err := common.DialEthClient(config.RPCUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	elReader, err := elcontracts.NewReaderFromConfig(
+		elcontracts.Config{
+			RewardsCoordinatorAddress: config.RewardsCoordinatorAddress,
+		},
+		ethClient,
+		logger,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new reader from config", err)
+	}
+
+	snapshots, err := newRewardsSnapshotVerifier(
+		ethClient,
+		config.RewardsCoordinatorAddress,
+		config.ProofStoreSigner,
+		logger,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create snapshot verifier", err)
+	}
+	df := httpProofDataFetcher.NewHttpProofDataFetcher(
+		config.ProofStoreBaseURL,
+		config.Environment,
+		config.Network,
+		snapshots.HTTPClient(),
+	)
+
+	claimDate, rootIndex, err := getClaimDistributionRoot(ctx, config.ClaimTimestamp, elReader, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get claim distribution root", err)
+	}
+
+	proofData, err := df.FetchClaimAmountsForDate(ctx, claimDate)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to fetch claim amounts for date", err)
+	}
+	if _, err := snapshots.Verify(ctx, claimDate, rootIndex, proofData.Distribution, logger); err != nil {
+		return err
+	}
+
+	earnerTokens, present := proofData.Distribution.GetTokensForEarner(config.EarnerAddress)
+	if !present {
+		return ErrEarnerNotFound
+	}
+	// Unlike a claim, the proof includes tokens which were already claimed, since it's about
+	// the inclusion of the cumulative earnings in the root
+	tokens := make([]gethcommon.Address, 0)
+	for token := range getTokensToClaim(earnerTokens, config.TokenAddresses) {
+		tokens = append(tokens, token)
+	}
+	if len(tokens) == 0 {
+		return errors.New("none of the tokens are in the distribution for the earner")
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].Hex() < tokens[j].Hex()
+	})
+
+	cg := claimgen.NewClaimgen(proofData.Distribution)
+	accounts, claim, err := cg.GenerateClaimProofForEarner(config.EarnerAddress, tokens, rootIndex)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to generate claim proof for earner", err)
+	}
+
+	root := gethcommon.BytesToHash(accounts.Root())
+	if err := checkPostedRoot(ctx, snapshots.roots, rootIndex, root); err != nil {
+		return err
+	}
+
+	proof := newEarnerProof(root, claim)
+	proof.ChainID = config.ChainID.String()
+	proof.RewardsCoordinator = config.RewardsCoordinatorAddress.Hex()
+	proof.SnapshotDate = claimDate
+	if err := verifyEarnerProof(proof); err != nil {
+		return err
+	}
+	logger.Infof("Proof of earner %s in root %s verified", config.EarnerAddress.Hex(), proof.Root)
+
+	out, err := json.MarshalIndent(proof, "", "  ")
+	if err != nil {
+		return err
+	}
+	if !common.IsEmptyString(config.Output) {
+		if err := common.WriteToFile(out, config.Output); err != nil {
+			return err
+		}
+		logger.Infof("%s Proof written to file: %s", utils.EmojiCheckMark, config.Output)
+		return nil
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// checkPostedRoot makes sure the root the proof is computed against is the root posted at the
+// index, so an exported proof always verifies against the chain
+func checkPostedRoot(ctx context.Context, roots catchUpRootReader, rootIndex uint32, root gethcommon.Hash) error {
+	posted, err := roots.GetDistributionRootAtIndex(
+		&bind.CallOpts{Context: ctx},
+		new(big.Int).SetUint64(uint64(rootIndex)),
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get the posted distribution root", err)
+	}
+	if root != posted.Root {
+		return fmt.Errorf(
+			"%w: computed root %s does not match the root %s posted at index %d",
+			ErrSnapshotNotAuthentic,
+			root.Hex(),
+			gethcommon.Hash(posted.Root).Hex(),
+			rootIndex,
+		)
+	}
+	return nil
+}
+
+func newEarnerProof(
+	root gethcommon.Hash,
+	claim *contractrewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim,
+) *earnerProofJson {
+	earnerLeafHash := hashEarnerLeaf(claim.EarnerLeaf.Earner, claim.EarnerLeaf.EarnerTokenRoot)
+	proof := &earnerProofJson{
+		Version:      earnerProofVersion,
+		RootIndex:    claim.RootIndex,
+		Root:         root.Hex(),
+		Verification: earnerProofScheme,
+		EarnerLeaf: earnerLeafJson{
+			Earner:          claim.EarnerLeaf.Earner.Hex(),
+			EarnerTokenRoot: gethcommon.Hash(claim.EarnerLeaf.EarnerTokenRoot).Hex(),
+			LeafHash:        earnerLeafHash.Hex(),
+			Index:           claim.EarnerIndex,
+			Siblings:        splitSiblings(claim.EarnerTreeProof),
+		},
+		TokenLeaves: make([]tokenLeafJson, 0, len(claim.TokenLeaves)),
+	}
+	for i, leaf := range claim.TokenLeaves {
+		proof.TokenLeaves = append(proof.TokenLeaves, tokenLeafJson{
+			Token:              leaf.Token.Hex(),
+			CumulativeEarnings: leaf.CumulativeEarnings.String(),
+			LeafHash:           hashTokenLeaf(leaf.Token, leaf.CumulativeEarnings).Hex(),
+			Index:              claim.TokenIndices[i],
+			Siblings:           splitSiblings(claim.TokenTreeProofs[i]),
+		})
+	}
+	return proof
+}
+
+// verifyEarnerProof recomputes the proof from its leaves, the same way a third party would
+func verifyEarnerProof(proof *earnerProofJson) error {
+	earner := gethcommon.HexToAddress(proof.EarnerLeaf.Earner)
+	earnerTokenRoot := gethcommon.HexToHash(proof.EarnerLeaf.EarnerTokenRoot)
+
+	for _, leaf := range proof.TokenLeaves {
+		earnings, ok := new(big.Int).SetString(leaf.CumulativeEarnings, 10)
+		if !ok {
+			return fmt.Errorf("%w: invalid cumulative earnings %s", ErrInvalidEarnerProof, leaf.CumulativeEarnings)
+		}
+		leafHash := hashTokenLeaf(gethcommon.HexToAddress(leaf.Token), earnings)
+		if leafHash.Hex() != leaf.LeafHash {
+			return fmt.Errorf("%w: leaf hash of token %s doesn't match", ErrInvalidEarnerProof, leaf.Token)
+		}
+		if processInclusionProof(leafHash, leaf.Index, leaf.Siblings) != earnerTokenRoot {
+			return fmt.Errorf(
+				"%w: token %s is not included in the earner token root",
+				ErrInvalidEarnerProof,
+				leaf.Token,
+			)
+		}
+	}
+
+	leafHash := hashEarnerLeaf(earner, earnerTokenRoot)
+	if leafHash.Hex() != proof.EarnerLeaf.LeafHash {
+		return fmt.Errorf("%w: earner leaf hash doesn't match", ErrInvalidEarnerProof)
+	}
+	root := processInclusionProof(leafHash, proof.EarnerLeaf.Index, proof.EarnerLeaf.Siblings)
+	if root.Hex() != proof.Root {
+		return fmt.Errorf("%w: earner %s is not included in root %s", ErrInvalidEarnerProof, earner.Hex(), proof.Root)
+	}
+	return nil
+}
+
+func hashEarnerLeaf(earner gethcommon.Address, earnerTokenRoot [32]byte) gethcommon.Hash {
+	return crypto.Keccak256Hash([]byte{earnerLeafSalt}, earner.Bytes(), earnerTokenRoot[:])
+}
+
+func hashTokenLeaf(token gethcommon.Address, cumulativeEarnings *big.Int) gethcommon.Hash {
+	earnings := gethcommon.LeftPadBytes(cumulativeEarnings.Bytes(), 32)
+	return crypto.Keccak256Hash([]byte{tokenLeafSalt}, token.Bytes(), earnings)
+}
+
+// processInclusionProof computes the root of a Merkle path like the Merkle library of the
+// rewards coordinator does
+func processInclusionProof(leafHash gethcommon.Hash, index uint32, siblings []string) gethcommon.Hash {
+	computed := leafHash
+	for _, sibling := range siblings {
+		siblingHash := gethcommon.HexToHash(sibling)
+		if index%2 == 0 {
+			computed = crypto.Keccak256Hash(computed.Bytes(), siblingHash.Bytes())
+		} else {
+			computed = crypto.Keccak256Hash(siblingHash.Bytes(), computed.Bytes())
+		}
+		index /= 2
+	}
+	return computed
+}
+
+// splitSiblings splits a proof of concatenated 32 byte hashes into its sibling hashes
+func splitSiblings(proof []byte) []string {
+	siblings := make([]string, 0, len(proof)/32)
+	for reader := bytes.NewReader(proof); reader.Len() >= 32; {
+		sibling := make([]byte, 32)
+		_, _ = reader.Read(sibling)
+		siblings = append(siblings, hexutil.Encode(sibling))
+	}
+	return siblings
+}
+
+func readAndValidateExportProofConfig(cCtx *cli.Context, logger logging.Logger) (*ExportProofConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	earnerAddress := gethcommon.HexToAddress(cCtx.String(EarnerAddressFlag.Name))
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	tokenAddresses := getValidHexAddresses(strings.Split(cCtx.String(TokenAddressesFlag.Name), ","))
+	env := cCtx.String(EnvironmentFlag.Name)
+	if env == "" {
+		env = getEnvFromNetwork(network)
+	}
+	logger.Debugf("Network: %s, Env: %s", network, env)
+
+	claimTimestamp := cCtx.String(ClaimTimestampFlag.Name)
+	if claimTimestamp != LatestTimestamp && claimTimestamp != LatestActiveTimestamp {
+		return nil, errors.New("claim timestamp must be 'latest' or 'latest_active'")
+	}
+
+	rewardsCoordinatorAddress := cCtx.String(RewardsCoordinatorAddressFlag.Name)
+	var err error
+	if common.IsEmptyString(rewardsCoordinatorAddress) {
+		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(utils.NetworkNameToChainId(network))
+		if err != nil {
+			return nil, err
+		}
+	}
+	logger.Debugf("Using Rewards Coordinator address: %s", rewardsCoordinatorAddress)
+
+	proofStoreBaseURL := cCtx.String(ProofStoreBaseURLFlag.Name)
+	if common.IsEmptyString(proofStoreBaseURL) {
+		proofStoreBaseURL = getProofStoreBaseURL(network)
+		if common.IsEmptyString(proofStoreBaseURL) {
+			return nil, errors.New("proof store base URL not provided")
+		}
+	}
+	logger.Debugf("Using Proof store base URL: %s", proofStoreBaseURL)
+
+	proofStoreSigner, err := readProofStoreSigner(cCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID := utils.NetworkNameToChainId(network)
+	logger.Debugf("Using chain ID: %s", chainID.String())
+
+	// TODO(shrimalmadhur): Fix to make sure correct S3 bucket is used. Clean up later
+	if network == utils.MainnetNetworkName {
+		network = "ethereum"
+	}
+
+	return &ExportProofConfig{
+		Network:                   network,
+		Environment:               env,
+		RPCUrl:                    rpcUrl,
+		EarnerAddress:             earnerAddress,
+		TokenAddresses:            tokenAddresses,
+		ClaimTimestamp:            claimTimestamp,
+		ProofStoreBaseURL:         proofStoreBaseURL,
+		ProofStoreSigner:          proofStoreSigner,
+		ChainID:                   chainID,
+		Output:                    output,
+		RewardsCoordinatorAddress: gethcommon.HexToAddress(rewardsCoordinatorAddress),
+	}, nil
+}
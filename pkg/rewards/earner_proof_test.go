@@ -0,0 +1,68 @@
+package rewards
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	contractrewardscoordinator "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IRewardsCoordinator"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEarnerProof(t *testing.T) {
+	earner := gethcommon.HexToAddress("0x1")
+	tokenA := gethcommon.HexToAddress("0x2")
+	tokenB := gethcommon.HexToAddress("0x3")
+
+	// Token tree of the earner with two leaves
+	leafA := hashTokenLeaf(tokenA, big.NewInt(100))
+	leafB := hashTokenLeaf(tokenB, big.NewInt(200))
+	earnerTokenRoot := crypto.Keccak256Hash(leafA.Bytes(), leafB.Bytes())
+
+	// Earner tree with another earner at index 0
+	otherEarnerLeaf := hashEarnerLeaf(gethcommon.HexToAddress("0x4"), [32]byte{0x01})
+	earnerLeaf := hashEarnerLeaf(earner, earnerTokenRoot)
+	root := crypto.Keccak256Hash(otherEarnerLeaf.Bytes(), earnerLeaf.Bytes())
+
+	claim := &contractrewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim{
+		RootIndex:       7,
+		EarnerIndex:     1,
+		EarnerTreeProof: otherEarnerLeaf.Bytes(),
+		EarnerLeaf: contractrewardscoordinator.IRewardsCoordinatorEarnerTreeMerkleLeaf{
+			Earner:          earner,
+			EarnerTokenRoot: earnerTokenRoot,
+		},
+		TokenIndices:    []uint32{0, 1},
+		TokenTreeProofs: [][]byte{leafB.Bytes(), leafA.Bytes()},
+		TokenLeaves: []contractrewardscoordinator.IRewardsCoordinatorTokenTreeMerkleLeaf{
+			{Token: tokenA, CumulativeEarnings: big.NewInt(100)},
+			{Token: tokenB, CumulativeEarnings: big.NewInt(200)},
+		},
+	}
+
+	proof := newEarnerProof(root, claim)
+	assert.Equal(t, root.Hex(), proof.Root)
+	assert.Equal(t, uint32(7), proof.RootIndex)
+	assert.Equal(t, []string{otherEarnerLeaf.Hex()}, proof.EarnerLeaf.Siblings)
+	assert.Equal(t, []string{leafB.Hex()}, proof.TokenLeaves[0].Siblings)
+	assert.NoError(t, verifyEarnerProof(proof))
+
+	proof.TokenLeaves[1].CumulativeEarnings = "300"
+	assert.True(t, errors.Is(verifyEarnerProof(proof), ErrInvalidEarnerProof))
+
+	proof = newEarnerProof(gethcommon.HexToHash("0x1234"), claim)
+	assert.True(t, errors.Is(verifyEarnerProof(proof), ErrInvalidEarnerProof))
+}
+
+func TestCheckPostedRoot(t *testing.T) {
+	roots := fakeCatchUpRoots{{Root: [32]byte{1}}, {Root: [32]byte{2}}}
+	assert.NoError(t, checkPostedRoot(context.Background(), roots, 1, gethcommon.Hash{2}))
+
+	err := checkPostedRoot(context.Background(), roots, 0, gethcommon.Hash{2})
+	assert.ErrorIs(t, err, ErrSnapshotNotAuthentic)
+	assert.ErrorContains(t, err, "posted at index 0")
+}
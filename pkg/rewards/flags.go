@@ -40,6 +40,12 @@ var (
 		EnvVars: []string{"PROOF_STORE_BASE_URL"},
 	}
 
+	ProofStoreSignerFlag = cli.StringFlag{
+		Name:    "proof-store-signer",
+		Usage:   "Address the proof store signs the checksums of snapshots with. If provided, a snapshot is only used if its signature is valid",
+		EnvVars: []string{"PROOF_STORE_SIGNER"},
+	}
+
 	EnvironmentFlag = cli.StringFlag{
 		Name:    "environment",
 		Aliases: []string{"env"},
@@ -91,4 +97,157 @@ var (
 		Value:   "all",
 		EnvVars: []string{"REWARDS_CLAIM_TYPE"},
 	}
+
+	VerifyProofsFlag = cli.BoolFlag{
+		Name:    "verify-proofs",
+		Aliases: []string{"vp"},
+		Usage:   "Verify the cumulative claimed amounts and the distribution root read from the RPC against eth_getProof storage proofs",
+		EnvVars: []string{"REWARDS_VERIFY_PROOFS"},
+	}
+
+	TrustedBlockHashFlag = cli.StringFlag{
+		Name:    "trusted-block-hash",
+		Aliases: []string{"tbh"},
+		Usage:   "Block hash from a source you trust to anchor storage proofs to. If not provided, the latest block from the RPC is used. Only used with --verify-proofs",
+		EnvVars: []string{"REWARDS_TRUSTED_BLOCK_HASH"},
+	}
+
+	AllowPartialFlag = cli.BoolFlag{
+		Name:    "allow-partial",
+		Aliases: []string{"ap"},
+		Usage:   "Show rewards for the tokens that could be read even if reading some of them failed. Failed tokens are listed in an errors section. Values failing --verify-proofs still fail the command",
+		EnvVars: []string{"REWARDS_ALLOW_PARTIAL"},
+	}
+
+	AccountingCSVFlag = cli.StringFlag{
+		Name:    "accounting-csv",
+		Aliases: []string{"acsv"},
+		Usage:   "Write the shown rewards to a CSV file for accounting systems (date, root hash, token symbol, decimals, raw and human amounts, claim tx hash)",
+		EnvVars: []string{"REWARDS_ACCOUNTING_CSV"},
+	}
+
+	YearFlag = cli.IntFlag{
+		Name:    "year",
+		Aliases: []string{"y"},
+		Usage:   "Year to generate the report for. If not provided, the current year will be used",
+		EnvVars: []string{"REWARDS_YEAR"},
+	}
+
+	BatchSizeFlag = cli.IntFlag{
+		Name:    "batch-size",
+		Usage:   "Number of claims per transaction of a resumable batch claim (--resume). Defaults to 20",
+		EnvVars: []string{"REWARDS_BATCH_SIZE"},
+	}
+
+	PriceSourceFlag = cli.StringFlag{
+		Name:    "price-source",
+		Aliases: []string{"ps"},
+		Usage:   "Source of token prices. Can be 'coingecko', 'chainlink', 'http', 'file' or 'none'",
+		Value:   "coingecko",
+		EnvVars: []string{"REWARDS_PRICE_SOURCE"},
+	}
+
+	PriceFileFlag = cli.StringFlag{
+		Name:    "price-file",
+		Aliases: []string{"pf"},
+		Usage:   "CSV file with the columns date (YYYY-MM-DD), token (address or symbol) and price_usd. Used with the 'file' price source",
+		EnvVars: []string{"REWARDS_PRICE_FILE"},
+	}
+
+	PriceURLFlag = cli.StringFlag{
+		Name:    "price-url",
+		Usage:   "URL of the 'http' price source, with the placeholders {token}, {symbol}, {date} and {timestamp}. The response is JSON like {\"priceUsd\": 3.5}",
+		EnvVars: []string{"REWARDS_PRICE_URL"},
+	}
+
+	PriceAuthTokenFlag = cli.StringFlag{
+		Name:    "price-auth-token",
+		Usage:   "Bearer token sent to the 'http' price source",
+		EnvVars: []string{"REWARDS_PRICE_AUTH_TOKEN"},
+	}
+
+	ChainlinkFeedsFlag = cli.StringFlag{
+		Name:    "chainlink-feeds",
+		Usage:   "USD price feeds of the 'chainlink' price source by token address or symbol, e.g. 'EIGEN=0x...,0x...=0x...'",
+		EnvVars: []string{"REWARDS_CHAINLINK_FEEDS"},
+	}
+
+	PriceRateLimitFlag = cli.Float64Flag{
+		Name:    "price-rate-limit",
+		Usage:   "Maximum requests per second to the 'coingecko' and 'http' price sources. Defaults to 0.4 for coingecko and no limit for http",
+		EnvVars: []string{"REWARDS_PRICE_RATE_LIMIT"},
+	}
+
+	PriceCacheFlag = cli.BoolFlag{
+		Name:    "price-cache",
+		Usage:   "Keep the prices of the 'coingecko', 'chainlink' and 'http' price sources in $HOME/.eigenlayer/price-cache.json",
+		Value:   true,
+		EnvVars: []string{"REWARDS_PRICE_CACHE"},
+	}
+
+	SnapshotFileFlag = cli.StringFlag{
+		Name:     "snapshot",
+		Usage:    "Snapshot file with one JSON line per earner and token (earner, token, cumulative_amount), in the format of the claim amounts of the proof store",
+		Required: true,
+		EnvVars:  []string{"REWARDS_SNAPSHOT_FILE"},
+	}
+
+	RewardsUpdaterAddressFlag = cli.StringFlag{
+		Name:     "rewards-updater-address",
+		Usage:    "Address of the rewards updater sending the transaction",
+		Required: true,
+		EnvVars:  []string{"REWARDS_UPDATER_ADDRESS"},
+	}
+
+	RootFlag = cli.StringFlag{
+		Name:     "root",
+		Usage:    "Distribution root to submit, as a 32 byte hex string",
+		Required: true,
+		EnvVars:  []string{"REWARDS_ROOT"},
+	}
+
+	RewardsCalculationEndTimestampFlag = cli.Uint64Flag{
+		Name:     "rewards-calculation-end-timestamp",
+		Usage:    "Timestamp until which rewards were calculated for the submitted root",
+		Required: true,
+		EnvVars:  []string{"REWARDS_CALCULATION_END_TIMESTAMP"},
+	}
+
+	RootIndexFlag = cli.Uint64Flag{
+		Name:     "root-index",
+		Usage:    "Index of the distribution root to disable",
+		Required: true,
+		EnvVars:  []string{"REWARDS_ROOT_INDEX"},
+	}
+
+	SkipConfirmationFlag = cli.BoolFlag{
+		Name:    "yes",
+		Usage:   "Skip the confirmation prompt before sending the transaction",
+		EnvVars: []string{"REWARDS_SKIP_CONFIRMATION"},
+	}
+
+	CatchUpFlag = cli.BoolFlag{
+		Name:    "catch-up",
+		Usage:   "Report which part of the claim against the latest active root was earned in each root the earner skipped",
+		EnvVars: []string{"REWARDS_CATCH_UP"},
+	}
+
+	IgnoreClaimHistoryFlag = cli.BoolFlag{
+		Name:    "ignore-claim-history",
+		Usage:   "Claim even if a cumulative amount is lower than in the last successful claim in the local claim history",
+		EnvVars: []string{"REWARDS_IGNORE_CLAIM_HISTORY"},
+	}
+
+	HideZeroFlag = cli.BoolFlag{
+		Name:    "hide-zero",
+		Usage:   "Hide tokens with a zero amount from pretty and markdown output. JSON output has every token",
+		Value:   true,
+		EnvVars: []string{"REWARDS_HIDE_ZERO"},
+	}
+
+	DustThresholdFlag = cli.StringFlag{
+		Name:    "dust-threshold",
+		Usage:   "Hide tokens with an amount below this threshold in wei from pretty and markdown output. JSON output has every token",
+		EnvVars: []string{"REWARDS_DUST_THRESHOLD"},
+	}
 )
@@ -0,0 +1,328 @@
+package rewards
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/distribution"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Ways a downloaded snapshot is verified, from the strongest to the weakest. The root computed from
+// every snapshot has to match the posted root, the checksum and the signature are additional
+// evidence of where the snapshot comes from.
+const (
+	// SnapshotVerificationSignature is a signature of the proof store signer over the checksum
+	SnapshotVerificationSignature = "signature"
+	// SnapshotVerificationChecksum is the checksum published next to the snapshot
+	SnapshotVerificationChecksum = "checksum"
+	// SnapshotVerificationRoot is the root computed from the snapshot matching the posted root
+	SnapshotVerificationRoot = "root"
+
+	snapshotVerificationMetaKey = "snapshotVerification"
+	snapshotFileName            = "claim-amounts.json"
+	checksumSuffix              = ".sha256"
+	signatureSuffix             = ".sig"
+)
+
+var ErrSnapshotNotAuthentic = errors.New("rewards snapshot is not authentic")
+
+// SnapshotVerification is the result of verifying a snapshot downloaded from the proof store. It's
+// recorded in the metadata of the run, so pipelines can prove the data they acted on.
+type SnapshotVerification struct {
+	Date              string `json:"date"`
+	RootIndex         uint32 `json:"rootIndex"`
+	URL               string `json:"url"`
+	SHA256            string `json:"sha256"`
+	Method            string `json:"method"`
+	Verified          bool   `json:"verified"`
+	PublishedChecksum string `json:"publishedChecksum,omitempty"`
+	Signer            string `json:"signer,omitempty"`
+	Root              string `json:"root,omitempty"`
+	OnchainRoot       string `json:"onchainRoot,omitempty"`
+	Error             string `json:"error,omitempty"`
+}
+
+// snapshotVerifier verifies the snapshots downloaded with its HTTP client. The root computed from a
+// snapshot has to match the root posted on-chain, since that is what claims are checked against.
+// The checksum and the signature the proof store publishes next to the snapshot, at <url>.sha256
+// and <url>.sig, are checked as well if they are published or a signer is set.
+type snapshotVerifier struct {
+	checksums *checksumTransport
+	client    *http.Client
+	signer    gethcommon.Address
+	roots     catchUpRootReader
+}
+
+func newSnapshotVerifier(signer gethcommon.Address, roots catchUpRootReader) *snapshotVerifier {
+	checksums := &checksumTransport{next: http.DefaultTransport, digests: make(map[string][]byte)}
+	return &snapshotVerifier{
+		checksums: checksums,
+		client:    &http.Client{Transport: checksums},
+		signer:    signer,
+		roots:     roots,
+	}
+}
+
+// newRewardsSnapshotVerifier creates a snapshot verifier reading the posted roots from the
+// rewards coordinator
+func newRewardsSnapshotVerifier(
+	ethClient *ethclient.Client,
+	rewardsCoordinatorAddress gethcommon.Address,
+	signer gethcommon.Address,
+	logger logging.Logger,
+) (*snapshotVerifier, error) {
+	_, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
+		RewardsCoordinatorAddress: rewardsCoordinatorAddress,
+	}, ethClient, nil, logger, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newSnapshotVerifier(signer, contractBindings.RewardsCoordinator), nil
+}
+
+// HTTPClient is the client to download snapshots with, which hashes them as they are read
+func (v *snapshotVerifier) HTTPClient() *http.Client {
+	return v.client
+}
+
+// Verify verifies the snapshot of the date, which has to be downloaded with the client of the
+// verifier, and records the result in the metadata of the run
+func (v *snapshotVerifier) Verify(
+	ctx context.Context,
+	date string,
+	rootIndex uint32,
+	snapshot *distribution.Distribution,
+	logger logging.Logger,
+) (*SnapshotVerification, error) {
+	url, digest, ok := v.checksums.get("/" + date + "/" + snapshotFileName)
+	if !ok {
+		return nil, fmt.Errorf("the snapshot of %s was not downloaded", date)
+	}
+
+	result := &SnapshotVerification{
+		Date:      date,
+		RootIndex: rootIndex,
+		URL:       url,
+		SHA256:    hex.EncodeToString(digest),
+	}
+	err := v.verify(ctx, result, digest, snapshot)
+	if err != nil {
+		result.Error = err.Error()
+	}
+	common.SetRunMeta(snapshotVerificationMetaKey, result)
+	if err != nil {
+		return result, err
+	}
+	logger.Infof("Rewards snapshot of %s verified with its %s, sha256 %s", date, result.Method, result.SHA256)
+	return result, nil
+}
+
+func (v *snapshotVerifier) verify(
+	ctx context.Context,
+	result *SnapshotVerification,
+	digest []byte,
+	snapshot *distribution.Distribution,
+) error {
+	result.Method = SnapshotVerificationRoot
+	accountTree, _, err := snapshot.Merklize()
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to compute the root of the snapshot", err)
+	}
+	root := gethcommon.BytesToHash(accountTree.Root())
+	result.Root = root.Hex()
+	posted, err := v.roots.GetDistributionRootAtIndex(
+		&bind.CallOpts{Context: ctx},
+		new(big.Int).SetUint64(uint64(result.RootIndex)),
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get the posted distribution root", err)
+	}
+	result.OnchainRoot = gethcommon.Hash(posted.Root).Hex()
+	if root != posted.Root {
+		return fmt.Errorf(
+			"%w: root %s of the snapshot does not match the posted root %s",
+			ErrSnapshotNotAuthentic,
+			result.Root,
+			result.OnchainRoot,
+		)
+	}
+
+	published, err := v.fetch(ctx, result.URL+checksumSuffix)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to fetch the published checksum", err)
+	}
+	if published != nil {
+		result.Method = SnapshotVerificationChecksum
+		result.PublishedChecksum = parseChecksum(published)
+		if !strings.EqualFold(result.PublishedChecksum, result.SHA256) {
+			return fmt.Errorf(
+				"%w: sha256 %s does not match the published checksum %s",
+				ErrSnapshotNotAuthentic,
+				result.SHA256,
+				result.PublishedChecksum,
+			)
+		}
+	}
+
+	if v.signer != (gethcommon.Address{}) {
+		result.Method = SnapshotVerificationSignature
+		result.Signer = v.signer.Hex()
+		signature, err := v.fetch(ctx, result.URL+signatureSuffix)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to fetch the published signature", err)
+		}
+		if signature == nil {
+			return fmt.Errorf("%w: no signature is published at %s", ErrSnapshotNotAuthentic, result.URL+signatureSuffix)
+		}
+		if err := verifySnapshotSignature(digest, string(signature), v.signer); err != nil {
+			return err
+		}
+	}
+	result.Verified = true
+	return nil
+}
+
+// fetch downloads a file published next to the snapshot. It returns nil if there is none.
+func (v *snapshotVerifier) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	// S3 answers 403 for missing files of buckets which can't be listed
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s of %s", resp.Status, url)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// parseChecksum parses a checksum in the format of sha256sum, '<hex>  claim-amounts.json', or a
+// bare hex checksum
+func parseChecksum(data []byte) string {
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(fields[0]), "0x")
+}
+
+// verifySnapshotSignature checks the hex signature is an EIP-191 signature of the signer over the
+// sha256 digest of the snapshot, as created with eth_sign or 'cast wallet sign'
+func verifySnapshotSignature(digest []byte, signatureHex string, signer gethcommon.Address) error {
+	signature, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signatureHex), "0x"))
+	if err != nil || len(signature) != crypto.SignatureLength {
+		return fmt.Errorf("%w: invalid signature %s", ErrSnapshotNotAuthentic, strings.TrimSpace(signatureHex))
+	}
+	if signature[crypto.RecoveryIDOffset] >= 27 {
+		signature[crypto.RecoveryIDOffset] -= 27
+	}
+	publicKey, err := crypto.SigToPub(accounts.TextHash(digest), signature)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrSnapshotNotAuthentic, err)
+	}
+	if recovered := crypto.PubkeyToAddress(*publicKey); recovered != signer {
+		return fmt.Errorf(
+			"%w: signed by %s instead of %s",
+			ErrSnapshotNotAuthentic,
+			recovered.Hex(),
+			signer.Hex(),
+		)
+	}
+	return nil
+}
+
+// readProofStoreSigner reads the address the proof store signs the checksums of snapshots with.
+// The zero address means signatures are not checked.
+func readProofStoreSigner(cCtx *cli.Context) (gethcommon.Address, error) {
+	signer := cCtx.String(ProofStoreSignerFlag.Name)
+	if common.IsEmptyString(signer) {
+		return gethcommon.Address{}, nil
+	}
+	if !gethcommon.IsHexAddress(signer) {
+		return gethcommon.Address{}, fmt.Errorf("invalid proof store signer address %s", signer)
+	}
+	return gethcommon.HexToAddress(signer), nil
+}
+
+// checksumTransport hashes the snapshots it downloads as they are read. The digest of a snapshot
+// is only kept once its body was read completely.
+type checksumTransport struct {
+	next    http.RoundTripper
+	mu      sync.Mutex
+	digests map[string][]byte
+}
+
+func (t *checksumTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK && strings.HasSuffix(req.URL.Path, "/"+snapshotFileName) {
+		resp.Body = &hashingBody{ReadCloser: resp.Body, hash: sha256.New(), url: req.URL.String(), transport: t}
+	}
+	return resp, nil
+}
+
+// get returns the URL and the digest of the downloaded snapshot whose URL ends with suffix
+func (t *checksumTransport) get(suffix string) (string, []byte, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for url, digest := range t.digests {
+		if strings.HasSuffix(url, suffix) {
+			return url, digest, true
+		}
+	}
+	return "", nil, false
+}
+
+func (t *checksumTransport) set(url string, digest []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.digests[url] = digest
+}
+
+type hashingBody struct {
+	io.ReadCloser
+	hash      hash.Hash
+	url       string
+	transport *checksumTransport
+}
+
+func (b *hashingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.hash.Write(p[:n])
+	if errors.Is(err, io.EOF) {
+		b.transport.set(b.url, b.hash.Sum(nil))
+	}
+	return n, err
+}
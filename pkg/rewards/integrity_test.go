@@ -0,0 +1,153 @@
+package rewards
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/proofDataFetcher/httpProofDataFetcher"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSnapshot = `{"earner":"0x1111111111111111111111111111111111111111",` +
+	`"token":"0x2222222222222222222222222222222222222222","cumulative_amount":"100"}
+{"earner":"0x3333333333333333333333333333333333333333",` +
+	`"token":"0x2222222222222222222222222222222222222222","cumulative_amount":"250"}
+`
+
+// newTestProofStore serves the test snapshot of 2024-08-01 and the given files published next to it
+func newTestProofStore(t *testing.T, files map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suffix, found := strings.CutPrefix(r.URL.Path, "/local/anvil/2024-08-01/claim-amounts.json")
+		if found && suffix == "" {
+			_, _ = w.Write([]byte(testSnapshot))
+			return
+		}
+		if content, ok := files[suffix]; found && ok {
+			_, _ = w.Write([]byte(content))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func verifyTestSnapshot(
+	t *testing.T,
+	files map[string]string,
+	signer gethcommon.Address,
+	roots fakeCatchUpRoots,
+) (*SnapshotVerification, error) {
+	server := newTestProofStore(t, files)
+	verifier := newSnapshotVerifier(signer, roots)
+	df := httpProofDataFetcher.NewHttpProofDataFetcher(server.URL, "local", "anvil", verifier.HTTPClient())
+	proofData, err := df.FetchClaimAmountsForDate(context.Background(), "2024-08-01")
+	assert.NoError(t, err)
+
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	return verifier.Verify(context.Background(), "2024-08-01", 0, proofData.Distribution, logger)
+}
+
+// testSnapshotRoots returns the posted roots of a chain the test snapshot was posted to
+func testSnapshotRoots(t *testing.T) fakeCatchUpRoots {
+	server := newTestProofStore(t, nil)
+	df := httpProofDataFetcher.NewHttpProofDataFetcher(server.URL, "local", "anvil", http.DefaultClient)
+	proofData, err := df.FetchClaimAmountsForDate(context.Background(), "2024-08-01")
+	assert.NoError(t, err)
+	accountTree, _, err := proofData.Distribution.Merklize()
+	assert.NoError(t, err)
+	var root [32]byte
+	copy(root[:], accountTree.Root())
+	return fakeCatchUpRoots{{Root: root}}
+}
+
+func TestVerifySnapshotChecksum(t *testing.T) {
+	roots := testSnapshotRoots(t)
+	digest := sha256.Sum256([]byte(testSnapshot))
+	checksum := hex.EncodeToString(digest[:])
+
+	result, err := verifyTestSnapshot(t, map[string]string{
+		checksumSuffix: checksum + "  claim-amounts.json\n",
+	}, gethcommon.Address{}, roots)
+	assert.NoError(t, err)
+	assert.True(t, result.Verified)
+	assert.Equal(t, SnapshotVerificationChecksum, result.Method)
+	assert.Equal(t, result.Root, result.OnchainRoot)
+	assert.Equal(t, checksum, result.SHA256)
+	assert.Equal(t, result, common.GetRunMeta()[snapshotVerificationMetaKey])
+
+	result, err = verifyTestSnapshot(t, map[string]string{
+		checksumSuffix: hex.EncodeToString(make([]byte, 32)),
+	}, gethcommon.Address{}, roots)
+	assert.ErrorIs(t, err, ErrSnapshotNotAuthentic)
+	assert.False(t, result.Verified)
+	assert.NotEmpty(t, result.Error)
+	assert.Equal(t, result, common.GetRunMeta()[snapshotVerificationMetaKey])
+
+	// A matching checksum doesn't make up for a root which was never posted
+	_, err = verifyTestSnapshot(t, map[string]string{
+		checksumSuffix: checksum,
+	}, gethcommon.Address{}, fakeCatchUpRoots{{Root: [32]byte{1}}})
+	assert.ErrorIs(t, err, ErrSnapshotNotAuthentic)
+}
+
+func TestVerifySnapshotSignature(t *testing.T) {
+	roots := testSnapshotRoots(t)
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	digest := sha256.Sum256([]byte(testSnapshot))
+	signature, err := crypto.Sign(accounts.TextHash(digest[:]), key)
+	assert.NoError(t, err)
+	signature[crypto.RecoveryIDOffset] += 27
+
+	result, err := verifyTestSnapshot(t, map[string]string{
+		signatureSuffix: "0x" + hex.EncodeToString(signature),
+	}, signer, roots)
+	assert.NoError(t, err)
+	assert.True(t, result.Verified)
+	assert.Equal(t, SnapshotVerificationSignature, result.Method)
+	assert.Equal(t, signer.Hex(), result.Signer)
+
+	// A signature of another signer is rejected
+	_, err = verifyTestSnapshot(t, map[string]string{
+		signatureSuffix: "0x" + hex.EncodeToString(signature),
+	}, gethcommon.HexToAddress("0x4444444444444444444444444444444444444444"), roots)
+	assert.ErrorIs(t, err, ErrSnapshotNotAuthentic)
+
+	// With a signer set, a snapshot without a signature is rejected
+	_, err = verifyTestSnapshot(t, nil, signer, roots)
+	assert.ErrorIs(t, err, ErrSnapshotNotAuthentic)
+}
+
+func TestVerifySnapshotRoot(t *testing.T) {
+	result, err := verifyTestSnapshot(t, nil, gethcommon.Address{}, testSnapshotRoots(t))
+	assert.NoError(t, err)
+	assert.True(t, result.Verified)
+	assert.Equal(t, SnapshotVerificationRoot, result.Method)
+	assert.Equal(t, result.Root, result.OnchainRoot)
+
+	result, err = verifyTestSnapshot(t, nil, gethcommon.Address{}, fakeCatchUpRoots{{Root: [32]byte{1}}})
+	assert.ErrorIs(t, err, ErrSnapshotNotAuthentic)
+	assert.NotEqual(t, result.Root, result.OnchainRoot)
+}
+
+func TestParseChecksum(t *testing.T) {
+	assert.Equal(t, "abcd", parseChecksum([]byte("ABCD  claim-amounts.json\n")))
+	assert.Equal(t, "abcd", parseChecksum([]byte("0xabcd")))
+	assert.Equal(t, "", parseChecksum([]byte("  ")))
+}
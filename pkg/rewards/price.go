@@ -0,0 +1,234 @@
+package rewards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/gocarina/gocsv"
+)
+
+const (
+	NoPriceSource        = "none"
+	CoinGeckoPriceSource = "coingecko"
+	FilePriceSource      = "file"
+	ChainlinkPriceSource = "chainlink"
+	HTTPPriceSource      = "http"
+
+	coinGeckoBaseURL = "https://api.coingecko.com/api/v3"
+	// coinGeckoRateLimit stays below the limit of the public CoinGecko API of about 30 requests a minute
+	coinGeckoRateLimit = 0.4
+)
+
+var ErrPriceNotFound = errors.New("price not found")
+
+// PriceSource returns the USD price of a token at a given time. Sources with daily prices return
+// the price of the day of the given time.
+type PriceSource interface {
+	GetPrice(ctx context.Context, token gethcommon.Address, symbol string, date time.Time) (float64, error)
+}
+
+// NewPriceSource creates the price source of the config. CoinGecko and HTTP sources are rate limited,
+// and the prices of network sources are kept in a disk cache unless disabled, since past prices
+// don't change. Returns nil for the 'none' source.
+func NewPriceSource(cfg PriceConfig, client bind.ContractCaller) (PriceSource, error) {
+	switch cfg.Source {
+	case NoPriceSource:
+		return nil, nil
+	case FilePriceSource:
+		return newFilePriceSource(cfg.File)
+	}
+
+	var source PriceSource
+	cacheName, cacheLayout := cfg.Source, time.DateOnly
+	switch cfg.Source {
+	case CoinGeckoPriceSource:
+		rateLimit := cfg.RateLimit
+		if rateLimit == 0 {
+			rateLimit = coinGeckoRateLimit
+		}
+		source = newCoinGeckoPriceSource(cfg.Network, newRateLimiter(rateLimit))
+	case HTTPPriceSource:
+		httpSource, err := newHTTPPriceSource(cfg.URL, cfg.AuthToken, newRateLimiter(cfg.RateLimit))
+		if err != nil {
+			return nil, err
+		}
+		source = httpSource
+		// Another URL is another source, so its prices are cached apart
+		httpCacheName, err := getHTTPPriceCacheName(cfg.URL)
+		if err != nil {
+			return nil, err
+		}
+		cacheName = HTTPPriceSource + "/" + httpCacheName
+	case ChainlinkPriceSource:
+		if client == nil {
+			return nil, errors.New("an RPC client is required for the chainlink price source")
+		}
+		chainlinkSource, err := newChainlinkPriceSource(client, cfg.ChainlinkFeeds)
+		if err != nil {
+			return nil, err
+		}
+		source = chainlinkSource
+		cacheName = fmt.Sprintf("%s/%s", ChainlinkPriceSource, cfg.Network)
+		// Feeds have prices of any time, not of a day
+		cacheLayout = time.RFC3339
+	default:
+		return nil, fmt.Errorf("price source %s is not supported", cfg.Source)
+	}
+
+	if !cfg.Cache {
+		return source, nil
+	}
+	return newCachedPriceSource(source, cacheName, cacheLayout, getPriceCachePath()), nil
+}
+
+// coinGeckoPriceSource reads historical daily prices from the public CoinGecko API
+type coinGeckoPriceSource struct {
+	client   *priceHTTPClient
+	baseURL  string
+	platform string
+	coinIds  map[gethcommon.Address]string
+	prices   map[string]float64
+}
+
+func newCoinGeckoPriceSource(network string, limiter *rateLimiter) *coinGeckoPriceSource {
+	return &coinGeckoPriceSource{
+		client:   &priceHTTPClient{client: http.DefaultClient, limiter: limiter},
+		baseURL:  coinGeckoBaseURL,
+		platform: getCoinGeckoPlatform(network),
+		coinIds:  make(map[gethcommon.Address]string),
+		prices:   make(map[string]float64),
+	}
+}
+
+func (s *coinGeckoPriceSource) GetPrice(
+	ctx context.Context,
+	token gethcommon.Address,
+	symbol string,
+	date time.Time,
+) (float64, error) {
+	coinId, err := s.getCoinId(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+
+	day := date.UTC().Format("02-01-2006")
+	cacheKey := coinId + "/" + day
+	if price, ok := s.prices[cacheKey]; ok {
+		return price, nil
+	}
+
+	var history struct {
+		MarketData struct {
+			CurrentPrice map[string]float64 `json:"current_price"`
+		} `json:"market_data"`
+	}
+	url := fmt.Sprintf("%s/coins/%s/history?date=%s&localization=false", s.baseURL, coinId, day)
+	err = s.client.get(ctx, url, &history)
+	if err != nil {
+		return 0, err
+	}
+	price, ok := history.MarketData.CurrentPrice["usd"]
+	if !ok {
+		return 0, fmt.Errorf("%w: no USD price for %s on %s", ErrPriceNotFound, symbol, day)
+	}
+	s.prices[cacheKey] = price
+	return price, nil
+}
+
+func (s *coinGeckoPriceSource) getCoinId(ctx context.Context, token gethcommon.Address) (string, error) {
+	if coinId, ok := s.coinIds[token]; ok {
+		return coinId, nil
+	}
+	if s.platform == "" {
+		return "", fmt.Errorf("%w: network is not supported by coingecko", ErrPriceNotFound)
+	}
+
+	var coin struct {
+		Id string `json:"id"`
+	}
+	url := fmt.Sprintf("%s/coins/%s/contract/%s", s.baseURL, s.platform, strings.ToLower(token.Hex()))
+	err := s.client.get(ctx, url, &coin)
+	if err != nil {
+		return "", err
+	}
+	s.coinIds[token] = coin.Id
+	return coin.Id, nil
+}
+
+func getCoinGeckoPlatform(network string) string {
+	switch network {
+	case utils.MainnetNetworkName, "ethereum":
+		return "ethereum"
+	default:
+		return ""
+	}
+}
+
+type filePrice struct {
+	Date     string `csv:"date"`
+	Token    string `csv:"token"`
+	PriceUSD string `csv:"price_usd"`
+}
+
+// filePriceSource reads static prices from a CSV file with the columns date (YYYY-MM-DD), token
+// (address or symbol) and price_usd. This is useful when prices come from an internal source.
+type filePriceSource struct {
+	prices map[string]float64
+}
+
+func newFilePriceSource(path string) (*filePriceSource, error) {
+	if path == "" {
+		return nil, errors.New("price file is required for the file price source")
+	}
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var rows []filePrice
+	if err := gocsv.UnmarshalFile(file, &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse price file: %w", err)
+	}
+
+	prices := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		price, err := strconv.ParseFloat(strings.TrimSpace(row.PriceUSD), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price %q for %s on %s: %w", row.PriceUSD, row.Token, row.Date, err)
+		}
+		prices[filePriceKey(row.Date, row.Token)] = price
+	}
+	return &filePriceSource{prices: prices}, nil
+}
+
+func (s *filePriceSource) GetPrice(
+	ctx context.Context,
+	token gethcommon.Address,
+	symbol string,
+	date time.Time,
+) (float64, error) {
+	day := date.UTC().Format(time.DateOnly)
+	if price, ok := s.prices[filePriceKey(day, token.Hex())]; ok {
+		return price, nil
+	}
+	if price, ok := s.prices[filePriceKey(day, symbol)]; ok {
+		return price, nil
+	}
+	return 0, fmt.Errorf("%w: no price for %s on %s in price file", ErrPriceNotFound, symbol, day)
+}
+
+func filePriceKey(date, token string) string {
+	return strings.TrimSpace(date) + "/" + strings.ToLower(strings.TrimSpace(token))
+}
@@ -0,0 +1,97 @@
+package rewards
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// priceCacheSubPath is the location of the price cache inside the home directory
+const priceCacheSubPath = ".eigenlayer/price-cache.json"
+
+func getPriceCachePath() string {
+	homePath, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homePath, priceCacheSubPath)
+}
+
+// cachedPriceSource keeps the prices of a source in a disk cache shared by all runs, keyed by the
+// source, the token and the time in the layout of the source, e.g. the day for daily prices.
+// Prices of the current day are not cached, since they may still change.
+type cachedPriceSource struct {
+	source PriceSource
+	name   string
+	layout string
+	path   string
+	now    func() time.Time
+
+	mu     sync.Mutex
+	prices map[string]float64
+}
+
+func newCachedPriceSource(source PriceSource, name string, layout string, path string) *cachedPriceSource {
+	return &cachedPriceSource{source: source, name: name, layout: layout, path: path, now: time.Now}
+}
+
+func (s *cachedPriceSource) GetPrice(
+	ctx context.Context,
+	token gethcommon.Address,
+	symbol string,
+	date time.Time,
+) (float64, error) {
+	key := fmt.Sprintf("%s/%s/%s", s.name, strings.ToLower(token.Hex()), date.UTC().Format(s.layout))
+	if price, ok := s.get(key); ok {
+		return price, nil
+	}
+
+	price, err := s.source.GetPrice(ctx, token, symbol, date)
+	if err != nil {
+		return 0, err
+	}
+	if date.UTC().Format(time.DateOnly) < s.now().UTC().Format(time.DateOnly) {
+		// The price is still returned if the cache can't be written
+		_ = s.put(key, price)
+	}
+	return price, nil
+}
+
+func (s *cachedPriceSource) get(key string) (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.prices == nil {
+		s.prices = make(map[string]float64)
+		data, err := common.ReadFileLocked(s.path)
+		if err == nil {
+			// A corrupt cache is overwritten with the next price
+			_ = json.Unmarshal(data, &s.prices)
+		}
+	}
+	price, ok := s.prices[key]
+	return price, ok
+}
+
+func (s *cachedPriceSource) put(key string, price float64) error {
+	s.mu.Lock()
+	s.prices[key] = price
+	s.mu.Unlock()
+
+	return common.UpdateFileLocked(s.path, 0o600, func(data []byte) ([]byte, error) {
+		prices := make(map[string]float64)
+		if len(data) > 0 && json.Unmarshal(data, &prices) != nil {
+			prices = make(map[string]float64)
+		}
+		prices[key] = price
+		return json.MarshalIndent(prices, "", "  ")
+	})
+}
@@ -0,0 +1,81 @@
+package rewards
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingPriceSource returns a fixed price and counts the calls
+type countingPriceSource struct {
+	calls int
+}
+
+func (s *countingPriceSource) GetPrice(
+	ctx context.Context,
+	token gethcommon.Address,
+	symbol string,
+	date time.Time,
+) (float64, error) {
+	s.calls++
+	return 2.5, nil
+}
+
+func TestCachedPriceSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "price-cache.json")
+	token := gethcommon.HexToAddress("0x3B78576F7D6837500bA3De27A60c7f594934027E")
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	source := &countingPriceSource{}
+
+	cached := newCachedPriceSource(source, CoinGeckoPriceSource, time.DateOnly, path)
+	cached.now = func() time.Time { return now }
+	for i := 0; i < 2; i++ {
+		price, err := cached.GetPrice(context.Background(), token, "EIGEN", time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.Equal(t, 2.5, price)
+	}
+	assert.Equal(t, 1, source.calls)
+
+	// The cache is shared with later runs
+	cached = newCachedPriceSource(source, CoinGeckoPriceSource, time.DateOnly, path)
+	cached.now = func() time.Time { return now }
+	_, err := cached.GetPrice(context.Background(), token, "EIGEN", time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, source.calls)
+
+	// Other sources don't share prices
+	other := newCachedPriceSource(source, ChainlinkPriceSource, time.DateOnly, path)
+	other.now = func() time.Time { return now }
+	_, err = other.GetPrice(context.Background(), token, "EIGEN", time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, source.calls)
+
+	// Prices of the current day are not cached
+	for i := 0; i < 2; i++ {
+		_, err = cached.GetPrice(context.Background(), token, "EIGEN", now)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 4, source.calls)
+}
+
+func TestRateLimiter(t *testing.T) {
+	assert.Nil(t, newRateLimiter(0))
+	assert.NoError(t, newRateLimiter(0).Wait(context.Background()))
+
+	limiter := newRateLimiter(20)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, limiter.Wait(context.Background()))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	limiter = newRateLimiter(0.001)
+	assert.NoError(t, limiter.Wait(ctx))
+	assert.ErrorIs(t, limiter.Wait(ctx), context.Canceled)
+}
@@ -0,0 +1,228 @@
+package rewards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// chainlinkAggregatorABI is the part of the AggregatorV3Interface of Chainlink price feeds the
+// chainlink price source reads
+const chainlinkAggregatorABI = `[
+{"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"latestRoundData","outputs":[{"name":"roundId","type":"uint80"},{"name":"answer","type":"int256"},
+{"name":"startedAt","type":"uint256"},{"name":"updatedAt","type":"uint256"},{"name":"answeredInRound","type":"uint80"}],
+"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"_roundId","type":"uint80"}],"name":"getRoundData","outputs":[{"name":"roundId","type":"uint80"},
+{"name":"answer","type":"int256"},{"name":"startedAt","type":"uint256"},{"name":"updatedAt","type":"uint256"},
+{"name":"answeredInRound","type":"uint80"}],"stateMutability":"view","type":"function"}
+]`
+
+// chainlinkPhaseOffset is the bit offset of the phase in the round IDs of a feed proxy
+const chainlinkPhaseOffset = 64
+
+// chainlinkClient calls the price feeds, e.g. an eth client
+type chainlinkClient interface {
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+type chainlinkRound struct {
+	RoundId         *big.Int
+	Answer          *big.Int
+	StartedAt       *big.Int
+	UpdatedAt       *big.Int
+	AnsweredInRound *big.Int
+}
+
+// chainlinkPriceSource reads prices on-chain from Chainlink USD price feeds, configured by token
+// address or symbol. The price at a time is the answer of the last round updated before it, found
+// with a binary search over the rounds of the current phase of the feed, so it doesn't need an
+// archive node.
+type chainlinkPriceSource struct {
+	client chainlinkClient
+	abi    abi.ABI
+	feeds  map[string]gethcommon.Address
+
+	mu       sync.Mutex
+	decimals map[gethcommon.Address]uint8
+}
+
+func newChainlinkPriceSource(
+	client chainlinkClient,
+	feeds map[string]gethcommon.Address,
+) (*chainlinkPriceSource, error) {
+	if len(feeds) == 0 {
+		return nil, errors.New("chainlink feeds are required for the chainlink price source")
+	}
+	parsed, err := abi.JSON(strings.NewReader(chainlinkAggregatorABI))
+	if err != nil {
+		return nil, err
+	}
+	return &chainlinkPriceSource{
+		client:   client,
+		abi:      parsed,
+		feeds:    feeds,
+		decimals: make(map[gethcommon.Address]uint8),
+	}, nil
+}
+
+// parseChainlinkFeeds parses feeds like 'EIGEN=0x...,0xTOKEN=0x...' into the feed of every token
+// address or symbol
+func parseChainlinkFeeds(value string) (map[string]gethcommon.Address, error) {
+	feeds := make(map[string]gethcommon.Address)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		token, feed, ok := strings.Cut(entry, "=")
+		token, feed = strings.TrimSpace(token), strings.TrimSpace(feed)
+		if !ok || token == "" || !gethcommon.IsHexAddress(feed) {
+			return nil, fmt.Errorf("invalid chainlink feed %s, expected token=feed address", entry)
+		}
+		feeds[strings.ToLower(token)] = gethcommon.HexToAddress(feed)
+	}
+	return feeds, nil
+}
+
+func (s *chainlinkPriceSource) GetPrice(
+	ctx context.Context,
+	token gethcommon.Address,
+	symbol string,
+	date time.Time,
+) (float64, error) {
+	feed, ok := s.feeds[strings.ToLower(token.Hex())]
+	if !ok {
+		feed, ok = s.feeds[strings.ToLower(symbol)]
+	}
+	if !ok {
+		return 0, fmt.Errorf("%w: no chainlink feed for %s", ErrPriceNotFound, symbol)
+	}
+
+	latest, err := s.getRound(ctx, feed, "latestRoundData")
+	if err != nil {
+		return 0, err
+	}
+	round, err := s.findRound(ctx, feed, latest, date)
+	if err != nil {
+		return 0, err
+	}
+	if round == nil {
+		return 0, fmt.Errorf(
+			"%w: the chainlink feed %s has no round of %s before %s",
+			ErrPriceNotFound,
+			feed.Hex(),
+			symbol,
+			date.UTC().Format(time.RFC3339),
+		)
+	}
+
+	decimals, err := s.getDecimals(ctx, feed)
+	if err != nil {
+		return 0, err
+	}
+	price, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(round.Answer),
+		new(big.Float).SetFloat64(math.Pow10(int(decimals))),
+	).Float64()
+	return price, nil
+}
+
+// findRound returns the last round of the current phase updated at or before the date, or nil if
+// the phase started after it
+func (s *chainlinkPriceSource) findRound(
+	ctx context.Context,
+	feed gethcommon.Address,
+	latest *chainlinkRound,
+	date time.Time,
+) (*chainlinkRound, error) {
+	target := big.NewInt(date.Unix())
+	if latest.UpdatedAt.Cmp(target) <= 0 {
+		return latest, nil
+	}
+
+	phase := new(big.Int).Rsh(latest.RoundId, chainlinkPhaseOffset)
+	phaseBase := new(big.Int).Lsh(phase, chainlinkPhaseOffset)
+	latestInPhase := new(big.Int).Sub(latest.RoundId, phaseBase).Uint64()
+	if latestInPhase < 2 {
+		return nil, nil
+	}
+
+	// Rounds of a phase start at 1 and are updated in order
+	var found *chainlinkRound
+	low, high := uint64(1), latestInPhase-1
+	for low <= high && high > 0 {
+		mid := low + (high-low)/2
+		roundId := new(big.Int).Add(phaseBase, new(big.Int).SetUint64(mid))
+		round, err := s.getRound(ctx, feed, "getRoundData", roundId)
+		if err != nil {
+			return nil, err
+		}
+		if round.UpdatedAt.Sign() > 0 && round.UpdatedAt.Cmp(target) <= 0 {
+			found = round
+			low = mid + 1
+		} else {
+			high = mid - 1
+		}
+	}
+	return found, nil
+}
+
+func (s *chainlinkPriceSource) getRound(
+	ctx context.Context,
+	feed gethcommon.Address,
+	method string,
+	args ...interface{},
+) (*chainlinkRound, error) {
+	var round chainlinkRound
+	if err := s.call(ctx, feed, &round, method, args...); err != nil {
+		return nil, err
+	}
+	return &round, nil
+}
+
+func (s *chainlinkPriceSource) getDecimals(ctx context.Context, feed gethcommon.Address) (uint8, error) {
+	s.mu.Lock()
+	decimals, ok := s.decimals[feed]
+	s.mu.Unlock()
+	if ok {
+		return decimals, nil
+	}
+	if err := s.call(ctx, feed, &decimals, "decimals"); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	s.decimals[feed] = decimals
+	s.mu.Unlock()
+	return decimals, nil
+}
+
+func (s *chainlinkPriceSource) call(
+	ctx context.Context,
+	feed gethcommon.Address,
+	out interface{},
+	method string,
+	args ...interface{},
+) error {
+	data, err := s.abi.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+	result, err := s.client.CallContract(ctx, ethereum.CallMsg{To: &feed, Data: data}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to call %s of chainlink feed %s: %w", method, feed.Hex(), err)
+	}
+	if err := s.abi.UnpackIntoInterface(out, method, result); err != nil {
+		return fmt.Errorf("failed to decode %s of chainlink feed %s: %w", method, feed.Hex(), err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package rewards
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeChainlinkFeed has the rounds 1 to 5 of phase 1, round i updated at 1000*i with a price of i USD
+type fakeChainlinkFeed struct {
+	abi abi.ABI
+}
+
+func (f *fakeChainlinkFeed) CallContract(
+	ctx context.Context,
+	msg ethereum.CallMsg,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	method, err := f.abi.MethodById(msg.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	phaseBase := new(big.Int).Lsh(big.NewInt(1), chainlinkPhaseOffset)
+	round := int64(5)
+	switch method.Name {
+	case "decimals":
+		return method.Outputs.Pack(uint8(8))
+	case "getRoundData":
+		args, err := method.Inputs.Unpack(msg.Data[4:])
+		if err != nil {
+			return nil, err
+		}
+		round = new(big.Int).Sub(args[0].(*big.Int), phaseBase).Int64()
+		if round < 1 || round > 5 {
+			return nil, errors.New("execution reverted: No data present")
+		}
+	}
+	roundId := new(big.Int).Add(phaseBase, big.NewInt(round))
+	return method.Outputs.Pack(
+		roundId,
+		new(big.Int).Mul(big.NewInt(round), big.NewInt(100_000_000)),
+		big.NewInt(1000*round),
+		big.NewInt(1000*round),
+		roundId,
+	)
+}
+
+func TestChainlinkPriceSource(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(chainlinkAggregatorABI))
+	assert.NoError(t, err)
+	feeds, err := parseChainlinkFeeds("EIGEN=0x0000000000000000000000000000000000000001")
+	assert.NoError(t, err)
+	source, err := newChainlinkPriceSource(&fakeChainlinkFeed{abi: parsed}, feeds)
+	assert.NoError(t, err)
+	token := gethcommon.HexToAddress("0x3B78576F7D6837500bA3De27A60c7f594934027E")
+
+	price, err := source.GetPrice(context.Background(), token, "EIGEN", time.Unix(3500, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, 3.0, price)
+
+	price, err = source.GetPrice(context.Background(), token, "EIGEN", time.Unix(1000, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, price)
+
+	price, err = source.GetPrice(context.Background(), token, "EIGEN", time.Unix(9000, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, 5.0, price)
+
+	_, err = source.GetPrice(context.Background(), token, "EIGEN", time.Unix(500, 0))
+	assert.ErrorIs(t, err, ErrPriceNotFound)
+
+	_, err = source.GetPrice(context.Background(), token, "OTHER", time.Unix(3500, 0))
+	assert.ErrorIs(t, err, ErrPriceNotFound)
+}
+
+func TestParseChainlinkFeeds(t *testing.T) {
+	feeds, err := parseChainlinkFeeds(
+		"EIGEN=0x0000000000000000000000000000000000000001, " +
+			"0x3B78576F7D6837500bA3De27A60c7f594934027E=0x0000000000000000000000000000000000000002",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, gethcommon.HexToAddress("0x01"), feeds["eigen"])
+	assert.Equal(t, gethcommon.HexToAddress("0x02"), feeds["0x3b78576f7d6837500ba3de27a60c7f594934027e"])
+
+	_, err = parseChainlinkFeeds("EIGEN")
+	assert.Error(t, err)
+	_, err = parseChainlinkFeeds("EIGEN=not-an-address")
+	assert.Error(t, err)
+}
@@ -0,0 +1,183 @@
+package rewards
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// httpPriceSource reads prices from a custom HTTP service, e.g. the internal price service of a
+// company. The URL is a template with the placeholders {token}, {symbol}, {date} (YYYY-MM-DD) and
+// {timestamp} (unix seconds), and the service answers with {"priceUsd": 3.5}.
+type httpPriceSource struct {
+	client      *priceHTTPClient
+	urlTemplate string
+}
+
+func newHTTPPriceSource(urlTemplate string, authToken string, limiter *rateLimiter) (*httpPriceSource, error) {
+	if urlTemplate == "" {
+		return nil, errors.New("price URL is required for the http price source")
+	}
+	if _, err := url.Parse(urlTemplate); err != nil {
+		return nil, fmt.Errorf("invalid price URL %s: %w", urlTemplate, err)
+	}
+	return &httpPriceSource{
+		client:      &priceHTTPClient{client: http.DefaultClient, limiter: limiter, authToken: authToken},
+		urlTemplate: urlTemplate,
+	}, nil
+}
+
+// credentialQueryKeys are the query keys price services commonly take API keys and tokens in,
+// compared case insensitively
+var credentialQueryKeys = map[string]bool{
+	"apikey":            true,
+	"api_key":           true,
+	"api-key":           true,
+	"key":               true,
+	"token":             true,
+	"access_token":      true,
+	"auth":              true,
+	"secret":            true,
+	"password":          true,
+	"signature":         true,
+	"x_cg_pro_api_key":  true,
+	"x_cg_demo_api_key": true,
+}
+
+// getHTTPPriceCacheName returns the name of an HTTP price source in the price cache. The cache is a
+// plain file, so the user info and the query values which are known credentials are left out. Any
+// other query value is kept, since e.g. ?currency=usd and ?currency=eur are different prices.
+func getHTTPPriceCacheName(urlTemplate string) (string, error) {
+	parsed, err := url.Parse(urlTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid price URL: %w", err)
+	}
+	query := make([]string, 0)
+	for key, values := range parsed.Query() {
+		for _, value := range values {
+			// A placeholder, e.g. ?token={token}, is never a credential
+			placeholder := strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}")
+			if credentialQueryKeys[strings.ToLower(key)] && !placeholder {
+				continue
+			}
+			query = append(query, key+"="+value)
+		}
+	}
+	sort.Strings(query)
+	name := parsed.Host + parsed.Path
+	if len(query) > 0 {
+		name += "?" + strings.Join(query, "&")
+	}
+	return name, nil
+}
+
+func (s *httpPriceSource) GetPrice(
+	ctx context.Context,
+	token gethcommon.Address,
+	symbol string,
+	date time.Time,
+) (float64, error) {
+	priceURL := strings.NewReplacer(
+		"{token}", url.QueryEscape(token.Hex()),
+		"{symbol}", url.QueryEscape(symbol),
+		"{date}", date.UTC().Format(time.DateOnly),
+		"{timestamp}", strconv.FormatInt(date.Unix(), 10),
+	).Replace(s.urlTemplate)
+
+	var response struct {
+		PriceUSD *float64 `json:"priceUsd"`
+	}
+	if err := s.client.get(ctx, priceURL, &response); err != nil {
+		return 0, err
+	}
+	if response.PriceUSD == nil {
+		return 0, fmt.Errorf("%w: no priceUsd for %s on %s", ErrPriceNotFound, symbol, date.UTC().Format(time.DateOnly))
+	}
+	return *response.PriceUSD, nil
+}
+
+// priceHTTPClient gets JSON from the HTTP APIs of price sources, waiting for the rate limiter
+// before every request
+type priceHTTPClient struct {
+	client    *http.Client
+	limiter   *rateLimiter
+	authToken string
+}
+
+func (c *priceHTTPClient) get(ctx context.Context, url string, out interface{}) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrPriceNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("price source returned status %d for %s", resp.StatusCode, req.URL.Redacted())
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// rateLimiter spaces out requests to at most a number of requests per second. A nil limiter
+// doesn't limit.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newRateLimiter returns a limiter of the given requests per second, or nil if it is not positive
+func newRateLimiter(perSecond float64) *rateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// Wait blocks until the next request is allowed or the context is done
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	wait := l.next.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	l.next = now.Add(wait + l.interval)
+	l.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
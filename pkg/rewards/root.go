@@ -0,0 +1,511 @@
+package rewards
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IRewardsCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	submitRootCommandName  = "rewards submit-root"
+	disableRootCommandName = "rewards disable-root"
+)
+
+var (
+	ErrNotRewardsUpdater = errors.New("address is not the rewards updater of the rewards coordinator")
+	ErrAborted           = errors.New("aborted by the user")
+)
+
+// rootCoordinator is the part of the rewards coordinator the root commands validate against
+type rootCoordinator interface {
+	RewardsUpdater(opts *bind.CallOpts) (gethcommon.Address, error)
+	CurrRewardsCalculationEndTimestamp(opts *bind.CallOpts) (uint32, error)
+	GetDistributionRootsLength(opts *bind.CallOpts) (*big.Int, error)
+	GetDistributionRootAtIndex(
+		opts *bind.CallOpts,
+		index *big.Int,
+	) (rewardscoordinator.IRewardsCoordinatorDistributionRoot, error)
+}
+
+// rootAction is a root command, which only differ in how they validate and build their transaction
+type rootAction struct {
+	command string
+	// method is the method of the rewards coordinator the transaction calls
+	method      string
+	description string
+	params      map[string]string
+	validate    func(ctx context.Context, coordinator rootCoordinator) error
+	buildTx     func(
+		coordinator *rewardscoordinator.ContractIRewardsCoordinator,
+		opts *bind.TransactOpts,
+	) (*types.Transaction, error)
+}
+
+func SubmitRootCmd(p utils.Prompter) *cli.Command {
+	submitRootCmd := &cli.Command{
+		Name:      "submit-root",
+		Usage:     "Submit a distribution root. Only for the rewards updater",
+		UsageText: "submit-root",
+		Description: `
+Submit a new distribution root to the rewards coordinator. Only the rewards updater of the rewards
+coordinator can submit roots, which is checked before anything is sent.
+
+Without --broadcast the calldata of the transaction is shown for review. With --broadcast the
+transaction is sent after a confirmation, which can be skipped with --yes.
+		`,
+		After: telemetry.AfterRunAction(),
+		Flags: getRootFlags(&RootFlag, &RewardsCalculationEndTimestampFlag),
+		Action: func(cCtx *cli.Context) error {
+			return SubmitRoot(cCtx, p)
+		},
+	}
+
+	return submitRootCmd
+}
+
+func DisableRootCmd(p utils.Prompter) *cli.Command {
+	disableRootCmd := &cli.Command{
+		Name:      "disable-root",
+		Usage:     "Disable a distribution root before it is activated. Only for the rewards updater",
+		UsageText: "disable-root",
+		Description: `
+Disable a distribution root which is not activated yet, e.g. because it was submitted with wrong
+amounts. Only the rewards updater of the rewards coordinator can disable roots, which is checked
+before anything is sent.
+
+Without --broadcast the calldata of the transaction is shown for review. With --broadcast the
+transaction is sent after a confirmation, which can be skipped with --yes.
+		`,
+		After: telemetry.AfterRunAction(),
+		Flags: getRootFlags(&RootIndexFlag),
+		Action: func(cCtx *cli.Context) error {
+			return DisableRoot(cCtx, p)
+		},
+	}
+
+	return disableRootCmd
+}
+
+func getRootFlags(commandFlags ...cli.Flag) []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.BroadcastFlag,
+		&flags.VerboseFlag,
+		&RewardsUpdaterAddressFlag,
+		&RewardsCoordinatorAddressFlag,
+		&SkipConfirmationFlag,
+	}
+	baseFlags = append(baseFlags, commandFlags...)
+
+	allFlags := append(baseFlags, flags.GetSignerFlags()...)
+	allFlags = append(allFlags, flags.GetPlanFlags()...)
+	sort.Sort(cli.FlagsByName(allFlags))
+	return allFlags
+}
+
+func SubmitRoot(cCtx *cli.Context, p utils.Prompter) error {
+	logger := common.GetLogger(cCtx)
+	config, err := readAndValidateRootConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate submit root config", err)
+	}
+
+	endTimestamp, err := getUint32Flag(cCtx, RewardsCalculationEndTimestampFlag.Name)
+	if err != nil {
+		return err
+	}
+	config.RewardsCalculationEndTimestamp = endTimestamp
+	rootBytes, err := hexutil.Decode(cCtx.String(RootFlag.Name))
+	if err != nil || len(rootBytes) != 32 {
+		return errors.New("root must be a 32 byte hex string")
+	}
+	copy(config.Root[:], rootBytes)
+
+	root := gethcommon.Hash(config.Root).Hex()
+	return runRootAction(cCtx, p, config, logger, &rootAction{
+		command: submitRootCommandName,
+		method:  "submitRoot",
+		description: fmt.Sprintf(
+			"Root %s with rewards calculated until %s will be submitted",
+			root,
+			common.FormatUnixTime(int64(config.RewardsCalculationEndTimestamp)),
+		),
+		params: map[string]string{
+			"root":                           root,
+			"rewardsCalculationEndTimestamp": strconv.FormatUint(uint64(config.RewardsCalculationEndTimestamp), 10),
+		},
+		validate: func(ctx context.Context, coordinator rootCoordinator) error {
+			return validateSubmitRoot(ctx, coordinator, config.RewardsCalculationEndTimestamp, time.Now())
+		},
+		buildTx: func(
+			coordinator *rewardscoordinator.ContractIRewardsCoordinator,
+			opts *bind.TransactOpts,
+		) (*types.Transaction, error) {
+			return coordinator.SubmitRoot(opts, config.Root, config.RewardsCalculationEndTimestamp)
+		},
+	})
+}
+
+func DisableRoot(cCtx *cli.Context, p utils.Prompter) error {
+	logger := common.GetLogger(cCtx)
+	config, err := readAndValidateRootConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate disable root config", err)
+	}
+
+	rootIndex, err := getUint32Flag(cCtx, RootIndexFlag.Name)
+	if err != nil {
+		return err
+	}
+	config.RootIndex = rootIndex
+
+	return runRootAction(cCtx, p, config, logger, &rootAction{
+		command:     disableRootCommandName,
+		method:      "disableRoot",
+		description: fmt.Sprintf("Root at index %d will be disabled", config.RootIndex),
+		params: map[string]string{
+			"rootIndex": strconv.FormatUint(uint64(config.RootIndex), 10),
+		},
+		validate: func(ctx context.Context, coordinator rootCoordinator) error {
+			return validateDisableRoot(ctx, coordinator, config.RootIndex, time.Now())
+		},
+		buildTx: func(
+			coordinator *rewardscoordinator.ContractIRewardsCoordinator,
+			opts *bind.TransactOpts,
+		) (*types.Transaction, error) {
+			return coordinator.DisableRoot(opts, config.RootIndex)
+		},
+	})
+}
+
+func runRootAction(
+	cCtx *cli.Context,
+	p utils.Prompter,
+	config *RootConfig,
+	logger logging.Logger,
+	action *rootAction,
+) error {
+	ctx := cCtx.Context
+	cCtx.App.Metadata["network"] = config.ChainID.String()
+
+	ethClient, err := common.DialEthClient(config.RPCUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	_, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
+		RewardsCoordinatorAddress: config.RewardsCoordinatorAddress,
+	}, ethClient, nil, logger, nil)
+	if err != nil {
+		return err
+	}
+	coordinator := contractBindings.RewardsCoordinator
+
+	// The role is checked for every mode, so nobody prepares or reviews a transaction that can't succeed
+	if err := checkRewardsUpdater(ctx, coordinator, config.UpdaterAddress); err != nil {
+		return err
+	}
+
+	if config.PlanConfig.IsApply() {
+		if err := confirmRootAction(p, config, "The reviewed plan will be applied"); err != nil {
+			return err
+		}
+		return common.ApplyTxPlan(
+			ctx,
+			config.PlanConfig,
+			action.command,
+			config.UpdaterAddress,
+			config.SignerConfig,
+			ethClient,
+			p,
+			config.ChainID,
+			logger,
+			planRootCheck(config.RewardsCoordinatorAddress, action.method),
+		)
+	}
+
+	if err := action.validate(ctx, coordinator); err != nil {
+		return err
+	}
+
+	if !config.Broadcast && !config.PlanConfig.IsPlan() {
+		unsignedTx, err := action.buildTx(coordinator, common.GetNoSendTxOpts(config.UpdaterAddress))
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to create unsigned tx", err)
+		}
+		return previewRootAction(config, action, unsignedTx)
+	}
+
+	if config.Broadcast {
+		if err := confirmRootAction(p, config, action.description); err != nil {
+			return err
+		}
+	}
+
+	txMgr, txPlan, err := common.GetTxManagerWithPlan(
+		config.PlanConfig,
+		action.command,
+		action.params,
+		config.UpdaterAddress,
+		config.SignerConfig,
+		ethClient,
+		p,
+		config.ChainID,
+		logger,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get tx manager", err)
+	}
+	noSendTxOpts, err := txMgr.GetNoSendTxOpts()
+	if err != nil {
+		return err
+	}
+	tx, err := action.buildTx(coordinator, noSendTxOpts)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create tx", err)
+	}
+	receipt, err := txMgr.Send(ctx, tx, true)
+	if err != nil {
+		return err
+	}
+
+	if txPlan != nil {
+		return txPlan.Write(config.PlanConfig.PlanFile, logger)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("transaction %s reverted", receipt.TxHash.Hex())
+	}
+
+	logger.Infof("%s Transaction of %s sent successfully", utils.EmojiCheckMark, action.command)
+	common.PrintTransactionInfo(receipt.TxHash.String(), config.ChainID)
+	common.PrintReceiptEvents(receipt, config.OutputType)
+	return nil
+}
+
+func previewRootAction(config *RootConfig, action *rootAction, unsignedTx *types.Transaction) error {
+	calldataHex := gethcommon.Bytes2Hex(unsignedTx.Data())
+	if config.OutputType == string(common.OutputType_Calldata) {
+		if !common.IsEmptyString(config.Output) {
+			if err := common.WriteToFile([]byte(calldataHex), config.Output); err != nil {
+				return err
+			}
+		} else {
+			fmt.Println(calldataHex)
+		}
+	} else if config.OutputType == string(common.OutputType_Pretty) {
+		if !common.IsEmptyString(config.Output) {
+			fmt.Println("output file not supported for pretty output type")
+			fmt.Println()
+		}
+		fmt.Println(action.description)
+		fmt.Printf("From: %s\n", config.UpdaterAddress.Hex())
+		fmt.Printf("To: %s\n", config.RewardsCoordinatorAddress.Hex())
+		fmt.Printf("Calldata: 0x%s\n", calldataHex)
+	} else {
+		return fmt.Errorf("unsupported output type for this command %s", config.OutputType)
+	}
+	txFeeDetails := common.GetTxFeeDetails(unsignedTx)
+	fmt.Println()
+	txFeeDetails.Print()
+	fmt.Println("To broadcast the transaction, use the --broadcast flag")
+	return nil
+}
+
+func confirmRootAction(p utils.Prompter, config *RootConfig, description string) error {
+	if config.SkipConfirmation {
+		return nil
+	}
+	confirmed, err := p.Confirm(fmt.Sprintf("%s on chain ID %s. Do you want to continue?", description, config.ChainID))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return ErrAborted
+	}
+	return nil
+}
+
+func checkRewardsUpdater(ctx context.Context, coordinator rootCoordinator, address gethcommon.Address) error {
+	updater, err := coordinator.RewardsUpdater(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get rewards updater", err)
+	}
+	if updater != address {
+		return fmt.Errorf("%w: the rewards updater is %s, not %s", ErrNotRewardsUpdater, updater.Hex(), address.Hex())
+	}
+	return nil
+}
+
+// validateSubmitRoot checks the requirements the rewards coordinator has for a new root, so the
+// transaction isn't sent only to revert
+func validateSubmitRoot(
+	ctx context.Context,
+	coordinator rootCoordinator,
+	endTimestamp uint32,
+	now time.Time,
+) error {
+	current, err := coordinator.CurrRewardsCalculationEndTimestamp(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get current rewards calculation end timestamp", err)
+	}
+	if endTimestamp <= current {
+		return fmt.Errorf(
+			"rewards calculation end timestamp %d (%s) must be after the one of the latest root %d (%s)",
+			endTimestamp,
+			common.FormatUnixTime(int64(endTimestamp)),
+			current,
+			common.FormatUnixTime(int64(current)),
+		)
+	}
+	if int64(endTimestamp) >= now.Unix() {
+		return fmt.Errorf(
+			"rewards calculation end timestamp %d (%s) must be in the past",
+			endTimestamp,
+			common.FormatUnixTime(int64(endTimestamp)),
+		)
+	}
+	return nil
+}
+
+// validateDisableRoot checks the root exists and can still be disabled, which is only possible
+// before it is activated
+func validateDisableRoot(ctx context.Context, coordinator rootCoordinator, rootIndex uint32, now time.Time) error {
+	opts := &bind.CallOpts{Context: ctx}
+	length, err := coordinator.GetDistributionRootsLength(opts)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get distribution roots length", err)
+	}
+	if big.NewInt(int64(rootIndex)).Cmp(length) >= 0 {
+		return fmt.Errorf("root index %d doesn't exist, there are %s roots", rootIndex, length)
+	}
+	root, err := coordinator.GetDistributionRootAtIndex(opts, big.NewInt(int64(rootIndex)))
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get distribution root", err)
+	}
+	if root.Disabled {
+		return fmt.Errorf("root at index %d is already disabled", rootIndex)
+	}
+	if int64(root.ActivatedAt) <= now.Unix() {
+		return fmt.Errorf(
+			"root at index %d is already activated since %s and can't be disabled",
+			rootIndex,
+			common.FormatUnixTime(int64(root.ActivatedAt)),
+		)
+	}
+	return nil
+}
+
+func getUint32Flag(cCtx *cli.Context, name string) (uint32, error) {
+	value := cCtx.Uint64(name)
+	if value > math.MaxUint32 {
+		return 0, fmt.Errorf("%s must fit in 32 bits", name)
+	}
+	return uint32(value), nil
+}
+
+// planRootCheck makes sure a root plan written elsewhere only calls the method of the command on
+// the rewards coordinator, so a plan of another command can't be applied with the updater key
+func planRootCheck(rewardsCoordinatorAddress gethcommon.Address, method string) common.PlanCheck {
+	return func(plan *common.TxPlan) error {
+		contractAbi, err := rewardscoordinator.ContractIRewardsCoordinatorMetaData.GetAbi()
+		if err != nil {
+			return err
+		}
+		selector := contractAbi.Methods[method].ID
+		for i, tx := range plan.Transactions {
+			if gethcommon.HexToAddress(tx.To) != rewardsCoordinatorAddress {
+				return fmt.Errorf(
+					"%w: transaction %d: target %s is not the rewards coordinator",
+					common.ErrInvalidPlan,
+					i+1,
+					tx.To,
+				)
+			}
+			if value, ok := new(big.Int).SetString(tx.Value, 10); !ok || value.Sign() != 0 {
+				return fmt.Errorf("%w: transaction %d: %s can't send value", common.ErrInvalidPlan, i+1, method)
+			}
+			data := gethcommon.FromHex(tx.Data)
+			if len(data) < 4 || !bytes.Equal(data[:4], selector) {
+				return fmt.Errorf("%w: transaction %d does not call %s", common.ErrInvalidPlan, i+1, method)
+			}
+		}
+		return nil
+	}
+}
+
+func readAndValidateRootConfig(cCtx *cli.Context, logger logging.Logger) (*RootConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+	broadcast := cCtx.Bool(flags.BroadcastFlag.Name)
+	skipConfirmation := cCtx.Bool(SkipConfirmationFlag.Name)
+
+	updaterAddress := cCtx.String(RewardsUpdaterAddressFlag.Name)
+	if !gethcommon.IsHexAddress(updaterAddress) {
+		return nil, fmt.Errorf("invalid rewards updater address %s", updaterAddress)
+	}
+
+	planConfig, err := common.ReadPlanConfig(cCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	rewardsCoordinatorAddress := cCtx.String(RewardsCoordinatorAddressFlag.Name)
+	if common.IsEmptyString(rewardsCoordinatorAddress) {
+		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(utils.NetworkNameToChainId(network))
+		if err != nil {
+			return nil, err
+		}
+	}
+	logger.Debugf("Using Rewards Coordinator address: %s", rewardsCoordinatorAddress)
+
+	chainID := utils.NetworkNameToChainId(network)
+	logger.Debugf("Using chain ID: %s", chainID.String())
+
+	signerConfig, err := common.GetSignerConfig(cCtx, logger)
+	if err != nil {
+		// The calldata can still be previewed without a signer
+		logger.Debugf("Failed to get signer config: %s", err)
+	}
+
+	return &RootConfig{
+		Network:                   network,
+		RPCUrl:                    rpcUrl,
+		UpdaterAddress:            gethcommon.HexToAddress(updaterAddress),
+		RewardsCoordinatorAddress: gethcommon.HexToAddress(rewardsCoordinatorAddress),
+		ChainID:                   chainID,
+		SignerConfig:              signerConfig,
+		Broadcast:                 broadcast,
+		Output:                    output,
+		OutputType:                outputType,
+		PlanConfig:                planConfig,
+		SkipConfirmation:          skipConfirmation,
+	}, nil
+}
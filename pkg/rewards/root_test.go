@@ -0,0 +1,147 @@
+package rewards
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IRewardsCoordinator"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRootCoordinator struct {
+	updater      gethcommon.Address
+	endTimestamp uint32
+	roots        []rewardscoordinator.IRewardsCoordinatorDistributionRoot
+}
+
+func (f *fakeRootCoordinator) RewardsUpdater(opts *bind.CallOpts) (gethcommon.Address, error) {
+	return f.updater, nil
+}
+
+func (f *fakeRootCoordinator) CurrRewardsCalculationEndTimestamp(opts *bind.CallOpts) (uint32, error) {
+	return f.endTimestamp, nil
+}
+
+func (f *fakeRootCoordinator) GetDistributionRootsLength(opts *bind.CallOpts) (*big.Int, error) {
+	return big.NewInt(int64(len(f.roots))), nil
+}
+
+func (f *fakeRootCoordinator) GetDistributionRootAtIndex(
+	opts *bind.CallOpts,
+	index *big.Int,
+) (rewardscoordinator.IRewardsCoordinatorDistributionRoot, error) {
+	return f.roots[index.Int64()], nil
+}
+
+func TestCheckRewardsUpdater(t *testing.T) {
+	coordinator := &fakeRootCoordinator{updater: gethcommon.HexToAddress("0x1")}
+	assert.NoError(t, checkRewardsUpdater(context.Background(), coordinator, gethcommon.HexToAddress("0x1")))
+
+	err := checkRewardsUpdater(context.Background(), coordinator, gethcommon.HexToAddress("0x2"))
+	assert.True(t, errors.Is(err, ErrNotRewardsUpdater))
+}
+
+func TestValidateSubmitRoot(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	coordinator := &fakeRootCoordinator{endTimestamp: 1_699_000_000}
+
+	assert.NoError(t, validateSubmitRoot(context.Background(), coordinator, 1_699_500_000, now))
+	assert.Error(t, validateSubmitRoot(context.Background(), coordinator, 1_699_000_000, now))
+	assert.Error(t, validateSubmitRoot(context.Background(), coordinator, 1_700_000_000, now))
+}
+
+func TestValidateDisableRoot(t *testing.T) {
+	assert.NoError(t, common.SetTimezone(common.TimezoneUTC))
+	now := time.Unix(1_700_000_000, 0)
+	coordinator := &fakeRootCoordinator{
+		roots: []rewardscoordinator.IRewardsCoordinatorDistributionRoot{
+			{ActivatedAt: 1_699_000_000},
+			{ActivatedAt: 1_700_100_000, Disabled: true},
+			{ActivatedAt: 1_700_100_000},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		rootIndex uint32
+		wantErr   string
+	}{
+		{
+			name:      "activated root",
+			rootIndex: 0,
+			wantErr:   "root at index 0 is already activated since 2023-11-03 08:26:40 UTC and can't be disabled",
+		},
+		{name: "disabled root", rootIndex: 1, wantErr: "root at index 1 is already disabled"},
+		{name: "pending root", rootIndex: 2},
+		{name: "missing root", rootIndex: 3, wantErr: "root index 3 doesn't exist, there are 3 roots"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDisableRoot(context.Background(), coordinator, tt.rootIndex, now)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPlanRootCheck(t *testing.T) {
+	rewardsCoordinator := gethcommon.HexToAddress("0x1")
+	contractAbi, err := rewardscoordinator.ContractIRewardsCoordinatorMetaData.GetAbi()
+	assert.NoError(t, err)
+	submitRoot, err := contractAbi.Pack("submitRoot", [32]byte{1}, uint32(1_700_000_000))
+	assert.NoError(t, err)
+	disableRoot, err := contractAbi.Pack("disableRoot", uint32(3))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		to      gethcommon.Address
+		value   string
+		data    []byte
+		wantErr string
+	}{
+		{name: "submitRoot", to: rewardsCoordinator, value: "0", data: submitRoot},
+		{
+			name:    "other contract",
+			to:      gethcommon.HexToAddress("0x2"),
+			value:   "0",
+			data:    submitRoot,
+			wantErr: "not the rewards coordinator",
+		},
+		{name: "value", to: rewardsCoordinator, value: "1", data: submitRoot, wantErr: "can't send value"},
+		{
+			name:    "other method",
+			to:      rewardsCoordinator,
+			value:   "0",
+			data:    disableRoot,
+			wantErr: "does not call submitRoot",
+		},
+		{name: "no calldata", to: rewardsCoordinator, value: "0", wantErr: "does not call submitRoot"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := &common.TxPlan{Transactions: []common.PlannedTx{
+				{To: tt.to.Hex(), Value: tt.value, Data: hexutil.Encode(tt.data)},
+			}}
+			err := planRootCheck(rewardsCoordinator, "submitRoot")(plan)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, common.ErrInvalidPlan)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -15,11 +15,12 @@ import (
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	gethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/urfave/cli/v2"
 )
 
+const setClaimerCommandName = "rewards set-claimer"
+
 func SetClaimerCmd(p utils.Prompter) *cli.Command {
 	setClaimerCmd := &cli.Command{
 		Name:      "set-claimer",
@@ -52,6 +53,7 @@ func getSetClaimerFlags() []cli.Flag {
 	}
 
 	allFlags := append(baseFlags, flags.GetSignerFlags()...)
+	allFlags = append(allFlags, flags.GetPlanFlags()...)
 	sort.Sort(cli.FlagsByName(allFlags))
 	return allFlags
 }
@@ -65,12 +67,26 @@ func SetClaimer(cCtx *cli.Context, p utils.Prompter) error {
 
 	cCtx.App.Metadata["network"] = config.ChainID.String()
 
-	ethClient, err := ethclient.Dial(config.RPCUrl)
+	ethClient, err := common.DialEthClient(config.RPCUrl)
 	if err != nil {
 		return err
 	}
 
-	if !config.Broadcast {
+	if config.PlanConfig.IsApply() {
+		return common.ApplyTxPlan(
+			cCtx.Context,
+			config.PlanConfig,
+			setClaimerCommandName,
+			config.EarnerAddress,
+			config.SignerConfig,
+			ethClient,
+			p,
+			config.ChainID,
+			logger,
+		)
+	}
+
+	if !config.Broadcast && !config.PlanConfig.IsPlan() {
 		_, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
 			RewardsCoordinatorAddress: config.RewardsCoordinatorAddress,
 		}, ethClient, nil, logger, nil)
@@ -118,7 +134,13 @@ func SetClaimer(cCtx *cli.Context, p utils.Prompter) error {
 		return nil
 	}
 
-	elWriter, err := common.GetELWriter(
+	elWriter, txPlan, err := common.GetELWriterWithPlan(
+		config.PlanConfig,
+		setClaimerCommandName,
+		map[string]string{
+			"earner":  config.EarnerAddress.Hex(),
+			"claimer": config.ClaimerAddress.Hex(),
+		},
 		config.EarnerAddress,
 		config.SignerConfig,
 		ethClient,
@@ -139,6 +161,10 @@ func SetClaimer(cCtx *cli.Context, p utils.Prompter) error {
 		return err
 	}
 
+	if txPlan != nil {
+		return txPlan.Write(config.PlanConfig.PlanFile, logger)
+	}
+
 	logger.Infof(
 		"%s Claimer address %s set successfully for operator %s\n",
 		utils.EmojiCheckMark,
@@ -150,6 +176,7 @@ func SetClaimer(cCtx *cli.Context, p utils.Prompter) error {
 		receipt.TxHash.String(),
 		config.ChainID,
 	)
+	common.PrintReceiptEvents(receipt, config.OutputType)
 
 	return nil
 }
@@ -167,8 +194,12 @@ func readAndValidateSetClaimerConfig(cCtx *cli.Context, logger logging.Logger) (
 		return nil, fmt.Errorf("claimer address is required")
 	}
 
+	planConfig, err := common.ReadPlanConfig(cCtx)
+	if err != nil {
+		return nil, err
+	}
+
 	rewardsCoordinatorAddress := cCtx.String(RewardsCoordinatorAddressFlag.Name)
-	var err error
 	if common.IsEmptyString(rewardsCoordinatorAddress) {
 		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(utils.NetworkNameToChainId(network))
 		if err != nil {
@@ -204,5 +235,6 @@ func readAndValidateSetClaimerConfig(cCtx *cli.Context, logger logging.Logger) (
 		EarnerAddress:             earnerAddress,
 		Output:                    output,
 		OutputType:                outputType,
+		PlanConfig:                planConfig,
 	}, nil
 }
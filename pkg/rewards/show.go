@@ -6,13 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"net/http"
 	"sort"
 	"strings"
 
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/erc20"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/profile"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
 
@@ -23,7 +23,6 @@ import (
 	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
 
 	gethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/urfave/cli/v2"
 )
@@ -60,6 +59,9 @@ Helpful flags
 		After: telemetry.AfterRunAction(),
 		Flags: getShowFlags(),
 		Action: func(cCtx *cli.Context) error {
+			if profile.IsFanOut(cCtx) {
+				return profile.FanOut(cCtx)
+			}
 			return ShowRewards(cCtx)
 		},
 	}
@@ -78,8 +80,16 @@ func getShowFlags() []cli.Flag {
 		&EnvironmentFlag,
 		&ClaimTypeFlag,
 		&ProofStoreBaseURLFlag,
+		&ProofStoreSignerFlag,
 		&ClaimTimestampFlag,
+		&VerifyProofsFlag,
+		&TrustedBlockHashFlag,
+		&AllowPartialFlag,
+		&AccountingCSVFlag,
+		&HideZeroFlag,
+		&DustThresholdFlag,
 	}
+	baseFlags = append(baseFlags, profile.FanOutFlags()...)
 
 	sort.Sort(cli.FlagsByName(baseFlags))
 	return baseFlags
@@ -95,7 +105,7 @@ func ShowRewards(cCtx *cli.Context) error {
 	}
 	cCtx.App.Metadata["network"] = config.ChainID.String()
 
-	ethClient, err := ethclient.Dial(config.RPCUrl)
+	ethClient, err := common.DialEthClient(config.RPCUrl)
 	if err != nil {
 		return eigenSdkUtils.WrapError("failed to create new eth client", err)
 	}
@@ -111,23 +121,54 @@ func ShowRewards(cCtx *cli.Context) error {
 		return eigenSdkUtils.WrapError("failed to create new reader from config", err)
 	}
 
+	snapshots, err := newRewardsSnapshotVerifier(
+		ethClient,
+		config.RewardsCoordinatorAddress,
+		config.ProofStoreSigner,
+		logger,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create snapshot verifier", err)
+	}
 	df := httpProofDataFetcher.NewHttpProofDataFetcher(
 		config.ProofStoreBaseURL,
 		config.Environment,
 		config.Network,
-		http.DefaultClient,
+		snapshots.HTTPClient(),
 	)
 
-	claimDate, _, err := getClaimDistributionRoot(ctx, config.ClaimTimestamp, elReader, logger)
+	var reader elChainReader = elReader
+	var verifier *proofVerifier
+	if config.VerifyProofs {
+		verifier, err = newProofVerifier(ctx, config.RPCUrl, config.RewardsCoordinatorAddress, config.TrustedBlockHash)
+		if err != nil {
+			return err
+		}
+		logger.Infof("Verifying critical reads with storage proofs at block %d", verifier.header.Number.Uint64())
+		reader = &verifiedELReader{elChainReader: elReader, verifier: verifier}
+	}
+
+	endPhase := common.StartPhase("distribution root")
+	claimDate, rootIndex, err := getClaimDistributionRoot(ctx, config.ClaimTimestamp, reader, logger)
+	endPhase()
 	if err != nil {
 		return eigenSdkUtils.WrapError("failed to get claim distribution root", err)
 	}
 
+	endPhase = common.StartPhase("proof data download")
 	proofData, err := df.FetchClaimAmountsForDate(ctx, claimDate)
+	endPhase()
 	if err != nil {
 		return eigenSdkUtils.WrapError("failed to fetch claim amounts for date", err)
 	}
 
+	endPhase = common.StartPhase("snapshot verification")
+	_, err = snapshots.Verify(ctx, claimDate, rootIndex, proofData.Distribution, logger)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
 	tokenAddressesMap, present := proofData.Distribution.GetTokensForEarner(config.EarnerAddress)
 	if !present {
 		return eigenSdkUtils.WrapError("earner address not found in distribution", nil)
@@ -140,10 +181,49 @@ func ShowRewards(cCtx *cli.Context) error {
 		allRewards[pair.Key] = amt
 	}
 
-	if config.ClaimType != All {
-		claimedRewards, err := getClaimedRewards(ctx, elReader, config.EarnerAddress, allRewards)
+	var rootHash gethcommon.Hash
+	if verifier != nil || !common.IsEmptyString(config.AccountingCSV) {
+		tokens := make([]gethcommon.Address, 0, len(allRewards))
+		for token := range allRewards {
+			tokens = append(tokens, token)
+		}
+		rootHash, err = getSnapshotRoot(proofData.Distribution, config.EarnerAddress, tokens, rootIndex)
+		if err != nil {
+			return err
+		}
+	}
+
+	if verifier != nil {
+		// The earner's claim proof commits to the snapshot root, which has to match the posted root
+		err = verifier.VerifyDistributionRoot(ctx, rootIndex, rootHash)
 		if err != nil {
-			return eigenSdkUtils.WrapError("failed to get claimed rewards", err)
+			return err
+		}
+		logger.Infof("Distribution root at index %d verified against storage proof", rootIndex)
+	}
+
+	failedTokens := make(map[gethcommon.Address]error)
+	if config.ClaimType != All {
+		var claimedRewards map[gethcommon.Address]*big.Int
+		if config.AllowPartial {
+			claimedRewards, failedTokens, err = getClaimedRewardsAllowPartial(
+				ctx,
+				reader,
+				config.EarnerAddress,
+				allRewards,
+			)
+			if err != nil {
+				return eigenSdkUtils.WrapError("failed to get claimed rewards", err)
+			}
+			for token, err := range failedTokens {
+				logger.Warnf("Failed to get claimed rewards for token %s: %s", token.Hex(), err)
+				delete(allRewards, token)
+			}
+		} else {
+			claimedRewards, err = getClaimedRewards(ctx, reader, config.EarnerAddress, allRewards)
+			if err != nil {
+				return eigenSdkUtils.WrapError("failed to get claimed rewards", err)
+			}
 		}
 		switch config.ClaimType {
 		case Claimed:
@@ -154,10 +234,17 @@ func ShowRewards(cCtx *cli.Context) error {
 			msg = "Unclaimed Rewards"
 		}
 	}
-	err = handleRewardsOutput(config, allRewards, msg)
+	err = handleRewardsOutput(config, allRewards, failedTokens, msg)
 	if err != nil {
 		return err
 	}
+
+	if !common.IsEmptyString(config.AccountingCSV) {
+		err = writeAccountingCSV(ctx, config, ethClient, allRewards, claimDate, rootIndex, rootHash, logger)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to write accounting CSV", err)
+		}
+	}
 	return nil
 }
 
@@ -178,6 +265,32 @@ func getClaimedRewards(
 	return claimedRewards, nil
 }
 
+// getClaimedRewardsAllowPartial is like getClaimedRewards but doesn't stop at the first failed token.
+// It returns the claimed rewards for the tokens that could be read and the errors for the ones that couldn't.
+// Only reads which failed are skipped. A value whose storage proof doesn't verify fails the whole call,
+// since it points to a dishonest RPC rather than a flaky one.
+func getClaimedRewardsAllowPartial(
+	ctx context.Context,
+	elReader ELReader,
+	earnerAddress gethcommon.Address,
+	allRewards map[gethcommon.Address]*big.Int,
+) (map[gethcommon.Address]*big.Int, map[gethcommon.Address]error, error) {
+	claimedRewards := make(map[gethcommon.Address]*big.Int)
+	failedTokens := make(map[gethcommon.Address]error)
+	for address := range allRewards {
+		claimed, err := getCummulativeClaimedRewards(ctx, elReader, earnerAddress, address)
+		if errors.Is(err, ErrProofVerificationFailed) {
+			return nil, nil, err
+		}
+		if err != nil {
+			failedTokens[address] = err
+			continue
+		}
+		claimedRewards[address] = claimed
+	}
+	return claimedRewards, failedTokens, nil
+}
+
 func getCummulativeClaimedRewards(
 	ctx context.Context,
 	elReader ELReader,
@@ -209,9 +322,10 @@ func calculateUnclaimedRewards(
 func handleRewardsOutput(
 	cfg *ShowConfig,
 	rewards map[gethcommon.Address]*big.Int,
+	failedTokens map[gethcommon.Address]error,
 	msg string,
 ) error {
-	client, err := ethclient.Dial(cfg.RPCUrl)
+	client, err := common.DialEthClient(cfg.RPCUrl)
 	if err != nil {
 		return err
 	}
@@ -223,8 +337,41 @@ func handleRewardsOutput(
 			Amount:    amount.String(),
 		})
 	}
-	if cfg.OutputType == "json" {
-		out, err := json.MarshalIndent(allRewards, "", "  ")
+	allErrors := make([]rewardsErrorJson, 0, len(failedTokens))
+	for address, err := range failedTokens {
+		allErrors = append(allErrors, rewardsErrorJson{
+			Address: address.Hex(),
+			Error:   err.Error(),
+		})
+	}
+	if cfg.OutputType == string(common.OutputType_Markdown) {
+		var b strings.Builder
+		fmt.Fprintf(&b, "## %s\n\n", msg)
+		fmt.Fprintf(&b, "> %s\n\n", getRootNote(cfg.ClaimTimestamp))
+		table, hidden := newRewardsTable(allRewards, cfg.DustFilter)
+		b.WriteString(table.Markdown())
+		if hidden.total() > 0 {
+			fmt.Fprintf(&b, "\n%s\n", hiddenNote(hidden))
+		}
+		if len(allErrors) > 0 {
+			fmt.Fprintf(&b, "\nRewards for %d token(s) could not be loaded:\n\n", len(allErrors))
+			for _, e := range allErrors {
+				fmt.Fprintf(&b, "- `%s`: %s\n", common.RedactText(e.Address), common.RedactText(e.Error))
+			}
+		}
+		if cfg.Output != "" {
+			return common.WriteToFile([]byte(b.String()), cfg.Output)
+		}
+		fmt.Print(b.String())
+	} else if cfg.OutputType == "json" {
+		var out []byte
+		if cfg.AllowPartial {
+			// With partial results the errors are part of the output, so callers can tell
+			// a token with no rewards apart from a token that failed to load
+			out, err = json.MarshalIndent(partialRewardsJson{Rewards: allRewards, Errors: allErrors}, "", "  ")
+		} else {
+			out, err = json.MarshalIndent(allRewards, "", "  ")
+		}
 		if err != nil {
 			return err
 		}
@@ -235,59 +382,55 @@ func handleRewardsOutput(
 		}
 	} else {
 		fmt.Println()
-		if cfg.ClaimTimestamp == LatestTimestamp {
-			fmt.Println("> Showing rewards for latest root (can contain non-claimable rewards)")
-		} else {
-			fmt.Println("> Showing rewards for latest active root (only claimable rewards)")
-		}
+		fmt.Println(">", getRootNote(cfg.ClaimTimestamp))
 		fmt.Println()
 		fmt.Println(strings.Repeat("-", 30), msg, strings.Repeat("-", 30))
-		printRewards(allRewards)
+		printRewards(allRewards, cfg.DustFilter)
+		if len(allErrors) > 0 {
+			fmt.Println()
+			fmt.Printf("%s Rewards for %d token(s) could not be loaded:\n", utils.EmojiWarning, len(allErrors))
+			for _, e := range allErrors {
+				fmt.Printf("  %s: %s\n", common.RedactText(e.Address), common.RedactText(e.Error))
+			}
+		}
 	}
 	return nil
 }
 
-func printRewards(allRewards allRewardsJson) {
-	// Define column headers and widths
-	headers := []string{
-		"Token Name",
-		"Token Address",
-		"Amount (Wei)",
-	}
-	widths := []int{20, 46, 30}
-
-	// print dashes
-	for _, width := range widths {
-		fmt.Print("+" + strings.Repeat("-", width+1))
-	}
-	fmt.Println("+")
+// maxTokenNameWidth keeps tokens with very long names from stretching reward tables
+const maxTokenNameWidth = 32
 
-	// Print header
-	for i, header := range headers {
-		fmt.Printf("| %-*s", widths[i], header)
+func getRootNote(claimTimestamp string) string {
+	if claimTimestamp == LatestTimestamp {
+		return "Showing rewards for latest root (can contain non-claimable rewards)"
 	}
-	fmt.Println("|")
+	return "Showing rewards for latest active root (only claimable rewards)"
+}
 
-	// Print separator
-	for _, width := range widths {
-		fmt.Print("|", strings.Repeat("-", width+1))
+func printRewards(allRewards allRewardsJson, dustFilter DustFilter) {
+	table, hidden := newRewardsTable(allRewards, dustFilter)
+	table.Print()
+	if hidden.total() > 0 {
+		fmt.Println(hiddenNote(hidden))
 	}
-	fmt.Println("|")
+}
 
-	// Print data rows
+// newRewardsTable returns the table of the rewards the filter doesn't hide, and the number of
+// hidden rewards
+func newRewardsTable(allRewards allRewardsJson, dustFilter DustFilter) (*common.Table, hiddenRows) {
+	table := common.NewTable(
+		common.TableColumn{Header: "Token Name", MaxWidth: maxTokenNameWidth},
+		common.TableColumn{Header: "Token Address"},
+		common.TableColumn{Header: "Amount (Wei)", Align: common.AlignRight},
+	)
+	var hidden hiddenRows
 	for _, rewards := range allRewards {
-		fmt.Printf("| %-*s| %-*s| %-*s|\n",
-			widths[0], rewards.TokenName,
-			widths[1], rewards.Address,
-			widths[2], rewards.Amount,
-		)
-	}
-
-	// print dashes
-	for _, width := range widths {
-		fmt.Print("+" + strings.Repeat("-", width+1))
+		if hidden.hide(dustFilter, rewards.Amount) {
+			continue
+		}
+		table.AddRow(rewards.TokenName, rewards.Address, common.LocalizeNumber(rewards.Amount))
 	}
-	fmt.Println("+")
+	return table, hidden
 }
 
 func readAndValidateConfig(cCtx *cli.Context, logger logging.Logger) (*ShowConfig, error) {
@@ -325,6 +468,11 @@ func readAndValidateConfig(cCtx *cli.Context, logger logging.Logger) (*ShowConfi
 	}
 	logger.Debugf("Using Proof store base URL: %s", proofStoreBaseURL)
 
+	proofStoreSigner, err := readProofStoreSigner(cCtx)
+	if err != nil {
+		return nil, err
+	}
+
 	claimType := ClaimType(cCtx.String(ClaimTypeFlag.Name))
 	if claimType != All && claimType != Unclaimed && claimType != Claimed {
 		return nil, errors.New("claim type must be 'all', 'unclaimed' or 'claimed'")
@@ -336,6 +484,20 @@ func readAndValidateConfig(cCtx *cli.Context, logger logging.Logger) (*ShowConfi
 		return nil, errors.New("claim timestamp must be 'latest' or 'latest_active'")
 	}
 
+	allowPartial := cCtx.Bool(AllowPartialFlag.Name)
+	accountingCSV := cCtx.String(AccountingCSVFlag.Name)
+
+	dustFilter, err := readDustFilter(cCtx.Bool(HideZeroFlag.Name), cCtx.String(DustThresholdFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	verifyProofs := cCtx.Bool(VerifyProofsFlag.Name)
+	trustedBlockHash := cCtx.String(TrustedBlockHashFlag.Name)
+	if !verifyProofs && !common.IsEmptyString(trustedBlockHash) {
+		return nil, errors.New("trusted block hash can only be used with --verify-proofs")
+	}
+
 	chainID := utils.NetworkNameToChainId(network)
 	logger.Debugf("Using chain ID: %s", chainID.String())
 
@@ -354,7 +516,13 @@ func readAndValidateConfig(cCtx *cli.Context, logger logging.Logger) (*ShowConfi
 		OutputType:                outputType,
 		RPCUrl:                    ethRpcUrl,
 		ProofStoreBaseURL:         proofStoreBaseURL,
+		ProofStoreSigner:          proofStoreSigner,
 		ClaimTimestamp:            claimTimestamp,
 		RewardsCoordinatorAddress: gethcommon.HexToAddress(rewardsCoordinatorAddress),
+		VerifyProofs:              verifyProofs,
+		TrustedBlockHash:          trustedBlockHash,
+		AllowPartial:              allowPartial,
+		AccountingCSV:             accountingCSV,
+		DustFilter:                dustFilter,
 	}, nil
 }
@@ -3,6 +3,7 @@ package rewards
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 	"testing"
 
@@ -173,3 +174,58 @@ func TestGetClaimedRewards(t *testing.T) {
 		})
 	}
 }
+
+// partialFakeELReader fails reads for the tokens in failingTokens with their error
+type partialFakeELReader struct {
+	claimedRewards map[gethcommon.Address]*big.Int
+	failingTokens  map[gethcommon.Address]error
+}
+
+func (f *partialFakeELReader) GetCumulativeClaimed(
+	ctx context.Context,
+	earnerAddress, tokenAddress gethcommon.Address,
+) (*big.Int, error) {
+	if err, ok := f.failingTokens[tokenAddress]; ok {
+		return nil, err
+	}
+	return f.claimedRewards[tokenAddress], nil
+}
+
+func TestGetClaimedRewardsAllowPartial(t *testing.T) {
+	token1 := gethcommon.HexToAddress("0x2")
+	token2 := gethcommon.HexToAddress("0x3")
+	reader := &partialFakeELReader{
+		claimedRewards: map[gethcommon.Address]*big.Int{
+			token1: big.NewInt(50),
+		},
+		failingTokens: map[gethcommon.Address]error{
+			token2: errors.New("mock error"),
+		},
+	}
+	allRewards := map[gethcommon.Address]*big.Int{
+		token1: big.NewInt(100),
+		token2: big.NewInt(200),
+	}
+
+	claimed, failed, err := getClaimedRewardsAllowPartial(
+		context.Background(),
+		reader,
+		gethcommon.HexToAddress("0x1"),
+		allRewards,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[gethcommon.Address]*big.Int{token1: big.NewInt(50)}, claimed)
+	assert.Len(t, failed, 1)
+	assert.Error(t, failed[token2])
+
+	// A value which fails verification is not a partial result
+	reader.failingTokens[token2] = fmt.Errorf("%w: invalid storage proof", ErrProofVerificationFailed)
+	_, _, err = getClaimedRewardsAllowPartial(
+		context.Background(),
+		reader,
+		gethcommon.HexToAddress("0x1"),
+		allRewards,
+	)
+	assert.ErrorIs(t, err, ErrProofVerificationFailed)
+}
@@ -0,0 +1,376 @@
+package rewards
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/erc20"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/distribution"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+type simulatedRewardJson struct {
+	TokenName string `json:"tokenName"`
+	Address   string `json:"tokenAddress"`
+	Total     string `json:"total"`
+	Claimed   string `json:"claimed"`
+	Claimable string `json:"claimable"`
+	Error     string `json:"error,omitempty"`
+}
+
+type simulationJson struct {
+	Earner       string                `json:"earner"`
+	SnapshotRoot string                `json:"snapshotRoot"`
+	Rewards      []simulatedRewardJson `json:"rewards"`
+}
+
+// simulatedReward is what an earner could claim of a token if the snapshot became the active root
+type simulatedReward struct {
+	Token     gethcommon.Address
+	Total     *big.Int
+	Claimed   *big.Int
+	Claimable *big.Int
+	// Decreased is set when the snapshot has less than the earner already claimed, which makes
+	// every claim of the token against the root revert
+	Decreased bool
+}
+
+func SimulateCmd(p utils.Prompter) *cli.Command {
+	simulateCmd := &cli.Command{
+		Name:      "simulate",
+		Usage:     "Simulate the rewards an earner could claim if a snapshot became the active root",
+		UsageText: "simulate",
+		Description: `
+Command to simulate the rewards an earner could claim if a snapshot, which is not posted yet,
+became the active distribution root. It's meant for rewards updaters and AVS teams to check a
+snapshot before its root is posted.
+
+The claimable amount of each token is the cumulative amount in the snapshot minus what the earner
+already claimed on-chain. Tokens with less in the snapshot than already claimed are reported,
+since claims against such a root would revert.
+
+Helpful flags
+- snapshot: File with one JSON line per earner and token, like the claim amounts of the proof store:
+	{"earner":"0x...","token":"0x...","cumulative_amount":"1000"}
+- token-addresses: Only simulate these tokens
+		`,
+		After: telemetry.AfterRunAction(),
+		Flags: getSimulateFlags(),
+		Action: func(cCtx *cli.Context) error {
+			return Simulate(cCtx)
+		},
+	}
+
+	return simulateCmd
+}
+
+func getSimulateFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.VerboseFlag,
+		&EarnerAddressFlag,
+		&RewardsCoordinatorAddressFlag,
+		&TokenAddressesFlag,
+		&SnapshotFileFlag,
+		&HideZeroFlag,
+		&DustThresholdFlag,
+	}
+
+	sort.Sort(cli.FlagsByName(baseFlags))
+	return baseFlags
+}
+
+func Simulate(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateSimulateConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate simulate config", err)
+	}
+
+	cCtx.App.Metadata["network"] = config.ChainID.String()
+
+	snapshot, err := loadSnapshot(config.SnapshotFile)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to load snapshot", err)
+	}
+
+	ethClient, err := common.DialEthClient(config.RPCUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	elReader, err := elcontracts.NewReaderFromConfig(
+		elcontracts.Config{
+			RewardsCoordinatorAddress: config.RewardsCoordinatorAddress,
+		},
+		ethClient,
+		logger,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new reader from config", err)
+	}
+
+	rewards, err := simulateClaims(ctx, elReader, snapshot, config.EarnerAddress, config.TokenAddresses)
+	if err != nil {
+		return err
+	}
+
+	tokens := make([]gethcommon.Address, 0, len(rewards))
+	for _, reward := range rewards {
+		tokens = append(tokens, reward.Token)
+	}
+	root, err := getSnapshotRoot(snapshot, config.EarnerAddress, tokens, 0)
+	if err != nil {
+		return err
+	}
+	logger.Debugf("Snapshot root: %s", root.Hex())
+
+	return handleSimulationOutput(config, ethClient, root, rewards)
+}
+
+// loadSnapshot reads a snapshot in the format of the claim amounts of the proof store, which is
+// one JSON line per earner and token
+func loadSnapshot(path string) (*distribution.Distribution, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]*distribution.EarnerLine, 0)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		earnerLine := &distribution.EarnerLine{}
+		if err := json.Unmarshal([]byte(line), earnerLine); err != nil {
+			return nil, fmt.Errorf("invalid snapshot line %d: %w", i+1, err)
+		}
+		lines = append(lines, earnerLine)
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("snapshot has no earners")
+	}
+
+	snapshot := distribution.NewDistribution()
+	if err := snapshot.LoadLines(lines); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func simulateClaims(
+	ctx context.Context,
+	elReader ELReader,
+	snapshot *distribution.Distribution,
+	earnerAddress gethcommon.Address,
+	tokenAddresses []gethcommon.Address,
+) ([]simulatedReward, error) {
+	earnerTokens, present := snapshot.GetTokensForEarner(earnerAddress)
+	if !present {
+		return nil, ErrEarnerNotFound
+	}
+	totals := getTokensToClaim(earnerTokens, tokenAddresses)
+	if len(totals) == 0 {
+		return nil, errors.New("none of the tokens are in the snapshot for the earner")
+	}
+
+	claimed, err := getClaimedRewards(ctx, elReader, earnerAddress, totals)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get claimed rewards", err)
+	}
+
+	rewards := make([]simulatedReward, 0, len(totals))
+	for token, total := range totals {
+		reward := simulatedReward{
+			Token:     token,
+			Total:     total,
+			Claimed:   claimed[token],
+			Claimable: new(big.Int).Sub(total, claimed[token]),
+		}
+		if reward.Claimable.Sign() < 0 {
+			reward.Decreased = true
+			reward.Claimable = big.NewInt(0)
+		}
+		rewards = append(rewards, reward)
+	}
+	sort.Slice(rewards, func(i, j int) bool {
+		return rewards[i].Token.Hex() < rewards[j].Token.Hex()
+	})
+	return rewards, nil
+}
+
+func handleSimulationOutput(
+	config *SimulateConfig,
+	ethClient *ethclient.Client,
+	root gethcommon.Hash,
+	rewards []simulatedReward,
+) error {
+	simulation := simulationJson{
+		Earner:       config.EarnerAddress.Hex(),
+		SnapshotRoot: root.Hex(),
+		Rewards:      make([]simulatedRewardJson, 0, len(rewards)),
+	}
+	for _, reward := range rewards {
+		rewardJson := simulatedRewardJson{
+			TokenName: erc20.GetTokenName(reward.Token, ethClient),
+			Address:   reward.Token.Hex(),
+			Total:     reward.Total.String(),
+			Claimed:   reward.Claimed.String(),
+			Claimable: reward.Claimable.String(),
+		}
+		if reward.Decreased {
+			rewardJson.Error = "cumulative amount in the snapshot is lower than the claimed amount"
+		}
+		simulation.Rewards = append(simulation.Rewards, rewardJson)
+	}
+
+	note := fmt.Sprintf("Simulating rewards if snapshot root %s became the active root", root.Hex())
+	switch config.OutputType {
+	case string(common.OutputType_Json):
+		out, err := json.MarshalIndent(simulation, "", "  ")
+		if err != nil {
+			return err
+		}
+		if config.Output != "" {
+			return common.WriteToFile(out, config.Output)
+		}
+		fmt.Println(string(out))
+	case string(common.OutputType_Markdown):
+		var b strings.Builder
+		b.WriteString("## Simulated Rewards\n\n")
+		fmt.Fprintf(&b, "> %s\n\n", note)
+		table, hidden := newSimulationTable(simulation.Rewards, config.DustFilter)
+		b.WriteString(table.Markdown())
+		if hidden.total() > 0 {
+			fmt.Fprintf(&b, "\n%s\n", hiddenNote(hidden))
+		}
+		for _, reward := range simulation.Rewards {
+			if reward.Error != "" {
+				fmt.Fprintf(
+					&b,
+					"\n- `%s`: %s, claims against the root would revert\n",
+					common.RedactText(reward.Address),
+					common.RedactText(reward.Error),
+				)
+			}
+		}
+		if config.Output != "" {
+			return common.WriteToFile([]byte(b.String()), config.Output)
+		}
+		fmt.Print(b.String())
+	default:
+		fmt.Println()
+		fmt.Println(">", note)
+		fmt.Println()
+		fmt.Println(strings.Repeat("-", 30), "Simulated Rewards", strings.Repeat("-", 30))
+		table, hidden := newSimulationTable(simulation.Rewards, config.DustFilter)
+		table.Print()
+		if hidden.total() > 0 {
+			fmt.Println(hiddenNote(hidden))
+		}
+		for _, reward := range simulation.Rewards {
+			if reward.Error != "" {
+				fmt.Printf(
+					"%s %s: %s, claims against the root would revert\n",
+					utils.EmojiWarning,
+					reward.Address,
+					reward.Error,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// newSimulationTable returns the table of the rewards the filter doesn't hide, and the number of
+// hidden rewards. Rewards with an error are never hidden, since their claimable amount is zero.
+func newSimulationTable(rewards []simulatedRewardJson, dustFilter DustFilter) (*common.Table, hiddenRows) {
+	table := common.NewTable(
+		common.TableColumn{Header: "Token Name", MaxWidth: maxTokenNameWidth},
+		common.TableColumn{Header: "Token Address"},
+		common.TableColumn{Header: "Snapshot Total (Wei)", Align: common.AlignRight},
+		common.TableColumn{Header: "Claimed (Wei)", Align: common.AlignRight},
+		common.TableColumn{Header: "Claimable (Wei)", Align: common.AlignRight},
+	)
+	var hidden hiddenRows
+	for _, reward := range rewards {
+		if reward.Error == "" && hidden.hide(dustFilter, reward.Claimable) {
+			continue
+		}
+		table.AddRow(
+			reward.TokenName,
+			reward.Address,
+			common.LocalizeNumber(reward.Total),
+			common.LocalizeNumber(reward.Claimed),
+			common.LocalizeNumber(reward.Claimable),
+		)
+	}
+	return table, hidden
+}
+
+func readAndValidateSimulateConfig(cCtx *cli.Context, logger logging.Logger) (*SimulateConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	earnerAddress := gethcommon.HexToAddress(cCtx.String(EarnerAddressFlag.Name))
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+	snapshotFile := cCtx.String(SnapshotFileFlag.Name)
+	tokenAddresses := getValidHexAddresses(strings.Split(cCtx.String(TokenAddressesFlag.Name), ","))
+
+	rewardsCoordinatorAddress := cCtx.String(RewardsCoordinatorAddressFlag.Name)
+	var err error
+	if common.IsEmptyString(rewardsCoordinatorAddress) {
+		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(utils.NetworkNameToChainId(network))
+		if err != nil {
+			return nil, err
+		}
+	}
+	logger.Debugf("Using Rewards Coordinator address: %s", rewardsCoordinatorAddress)
+
+	chainID := utils.NetworkNameToChainId(network)
+	logger.Debugf("Using chain ID: %s", chainID.String())
+
+	dustFilter, err := readDustFilter(cCtx.Bool(HideZeroFlag.Name), cCtx.String(DustThresholdFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	return &SimulateConfig{
+		Network:                   network,
+		RPCUrl:                    rpcUrl,
+		EarnerAddress:             earnerAddress,
+		TokenAddresses:            tokenAddresses,
+		SnapshotFile:              snapshotFile,
+		ChainID:                   chainID,
+		Output:                    output,
+		OutputType:                outputType,
+		RewardsCoordinatorAddress: gethcommon.HexToAddress(rewardsCoordinatorAddress),
+		DustFilter:                dustFilter,
+	}, nil
+}
@@ -0,0 +1,68 @@
+package rewards
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeSnapshot(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "claim-amounts.json")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	earner := gethcommon.HexToAddress("0x1")
+	path := writeSnapshot(t, `{"earner":"0x0000000000000000000000000000000000000001","token":"0x0000000000000000000000000000000000000002","cumulative_amount":"100"}
+
+{"earner":"0x0000000000000000000000000000000000000001","token":"0x0000000000000000000000000000000000000003","cumulative_amount":"200"}
+`)
+	snapshot, err := loadSnapshot(path)
+	assert.NoError(t, err)
+	tokens, present := snapshot.GetTokensForEarner(earner)
+	assert.True(t, present)
+	assert.Equal(t, 2, tokens.Len())
+
+	_, err = loadSnapshot(writeSnapshot(t, "\n"))
+	assert.EqualError(t, err, "snapshot has no earners")
+
+	_, err = loadSnapshot(writeSnapshot(t, "not json"))
+	assert.Error(t, err)
+}
+
+func TestSimulateClaims(t *testing.T) {
+	earner := gethcommon.HexToAddress("0x1")
+	tokenA := gethcommon.HexToAddress("0x2")
+	tokenB := gethcommon.HexToAddress("0x3")
+	snapshot, err := loadSnapshot(writeSnapshot(t, `{"earner":"0x0000000000000000000000000000000000000001","token":"0x0000000000000000000000000000000000000002","cumulative_amount":"100"}
+{"earner":"0x0000000000000000000000000000000000000001","token":"0x0000000000000000000000000000000000000003","cumulative_amount":"200"}
+`))
+	assert.NoError(t, err)
+
+	reader := &FakeELReader{
+		claimedRewards: map[gethcommon.Address]*big.Int{
+			tokenA: big.NewInt(40),
+			tokenB: big.NewInt(250),
+		},
+	}
+
+	rewards, err := simulateClaims(context.Background(), reader, snapshot, earner, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []simulatedReward{
+		{Token: tokenA, Total: big.NewInt(100), Claimed: big.NewInt(40), Claimable: big.NewInt(60)},
+		{Token: tokenB, Total: big.NewInt(200), Claimed: big.NewInt(250), Claimable: big.NewInt(0), Decreased: true},
+	}, rewards)
+
+	rewards, err = simulateClaims(context.Background(), reader, snapshot, earner, []gethcommon.Address{tokenA})
+	assert.NoError(t, err)
+	assert.Len(t, rewards, 1)
+
+	_, err = simulateClaims(context.Background(), reader, snapshot, gethcommon.HexToAddress("0x4"), nil)
+	assert.ErrorIs(t, err, ErrEarnerNotFound)
+}
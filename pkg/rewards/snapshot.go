@@ -0,0 +1,245 @@
+package rewards
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/proofDataFetcher"
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/proofDataFetcher/httpProofDataFetcher"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var ErrEarnerNotFound = errors.New("earner address not found in distribution")
+
+type claimAmountsFetcher interface {
+	FetchClaimAmountsForDate(ctx context.Context, date string) (*proofDataFetcher.RewardProofData, error)
+}
+
+// RewardsSnapshot holds the rewards of an earner in one distribution root
+type RewardsSnapshot struct {
+	Date      string
+	RootIndex uint32
+	Total     map[gethcommon.Address]*big.Int
+	Unclaimed map[gethcommon.Address]*big.Int
+}
+
+// SnapshotReader reads the rewards of an earner in the latest active distribution root. It's meant
+// for long running commands, e.g. operator monitor, which poll the rewards of an earner. Every
+// snapshot is verified against the posted root before its rewards are read.
+type SnapshotReader struct {
+	elReader  elChainReader
+	fetcher   claimAmountsFetcher
+	snapshots *snapshotVerifier
+	logger    logging.Logger
+
+	mu       sync.Mutex
+	verified map[string]bool
+}
+
+func NewSnapshotReader(
+	ethClient *ethclient.Client,
+	network string,
+	rewardsCoordinatorAddress gethcommon.Address,
+	logger logging.Logger,
+) (*SnapshotReader, error) {
+	elReader, err := elcontracts.NewReaderFromConfig(
+		elcontracts.Config{
+			RewardsCoordinatorAddress: rewardsCoordinatorAddress,
+		},
+		ethClient,
+		logger,
+	)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to create new reader from config", err)
+	}
+
+	proofStoreBaseURL := getProofStoreBaseURL(network)
+	if proofStoreBaseURL == "" {
+		return nil, errors.New("proof store base URL not available for network " + network)
+	}
+	env := getEnvFromNetwork(network)
+	// TODO(shrimalmadhur): Fix to make sure correct S3 bucket is used. Clean up later
+	if network == utils.MainnetNetworkName {
+		network = "ethereum"
+	}
+
+	snapshots, err := newRewardsSnapshotVerifier(ethClient, rewardsCoordinatorAddress, gethcommon.Address{}, logger)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to create snapshot verifier", err)
+	}
+
+	return &SnapshotReader{
+		elReader: elReader,
+		fetcher: newCachedClaimAmountsFetcher(
+			httpProofDataFetcher.NewHttpProofDataFetcher(proofStoreBaseURL, env, network, snapshots.HTTPClient()),
+		),
+		snapshots: snapshots,
+		logger:    logger,
+		verified:  make(map[string]bool),
+	}, nil
+}
+
+// GetLatestSnapshot returns the total and unclaimed rewards of the earner in the latest active root
+func (r *SnapshotReader) GetLatestSnapshot(ctx context.Context, earnerAddress gethcommon.Address) (*RewardsSnapshot, error) {
+	return r.getSnapshot(ctx, earnerAddress, LatestActiveTimestamp)
+}
+
+// GetUpcomingSnapshot returns the rewards of the earner in the latest submitted root, which may
+// not be claimable yet
+func (r *SnapshotReader) GetUpcomingSnapshot(ctx context.Context, earnerAddress gethcommon.Address) (*RewardsSnapshot, error) {
+	return r.getSnapshot(ctx, earnerAddress, LatestTimestamp)
+}
+
+func (r *SnapshotReader) getSnapshot(
+	ctx context.Context,
+	earnerAddress gethcommon.Address,
+	claimTimestamp string,
+) (*RewardsSnapshot, error) {
+	claimDate, rootIndex, err := getClaimDistributionRoot(ctx, claimTimestamp, r.elReader, r.logger)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get claim distribution root", err)
+	}
+
+	proofData, err := r.fetcher.FetchClaimAmountsForDate(ctx, claimDate)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to fetch claim amounts for date", err)
+	}
+	if err := r.verify(ctx, claimDate, rootIndex, proofData); err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to verify rewards snapshot", err)
+	}
+
+	tokenAddressesMap, present := proofData.Distribution.GetTokensForEarner(earnerAddress)
+	if !present {
+		return nil, ErrEarnerNotFound
+	}
+
+	total := make(map[gethcommon.Address]*big.Int)
+	for pair := tokenAddressesMap.Oldest(); pair != nil; pair = pair.Next() {
+		amt, _ := new(big.Int).SetString(pair.Value.String(), 10)
+		total[pair.Key] = amt
+	}
+
+	claimed, err := getClaimedRewards(ctx, r.elReader, earnerAddress, total)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get claimed rewards", err)
+	}
+
+	return &RewardsSnapshot{
+		Date:      claimDate,
+		RootIndex: rootIndex,
+		Total:     total,
+		Unclaimed: calculateUnclaimedRewards(total, claimed),
+	}, nil
+}
+
+// verify verifies the snapshot of a date once. Readers racing for the same snapshot may both
+// verify it, which is only redundant work.
+func (r *SnapshotReader) verify(
+	ctx context.Context,
+	date string,
+	rootIndex uint32,
+	proofData *proofDataFetcher.RewardProofData,
+) error {
+	r.mu.Lock()
+	verified := r.verified[date]
+	r.mu.Unlock()
+	if verified {
+		return nil
+	}
+	if _, err := r.snapshots.Verify(ctx, date, rootIndex, proofData.Distribution, r.logger); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.verified[date] = true
+	r.mu.Unlock()
+	return nil
+}
+
+// maxCachedSnapshots is the number of snapshots kept by the cached fetcher. Two cover readers
+// comparing the latest root with the previous one.
+const maxCachedSnapshots = 2
+
+// cachedClaimAmountsFetcher keeps the last downloaded snapshots, so the rewards of many earners in
+// the same root are read with a single download. Concurrent reads of a snapshot wait for the first
+// download instead of starting their own, while snapshots of other dates download in parallel.
+type cachedClaimAmountsFetcher struct {
+	fetcher claimAmountsFetcher
+	mu      sync.Mutex
+	cache   map[string]*cachedSnapshot
+}
+
+// cachedSnapshot is the download of one snapshot. done is closed once proofData or err is set.
+type cachedSnapshot struct {
+	done      chan struct{}
+	proofData *proofDataFetcher.RewardProofData
+	err       error
+}
+
+func newCachedClaimAmountsFetcher(fetcher claimAmountsFetcher) *cachedClaimAmountsFetcher {
+	return &cachedClaimAmountsFetcher{
+		fetcher: fetcher,
+		cache:   make(map[string]*cachedSnapshot),
+	}
+}
+
+func (f *cachedClaimAmountsFetcher) FetchClaimAmountsForDate(
+	ctx context.Context,
+	date string,
+) (*proofDataFetcher.RewardProofData, error) {
+	f.mu.Lock()
+	snapshot, ok := f.cache[date]
+	if !ok {
+		snapshot = &cachedSnapshot{done: make(chan struct{})}
+		f.cache[date] = snapshot
+		f.evict()
+	}
+	f.mu.Unlock()
+
+	if ok {
+		select {
+		case <-snapshot.done:
+			return snapshot.proofData, snapshot.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	snapshot.proofData, snapshot.err = f.fetcher.FetchClaimAmountsForDate(ctx, date)
+	if snapshot.err != nil {
+		// A failed download is retried by the next read
+		f.mu.Lock()
+		if f.cache[date] == snapshot {
+			delete(f.cache, date)
+		}
+		f.mu.Unlock()
+	}
+	close(snapshot.done)
+	return snapshot.proofData, snapshot.err
+}
+
+// evict drops the oldest snapshots above maxCachedSnapshots. Snapshot dates sort chronologically.
+// Reads waiting for an evicted download still get its result. It must be called with mu held.
+func (f *cachedClaimAmountsFetcher) evict() {
+	if len(f.cache) <= maxCachedSnapshots {
+		return
+	}
+	dates := make([]string, 0, len(f.cache))
+	for cached := range f.cache {
+		dates = append(dates, cached)
+	}
+	sort.Strings(dates)
+	for _, evicted := range dates[:len(dates)-maxCachedSnapshots] {
+		delete(f.cache, evicted)
+	}
+}
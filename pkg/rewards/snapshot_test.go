@@ -0,0 +1,82 @@
+package rewards
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/proofDataFetcher"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingClaimAmountsFetcher struct {
+	fetches map[string]int
+}
+
+func (f *countingClaimAmountsFetcher) FetchClaimAmountsForDate(
+	ctx context.Context,
+	date string,
+) (*proofDataFetcher.RewardProofData, error) {
+	f.fetches[date]++
+	return &proofDataFetcher.RewardProofData{}, nil
+}
+
+func TestCachedClaimAmountsFetcher(t *testing.T) {
+	counter := &countingClaimAmountsFetcher{fetches: make(map[string]int)}
+	fetcher := newCachedClaimAmountsFetcher(counter)
+	for _, date := range []string{"2024-08-01", "2024-08-01", "2024-08-02", "2024-08-01", "2024-08-03", "2024-08-01"} {
+		_, err := fetcher.FetchClaimAmountsForDate(context.Background(), date)
+		assert.NoError(t, err)
+	}
+
+	// The oldest snapshot is evicted once a third one is fetched
+	assert.Equal(t, map[string]int{"2024-08-01": 2, "2024-08-02": 1, "2024-08-03": 1}, counter.fetches)
+	assert.Len(t, fetcher.cache, maxCachedSnapshots)
+}
+
+// blockingClaimAmountsFetcher blocks the download of a date until it is released
+type blockingClaimAmountsFetcher struct {
+	mu      sync.Mutex
+	fetches map[string]int
+	release map[string]chan struct{}
+}
+
+func (f *blockingClaimAmountsFetcher) FetchClaimAmountsForDate(
+	ctx context.Context,
+	date string,
+) (*proofDataFetcher.RewardProofData, error) {
+	f.mu.Lock()
+	f.fetches[date]++
+	release := f.release[date]
+	f.mu.Unlock()
+	<-release
+	return &proofDataFetcher.RewardProofData{}, nil
+}
+
+func TestCachedClaimAmountsFetcherConcurrent(t *testing.T) {
+	blocking := &blockingClaimAmountsFetcher{
+		fetches: make(map[string]int),
+		release: map[string]chan struct{}{"2024-08-01": make(chan struct{}), "2024-08-02": make(chan struct{})},
+	}
+	fetcher := newCachedClaimAmountsFetcher(blocking)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := fetcher.FetchClaimAmountsForDate(context.Background(), "2024-08-01")
+			assert.NoError(t, err)
+		}()
+	}
+
+	// Another date is read while the first one is still downloading
+	close(blocking.release["2024-08-02"])
+	_, err := fetcher.FetchClaimAmountsForDate(context.Background(), "2024-08-02")
+	assert.NoError(t, err)
+
+	close(blocking.release["2024-08-01"])
+	wg.Wait()
+	assert.Equal(t, map[string]int{"2024-08-01": 1, "2024-08-02": 1}, blocking.fetches)
+}
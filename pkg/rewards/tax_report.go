@@ -0,0 +1,406 @@
+package rewards
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/erc20"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/profile"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+const taxReportEventType = "reward_claim"
+
+type taxReportRow struct {
+	Timestamp    string `csv:"timestamp"     json:"timestamp"`
+	Date         string `csv:"date"          json:"date"`
+	Type         string `csv:"type"          json:"type"`
+	TokenSymbol  string `csv:"token_symbol"  json:"tokenSymbol"`
+	TokenAddress string `csv:"token_address" json:"tokenAddress"`
+	Amount       string `csv:"amount"        json:"amount"`
+	AmountWei    string `csv:"amount_wei"    json:"amountWei"`
+	PriceUSD     string `csv:"price_usd"     json:"priceUsd"`
+	ValueUSD     string `csv:"value_usd"     json:"valueUsd"`
+	Recipient    string `csv:"recipient"     json:"recipient"`
+	TxHash       string `csv:"tx_hash"       json:"txHash"`
+}
+
+func TaxReportCmd(p utils.Prompter) *cli.Command {
+	taxReportCmd := &cli.Command{
+		Name:      "tax-report",
+		Usage:     "List the reward claims of an earner in a year with the token price at claim time",
+		UsageText: "tax-report",
+		Description: `
+Command to generate a report of all rewards claimed by an earner in a year, shaped for tax preparation.
+
+Each claim is listed with its timestamp, the claimed amount and the USD price of the token at the
+day of the claim, taken from the configured price source. The 'chainlink' source reads the price at
+the time of the claim from on-chain price feeds.
+
+Helpful flags
+- price-source: Where to get token prices from. Can be 'coingecko', 'chainlink', 'http', 'file' or 'none'
+- price-file: CSV file with the columns date (YYYY-MM-DD), token (address or symbol) and price_usd. Used with the 'file' price source
+- price-url: URL template of an internal price service. Used with the 'http' price source
+- chainlink-feeds: USD price feeds by token address or symbol. Used with the 'chainlink' price source
+- price-cache: Keep fetched prices on disk, since past prices don't change. Enabled by default
+- output-type: 'pretty', 'json' or 'csv'
+		`,
+		After: telemetry.AfterRunAction(),
+		Flags: getTaxReportFlags(),
+		Action: func(cCtx *cli.Context) error {
+			if profile.IsFanOut(cCtx) {
+				return profile.FanOut(cCtx)
+			}
+			return TaxReport(cCtx)
+		},
+	}
+
+	return taxReportCmd
+}
+
+func getTaxReportFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.VerboseFlag,
+		&EarnerAddressFlag,
+		&RewardsCoordinatorAddressFlag,
+		&YearFlag,
+		&PriceSourceFlag,
+		&PriceFileFlag,
+		&PriceURLFlag,
+		&PriceAuthTokenFlag,
+		&ChainlinkFeedsFlag,
+		&PriceRateLimitFlag,
+		&PriceCacheFlag,
+	}
+	baseFlags = append(baseFlags, profile.FanOutFlags()...)
+
+	sort.Sort(cli.FlagsByName(baseFlags))
+	return baseFlags
+}
+
+func TaxReport(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateTaxReportConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate tax report config", err)
+	}
+	cCtx.App.Metadata["network"] = config.ChainID.String()
+
+	ethClient, err := common.DialEthClient(config.RPCUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	prices, err := NewPriceSource(config.Price, ethClient)
+	if err != nil {
+		return err
+	}
+
+	rows, err := getTaxReportRows(ctx, config, ethClient, prices, logger)
+	if err != nil {
+		return err
+	}
+
+	return handleTaxReportOutput(config, rows)
+}
+
+func getTaxReportRows(
+	ctx context.Context,
+	cfg *TaxReportConfig,
+	ethClient *ethclient.Client,
+	prices PriceSource,
+	logger logging.Logger,
+) ([]taxReportRow, error) {
+	_, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
+		RewardsCoordinatorAddress: cfg.RewardsCoordinatorAddress,
+	}, ethClient, nil, logger, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := getYearBlocks(ctx, ethClient, cfg.Year)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get blocks of the year", err)
+	}
+
+	blockTimes := make(map[uint64]time.Time)
+	symbols := make(map[gethcommon.Address]string)
+	decimals := make(map[gethcommon.Address]uint8)
+	rows := make([]taxReportRow, 0)
+	err = common.FilterBlockRange(ctx, start, end, func(opts *bind.FilterOpts) error {
+		iter, err := contractBindings.RewardsCoordinator.FilterRewardsClaimed(
+			opts,
+			[]gethcommon.Address{cfg.EarnerAddress},
+			nil,
+			nil,
+		)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to get claim events", err)
+		}
+		defer iter.Close()
+		for iter.Next() {
+			event := iter.Event
+			blockTime, ok := blockTimes[event.Raw.BlockNumber]
+			if !ok {
+				header, err := ethClient.HeaderByNumber(ctx, new(big.Int).SetUint64(event.Raw.BlockNumber))
+				if err != nil {
+					return eigenSdkUtils.WrapError("failed to get block header", err)
+				}
+				blockTime = time.Unix(int64(header.Time), 0).UTC()
+				blockTimes[event.Raw.BlockNumber] = blockTime
+			}
+
+			if _, ok := symbols[event.Token]; !ok {
+				symbols[event.Token] = erc20.GetTokenSymbol(event.Token, ethClient)
+				tokenDecimals, err := erc20.GetTokenDecimals(event.Token, ethClient)
+				if err != nil {
+					logger.Warnf(
+						"Failed to get decimals for token %s, using %d: %s",
+						event.Token.Hex(),
+						erc20.DefaultDecimals,
+						err,
+					)
+					tokenDecimals = erc20.DefaultDecimals
+				}
+				decimals[event.Token] = tokenDecimals
+			}
+			symbol := symbols[event.Token]
+			amount := erc20.FormatUnits(event.ClaimedAmount, decimals[event.Token])
+
+			priceUSD, valueUSD := "", ""
+			if prices != nil {
+				price, err := prices.GetPrice(ctx, event.Token, symbol, blockTime)
+				if err != nil {
+					logger.Warnf("Failed to get price of %s on %s: %s", symbol, blockTime.Format(time.DateOnly), err)
+				} else {
+					priceUSD = fmt.Sprintf("%f", price)
+					valueUSD = getUSDValue(amount, price)
+				}
+			}
+
+			rows = append(rows, taxReportRow{
+				Timestamp:    blockTime.Format(time.RFC3339),
+				Date:         blockTime.Format(time.DateOnly),
+				Type:         taxReportEventType,
+				TokenSymbol:  symbol,
+				TokenAddress: event.Token.Hex(),
+				Amount:       amount,
+				AmountWei:    event.ClaimedAmount.String(),
+				PriceUSD:     priceUSD,
+				ValueUSD:     valueUSD,
+				Recipient:    event.Recipient.Hex(),
+				TxHash:       event.Raw.TxHash.Hex(),
+			})
+		}
+		if err := iter.Error(); err != nil {
+			return eigenSdkUtils.WrapError("failed to iterate claim events", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// getYearBlocks returns the first and the last block of the year, or of the year so far. The
+// last block is before the first one if the year has no blocks.
+func getYearBlocks(ctx context.Context, ethClient *ethclient.Client, year int) (uint64, uint64, error) {
+	start, err := common.BlockAtTimestamp(
+		ctx,
+		ethClient,
+		uint64(time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()),
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	next, err := common.BlockAtTimestamp(
+		ctx,
+		ethClient,
+		uint64(time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()),
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	if next == 0 {
+		return 1, 0, nil
+	}
+	return start, next - 1, nil
+}
+
+// getUSDValue multiplies a token amount in token units with its USD price
+func getUSDValue(amount string, price float64) string {
+	value, ok := new(big.Float).SetString(amount)
+	if !ok {
+		return ""
+	}
+	value.Mul(value, big.NewFloat(price))
+	return value.Text('f', 2)
+}
+
+func handleTaxReportOutput(cfg *TaxReportConfig, rows []taxReportRow) error {
+	switch cfg.OutputType {
+	case string(common.OutputType_Json):
+		out, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return err
+		}
+		if !common.IsEmptyString(cfg.Output) {
+			return common.WriteToFile(out, cfg.Output)
+		}
+		fmt.Println(string(out))
+	case string(common.OutputType_Csv):
+		if common.IsEmptyString(cfg.Output) {
+			return errors.New("output file is required for csv output type")
+		}
+		return common.WriteToCSV(rows, cfg.Output)
+	case string(common.OutputType_Markdown):
+		table, total := newTaxReportTable(rows)
+		var b strings.Builder
+		fmt.Fprintf(&b, "## Reward Claims in %d\n\n", cfg.Year)
+		b.WriteString(table.Markdown())
+		fmt.Fprintf(
+			&b,
+			"\n**Total claims:** %d, **total value:** %s USD\n",
+			len(rows),
+			common.LocalizeNumber(total.Text('f', 2)),
+		)
+		if !common.IsEmptyString(cfg.Output) {
+			return common.WriteToFile([]byte(b.String()), cfg.Output)
+		}
+		fmt.Print(b.String())
+	default:
+		if !common.IsEmptyString(cfg.Output) {
+			fmt.Println("output file not supported for pretty output type")
+			fmt.Println()
+		}
+		fmt.Println()
+		fmt.Println(strings.Repeat("-", 30), fmt.Sprintf("Reward Claims in %d", cfg.Year), strings.Repeat("-", 30))
+		printTaxReport(rows)
+	}
+	return nil
+}
+
+func printTaxReport(rows []taxReportRow) {
+	table, total := newTaxReportTable(rows)
+	table.Print()
+	fmt.Printf("Total claims: %d, total value: %s USD\n", len(rows), common.LocalizeNumber(total.Text('f', 2)))
+}
+
+// newTaxReportTable returns the table of the report and the total USD value of the claims
+func newTaxReportTable(rows []taxReportRow) (*common.Table, *big.Float) {
+	table := common.NewTable(
+		common.TableColumn{Header: "Timestamp"},
+		common.TableColumn{Header: "Token", MaxWidth: maxTokenNameWidth},
+		common.TableColumn{Header: "Amount", Align: common.AlignRight},
+		common.TableColumn{Header: "Price (USD)", Align: common.AlignRight},
+		common.TableColumn{Header: "Value (USD)", Align: common.AlignRight},
+		common.TableColumn{Header: "Transaction Hash"},
+	)
+	total := new(big.Float)
+	for _, row := range rows {
+		table.AddRow(
+			localizeTimestamp(row.Timestamp),
+			row.TokenSymbol,
+			common.LocalizeNumber(row.Amount),
+			common.LocalizeNumber(row.PriceUSD),
+			common.LocalizeNumber(row.ValueUSD),
+			row.TxHash,
+		)
+		if value, ok := new(big.Float).SetString(row.ValueUSD); ok {
+			total.Add(total, value)
+		}
+	}
+	return table, total
+}
+
+// localizeTimestamp formats an RFC 3339 timestamp of a row in the time zone and locale of the output
+func localizeTimestamp(timestamp string) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	return common.FormatTime(t)
+}
+
+func readAndValidateTaxReportConfig(cCtx *cli.Context, logger logging.Logger) (*TaxReportConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	earner := cCtx.String(EarnerAddressFlag.Name)
+	if !gethcommon.IsHexAddress(earner) || gethcommon.HexToAddress(earner) == utils.ZeroAddress {
+		return nil, fmt.Errorf("invalid earner address %s", earner)
+	}
+	earnerAddress := gethcommon.HexToAddress(earner)
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+	chainlinkFeeds, err := parseChainlinkFeeds(cCtx.String(ChainlinkFeedsFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	price := PriceConfig{
+		Source:         cCtx.String(PriceSourceFlag.Name),
+		File:           cCtx.String(PriceFileFlag.Name),
+		URL:            cCtx.String(PriceURLFlag.Name),
+		AuthToken:      cCtx.String(PriceAuthTokenFlag.Name),
+		ChainlinkFeeds: chainlinkFeeds,
+		RateLimit:      cCtx.Float64(PriceRateLimitFlag.Name),
+		Cache:          cCtx.Bool(PriceCacheFlag.Name),
+		Network:        network,
+	}
+
+	year := cCtx.Int(YearFlag.Name)
+	if year == 0 {
+		year = time.Now().UTC().Year()
+	}
+	logger.Debugf("Using year: %d", year)
+
+	rewardsCoordinatorAddress := cCtx.String(RewardsCoordinatorAddressFlag.Name)
+	if common.IsEmptyString(rewardsCoordinatorAddress) {
+		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(utils.NetworkNameToChainId(network))
+		if err != nil {
+			return nil, err
+		}
+	}
+	logger.Debugf("Using Rewards Coordinator address: %s", rewardsCoordinatorAddress)
+
+	chainID := utils.NetworkNameToChainId(network)
+	logger.Debugf("Using chain ID: %s", chainID.String())
+
+	return &TaxReportConfig{
+		Network:                   network,
+		RPCUrl:                    rpcUrl,
+		EarnerAddress:             earnerAddress,
+		Year:                      year,
+		Price:                     price,
+		ChainID:                   chainID,
+		Output:                    output,
+		OutputType:                outputType,
+		RewardsCoordinatorAddress: gethcommon.HexToAddress(rewardsCoordinatorAddress),
+	}, nil
+}
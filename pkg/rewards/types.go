@@ -3,6 +3,7 @@ package rewards
 import (
 	"math/big"
 
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/types"
 	gethcommon "github.com/ethereum/go-ethereum/common"
 )
@@ -15,6 +16,16 @@ type rewardsJson struct {
 
 type allRewardsJson []rewardsJson
 
+type rewardsErrorJson struct {
+	Address string `json:"tokenAddress"`
+	Error   string `json:"error"`
+}
+
+type partialRewardsJson struct {
+	Rewards allRewardsJson     `json:"rewards"`
+	Errors  []rewardsErrorJson `json:"errors"`
+}
+
 type ClaimConfig struct {
 	Network                   string
 	RPCUrl                    string
@@ -29,10 +40,18 @@ type ClaimConfig struct {
 	ClaimTimestamp            string
 	ChainID                   *big.Int
 	ProofStoreBaseURL         string
+	ProofStoreSigner          gethcommon.Address
 	Environment               string
 	SignerConfig              *types.SignerConfig
 	IsSilent                  bool
 	BatchClaimFile            string
+	VerifyProofs              bool
+	TrustedBlockHash          string
+	PlanConfig                *common.PlanConfig
+	ResumeFile                string
+	BatchSize                 int
+	IgnoreClaimHistory        bool
+	CatchUp                   bool
 }
 
 type SetClaimerConfig struct {
@@ -46,6 +65,7 @@ type SetClaimerConfig struct {
 	EarnerAddress             gethcommon.Address
 	Output                    string
 	OutputType                string
+	PlanConfig                *common.PlanConfig
 }
 
 type ShowConfig struct {
@@ -59,6 +79,85 @@ type ShowConfig struct {
 	Output                    string
 	OutputType                string
 	ProofStoreBaseURL         string
+	ProofStoreSigner          gethcommon.Address
 	ClaimTimestamp            string
 	RewardsCoordinatorAddress gethcommon.Address
+	VerifyProofs              bool
+	TrustedBlockHash          string
+	AllowPartial              bool
+	AccountingCSV             string
+	DustFilter                DustFilter
+}
+
+type TaxReportConfig struct {
+	Network                   string
+	RPCUrl                    string
+	EarnerAddress             gethcommon.Address
+	Year                      int
+	Price                     PriceConfig
+	ChainID                   *big.Int
+	Output                    string
+	OutputType                string
+	RewardsCoordinatorAddress gethcommon.Address
+}
+
+// PriceConfig selects and configures the source of token prices, see NewPriceSource
+type PriceConfig struct {
+	Source string
+	// File is the CSV file of the file source
+	File string
+	// URL is the URL template of the http source
+	URL       string
+	AuthToken string
+	// ChainlinkFeeds are the USD price feeds of the chainlink source by lowercase token address or symbol
+	ChainlinkFeeds map[string]gethcommon.Address
+	// RateLimit is the maximum of requests per second to the coingecko and http sources
+	RateLimit float64
+	// Cache keeps the prices of network sources in a disk cache
+	Cache   bool
+	Network string
+}
+
+type SimulateConfig struct {
+	Network                   string
+	RPCUrl                    string
+	EarnerAddress             gethcommon.Address
+	TokenAddresses            []gethcommon.Address
+	SnapshotFile              string
+	ChainID                   *big.Int
+	Output                    string
+	OutputType                string
+	RewardsCoordinatorAddress gethcommon.Address
+	DustFilter                DustFilter
+}
+
+type RootConfig struct {
+	Network                        string
+	RPCUrl                         string
+	UpdaterAddress                 gethcommon.Address
+	RewardsCoordinatorAddress      gethcommon.Address
+	ChainID                        *big.Int
+	SignerConfig                   *types.SignerConfig
+	Broadcast                      bool
+	Output                         string
+	OutputType                     string
+	PlanConfig                     *common.PlanConfig
+	SkipConfirmation               bool
+	Root                           [32]byte
+	RewardsCalculationEndTimestamp uint32
+	RootIndex                      uint32
+}
+
+type ExportProofConfig struct {
+	Network                   string
+	Environment               string
+	RPCUrl                    string
+	EarnerAddress             gethcommon.Address
+	TokenAddresses            []gethcommon.Address
+	ClaimTimestamp            string
+	ProofStoreBaseURL         string
+	ProofStoreSigner          gethcommon.Address
+	ChainID                   *big.Int
+	Output                    string
+	RewardsCoordinatorAddress gethcommon.Address
 }
@@ -0,0 +1,400 @@
+package rewards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IRewardsCoordinator"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Storage slots of the RewardsCoordinator state variables we verify. These follow the
+// storage layout of RewardsCoordinatorStorage once the upgradeable base contracts
+// (Initializable, OwnableUpgradeable, ReentrancyGuardUpgradeable, Pausable) are accounted for.
+const (
+	distributionRootsSlot = 202
+	cumulativeClaimedSlot = 205
+)
+
+var ErrProofVerificationFailed = errors.New("storage proof verification failed")
+
+// proofVerifier checks values returned by the RPC against eth_getProof storage proofs
+// anchored in a block header, so the RPC can't hand out values the chain never committed to.
+// Values are read at the block of the header, so a transaction landing in between can't make
+// the read and the proof disagree.
+type proofVerifier struct {
+	proofClient        *gethclient.Client
+	caller             *rewardscoordinator.ContractIRewardsCoordinatorCaller
+	rewardsCoordinator gethcommon.Address
+	header             *types.Header
+}
+
+func newProofVerifier(
+	ctx context.Context,
+	rpcUrl string,
+	rewardsCoordinator gethcommon.Address,
+	trustedBlockHash string,
+) (*proofVerifier, error) {
+	rpcClient, err := rpc.DialContext(ctx, rpcUrl)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to create rpc client for proof verification", err)
+	}
+	ethClient := ethclient.NewClient(rpcClient)
+
+	var header *types.Header
+	if trustedBlockHash != "" {
+		hash := gethcommon.HexToHash(trustedBlockHash)
+		header, err = ethClient.HeaderByHash(ctx, hash)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to get trusted block header", err)
+		}
+		// The header is only trustworthy if it hashes to the block hash the user provided
+		if header.Hash() != hash {
+			return nil, fmt.Errorf(
+				"%w: header returned by RPC does not match trusted block hash %s",
+				ErrProofVerificationFailed,
+				hash.Hex(),
+			)
+		}
+	} else {
+		header, err = ethClient.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to get latest block header", err)
+		}
+	}
+
+	caller, err := rewardscoordinator.NewContractIRewardsCoordinatorCaller(rewardsCoordinator, ethClient)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to create rewards coordinator caller", err)
+	}
+
+	return &proofVerifier{
+		proofClient:        gethclient.New(rpcClient),
+		caller:             caller,
+		rewardsCoordinator: rewardsCoordinator,
+		header:             header,
+	}, nil
+}
+
+// callOpts reads contract state at the block the proofs are taken at
+func (v *proofVerifier) callOpts(ctx context.Context) *bind.CallOpts {
+	return &bind.CallOpts{Context: ctx, BlockNumber: v.header.Number}
+}
+
+// VerifyCumulativeClaimed checks that cumulativeClaimed[earner][token] matches the proven storage value
+func (v *proofVerifier) VerifyCumulativeClaimed(
+	ctx context.Context,
+	earnerAddress, tokenAddress gethcommon.Address,
+	claimed *big.Int,
+) error {
+	slot := cumulativeClaimedStorageSlot(earnerAddress, tokenAddress)
+	proven, err := v.provenStorageValue(ctx, slot)
+	if err != nil {
+		return err
+	}
+	if proven.Cmp(claimed) != 0 {
+		return fmt.Errorf(
+			"%w: cumulative claimed for earner %s and token %s is %s but proof at block %d shows %s",
+			ErrProofVerificationFailed,
+			earnerAddress.Hex(),
+			tokenAddress.Hex(),
+			claimed.String(),
+			v.header.Number.Uint64(),
+			proven.String(),
+		)
+	}
+	return nil
+}
+
+// VerifyDistributionRoot checks that the distribution root posted at rootIndex is the expected
+// root and that it has not been disabled
+func (v *proofVerifier) VerifyDistributionRoot(
+	ctx context.Context,
+	rootIndex uint32,
+	expectedRoot gethcommon.Hash,
+) error {
+	root, err := v.provenDistributionRoot(ctx, rootIndex)
+	if err != nil {
+		return err
+	}
+	if root.Root != expectedRoot {
+		return fmt.Errorf(
+			"%w: root at index %d is %s but proof at block %d shows %s",
+			ErrProofVerificationFailed,
+			rootIndex,
+			expectedRoot.Hex(),
+			v.header.Number.Uint64(),
+			gethcommon.Hash(root.Root).Hex(),
+		)
+	}
+	if root.Disabled {
+		return fmt.Errorf("%w: root at index %d is disabled", ErrProofVerificationFailed, rootIndex)
+	}
+	return nil
+}
+
+// provenDistributionRootsLength returns the number of posted distribution roots
+func (v *proofVerifier) provenDistributionRootsLength(ctx context.Context) (*big.Int, error) {
+	return v.provenStorageValue(ctx, gethcommon.BigToHash(big.NewInt(distributionRootsSlot)))
+}
+
+// provenDistributionRoot returns _distributionRoots[rootIndex] as proven by the storage proofs
+func (v *proofVerifier) provenDistributionRoot(
+	ctx context.Context,
+	rootIndex uint32,
+) (rewardscoordinator.IRewardsCoordinatorDistributionRoot, error) {
+	var root rewardscoordinator.IRewardsCoordinatorDistributionRoot
+	length, err := v.provenDistributionRootsLength(ctx)
+	if err != nil {
+		return root, err
+	}
+	if length.Cmp(new(big.Int).SetUint64(uint64(rootIndex))) <= 0 {
+		return root, fmt.Errorf(
+			"%w: root index %d is out of range, proof at block %d shows %s roots",
+			ErrProofVerificationFailed,
+			rootIndex,
+			v.header.Number.Uint64(),
+			length.String(),
+		)
+	}
+
+	rootSlot, packedSlot := distributionRootStorageSlots(rootIndex)
+	hash, err := v.provenStorageValue(ctx, rootSlot)
+	if err != nil {
+		return root, err
+	}
+	packed, err := v.provenStorageValue(ctx, packedSlot)
+	if err != nil {
+		return root, err
+	}
+	// struct DistributionRoot packs (uint32 rewardsCalculationEndTimestamp, uint32 activatedAt, bool disabled)
+	// into its second slot, so the disabled flag starts at bit 64
+	mask := big.NewInt(0xffffffff)
+	root.Root = gethcommon.BigToHash(hash)
+	root.RewardsCalculationEndTimestamp = uint32(new(big.Int).And(packed, mask).Uint64())
+	root.ActivatedAt = uint32(new(big.Int).And(new(big.Int).Rsh(packed, 32), mask).Uint64())
+	root.Disabled = new(big.Int).Rsh(packed, 64).Bit(0) == 1
+	return root, nil
+}
+
+// provenStorageValue fetches the proof for a storage slot of the rewards coordinator and
+// verifies it all the way up to the state root of the verifier's block header
+func (v *proofVerifier) provenStorageValue(ctx context.Context, slot gethcommon.Hash) (*big.Int, error) {
+	result, err := v.proofClient.GetProof(ctx, v.rewardsCoordinator, []string{slot.Hex()}, v.header.Number)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get storage proof", err)
+	}
+	if len(result.StorageProof) != 1 {
+		return nil, fmt.Errorf("%w: expected 1 storage proof, got %d", ErrProofVerificationFailed, len(result.StorageProof))
+	}
+
+	accountRLP, err := trie.VerifyProof(
+		v.header.Root,
+		crypto.Keccak256(v.rewardsCoordinator.Bytes()),
+		newProofDB(result.AccountProof),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid account proof: %s", ErrProofVerificationFailed, err)
+	}
+	if accountRLP == nil {
+		return nil, fmt.Errorf(
+			"%w: rewards coordinator %s does not exist at block %d",
+			ErrProofVerificationFailed,
+			v.rewardsCoordinator.Hex(),
+			v.header.Number.Uint64(),
+		)
+	}
+	var account types.StateAccount
+	if err := rlp.DecodeBytes(accountRLP, &account); err != nil {
+		return nil, fmt.Errorf("%w: invalid account in proof: %s", ErrProofVerificationFailed, err)
+	}
+
+	valueRLP, err := trie.VerifyProof(
+		account.Root,
+		crypto.Keccak256(slot.Bytes()),
+		newProofDB(result.StorageProof[0].Proof),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid storage proof: %s", ErrProofVerificationFailed, err)
+	}
+	// Empty slots are not part of the trie
+	if valueRLP == nil {
+		return big.NewInt(0), nil
+	}
+	_, content, _, err := rlp.Split(valueRLP)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid storage value in proof: %s", ErrProofVerificationFailed, err)
+	}
+	return new(big.Int).SetBytes(content), nil
+}
+
+func newProofDB(proof []string) *memorydb.Database {
+	db := memorydb.New()
+	for _, node := range proof {
+		blob := gethcommon.FromHex(node)
+		_ = db.Put(crypto.Keccak256(blob), blob)
+	}
+	return db
+}
+
+// mappingStorageSlot returns the slot of mapping[key] for a mapping declared at slot
+func mappingStorageSlot(key gethcommon.Hash, slot gethcommon.Hash) gethcommon.Hash {
+	return crypto.Keccak256Hash(key.Bytes(), slot.Bytes())
+}
+
+// arrayStorageSlot returns the first slot of array[index] for a dynamic array declared at slot
+// whose elements take elementSize slots each
+func arrayStorageSlot(slot gethcommon.Hash, index uint64, elementSize uint64) gethcommon.Hash {
+	base := crypto.Keccak256Hash(slot.Bytes()).Big()
+	offset := new(big.Int).Mul(new(big.Int).SetUint64(index), new(big.Int).SetUint64(elementSize))
+	return gethcommon.BigToHash(new(big.Int).Add(base, offset))
+}
+
+// cumulativeClaimedStorageSlot returns the slot of cumulativeClaimed[earner][token]
+func cumulativeClaimedStorageSlot(earnerAddress, tokenAddress gethcommon.Address) gethcommon.Hash {
+	inner := mappingStorageSlot(
+		gethcommon.BytesToHash(earnerAddress.Bytes()),
+		gethcommon.BigToHash(big.NewInt(cumulativeClaimedSlot)),
+	)
+	return mappingStorageSlot(gethcommon.BytesToHash(tokenAddress.Bytes()), inner)
+}
+
+// distributionRootStorageSlots returns the two slots holding _distributionRoots[rootIndex]
+func distributionRootStorageSlots(rootIndex uint32) (gethcommon.Hash, gethcommon.Hash) {
+	rootSlot := arrayStorageSlot(gethcommon.BigToHash(big.NewInt(distributionRootsSlot)), uint64(rootIndex), 2)
+	packedSlot := new(big.Int).Add(rootSlot.Big(), big.NewInt(1))
+	return rootSlot, gethcommon.BigToHash(packedSlot)
+}
+
+// verifiedELReader wraps a chain reader and verifies the distribution roots and cumulative claimed
+// amounts it reads with storage proofs. All of them are read at the block of the verifier.
+type verifiedELReader struct {
+	elChainReader
+	verifier *proofVerifier
+}
+
+func (r *verifiedELReader) GetDistributionRootsLength(ctx context.Context) (*big.Int, error) {
+	length, err := r.verifier.caller.GetDistributionRootsLength(r.verifier.callOpts(ctx))
+	if err != nil {
+		return nil, err
+	}
+	proven, err := r.verifier.provenDistributionRootsLength(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if proven.Cmp(length) != 0 {
+		return nil, fmt.Errorf(
+			"%w: number of roots is %s but proof at block %d shows %s",
+			ErrProofVerificationFailed,
+			length.String(),
+			r.verifier.header.Number.Uint64(),
+			proven.String(),
+		)
+	}
+	return length, nil
+}
+
+// CurrRewardsCalculationEndTimestamp is verified against the latest posted root, which is the root
+// that set it
+func (r *verifiedELReader) CurrRewardsCalculationEndTimestamp(ctx context.Context) (uint32, error) {
+	timestamp, err := r.verifier.caller.CurrRewardsCalculationEndTimestamp(r.verifier.callOpts(ctx))
+	if err != nil {
+		return 0, err
+	}
+	length, err := r.verifier.provenDistributionRootsLength(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if length.Sign() == 0 {
+		return 0, fmt.Errorf("%w: no distribution roots have been posted", ErrProofVerificationFailed)
+	}
+	latest, err := r.verifier.provenDistributionRoot(ctx, uint32(length.Uint64()-1))
+	if err != nil {
+		return 0, err
+	}
+	if latest.RewardsCalculationEndTimestamp != timestamp {
+		return 0, fmt.Errorf(
+			"%w: rewards calculation end timestamp is %d but proof at block %d shows %d",
+			ErrProofVerificationFailed,
+			timestamp,
+			r.verifier.header.Number.Uint64(),
+			latest.RewardsCalculationEndTimestamp,
+		)
+	}
+	return timestamp, nil
+}
+
+func (r *verifiedELReader) GetRootIndexFromHash(ctx context.Context, hash [32]byte) (uint32, error) {
+	rootIndex, err := r.verifier.caller.GetRootIndexFromHash(r.verifier.callOpts(ctx), hash)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.verifier.VerifyDistributionRoot(ctx, rootIndex, hash); err != nil {
+		return 0, err
+	}
+	return rootIndex, nil
+}
+
+func (r *verifiedELReader) GetCurrentClaimableDistributionRoot(
+	ctx context.Context,
+) (rewardscoordinator.IRewardsCoordinatorDistributionRoot, error) {
+	root, err := r.verifier.caller.GetCurrentClaimableDistributionRoot(r.verifier.callOpts(ctx))
+	if err != nil {
+		return root, err
+	}
+	rootIndex, err := r.verifier.caller.GetRootIndexFromHash(r.verifier.callOpts(ctx), root.Root)
+	if err != nil {
+		return root, err
+	}
+	proven, err := r.verifier.provenDistributionRoot(ctx, rootIndex)
+	if err != nil {
+		return root, err
+	}
+	if proven != root {
+		return root, fmt.Errorf(
+			"%w: claimable root at index %d does not match the proof at block %d",
+			ErrProofVerificationFailed,
+			rootIndex,
+			r.verifier.header.Number.Uint64(),
+		)
+	}
+	if root.Disabled || uint64(root.ActivatedAt) > r.verifier.header.Time {
+		return root, fmt.Errorf(
+			"%w: root at index %d is not claimable at block %d",
+			ErrProofVerificationFailed,
+			rootIndex,
+			r.verifier.header.Number.Uint64(),
+		)
+	}
+	return root, nil
+}
+
+func (r *verifiedELReader) GetCumulativeClaimed(
+	ctx context.Context,
+	earnerAddress, tokenAddress gethcommon.Address,
+) (*big.Int, error) {
+	claimed, err := r.verifier.caller.CumulativeClaimed(r.verifier.callOpts(ctx), earnerAddress, tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+	if claimed == nil {
+		claimed = big.NewInt(0)
+	}
+	if err := r.verifier.VerifyCumulativeClaimed(ctx, earnerAddress, tokenAddress, claimed); err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
@@ -0,0 +1,46 @@
+package rewards
+
+import (
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMappingStorageSlot(t *testing.T) {
+	// keccak256(abi.encode(key, slot)), here for mapping(address => ...) at slot 205
+	slot := mappingStorageSlot(
+		gethcommon.BytesToHash(gethcommon.HexToAddress("0x1111111111111111111111111111111111111111").Bytes()),
+		gethcommon.BigToHash(big.NewInt(cumulativeClaimedSlot)),
+	)
+	assert.Equal(t, "0x78f047547b4b8270f7685131cee6d8582fb17daeff44af2df818d8f449dec5ef", slot.Hex())
+}
+
+func TestArrayStorageSlot(t *testing.T) {
+	// The elements of a dynamic array at slot 0 start at keccak256(abi.encode(0))
+	slot := arrayStorageSlot(gethcommon.Hash{}, 0, 1)
+	assert.Equal(t, "0x290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e563", slot.Hex())
+
+	slot = arrayStorageSlot(gethcommon.Hash{}, 3, 2)
+	assert.Equal(t, "0x290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e569", slot.Hex())
+}
+
+func TestCumulativeClaimedStorageSlot(t *testing.T) {
+	slot := cumulativeClaimedStorageSlot(
+		gethcommon.HexToAddress("0x1111111111111111111111111111111111111111"),
+		gethcommon.HexToAddress("0x2222222222222222222222222222222222222222"),
+	)
+	assert.Equal(t, "0xb4bcee8d35b0fbe837bb8ad3a0f790e18408f0de6f799eecebde6d0e3ab17cb4", slot.Hex())
+}
+
+func TestDistributionRootStorageSlots(t *testing.T) {
+	// keccak256(abi.encode(202)) is 0x42d7...0ee1 and every DistributionRoot takes two slots
+	rootSlot, packedSlot := distributionRootStorageSlots(0)
+	assert.Equal(t, "0x42d72674974f694b5f5159593243114d38a5c39c89d6b62fee061ff523240ee1", rootSlot.Hex())
+	assert.Equal(t, "0x42d72674974f694b5f5159593243114d38a5c39c89d6b62fee061ff523240ee2", packedSlot.Hex())
+
+	rootSlot, packedSlot = distributionRootStorageSlots(3)
+	assert.Equal(t, "0x42d72674974f694b5f5159593243114d38a5c39c89d6b62fee061ff523240ee7", rootSlot.Hex())
+	assert.Equal(t, "0x42d72674974f694b5f5159593243114d38a5c39c89d6b62fee061ff523240ee8", packedSlot.Hex())
+}
@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+// ClaimJobConfig configures a job claiming rewards with 'rewards claim'
+type ClaimJobConfig struct {
+	// Name of the job in logs and metrics, e.g. the earner claimed for
+	Name string
+	// GlobalArgs are the global flags of the CLI, e.g. --profile or --read-only
+	GlobalArgs []string
+	// Args are the flags of 'rewards claim', e.g. --network, --earner-address, --eth-rpc-url and
+	// --broadcast with the flags of the signer
+	Args []string
+	// Prompter is used by the signer, e.g. to read the password of a keystore
+	Prompter utils.Prompter
+}
+
+// claimJob runs 'rewards claim' in-process, so the claims of a schedule are the same as the ones
+// of the CLI, including the global flags and the checks of the command
+type claimJob struct {
+	config ClaimJobConfig
+}
+
+// runMu serializes the runs of all claim jobs. The flags of the CLI are package level variables
+// which are changed while the args of a run are parsed, and the global flags set process wide
+// state, so two runs at the same time would race on both.
+var runMu sync.Mutex
+
+func NewClaimJob(config ClaimJobConfig) (Job, error) {
+	if config.Name == "" {
+		return nil, errors.New("name of the claim job is required")
+	}
+	if config.Prompter == nil {
+		return nil, errors.New("prompter of the claim job is required")
+	}
+	return &claimJob{config: config}, nil
+}
+
+func (j *claimJob) Name() string {
+	return j.config.Name
+}
+
+func (j *claimJob) Run(ctx context.Context) error {
+	runMu.Lock()
+	defer runMu.Unlock()
+
+	// The app is created for every run like the CLI creates it, so no flag values are left over
+	// from a previous run. Its Before resets the process wide state the global flags and the
+	// profile of the previous run set.
+	app := &cli.App{
+		Name:     "eigenlayer",
+		Flags:    pkg.GlobalFlags(),
+		Before:   pkg.BeforeRunAction(),
+		After:    pkg.AfterRunAction(),
+		Commands: []*cli.Command{pkg.RewardsCmd(j.config.Prompter)},
+		// Errors are returned to the scheduler instead of exiting the embedding process
+		ExitErrHandler: func(*cli.Context, error) {},
+	}
+	args := append([]string{"eigenlayer"}, j.config.GlobalArgs...)
+	args = append(args, "rewards", "claim")
+	args = append(args, j.config.Args...)
+	return app.RunContext(ctx, args)
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type chainIDClient struct {
+	chainID *big.Int
+}
+
+func (c *chainIDClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.chainID, nil
+}
+
+func TestClaimJobResetsGlobalFlags(t *testing.T) {
+	defer common.ResetRunState()
+	profilesFile := filepath.Join(t.TempDir(), "profiles.yaml")
+	content := `profiles:
+  mainnet-operator:
+    read-only: true
+    allowed-networks: [mainnet]
+    hooks:
+      pre-broadcast: /bin/true
+`
+	assert.NoError(t, os.WriteFile(profilesFile, []byte(content), 0o600))
+	holesky := &chainIDClient{chainID: big.NewInt(17000)}
+
+	// The claims fail for the missing flags of 'rewards claim', after the global flags are set
+	restricted, err := NewClaimJob(ClaimJobConfig{
+		Name:       "restricted",
+		GlobalArgs: []string{"--profiles-file", profilesFile, "--profile", "mainnet-operator", "--redact"},
+		Prompter:   utils.NewPrompter(),
+	})
+	assert.NoError(t, err)
+	assert.Error(t, restricted.Run(context.Background()))
+	assert.True(t, common.IsReadOnly())
+	assert.True(t, common.IsRedacted())
+	assert.Equal(t, "/bin/true", common.GetHooks().PreBroadcast)
+	assert.ErrorIs(t, common.CheckAllowedNetwork(context.Background(), holesky), common.ErrNetworkDenied)
+
+	unrestricted, err := NewClaimJob(ClaimJobConfig{Name: "unrestricted", Prompter: utils.NewPrompter()})
+	assert.NoError(t, err)
+	assert.Error(t, unrestricted.Run(context.Background()))
+	assert.False(t, common.IsReadOnly())
+	assert.False(t, common.IsRedacted())
+	assert.Equal(t, common.Hooks{}, common.GetHooks())
+	assert.NoError(t, common.CheckAllowedNetwork(context.Background(), holesky))
+}
@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "eigenlayer_scheduler"
+
+const (
+	statusSucceeded = "succeeded"
+	statusFailed    = "failed"
+	statusSkipped   = "skipped"
+)
+
+// Metrics are the Prometheus metrics of the runs of the scheduled jobs
+type Metrics struct {
+	runs           *prometheus.CounterVec
+	runDuration    *prometheus.HistogramVec
+	lastRun        *prometheus.GaugeVec
+	lastSuccessRun *prometheus.GaugeVec
+}
+
+// NewMetrics registers the metrics with the registerer, so embedders can publish them with the
+// metrics of their service
+func NewMetrics(registerer prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "runs_total",
+			Help:      "Number of runs of the job by status",
+		}, []string{"job", "status"}),
+		runDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "run_duration_seconds",
+			Help:      "Duration of the runs of the job",
+			Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600},
+		}, []string{"job"}),
+		lastRun: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "last_run_timestamp_seconds",
+			Help:      "Unix timestamp of the last run of the job",
+		}, []string{"job"}),
+		lastSuccessRun: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "last_success_timestamp_seconds",
+			Help:      "Unix timestamp of the last successful run of the job",
+		}, []string{"job"}),
+	}
+	for _, collector := range []prometheus.Collector{m.runs, m.runDuration, m.lastRun, m.lastSuccessRun} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m *Metrics) observe(job string, status string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.runs.WithLabelValues(job, status).Inc()
+	if status == statusSkipped {
+		return
+	}
+	now := float64(time.Now().Unix())
+	m.runDuration.WithLabelValues(job).Observe(duration.Seconds())
+	m.lastRun.WithLabelValues(job).Set(now)
+	if status == statusSucceeded {
+		m.lastSuccessRun.WithLabelValues(job).Set(now)
+	}
+}
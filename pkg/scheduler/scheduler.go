@@ -0,0 +1,278 @@
+// Package scheduler runs jobs, e.g. automated rewards claims, periodically inside the process
+// embedding it. Operator stacks use it to claim from their existing services instead of running
+// the CLI as a separate process.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+const (
+	// heartbeatCommand is the command the heartbeats of the scheduler are written for
+	heartbeatCommand = "scheduler"
+	// heartbeatInterval is how often the heartbeat is written, independent of the job intervals
+	heartbeatInterval = time.Minute
+)
+
+var (
+	ErrAlreadyRunning = errors.New("scheduler is already running")
+	ErrNotRunning     = errors.New("scheduler is not running")
+)
+
+// Job is a unit of work run by the scheduler
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+type jobFunc struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// NewJob returns a job running the given function
+func NewJob(name string, run func(ctx context.Context) error) Job {
+	return &jobFunc{name: name, run: run}
+}
+
+func (j *jobFunc) Name() string {
+	return j.name
+}
+
+func (j *jobFunc) Run(ctx context.Context) error {
+	return j.run(ctx)
+}
+
+// Hooks are called around every run of a job. A BeforeRun hook returning an error skips the run,
+// which is how embedders can pause claiming, e.g. while the gas price is high.
+type Hooks struct {
+	BeforeRun func(ctx context.Context, job string) error
+	AfterRun  func(ctx context.Context, job string, err error, duration time.Duration)
+}
+
+type entry struct {
+	index    int
+	job      Job
+	interval time.Duration
+	timeout  time.Duration
+}
+
+// stuckAfter is how long a run can take before the job counts as stuck: its timeout, which the
+// run ignored if it's still running, or else its interval
+func (e entry) stuckAfter() time.Duration {
+	if e.timeout > 0 {
+		return e.timeout
+	}
+	return e.interval
+}
+
+// Scheduler runs every job in its own goroutine at its interval. Runs of the same job never
+// overlap, a run taking longer than the interval delays the next one.
+type Scheduler struct {
+	logger        logging.Logger
+	hooks         Hooks
+	metrics       *Metrics
+	heartbeatPath string
+
+	mu      sync.Mutex
+	entries []entry
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	// started has the start of the current run of every job, zero while it isn't running
+	runMu   sync.Mutex
+	started []time.Time
+}
+
+func New(logger logging.Logger) *Scheduler {
+	return &Scheduler{logger: logger}
+}
+
+// WithHooks sets the hooks called around every run
+func (s *Scheduler) WithHooks(hooks Hooks) *Scheduler {
+	s.hooks = hooks
+	return s
+}
+
+// WithMetrics publishes the runs of the jobs as metrics
+func (s *Scheduler) WithMetrics(metrics *Metrics) *Scheduler {
+	s.metrics = metrics
+	return s
+}
+
+// HeartbeatPath returns the heartbeat file of the scheduler with the name, e.g. the name of the
+// service embedding it. 'eigenlayer healthcheck' checks the heartbeats in the same directory.
+func HeartbeatPath(name string) string {
+	return common.GetHeartbeatPath(heartbeatCommand, name)
+}
+
+// WithHeartbeat makes the scheduler write a heartbeat to path every minute while no job is stuck,
+// so a healthcheck can detect a stuck scheduler. The heartbeat is removed when the scheduler stops.
+func (s *Scheduler) WithHeartbeat(path string) *Scheduler {
+	s.heartbeatPath = path
+	return s
+}
+
+// Add schedules the job every interval, the first run being right after Start. A run is cancelled
+// after timeout, if it is set. Jobs can only be added while the scheduler is not running.
+func (s *Scheduler) Add(job Job, interval time.Duration, timeout time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval of job %s must be positive", job.Name())
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return ErrAlreadyRunning
+	}
+	s.entries = append(s.entries, entry{index: len(s.entries), job: job, interval: interval, timeout: timeout})
+	return nil
+}
+
+// Start runs the jobs in the background until Stop is called or the context is cancelled
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return ErrAlreadyRunning
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.runMu.Lock()
+	s.started = make([]time.Time, len(s.entries))
+	s.runMu.Unlock()
+	if s.heartbeatPath != "" {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.heartbeat(ctx)
+		}()
+	}
+	for _, e := range s.entries {
+		s.wg.Add(1)
+		go func(e entry) {
+			defer s.wg.Done()
+			s.loop(ctx, e)
+		}(e)
+	}
+	s.logger.Infof("Scheduler started with %d jobs", len(s.entries))
+	return nil
+}
+
+// Stop cancels the running jobs and waits for them to return
+func (s *Scheduler) Stop() error {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+	if cancel == nil {
+		return ErrNotRunning
+	}
+	cancel()
+	s.wg.Wait()
+	if s.heartbeatPath != "" {
+		if err := common.RemoveHeartbeat(s.heartbeatPath); err != nil {
+			s.logger.Warnf("Failed to remove heartbeat: %s", err)
+		}
+	}
+	s.logger.Info("Scheduler stopped")
+	return nil
+}
+
+func (s *Scheduler) loop(ctx context.Context, e entry) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		s.run(ctx, e)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run runs the job once. Failures are logged and published, they don't stop the schedule.
+func (s *Scheduler) run(ctx context.Context, e entry) {
+	name := e.job.Name()
+	if s.hooks.BeforeRun != nil {
+		if err := s.hooks.BeforeRun(ctx, name); err != nil {
+			s.logger.Infof("Skipping run of job %s: %s", name, err)
+			s.metrics.observe(name, statusSkipped, 0)
+			return
+		}
+	}
+
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+	s.logger.Debugf("Running job %s", name)
+	start := time.Now()
+	s.setStarted(e, start)
+	err := e.job.Run(ctx)
+	s.setStarted(e, time.Time{})
+	duration := time.Since(start)
+
+	if err != nil {
+		s.logger.Errorf("Job %s failed after %s: %s", name, duration, err)
+		s.metrics.observe(name, statusFailed, duration)
+	} else {
+		s.logger.Infof("Job %s completed in %s", name, duration)
+		s.metrics.observe(name, statusSucceeded, duration)
+	}
+	if s.hooks.AfterRun != nil {
+		s.hooks.AfterRun(ctx, name, err, duration)
+	}
+}
+
+func (s *Scheduler) setStarted(e entry, start time.Time) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	s.started[e.index] = start
+}
+
+// stuckJob returns the name of a job whose run takes longer than it should, or "" if none is stuck
+func (s *Scheduler) stuckJob(now time.Time) string {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	for _, e := range s.entries {
+		start := s.started[e.index]
+		if !start.IsZero() && now.Sub(start) > e.stuckAfter() {
+			return e.job.Name()
+		}
+	}
+	return ""
+}
+
+func (s *Scheduler) heartbeat(ctx context.Context) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		s.writeHeartbeat(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeHeartbeat records that the scheduler is alive, unless a job is stuck. Failed and skipped
+// runs don't stop the heartbeat, only a run which doesn't return in time does.
+func (s *Scheduler) writeHeartbeat(now time.Time) {
+	if job := s.stuckJob(now); job != "" {
+		s.logger.Warnf("Not writing heartbeat, job %s is stuck", job)
+		return
+	}
+	if err := common.WriteHeartbeat(s.heartbeatPath, heartbeatCommand, now); err != nil {
+		s.logger.Warnf("Failed to write heartbeat: %s", err)
+	}
+}
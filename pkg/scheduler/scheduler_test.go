@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingJob fails every other run and signals every run on a channel
+type countingJob struct {
+	mu   sync.Mutex
+	runs int
+	done chan struct{}
+}
+
+func (j *countingJob) Name() string {
+	return "counting"
+}
+
+func (j *countingJob) Run(ctx context.Context) error {
+	j.mu.Lock()
+	j.runs++
+	runs := j.runs
+	j.mu.Unlock()
+	defer func() { j.done <- struct{}{} }()
+	if runs%2 == 0 {
+		return errors.New("failed")
+	}
+	return nil
+}
+
+func TestScheduler(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	metrics, err := NewMetrics(prometheus.NewRegistry())
+	assert.NoError(t, err)
+
+	var afterRuns []error
+	var mu sync.Mutex
+	job := &countingJob{done: make(chan struct{}, 10)}
+	s := New(logger).WithMetrics(metrics).WithHooks(Hooks{
+		AfterRun: func(ctx context.Context, job string, err error, duration time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			afterRuns = append(afterRuns, err)
+		},
+	})
+	assert.NoError(t, s.Add(job, 10*time.Millisecond, time.Second))
+	assert.Error(t, s.Add(job, 0, 0))
+
+	assert.NoError(t, s.Start(context.Background()))
+	assert.ErrorIs(t, s.Start(context.Background()), ErrAlreadyRunning)
+	assert.ErrorIs(t, s.Add(job, time.Second, 0), ErrAlreadyRunning)
+	<-job.done
+	<-job.done
+	assert.NoError(t, s.Stop())
+	assert.ErrorIs(t, s.Stop(), ErrNotRunning)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.GreaterOrEqual(t, len(afterRuns), 2)
+	assert.NoError(t, afterRuns[0])
+	assert.Error(t, afterRuns[1])
+	assert.GreaterOrEqual(t, testutil.ToFloat64(metrics.runs.WithLabelValues("counting", statusSucceeded)), 1.0)
+	assert.GreaterOrEqual(t, testutil.ToFloat64(metrics.runs.WithLabelValues("counting", statusFailed)), 1.0)
+}
+
+func TestSchedulerBeforeRunSkips(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	metrics, err := NewMetrics(prometheus.NewRegistry())
+	assert.NoError(t, err)
+
+	skipped := make(chan struct{}, 10)
+	ran := false
+	s := New(logger).WithMetrics(metrics).WithHooks(Hooks{
+		BeforeRun: func(ctx context.Context, job string) error {
+			skipped <- struct{}{}
+			return errors.New("gas price too high")
+		},
+	})
+	assert.NoError(t, s.Add(NewJob("claim", func(ctx context.Context) error {
+		ran = true
+		return nil
+	}), time.Hour, 0))
+
+	assert.NoError(t, s.Start(context.Background()))
+	<-skipped
+	assert.NoError(t, s.Stop())
+	assert.False(t, ran)
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.runs.WithLabelValues("claim", statusSkipped)))
+}
+
+func TestNewClaimJob(t *testing.T) {
+	_, err := NewClaimJob(ClaimJobConfig{})
+	assert.Error(t, err)
+}
+
+// blockingJob runs until it is released, ignoring the context like a stuck run
+type blockingJob struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (j *blockingJob) Name() string {
+	return "blocking"
+}
+
+func (j *blockingJob) Run(ctx context.Context) error {
+	j.started <- struct{}{}
+	<-j.release
+	return nil
+}
+
+func TestSchedulerHeartbeat(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	path := filepath.Join(t.TempDir(), "scheduler.json")
+	job := &blockingJob{started: make(chan struct{}, 1), release: make(chan struct{})}
+	s := New(logger).WithHeartbeat(path)
+	assert.NoError(t, s.Add(job, time.Hour, time.Second))
+	assert.NoError(t, s.Start(context.Background()))
+	<-job.started
+
+	// The heartbeat is written while the run is within its timeout
+	now := time.Now()
+	s.writeHeartbeat(now)
+	heartbeat, err := common.ReadHeartbeat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "scheduler", heartbeat.Command)
+	assert.Equal(t, now.UTC().Unix(), heartbeat.Timestamp.Unix())
+
+	// A run past its timeout is stuck and stops the heartbeat
+	s.writeHeartbeat(now.Add(time.Minute))
+	heartbeat, err = common.ReadHeartbeat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, now.UTC().Unix(), heartbeat.Timestamp.Unix())
+
+	close(job.release)
+	assert.NoError(t, s.Stop())
+	assert.NoFileExists(t, path)
+}
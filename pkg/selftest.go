@@ -0,0 +1,211 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/selftest"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// anvilFirstAccountKey is the key of the first prefunded account of anvil, which deploys the
+	// EigenLayer contracts of the devnet
+	anvilFirstAccountKey = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcc5f7c0a9f6d4ff80"
+	selftestMetadataURL  = "https://madhur-test-public.s3.us-east-2.amazonaws.com/metadata.json"
+)
+
+var (
+	SelftestRPCUrlFlag = cli.StringFlag{
+		Name:    "eth-rpc-url",
+		Aliases: []string{"r"},
+		Usage:   "URL of the anvil devnet with the EigenLayer contracts deployed",
+		Value:   "http://localhost:8545",
+		EnvVars: []string{"EIGENLAYER_SELFTEST_RPC_URL"},
+	}
+
+	SelftestRewardsUpdaterKeyFlag = cli.StringFlag{
+		Name:    "rewards-updater-key",
+		Usage:   "Hex private key of the rewards updater of the devnet. Defaults to the first account of anvil",
+		Value:   anvilFirstAccountKey,
+		EnvVars: []string{"EIGENLAYER_SELFTEST_REWARDS_UPDATER_KEY"},
+	}
+
+	SelftestRewardsCoordinatorFlag = cli.StringFlag{
+		Name:    "rewards-coordinator-address",
+		Usage:   "Address of the rewards coordinator of the devnet. Defaults to the one of the anvil devnet",
+		EnvVars: []string{"EIGENLAYER_SELFTEST_REWARDS_COORDINATOR_ADDRESS"},
+	}
+
+	SelftestTokenFlag = cli.StringFlag{
+		Name:    "token-address",
+		Usage:   "ERC20 token of the devnet the test root pays out. The claim step is skipped without it",
+		EnvVars: []string{"EIGENLAYER_SELFTEST_TOKEN_ADDRESS"},
+	}
+
+	SelftestTokenBalanceSlotFlag = cli.Int64Flag{
+		Name:    "token-balance-slot",
+		Usage:   "Storage slot of the balances of the token, which are set to fund the rewards coordinator",
+		EnvVars: []string{"EIGENLAYER_SELFTEST_TOKEN_BALANCE_SLOT"},
+	}
+
+	SelftestMetadataURLFlag = cli.StringFlag{
+		Name:    "metadata-url",
+		Usage:   "Metadata URL of the registered operator",
+		Value:   selftestMetadataURL,
+		EnvVars: []string{"EIGENLAYER_SELFTEST_METADATA_URL"},
+	}
+
+	SelftestKeepFlag = cli.BoolFlag{
+		Name:    "keep",
+		Usage:   "Keep the work directory with the keys and the log of the CLI runs. It is always kept if a step fails",
+		EnvVars: []string{"EIGENLAYER_SELFTEST_KEEP"},
+	}
+)
+
+func SelftestCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "selftest",
+		Usage:     "Run an end-to-end scenario against a local anvil devnet and report pass/fail per step",
+		UsageText: "selftest",
+		Description: `
+Runs the CLI itself through a scripted scenario against a local anvil devnet with the EigenLayer
+contracts deployed, e.g. started with 'make start-anvil-with-contracts-deployed' of eigensdk-go:
+- create keys: a new operator key is imported into the keystore
+- fund accounts: the operator and the rewards updater are funded with anvil_setBalance
+- register operator: the operator is registered and its status checked
+- submit test root: a root with rewards for the operator is submitted, and the devnet time is
+  advanced until it is activated. Its claim amounts are served by a local proof store.
+- show rewards: the rewards of the test root are shown
+- claim rewards: the rewards are claimed and the token balance of the operator checked
+
+Every step runs the CLI with its home directory in a temporary work directory, so the keys and
+the state of the user are not touched. The claim needs an ERC20 token of the devnet, set with
+--token-address, whose balance for the rewards coordinator is set with anvil_setStorageAt.
+
+Exits with 0 if no step fails and with 1 otherwise.
+		`,
+		Flags: []cli.Flag{
+			&SelftestRPCUrlFlag,
+			&SelftestRewardsUpdaterKeyFlag,
+			&SelftestRewardsCoordinatorFlag,
+			&SelftestTokenFlag,
+			&SelftestTokenBalanceSlotFlag,
+			&SelftestMetadataURLFlag,
+			&SelftestKeepFlag,
+			&flags.OutputTypeFlag,
+		},
+		Action: func(cCtx *cli.Context) error {
+			outputType := cCtx.String(flags.OutputTypeFlag.Name)
+			if outputType != string(common.OutputType_Pretty) && outputType != string(common.OutputType_Json) {
+				return fmt.Errorf("unsupported output type %s, use 'pretty' or 'json'", outputType)
+			}
+
+			cfg, err := readSelftestConfig(cCtx)
+			if err != nil {
+				return err
+			}
+			workDir, err := os.MkdirTemp("", "eigenlayer-selftest-")
+			if err != nil {
+				return err
+			}
+			cfg.WorkDir = workDir
+			logPath := filepath.Join(workDir, "selftest.log")
+			log, err := os.Create(filepath.Clean(logPath))
+			if err != nil {
+				return err
+			}
+			defer log.Close()
+
+			scenario, err := selftest.NewScenario(cfg, log)
+			if err != nil {
+				return err
+			}
+			defer scenario.Close()
+
+			results := selftest.Run(cCtx.Context, scenario.Steps(), func(result selftest.Result) {
+				if outputType == string(common.OutputType_Pretty) {
+					printSelftestResult(result)
+				}
+			})
+			passed := selftest.Passed(results)
+
+			if outputType == string(common.OutputType_Json) {
+				out, err := json.MarshalIndent(map[string]interface{}{
+					"passed":  passed,
+					"steps":   results,
+					"workDir": workDir,
+				}, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(out))
+			}
+
+			if passed && !cCtx.Bool(SelftestKeepFlag.Name) {
+				_ = log.Close()
+				_ = os.RemoveAll(workDir)
+			} else if outputType == string(common.OutputType_Pretty) {
+				fmt.Printf("\nThe keys and the log of the CLI runs are in %s\n", workDir)
+			}
+			if !passed {
+				return cli.Exit("selftest failed", 1)
+			}
+			return nil
+		},
+	}
+}
+
+func readSelftestConfig(cCtx *cli.Context) (selftest.Config, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return selftest.Config{}, fmt.Errorf("failed to find the eigenlayer executable: %w", err)
+	}
+
+	coordinator := cCtx.String(SelftestRewardsCoordinatorFlag.Name)
+	if common.IsEmptyString(coordinator) {
+		coordinator = common.ChainMetadataMap[common.AnvilChainId].ELRewardsCoordinatorAddress
+	}
+	if !gethcommon.IsHexAddress(coordinator) {
+		return selftest.Config{}, fmt.Errorf("invalid rewards coordinator address %s", coordinator)
+	}
+
+	var token gethcommon.Address
+	if tokenAddress := cCtx.String(SelftestTokenFlag.Name); !common.IsEmptyString(tokenAddress) {
+		if !gethcommon.IsHexAddress(tokenAddress) {
+			return selftest.Config{}, fmt.Errorf("invalid token address %s", tokenAddress)
+		}
+		token = gethcommon.HexToAddress(tokenAddress)
+	}
+
+	return selftest.Config{
+		RPCURL:                    cCtx.String(SelftestRPCUrlFlag.Name),
+		Executable:                executable,
+		RewardsUpdaterKey:         cCtx.String(SelftestRewardsUpdaterKeyFlag.Name),
+		RewardsCoordinatorAddress: gethcommon.HexToAddress(coordinator),
+		Token:                     token,
+		TokenBalanceSlot:          cCtx.Int64(SelftestTokenBalanceSlotFlag.Name),
+		MetadataURL:               cCtx.String(SelftestMetadataURLFlag.Name),
+	}, nil
+}
+
+func printSelftestResult(result selftest.Result) {
+	duration := (time.Duration(result.DurationMs) * time.Millisecond).String()
+	switch result.Status {
+	case selftest.StatusPassed:
+		fmt.Printf("%s %s (%s)\n", utils.EmojiCheckMark, result.Step, duration)
+	case selftest.StatusFailed:
+		fmt.Printf("%s %s (%s): %s\n", utils.EmojiCrossMark, result.Step, duration, result.Message)
+	default:
+		fmt.Printf("%s %s skipped: %s\n", utils.EmojiInfo, result.Step, result.Message)
+	}
+}
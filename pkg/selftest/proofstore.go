@@ -0,0 +1,98 @@
+package selftest
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/claimgen"
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/distribution"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// claimAmountsFile is the file of the proof store with the claim amounts of a snapshot
+const claimAmountsFile = "claim-amounts.json"
+
+// proofStore serves the claim amounts of the test root like the proof store of a network, so
+// show and claim read them with --proof-store-base-url. The same amounts are served for every
+// environment, network and snapshot date.
+type proofStore struct {
+	claimAmounts []byte
+	server       *http.Server
+}
+
+// newProofStore returns the proof store of an earner with a cumulative amount of every token
+func newProofStore(earner gethcommon.Address, amounts map[gethcommon.Address]*big.Int) (*proofStore, error) {
+	var claimAmounts bytes.Buffer
+	for token, amount := range amounts {
+		line, err := json.Marshal(map[string]string{
+			"earner":            strings.ToLower(earner.Hex()),
+			"token":             strings.ToLower(token.Hex()),
+			"cumulative_amount": amount.String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		claimAmounts.Write(line)
+		claimAmounts.WriteByte('\n')
+	}
+	return &proofStore{claimAmounts: claimAmounts.Bytes()}, nil
+}
+
+// Root returns the distribution root of the claim amounts, which is submitted for them
+func (s *proofStore) Root(earner gethcommon.Address, tokens []gethcommon.Address) (gethcommon.Hash, error) {
+	lines := make([]*distribution.EarnerLine, 0)
+	for _, line := range bytes.Split(bytes.TrimSpace(s.claimAmounts), []byte("\n")) {
+		earnerLine := &distribution.EarnerLine{}
+		if err := json.Unmarshal(line, earnerLine); err != nil {
+			return gethcommon.Hash{}, err
+		}
+		lines = append(lines, earnerLine)
+	}
+	snapshot := distribution.NewDistribution()
+	if err := snapshot.LoadLines(lines); err != nil {
+		return gethcommon.Hash{}, err
+	}
+	accounts, _, err := claimgen.NewClaimgen(snapshot).GenerateClaimProofForEarner(earner, tokens, 0)
+	if err != nil {
+		return gethcommon.Hash{}, err
+	}
+	return gethcommon.BytesToHash(accounts.Root()), nil
+}
+
+// Start serves the proof store on a free local port and returns its base URL
+func (s *proofStore) Start() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	s.server = &http.Server{
+		Handler:           http.HandlerFunc(s.serveHTTP),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = s.server.Serve(listener)
+	}()
+	return "http://" + listener.Addr().String(), nil
+}
+
+func (s *proofStore) Close() error {
+	if s.server == nil {
+		return nil
+	}
+	return s.server.Close()
+}
+
+func (s *proofStore) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasSuffix(r.URL.Path, "/"+claimAmountsFile) {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(s.claimAmounts)
+}
@@ -0,0 +1,45 @@
+package selftest
+
+import (
+	"io"
+	"math/big"
+	"net/http"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProofStore(t *testing.T) {
+	earner := gethcommon.HexToAddress("0xcaB1b44dd1f1C265405878Ac1179cd94D0dBA634")
+	token := gethcommon.HexToAddress("0x3B78576F7D6837500bA3De27A60c7f594934027E")
+	store, err := newProofStore(earner, map[gethcommon.Address]*big.Int{token: big.NewInt(1000)})
+	assert.NoError(t, err)
+
+	root, err := store.Root(earner, []gethcommon.Address{token})
+	assert.NoError(t, err)
+	assert.NotEqual(t, gethcommon.Hash{}, root)
+	other, err := newProofStore(earner, map[gethcommon.Address]*big.Int{token: big.NewInt(2000)})
+	assert.NoError(t, err)
+	otherRoot, err := other.Root(earner, []gethcommon.Address{token})
+	assert.NoError(t, err)
+	assert.NotEqual(t, root, otherRoot)
+
+	baseURL, err := store.Start()
+	assert.NoError(t, err)
+	defer store.Close()
+
+	resp, err := http.Get(baseURL + "/local/anvil/2024-05-01/claim-amounts.json")
+	assert.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `"cumulative_amount":"1000"`)
+	assert.Contains(t, string(body), `"earner":"0xcab1b44dd1f1c265405878ac1179cd94d0dba634"`)
+
+	resp, err = http.Get(baseURL + "/local/anvil/recent-snapshots.json")
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,445 @@
+package selftest
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IRewardsCoordinator"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	keyName     = "selftest"
+	keyPassword = "selftest"
+)
+
+var (
+	// fundingBalance is set as balance of the accounts sending transactions
+	fundingBalance = new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))
+	// claimAmount is the cumulative amount of the token of the test root
+	claimAmount = big.NewInt(1e18)
+	// placeholderToken is the token of the test root if no token is set, which can be shown but
+	// not claimed
+	placeholderToken = gethcommon.HexToAddress("0x000000000000000000000000000000000000dEaD")
+)
+
+// Config of the scenario
+type Config struct {
+	RPCURL string
+	// Executable is the eigenlayer CLI run for every step, so the steps test the commands as users run them
+	Executable string
+	// WorkDir is the home directory of the CLI runs, which keeps the keys and the state of the selftest
+	WorkDir string
+	// RewardsUpdaterKey is the hex private key of the rewards updater of the devnet
+	RewardsUpdaterKey         string
+	RewardsCoordinatorAddress gethcommon.Address
+	// Token is an ERC20 token of the devnet the test root pays out. The claim is skipped without it.
+	Token gethcommon.Address
+	// TokenBalanceSlot is the storage slot of the balances mapping of the token
+	TokenBalanceSlot int64
+	MetadataURL      string
+}
+
+// Scenario creates keys, registers an operator, submits a test root with rewards for the operator,
+// and shows and claims them on a local anvil devnet
+type Scenario struct {
+	cfg Config
+	log io.Writer
+
+	ethClient   *ethclient.Client
+	rpcClient   *rpc.Client
+	coordinator *rewardscoordinator.ContractIRewardsCoordinator
+
+	operatorKey     *ecdsa.PrivateKey
+	operatorAddress gethcommon.Address
+	operatorFile    string
+	updaterKey      *ecdsa.PrivateKey
+	proofStore      *proofStore
+	proofStoreURL   string
+}
+
+// NewScenario returns the scenario of the config. The output of every CLI run is written to log.
+func NewScenario(cfg Config, log io.Writer) (*Scenario, error) {
+	updaterKey, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.RewardsUpdaterKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid rewards updater key: %w", err)
+	}
+	return &Scenario{cfg: cfg, log: log, updaterKey: updaterKey}, nil
+}
+
+// Steps returns the steps of the scenario
+func (s *Scenario) Steps() []Step {
+	return []Step{
+		{Name: "connect to devnet", Run: s.connect},
+		{Name: "create keys", Run: s.createKeys},
+		{Name: "fund accounts", Run: s.fundAccounts},
+		{Name: "register operator", Run: s.registerOperator},
+		{Name: "submit test root", Run: s.submitRoot},
+		{Name: "show rewards", Run: s.showRewards},
+		{Name: "claim rewards", Run: s.claimRewards},
+	}
+}
+
+// Close stops the local proof store and closes the connection to the devnet
+func (s *Scenario) Close() {
+	if s.proofStore != nil {
+		_ = s.proofStore.Close()
+	}
+	if s.ethClient != nil {
+		s.ethClient.Close()
+	}
+}
+
+func (s *Scenario) connect(ctx context.Context) error {
+	rpcClient, err := rpc.DialContext(ctx, s.cfg.RPCURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", s.cfg.RPCURL, err)
+	}
+	s.rpcClient = rpcClient
+	s.ethClient = ethclient.NewClient(rpcClient)
+
+	chainID, err := s.ethClient.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the chain ID of %s: %w", s.cfg.RPCURL, err)
+	}
+	if chainID.Int64() != common.AnvilChainId {
+		return fmt.Errorf("chain ID of %s is %s, not the one of anvil (%d)", s.cfg.RPCURL, chainID, common.AnvilChainId)
+	}
+	code, err := s.ethClient.CodeAt(ctx, s.cfg.RewardsCoordinatorAddress, nil)
+	if err != nil {
+		return err
+	}
+	if len(code) == 0 {
+		return fmt.Errorf(
+			"no rewards coordinator at %s, start anvil with the EigenLayer contracts deployed",
+			s.cfg.RewardsCoordinatorAddress.Hex(),
+		)
+	}
+	s.coordinator, err = rewardscoordinator.NewContractIRewardsCoordinator(s.cfg.RewardsCoordinatorAddress, s.ethClient)
+	return err
+}
+
+func (s *Scenario) createKeys(ctx context.Context) error {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return err
+	}
+	s.operatorKey = key
+	s.operatorAddress = crypto.PubkeyToAddress(key.PublicKey)
+	_, err = s.runCLI(ctx, keyPassword,
+		"keys", "import", "--key-type", "ecdsa", "--insecure", keyName, hexutil.Encode(crypto.FromECDSA(key))[2:],
+	)
+	if err != nil {
+		return err
+	}
+	keyFile := filepath.Join(s.cfg.WorkDir, ".eigenlayer", "operator_keys", keyName+".ecdsa.key.json")
+	if _, err := os.Stat(keyFile); err != nil {
+		return fmt.Errorf("key file was not created: %w", err)
+	}
+	return nil
+}
+
+func (s *Scenario) fundAccounts(ctx context.Context) error {
+	for _, address := range []gethcommon.Address{s.operatorAddress, crypto.PubkeyToAddress(s.updaterKey.PublicKey)} {
+		err := s.rpcClient.CallContext(ctx, nil, "anvil_setBalance", address, hexutil.EncodeBig(fundingBalance))
+		if err != nil {
+			return fmt.Errorf("failed to fund %s: %w", address.Hex(), err)
+		}
+	}
+	return nil
+}
+
+func (s *Scenario) registerOperator(ctx context.Context) error {
+	config := map[string]interface{}{
+		"operator": map[string]interface{}{
+			"address":                      s.operatorAddress.Hex(),
+			"delegation_approver_address":  gethcommon.Address{}.Hex(),
+			"staker_opt_out_window_blocks": 0,
+			"metadata_url":                 s.cfg.MetadataURL,
+		},
+		"el_delegation_manager_address": common.ChainMetadataMap[common.AnvilChainId].ELDelegationManagerAddress,
+		"eth_rpc_url":                   s.cfg.RPCURL,
+		"private_key_store_path": filepath.Join(
+			s.cfg.WorkDir, ".eigenlayer", "operator_keys", keyName+".ecdsa.key.json",
+		),
+		"signer_type": "local_keystore",
+		"chain_id":    common.AnvilChainId,
+	}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	s.operatorFile = filepath.Join(s.cfg.WorkDir, "operator.yaml")
+	if err := os.WriteFile(s.operatorFile, data, 0o600); err != nil {
+		return err
+	}
+
+	if _, err := s.runCLI(ctx, keyPassword, "operator", "register", s.operatorFile); err != nil {
+		return err
+	}
+	output, err := s.runCLI(ctx, "", "operator", "status", s.operatorFile)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(output, "Operator is registered") {
+		return errors.New("operator status doesn't show the operator as registered")
+	}
+	return nil
+}
+
+func (s *Scenario) submitRoot(ctx context.Context) error {
+	token := s.getToken()
+	store, err := newProofStore(s.operatorAddress, map[gethcommon.Address]*big.Int{token: claimAmount})
+	if err != nil {
+		return err
+	}
+	root, err := store.Root(s.operatorAddress, []gethcommon.Address{token})
+	if err != nil {
+		return fmt.Errorf("failed to compute the test root: %w", err)
+	}
+	s.proofStoreURL, err = store.Start()
+	if err != nil {
+		return fmt.Errorf("failed to start the local proof store: %w", err)
+	}
+	s.proofStore = store
+
+	opts := &bind.CallOpts{Context: ctx}
+	updater, err := s.coordinator.RewardsUpdater(opts)
+	if err != nil {
+		return err
+	}
+	if updater != crypto.PubkeyToAddress(s.updaterKey.PublicKey) {
+		return fmt.Errorf(
+			"the rewards updater of the devnet is %s, set --rewards-updater-key to its key",
+			updater.Hex(),
+		)
+	}
+
+	// The end of the calculation must be after the one of the latest root, and in the past both
+	// for the wall clock and for the devnet
+	current, err := s.coordinator.CurrRewardsCalculationEndTimestamp(opts)
+	if err != nil {
+		return err
+	}
+	endTimestamp := uint32(time.Now().Unix() - 1)
+	if endTimestamp <= current {
+		return fmt.Errorf("the latest root of the devnet ends at %d, which is not in the past", current)
+	}
+	if err := s.advanceTime(ctx, int64(endTimestamp)+1); err != nil {
+		return err
+	}
+
+	_, err = s.runCLI(ctx, "",
+		"rewards", "submit-root",
+		"--network", common.AnvilNetworkName,
+		"--eth-rpc-url", s.cfg.RPCURL,
+		"--rewards-coordinator-address", s.cfg.RewardsCoordinatorAddress.Hex(),
+		"--rewards-updater-address", updater.Hex(),
+		"--ecdsa-private-key", hexutil.Encode(crypto.FromECDSA(s.updaterKey))[2:],
+		"--root", root.Hex(),
+		"--rewards-calculation-end-timestamp", fmt.Sprint(endTimestamp),
+		"--broadcast",
+		"--yes",
+	)
+	if err != nil {
+		return err
+	}
+
+	// Claims are only possible once the root is activated
+	delay, err := s.coordinator.ActivationDelay(opts)
+	if err != nil {
+		return err
+	}
+	header, err := s.ethClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return s.advanceTime(ctx, int64(header.Time)+int64(delay)+1)
+}
+
+func (s *Scenario) showRewards(ctx context.Context) error {
+	output, err := s.runCLI(ctx, "",
+		"rewards", "show",
+		"--network", common.AnvilNetworkName,
+		"--eth-rpc-url", s.cfg.RPCURL,
+		"--earner-address", s.operatorAddress.Hex(),
+		"--proof-store-base-url", s.proofStoreURL,
+		"--claim-type", "all",
+		"--hide-zero=false",
+	)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(strings.ToLower(output), strings.ToLower(s.getToken().Hex())) {
+		return errors.New("the rewards of the test root are not shown")
+	}
+	return nil
+}
+
+func (s *Scenario) claimRewards(ctx context.Context) error {
+	if s.cfg.Token == (gethcommon.Address{}) {
+		return fmt.Errorf("%w: set --token-address to an ERC20 token of the devnet to test claims", ErrSkipped)
+	}
+	if err := s.setTokenBalance(ctx, s.cfg.RewardsCoordinatorAddress, claimAmount); err != nil {
+		return err
+	}
+	before, err := s.balanceOf(ctx, s.operatorAddress)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.runCLI(ctx, "",
+		"rewards", "claim",
+		"--network", common.AnvilNetworkName,
+		"--eth-rpc-url", s.cfg.RPCURL,
+		"--earner-address", s.operatorAddress.Hex(),
+		"--token-addresses", s.cfg.Token.Hex(),
+		"--proof-store-base-url", s.proofStoreURL,
+		"--ecdsa-private-key", hexutil.Encode(crypto.FromECDSA(s.operatorKey))[2:],
+		"--broadcast",
+	)
+	if err != nil {
+		return err
+	}
+
+	after, err := s.balanceOf(ctx, s.operatorAddress)
+	if err != nil {
+		return err
+	}
+	if received := new(big.Int).Sub(after, before); received.Cmp(claimAmount) != 0 {
+		return fmt.Errorf("the operator received %s of the token instead of %s", received, claimAmount)
+	}
+	return nil
+}
+
+func (s *Scenario) getToken() gethcommon.Address {
+	if s.cfg.Token == (gethcommon.Address{}) {
+		return placeholderToken
+	}
+	return s.cfg.Token
+}
+
+// advanceTime mines a block at the timestamp if the devnet is behind it
+func (s *Scenario) advanceTime(ctx context.Context, timestamp int64) error {
+	header, err := s.ethClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if int64(header.Time) >= timestamp {
+		return nil
+	}
+	if err := s.rpcClient.CallContext(ctx, nil, "evm_mine", timestamp); err != nil {
+		return fmt.Errorf("failed to advance the time of the devnet: %w", err)
+	}
+	return nil
+}
+
+// setTokenBalance sets the balance of an account in the balances mapping of the token, which
+// holds the balance of an account at keccak256(account . slot)
+func (s *Scenario) setTokenBalance(ctx context.Context, account gethcommon.Address, amount *big.Int) error {
+	slot := crypto.Keccak256Hash(
+		gethcommon.LeftPadBytes(account.Bytes(), 32),
+		gethcommon.LeftPadBytes(big.NewInt(s.cfg.TokenBalanceSlot).Bytes(), 32),
+	)
+	value := gethcommon.BigToHash(amount)
+	if err := s.rpcClient.CallContext(ctx, nil, "anvil_setStorageAt", s.cfg.Token, slot, value); err != nil {
+		return fmt.Errorf("failed to set the token balance of %s: %w", account.Hex(), err)
+	}
+	balance, err := s.balanceOf(ctx, account)
+	if err != nil {
+		return err
+	}
+	if balance.Cmp(amount) != 0 {
+		return fmt.Errorf(
+			"the balances of %s are not at slot %d, set --token-balance-slot",
+			s.cfg.Token.Hex(),
+			s.cfg.TokenBalanceSlot,
+		)
+	}
+	return nil
+}
+
+func (s *Scenario) balanceOf(ctx context.Context, account gethcommon.Address) (*big.Int, error) {
+	// balanceOf(address)
+	data := append(hexutil.MustDecode("0x70a08231"), gethcommon.LeftPadBytes(account.Bytes(), 32)...)
+	result, err := s.ethClient.CallContract(ctx, ethereum.CallMsg{To: &s.cfg.Token, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the token balance of %s: %w", account.Hex(), err)
+	}
+	return new(big.Int).SetBytes(result), nil
+}
+
+// runCLI runs the CLI with its home directory in the work dir, with the input piped to stdin,
+// e.g. the keystore password. The output goes to the log, and the last line of it is the error
+// if the run fails.
+func (s *Scenario) runCLI(ctx context.Context, input string, args ...string) (string, error) {
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.cfg.Executable, args...)
+	cmd.Env = getChildEnv(s.cfg.WorkDir)
+	cmd.Stdin = strings.NewReader(input + "\n")
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	_, _ = fmt.Fprintf(s.log, "$ eigenlayer %s\n", strings.Join(redactArgs(args), " "))
+	err := cmd.Run()
+	_, _ = s.log.Write(output.Bytes())
+	if err != nil {
+		command := strings.Join(args[:2], " ")
+		return output.String(), fmt.Errorf("eigenlayer %s failed: %s", command, lastLine(output.String(), err))
+	}
+	return output.String(), nil
+}
+
+// getChildEnv returns the environment of the CLI runs. A profile of the user would not be found in
+// the work dir, so profiles are not selected.
+func getChildEnv(workDir string) []string {
+	env := make([]string, 0, len(os.Environ())+1)
+	for _, entry := range os.Environ() {
+		if strings.HasPrefix(entry, "HOME=") || strings.HasPrefix(entry, "EIGENLAYER_PROFILE") {
+			continue
+		}
+		env = append(env, entry)
+	}
+	return append(env, "HOME="+workDir)
+}
+
+// redactArgs hides the private keys in the log
+func redactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		if _, err := hexutil.Decode("0x" + arg); err == nil && len(arg) == 64 {
+			arg = "***"
+		}
+		redacted[i] = arg
+	}
+	return redacted
+}
+
+func lastLine(output string, err error) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			return line
+		}
+	}
+	return err.Error()
+}
@@ -0,0 +1,37 @@
+package selftest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactArgs(t *testing.T) {
+	key := strings.Repeat("ab", 32)
+	args := []string{"rewards", "claim", "--ecdsa-private-key", key, "--root", "0x" + key}
+	assert.Equal(
+		t,
+		[]string{"rewards", "claim", "--ecdsa-private-key", "***", "--root", "0x" + key},
+		redactArgs(args),
+	)
+	// The args are not changed
+	assert.Equal(t, key, args[3])
+}
+
+func TestGetChildEnv(t *testing.T) {
+	t.Setenv("EIGENLAYER_PROFILE", "operator-a")
+	t.Setenv("ETH_RPC_URL", "http://localhost:8545")
+	env := getChildEnv("/tmp/selftest")
+	assert.Contains(t, env, "HOME=/tmp/selftest")
+	assert.Contains(t, env, "ETH_RPC_URL=http://localhost:8545")
+	for _, entry := range env {
+		assert.False(t, strings.HasPrefix(entry, "EIGENLAYER_PROFILE="))
+	}
+}
+
+func TestLastLine(t *testing.T) {
+	assert.Equal(t, "execution reverted", lastLine("sending\nexecution reverted\n\n", errors.New("exit status 1")))
+	assert.Equal(t, "exit status 1", lastLine("  \n", errors.New("exit status 1")))
+}
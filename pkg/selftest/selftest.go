@@ -0,0 +1,82 @@
+package selftest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Status of a step of the selftest
+const (
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// ErrSkipped is returned, wrapped with the reason, by a step which can't run in the environment,
+// e.g. the claim without a token
+var ErrSkipped = errors.New("skipped")
+
+// Step is a step of the scenario. Steps build on the ones before them, so the steps after a
+// failed step are skipped.
+type Step struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Result is the outcome of a step
+type Result struct {
+	Step       string `json:"step"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"durationMs"`
+	Message    string `json:"message,omitempty"`
+}
+
+// Run runs the steps in order and returns the result of every step. The function is called
+// after every step, e.g. to print progress.
+func Run(ctx context.Context, steps []Step, onResult func(Result)) []Result {
+	results := make([]Result, 0, len(steps))
+	var failed string
+	for _, step := range steps {
+		result := Result{Step: step.Name}
+		switch {
+		case failed != "":
+			result.Status = StatusSkipped
+			result.Message = fmt.Sprintf("step %s failed", failed)
+		case ctx.Err() != nil:
+			result.Status = StatusSkipped
+			result.Message = ctx.Err().Error()
+		default:
+			start := time.Now()
+			err := step.Run(ctx)
+			result.DurationMs = time.Since(start).Milliseconds()
+			switch {
+			case err == nil:
+				result.Status = StatusPassed
+			case errors.Is(err, ErrSkipped):
+				result.Status = StatusSkipped
+				result.Message = err.Error()
+			default:
+				result.Status = StatusFailed
+				result.Message = err.Error()
+				failed = step.Name
+			}
+		}
+		results = append(results, result)
+		if onResult != nil {
+			onResult(result)
+		}
+	}
+	return results
+}
+
+// Passed returns true if no step failed
+func Passed(results []Result) bool {
+	for _, result := range results {
+		if result.Status == StatusFailed {
+			return false
+		}
+	}
+	return true
+}
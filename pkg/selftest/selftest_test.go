@@ -0,0 +1,52 @@
+package selftest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	ran := make([]string, 0)
+	step := func(name string, err error) Step {
+		return Step{Name: name, Run: func(ctx context.Context) error {
+			ran = append(ran, name)
+			return err
+		}}
+	}
+
+	printed := 0
+	results := Run(context.Background(), []Step{
+		step("keys", nil),
+		step("claim", fmt.Errorf("%w: no token", ErrSkipped)),
+		step("register", errors.New("reverted")),
+		step("show", nil),
+	}, func(result Result) {
+		printed++
+	})
+
+	assert.Equal(t, []string{"keys", "claim", "register"}, ran)
+	assert.Equal(t, 4, printed)
+	assert.Equal(t, StatusPassed, results[0].Status)
+	assert.Equal(t, StatusSkipped, results[1].Status)
+	assert.Equal(t, "skipped: no token", results[1].Message)
+	assert.Equal(t, StatusFailed, results[2].Status)
+	assert.Equal(t, "reverted", results[2].Message)
+	assert.Equal(t, StatusSkipped, results[3].Status)
+	assert.Equal(t, "step register failed", results[3].Message)
+	assert.False(t, Passed(results))
+	assert.True(t, Passed(results[:2]))
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := Run(ctx, []Step{{Name: "keys", Run: func(ctx context.Context) error {
+		t.Fatal("step must not run")
+		return nil
+	}}}, nil)
+	assert.Equal(t, StatusSkipped, results[0].Status)
+}
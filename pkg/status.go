@@ -0,0 +1,191 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/keys"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operator/monitor"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/profile"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/rewards"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/status"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+var StatusWithdrawalLookbackFlag = cli.Uint64Flag{
+	Name:    "withdrawal-lookback-blocks",
+	Usage:   "Number of blocks to look back for withdrawals queued by the operator",
+	Value:   status.DefaultWithdrawalLookbackBlocks,
+	EnvVars: []string{"EIGENLAYER_STATUS_WITHDRAWAL_LOOKBACK_BLOCKS"},
+}
+
+func StatusCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "status",
+		Usage:     "Summarize the registration, allocations, rewards, withdrawals and keys of an operator on one screen",
+		UsageText: "status",
+		Description: `
+The one command to run every morning. It's meant to be run with a profile, which sets the network,
+the RPC and the operator address, e.g.
+
+eigenlayer --profile mainnet-operator status
+
+It summarizes
+- registration: the registration and metadata URI checks of 'operator monitor'
+- allocations: the operator sets the operator is registered to, whether it allocates to them and its
+  allocation delay. Shown as not supported on networks whose AllocationManager doesn't support
+  allocations yet
+- unclaimed rewards: total and unclaimed rewards per token in the latest distribution root
+- pending withdrawals: withdrawals queued by the operator in the last --withdrawal-lookback-blocks
+  blocks which were not completed yet, and the block from which they can be completed
+- keys: the keys in the keystore of the CLI, $HOME/.eigenlayer/operator_keys
+
+Anything which needs attention is listed as a warning: failing checks, sections which could not be
+read, operator sets without an allocation, withdrawals which can be completed and a keystore
+without the operator key. A section which can't be read doesn't stop the others from being shown.
+		`,
+		After: telemetry.AfterRunAction(),
+		Flags: getStatusFlags(),
+		Action: func(cCtx *cli.Context) error {
+			return Status(cCtx)
+		},
+	}
+}
+
+func getStatusFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OperatorAddressFlag,
+		&flags.OutputTypeFlag,
+		&flags.VerboseFlag,
+		&rewards.RewardsCoordinatorAddressFlag,
+		&StatusWithdrawalLookbackFlag,
+	}
+
+	sort.Sort(cli.FlagsByName(baseFlags))
+	return baseFlags
+}
+
+func Status(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+	if outputType != string(common.OutputType_Pretty) && outputType != string(common.OutputType_Json) {
+		return fmt.Errorf("unsupported output type %s, use 'pretty' or 'json'", outputType)
+	}
+
+	network := cCtx.String(flags.NetworkFlag.Name)
+	operatorAddress := cCtx.String(flags.OperatorAddressFlag.Name)
+	if !gethcommon.IsHexAddress(operatorAddress) {
+		return errors.New("operator address must be a valid address")
+	}
+
+	chainID := utils.NetworkNameToChainId(network)
+	cCtx.App.Metadata["network"] = chainID.String()
+	chainMetadata, ok := common.ChainMetadataMap[chainID.Int64()]
+	if !ok {
+		return fmt.Errorf("network %s is not supported", network)
+	}
+	rewardsCoordinatorAddress := cCtx.String(rewards.RewardsCoordinatorAddressFlag.Name)
+	if common.IsEmptyString(rewardsCoordinatorAddress) {
+		rewardsCoordinatorAddress = chainMetadata.ELRewardsCoordinatorAddress
+	}
+
+	homePath, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	ethClient, err := common.DialEthClient(cCtx.String(flags.ETHRpcUrlFlag.Name))
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	elReader, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
+		DelegationManagerAddress:  gethcommon.HexToAddress(chainMetadata.ELDelegationManagerAddress),
+		RewardsCoordinatorAddress: gethcommon.HexToAddress(rewardsCoordinatorAddress),
+	}, ethClient, nil, logger, nil)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create EL clients", err)
+	}
+
+	cfg := status.Config{
+		Network:                  network,
+		OperatorAddress:          gethcommon.HexToAddress(operatorAddress),
+		KeyStorePath:             filepath.Clean(filepath.Join(homePath, keys.OperatorKeystoreSubFolder)),
+		WithdrawalLookbackBlocks: cCtx.Uint64(StatusWithdrawalLookbackFlag.Name),
+	}
+	if selected := profile.Selected(cCtx); selected != nil {
+		cfg.Profile = selected.Name
+	}
+
+	allocationReader, err := monitor.NewAllocationReader(
+		ethClient,
+		gethcommon.HexToAddress(chainMetadata.ELAllocationManagerAddress),
+	)
+	if err != nil {
+		return err
+	}
+
+	src := status.Sources{
+		Checks: []monitor.Check{
+			monitor.NewRegistrationCheck(elReader, cfg.OperatorAddress),
+			monitor.NewMetadataCheck(contractBindings.DelegationManager, ethClient, cfg.OperatorAddress, ""),
+		},
+		Allocations: allocationReader,
+		Withdrawals: status.NewWithdrawalsReader(ethClient, contractBindings.DelegationManager),
+	}
+	snapshotReader, err := rewards.NewSnapshotReader(
+		ethClient,
+		network,
+		gethcommon.HexToAddress(rewardsCoordinatorAddress),
+		logger,
+	)
+	if err != nil {
+		// Shown as a warning of the rewards section
+		src.Rewards = unavailableRewards{err: err}
+	} else {
+		src.Rewards = snapshotReader
+	}
+
+	report := status.Collect(ctx, cfg, src)
+	if outputType == string(common.OutputType_Json) {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	status.Print(os.Stdout, report)
+	return nil
+}
+
+// unavailableRewards stands in for the rewards of a network without a proof store
+type unavailableRewards struct {
+	err error
+}
+
+func (r unavailableRewards) GetLatestSnapshot(
+	ctx context.Context,
+	earnerAddress gethcommon.Address,
+) (*rewards.RewardsSnapshot, error) {
+	return nil, r.err
+}
@@ -0,0 +1,62 @@
+package status
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operator/monitor"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// Allocations are the operator sets the operator is registered to, and whether it allocates to
+// them. Supported is false on networks whose AllocationManager doesn't support allocations yet.
+type Allocations struct {
+	Supported    bool                    `json:"supported"`
+	DelaySet     bool                    `json:"delaySet"`
+	Delay        uint32                  `json:"delay"`
+	OperatorSets []OperatorSetAllocation `json:"operatorSets"`
+}
+
+type OperatorSetAllocation struct {
+	Avs       string `json:"avs"`
+	Id        uint32 `json:"id"`
+	Allocated bool   `json:"allocated"`
+}
+
+type allocationsReader interface {
+	GetAllocations(ctx context.Context, operator gethcommon.Address) (*monitor.Allocations, error)
+}
+
+func getAllocations(
+	ctx context.Context,
+	reader allocationsReader,
+	operator gethcommon.Address,
+) (*Allocations, error) {
+	allocations, err := reader.GetAllocations(ctx, operator)
+	if errors.Is(err, monitor.ErrAllocationsUnsupported) {
+		return &Allocations{OperatorSets: make([]OperatorSetAllocation, 0)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	unallocated := make(map[monitor.OperatorSet]bool)
+	for _, set := range allocations.Unallocated() {
+		unallocated[set] = true
+	}
+	status := &Allocations{
+		Supported:    true,
+		DelaySet:     allocations.DelaySet,
+		Delay:        allocations.Delay,
+		OperatorSets: make([]OperatorSetAllocation, 0, len(allocations.RegisteredSets)),
+	}
+	for _, set := range allocations.RegisteredSets {
+		status.OperatorSets = append(status.OperatorSets, OperatorSetAllocation{
+			Avs:       set.Avs.Hex(),
+			Id:        set.Id,
+			Allocated: !unallocated[set],
+		})
+	}
+	return status, nil
+}
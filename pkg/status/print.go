@@ -0,0 +1,155 @@
+package status
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operator/monitor"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+)
+
+// Print prints the report as one screen of sections
+func Print(w io.Writer, report *Report) {
+	title := fmt.Sprintf("Operator %s on %s", common.RedactAddress(report.Operator), report.Network)
+	if report.Profile != "" {
+		title += fmt.Sprintf(" (profile %s)", report.Profile)
+	}
+	fmt.Fprintln(w, title)
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Registration")
+	for _, result := range report.Checks {
+		fmt.Fprintf(w, "%s %s: %s\n", statusMark(result.Status), result.Check, common.RedactText(result.Message))
+	}
+
+	fmt.Fprintln(w)
+	printAllocations(w, report.Allocations)
+	fmt.Fprintln(w)
+	printRewards(w, report.Rewards)
+	fmt.Fprintln(w)
+	printWithdrawals(w, report.Withdrawals)
+	fmt.Fprintln(w)
+	printKeys(w, report)
+
+	fmt.Fprintln(w)
+	if len(report.Warnings) == 0 {
+		fmt.Fprintf(w, "%s No warnings\n", utils.EmojiCheckMark)
+		return
+	}
+	fmt.Fprintf(w, "Warnings (%d)\n", len(report.Warnings))
+	for _, warning := range report.Warnings {
+		fmt.Fprintf(w, "%s %s\n", utils.EmojiWarning, common.RedactText(warning))
+	}
+}
+
+func printAllocations(w io.Writer, allocations *Allocations) {
+	switch {
+	case allocations == nil:
+		fmt.Fprintln(w, "Allocations: unavailable")
+		return
+	case !allocations.Supported:
+		fmt.Fprintln(w, "Allocations: not supported by the contracts deployed on this network")
+		return
+	case len(allocations.OperatorSets) == 0:
+		fmt.Fprintln(w, "Allocations: not registered to any operator set")
+		return
+	}
+	delay := "not set"
+	if allocations.DelaySet {
+		delay = fmt.Sprintf("%d", allocations.Delay)
+	}
+	fmt.Fprintf(w, "Allocations (allocation delay %s)\n", delay)
+	table := common.NewTableWithHeaders("AVS", "Operator Set", "Allocated")
+	for _, set := range allocations.OperatorSets {
+		allocated := "no"
+		if set.Allocated {
+			allocated = "yes"
+		}
+		table.AddRow(set.Avs, fmt.Sprintf("%d", set.Id), allocated)
+	}
+	table.Render(w)
+}
+
+func printRewards(w io.Writer, rewards *Rewards) {
+	switch {
+	case rewards == nil:
+		fmt.Fprintln(w, "Unclaimed rewards: unavailable")
+		return
+	case rewards.Date == "":
+		fmt.Fprintln(w, "Unclaimed rewards: none in the latest distribution root")
+		return
+	}
+	fmt.Fprintf(w, "Unclaimed rewards (snapshot %s, root index %d)\n", rewards.Date, rewards.RootIndex)
+	table := common.NewTableWithHeaders("Token", "Total (wei)", "Unclaimed (wei)")
+	for _, token := range rewards.Tokens {
+		table.AddRow(token.Token, common.LocalizeNumber(token.Total), common.LocalizeNumber(token.Unclaimed))
+	}
+	table.Render(w)
+}
+
+func printWithdrawals(w io.Writer, withdrawals *Withdrawals) {
+	if withdrawals == nil {
+		fmt.Fprintln(w, "Pending withdrawals: unavailable")
+		return
+	}
+	if len(withdrawals.Pending) == 0 {
+		fmt.Fprintf(
+			w,
+			"Pending withdrawals: none in the last %d blocks (%d queued in total)\n",
+			withdrawals.LookbackBlocks,
+			withdrawals.Queued,
+		)
+		return
+	}
+	fmt.Fprintf(w, "Pending withdrawals (%d)\n", len(withdrawals.Pending))
+	table := common.NewTableWithHeaders("Root", "Start Block", "Completable At", "Strategy", "Shares")
+	for _, withdrawal := range withdrawals.Pending {
+		completable := fmt.Sprintf("%d", withdrawal.CompletableBlock)
+		if withdrawal.Completable {
+			completable += " (now)"
+		}
+		// One row per strategy of the withdrawal
+		for i, strategy := range withdrawal.Strategies {
+			shares := ""
+			if i < len(withdrawal.Shares) {
+				shares = common.LocalizeNumber(withdrawal.Shares[i])
+			}
+			if i == 0 {
+				table.AddRow(withdrawal.Root, fmt.Sprintf("%d", withdrawal.StartBlock), completable, strategy, shares)
+			} else {
+				table.AddRow("", "", "", strategy, shares)
+			}
+		}
+	}
+	table.Render(w)
+}
+
+func printKeys(w io.Writer, report *Report) {
+	if len(report.Keys) == 0 {
+		fmt.Fprintf(w, "Keys: none in %s\n", report.KeyStore)
+		return
+	}
+	fmt.Fprintf(w, "Keys in %s\n", report.KeyStore)
+	table := common.NewTableWithHeaders("Name", "Type", "Address / Operator Id")
+	for _, key := range report.Keys {
+		id := key.Address
+		if id == "" {
+			id = key.OperatorID
+		}
+		table.AddRow(key.Name, strings.ToUpper(key.Type), id)
+	}
+	table.Render(w)
+}
+
+func statusMark(status monitor.Status) string {
+	switch status {
+	case monitor.StatusOK:
+		return utils.EmojiCheckMark
+	case monitor.StatusFailed, monitor.StatusError:
+		return utils.EmojiCrossMark
+	default:
+		return utils.EmojiInfo
+	}
+}
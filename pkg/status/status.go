@@ -0,0 +1,196 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/keys"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operator/monitor"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/rewards"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// Report is everything an operator checks every morning, collected in one run. A section which
+// can't be read is left out and reported as a warning, so one failing source never hides the rest.
+type Report struct {
+	Profile     string           `json:"profile,omitempty"`
+	Network     string           `json:"network"`
+	Operator    string           `json:"operator"`
+	Checks      []monitor.Result `json:"checks"`
+	Allocations *Allocations     `json:"allocations,omitempty"`
+	Rewards     *Rewards         `json:"rewards,omitempty"`
+	Withdrawals *Withdrawals     `json:"withdrawals,omitempty"`
+	KeyStore    string           `json:"keyStore"`
+	Keys        []keys.KeyInfo   `json:"keys"`
+	Warnings    []string         `json:"warnings"`
+}
+
+// Rewards are the rewards of the operator in the latest active distribution root. Date is empty
+// if the operator has no rewards in the root.
+type Rewards struct {
+	Date      string         `json:"date,omitempty"`
+	RootIndex uint32         `json:"rootIndex"`
+	Tokens    []TokenRewards `json:"tokens"`
+}
+
+type TokenRewards struct {
+	Token     string `json:"token"`
+	Total     string `json:"total"`
+	Unclaimed string `json:"unclaimed"`
+}
+
+type rewardsReader interface {
+	GetLatestSnapshot(ctx context.Context, earnerAddress gethcommon.Address) (*rewards.RewardsSnapshot, error)
+}
+
+// Config is what the report is collected for
+type Config struct {
+	Profile                  string
+	Network                  string
+	OperatorAddress          gethcommon.Address
+	KeyStorePath             string
+	WithdrawalLookbackBlocks uint64
+}
+
+// Sources are where the sections of the report are read from
+type Sources struct {
+	// Checks are the health checks of the operator, e.g. its registration and metadata URI
+	Checks      []monitor.Check
+	Allocations allocationsReader
+	Rewards     rewardsReader
+	Withdrawals WithdrawalsReader
+}
+
+// Collect collects the report of the operator
+func Collect(ctx context.Context, cfg Config, src Sources) *Report {
+	report := &Report{
+		Profile:  cfg.Profile,
+		Network:  cfg.Network,
+		Operator: cfg.OperatorAddress.Hex(),
+		Checks:   make([]monitor.Result, 0, len(src.Checks)),
+		KeyStore: cfg.KeyStorePath,
+		Keys:     make([]keys.KeyInfo, 0),
+		Warnings: make([]string, 0),
+	}
+
+	for _, check := range src.Checks {
+		result := check.Run(ctx)
+		result.Check = check.Name()
+		result.CheckedAt = time.Now()
+		report.Checks = append(report.Checks, result)
+		if result.Status == monitor.StatusFailed || result.Status == monitor.StatusError {
+			report.warn("%s: %s", result.Check, result.Message)
+		}
+	}
+
+	allocations, err := getAllocations(ctx, src.Allocations, cfg.OperatorAddress)
+	if err != nil {
+		report.warn("allocations: %s", err)
+	}
+	report.Allocations = allocations
+	if allocations != nil && allocations.Supported && len(allocations.OperatorSets) > 0 {
+		if !allocations.DelaySet {
+			report.warn("allocations: the allocation delay is not set")
+		}
+		unallocated := 0
+		for _, set := range allocations.OperatorSets {
+			if !set.Allocated {
+				unallocated++
+			}
+		}
+		if unallocated > 0 {
+			report.warn("allocations: no allocation to %d operator set(s)", unallocated)
+		}
+	}
+
+	rewardsStatus, err := getRewards(ctx, src.Rewards, cfg.OperatorAddress)
+	if err != nil {
+		report.warn("rewards: %s", err)
+	}
+	report.Rewards = rewardsStatus
+
+	withdrawals, err := getWithdrawals(ctx, src.Withdrawals, cfg.OperatorAddress, cfg.WithdrawalLookbackBlocks)
+	if err != nil {
+		report.warn("withdrawals: %s", err)
+	}
+	report.Withdrawals = withdrawals
+	if withdrawals != nil {
+		completable := 0
+		for _, withdrawal := range withdrawals.Pending {
+			if withdrawal.Completable {
+				completable++
+			}
+		}
+		if completable > 0 {
+			report.warn("withdrawals: %d pending withdrawal(s) can be completed", completable)
+		}
+	}
+
+	keyInfos, err := keys.ListKeys(cfg.KeyStorePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		report.warn("keys: %s", err)
+	}
+	if keyInfos != nil {
+		report.Keys = keyInfos
+	}
+	if warning := checkOperatorKey(report.Keys, cfg.OperatorAddress); warning != "" {
+		report.warn("keys: %s", warning)
+	}
+	return report
+}
+
+func (r *Report) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+func getRewards(ctx context.Context, reader rewardsReader, operator gethcommon.Address) (*Rewards, error) {
+	snapshot, err := reader.GetLatestSnapshot(ctx, operator)
+	if errors.Is(err, rewards.ErrEarnerNotFound) {
+		return &Rewards{Tokens: make([]TokenRewards, 0)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Rewards{
+		Date:      snapshot.Date,
+		RootIndex: snapshot.RootIndex,
+		Tokens:    make([]TokenRewards, 0, len(snapshot.Total)),
+	}
+	for token, total := range snapshot.Total {
+		tokenRewards := TokenRewards{Token: token.Hex(), Total: total.String(), Unclaimed: "0"}
+		if unclaimed, ok := snapshot.Unclaimed[token]; ok {
+			tokenRewards.Unclaimed = unclaimed.String()
+		}
+		status.Tokens = append(status.Tokens, tokenRewards)
+	}
+	sort.Slice(status.Tokens, func(i, j int) bool {
+		return status.Tokens[i].Token < status.Tokens[j].Token
+	})
+	return status, nil
+}
+
+// checkOperatorKey warns if the keystore has ECDSA keys, but none of the operator. Without any
+// ECDSA key the operator is assumed to sign with a remote signer.
+func checkOperatorKey(keyInfos []keys.KeyInfo, operator gethcommon.Address) string {
+	ecdsaKeys := 0
+	for _, key := range keyInfos {
+		if key.Type != keys.KeyTypeECDSA {
+			continue
+		}
+		ecdsaKeys++
+		if strings.EqualFold(key.Address, operator.Hex()) {
+			return ""
+		}
+	}
+	if ecdsaKeys == 0 {
+		return ""
+	}
+	return fmt.Sprintf("none of the %d ECDSA key(s) in the keystore is the operator key", ecdsaKeys)
+}
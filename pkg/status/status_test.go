@@ -0,0 +1,232 @@
+package status
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operator/monitor"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/rewards"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testOperator = gethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+	testToken    = gethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+	testStrategy = gethcommon.HexToAddress("0x3333333333333333333333333333333333333333")
+	testAvs      = gethcommon.HexToAddress("0x4444444444444444444444444444444444444444")
+)
+
+type fakeCheck struct {
+	name   string
+	result monitor.Result
+}
+
+func (c fakeCheck) Name() string {
+	return c.name
+}
+
+func (c fakeCheck) Run(ctx context.Context) monitor.Result {
+	return c.result
+}
+
+type fakeRewardsReader struct {
+	snapshot *rewards.RewardsSnapshot
+	err      error
+}
+
+func (r fakeRewardsReader) GetLatestSnapshot(
+	ctx context.Context,
+	earnerAddress gethcommon.Address,
+) (*rewards.RewardsSnapshot, error) {
+	return r.snapshot, r.err
+}
+
+type fakeAllocationsReader struct {
+	allocations *monitor.Allocations
+	err         error
+}
+
+func (r fakeAllocationsReader) GetAllocations(
+	ctx context.Context,
+	operator gethcommon.Address,
+) (*monitor.Allocations, error) {
+	return r.allocations, r.err
+}
+
+type fakeWithdrawalsReader struct {
+	queued      []QueuedWithdrawal
+	completed   map[[32]byte]bool
+	head        uint64
+	delay       uint64
+	lookback    uint64
+	cumulative  uint64
+	queuedError error
+}
+
+func (r *fakeWithdrawalsReader) CumulativeWithdrawalsQueued(
+	ctx context.Context,
+	staker gethcommon.Address,
+) (uint64, error) {
+	return r.cumulative, nil
+}
+
+func (r *fakeWithdrawalsReader) QueuedWithdrawals(
+	ctx context.Context,
+	staker gethcommon.Address,
+	lookbackBlocks uint64,
+) ([]QueuedWithdrawal, uint64, error) {
+	r.lookback = lookbackBlocks
+	return r.queued, r.head, r.queuedError
+}
+
+func (r *fakeWithdrawalsReader) IsPending(ctx context.Context, root [32]byte) (bool, error) {
+	return !r.completed[root], nil
+}
+
+func (r *fakeWithdrawalsReader) WithdrawalDelay(ctx context.Context, strategies []gethcommon.Address) (uint64, error) {
+	return r.delay, nil
+}
+
+func newTestWithdrawal(root byte, startBlock uint32) QueuedWithdrawal {
+	return QueuedWithdrawal{
+		Root:       [32]byte{root},
+		Nonce:      big.NewInt(int64(root)),
+		StartBlock: startBlock,
+		Strategies: []gethcommon.Address{testStrategy},
+		Shares:     []*big.Int{big.NewInt(1000)},
+	}
+}
+
+func TestCollect(t *testing.T) {
+	withdrawals := &fakeWithdrawalsReader{
+		queued: []QueuedWithdrawal{
+			newTestWithdrawal(1, 100),
+			newTestWithdrawal(2, 900),
+			newTestWithdrawal(3, 50),
+		},
+		completed:  map[[32]byte]bool{{3}: true},
+		head:       1000,
+		delay:      500,
+		cumulative: 3,
+	}
+	report := Collect(context.Background(), Config{
+		Profile:                  "mainnet-operator",
+		Network:                  "holesky",
+		OperatorAddress:          testOperator,
+		KeyStorePath:             filepath.Join(t.TempDir(), "missing"),
+		WithdrawalLookbackBlocks: 1000,
+	}, Sources{
+		Checks: []monitor.Check{
+			fakeCheck{name: monitor.RegistrationCheckName, result: monitor.Result{Status: monitor.StatusOK}},
+			fakeCheck{
+				name:   monitor.MetadataCheckName,
+				result: monitor.Result{Status: monitor.StatusFailed, Message: "metadata URI is not reachable"},
+			},
+		},
+		Allocations: fakeAllocationsReader{allocations: &monitor.Allocations{
+			DelaySet:       true,
+			Delay:          75,
+			RegisteredSets: []monitor.OperatorSet{{Avs: testAvs, Id: 1}, {Avs: testAvs, Id: 2}},
+			AllocatedSets:  []monitor.OperatorSet{{Avs: testAvs, Id: 1}},
+		}},
+		Rewards: fakeRewardsReader{snapshot: &rewards.RewardsSnapshot{
+			Date:      "2024-08-01",
+			RootIndex: 7,
+			Total:     map[gethcommon.Address]*big.Int{testToken: big.NewInt(300)},
+			Unclaimed: map[gethcommon.Address]*big.Int{testToken: big.NewInt(100)},
+		}},
+		Withdrawals: withdrawals,
+	})
+
+	assert.Equal(t, "mainnet-operator", report.Profile)
+	assert.Len(t, report.Checks, 2)
+	assert.Equal(t, monitor.RegistrationCheckName, report.Checks[0].Check)
+
+	assert.Equal(t, &Allocations{
+		Supported: true,
+		DelaySet:  true,
+		Delay:     75,
+		OperatorSets: []OperatorSetAllocation{
+			{Avs: testAvs.Hex(), Id: 1, Allocated: true},
+			{Avs: testAvs.Hex(), Id: 2, Allocated: false},
+		},
+	}, report.Allocations)
+
+	assert.Equal(t, "2024-08-01", report.Rewards.Date)
+	assert.Equal(t, []TokenRewards{{Token: testToken.Hex(), Total: "300", Unclaimed: "100"}}, report.Rewards.Tokens)
+
+	assert.Equal(t, uint64(1000), withdrawals.lookback)
+	assert.Len(t, report.Withdrawals.Pending, 2)
+	assert.True(t, report.Withdrawals.Pending[0].Completable)
+	assert.Equal(t, uint64(600), report.Withdrawals.Pending[0].CompletableBlock)
+	assert.False(t, report.Withdrawals.Pending[1].Completable)
+
+	// A missing keystore is an empty inventory
+	assert.Empty(t, report.Keys)
+	assert.Equal(t, []string{
+		"metadata_uri: metadata URI is not reachable",
+		"allocations: no allocation to 1 operator set(s)",
+		"withdrawals: 1 pending withdrawal(s) can be completed",
+	}, report.Warnings)
+
+	var out bytes.Buffer
+	Print(&out, report)
+	assert.Contains(t, out.String(), "profile mainnet-operator")
+	assert.Contains(t, out.String(), "Allocations (allocation delay 75)")
+	assert.Contains(t, out.String(), "Unclaimed rewards (snapshot 2024-08-01, root index 7)")
+	assert.Contains(t, out.String(), "600 (now)")
+	assert.Contains(t, out.String(), "Warnings (3)")
+}
+
+func TestCollectUnavailableSections(t *testing.T) {
+	report := Collect(context.Background(), Config{
+		Network:         "holesky",
+		OperatorAddress: testOperator,
+		KeyStorePath:    t.TempDir(),
+	}, Sources{
+		Allocations: fakeAllocationsReader{err: errors.New("rpc down")},
+		Rewards:     fakeRewardsReader{err: errors.New("no proof store")},
+		Withdrawals: &fakeWithdrawalsReader{
+			cumulative:  1,
+			queuedError: errors.New("query returned more than 10000 results"),
+		},
+	})
+
+	assert.Nil(t, report.Allocations)
+	assert.Nil(t, report.Rewards)
+	assert.Nil(t, report.Withdrawals)
+	assert.Equal(t, []string{
+		"allocations: rpc down",
+		"rewards: no proof store",
+		"withdrawals: query returned more than 10000 results",
+	}, report.Warnings)
+
+	var out bytes.Buffer
+	Print(&out, report)
+	assert.Contains(t, out.String(), "Allocations: unavailable")
+	assert.Contains(t, out.String(), "Unclaimed rewards: unavailable")
+	assert.Contains(t, out.String(), "Pending withdrawals: unavailable")
+}
+
+func TestCollectEarnerNotInSnapshot(t *testing.T) {
+	report := Collect(context.Background(), Config{OperatorAddress: testOperator, KeyStorePath: t.TempDir()}, Sources{
+		Allocations: fakeAllocationsReader{err: monitor.ErrAllocationsUnsupported},
+		Rewards:     fakeRewardsReader{err: rewards.ErrEarnerNotFound},
+		Withdrawals: &fakeWithdrawalsReader{},
+	})
+
+	assert.Empty(t, report.Warnings)
+	assert.False(t, report.Allocations.Supported)
+	assert.Equal(t, "", report.Rewards.Date)
+	assert.Empty(t, report.Withdrawals.Pending)
+
+	var out bytes.Buffer
+	Print(&out, report)
+	assert.Contains(t, out.String(), "Allocations: not supported by the contracts deployed on this network")
+}
@@ -0,0 +1,210 @@
+package status
+
+import (
+	"context"
+	"math/big"
+
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// DefaultWithdrawalLookbackBlocks is about two weeks of mainnet blocks, which covers the
+	// withdrawal delay of the core contracts with time to spare to complete the withdrawals
+	DefaultWithdrawalLookbackBlocks = 100800
+
+	// withdrawalLogsChunkBlocks is the block range of a log query, which most RPC providers accept
+	withdrawalLogsChunkBlocks = 10000
+)
+
+// Withdrawals are the withdrawals of the operator which were queued, but not completed yet.
+// Only withdrawals queued in the lookback window are found.
+type Withdrawals struct {
+	Queued         uint64              `json:"queued"`
+	LookbackBlocks uint64              `json:"lookbackBlocks"`
+	Pending        []PendingWithdrawal `json:"pending"`
+}
+
+type PendingWithdrawal struct {
+	Root             string   `json:"root"`
+	Nonce            string   `json:"nonce"`
+	StartBlock       uint32   `json:"startBlock"`
+	CompletableBlock uint64   `json:"completableBlock"`
+	Completable      bool     `json:"completable"`
+	Strategies       []string `json:"strategies"`
+	Shares           []string `json:"shares"`
+}
+
+// QueuedWithdrawal is a withdrawal queued by a staker
+type QueuedWithdrawal struct {
+	Root       [32]byte
+	Nonce      *big.Int
+	StartBlock uint32
+	Strategies []gethcommon.Address
+	Shares     []*big.Int
+}
+
+// WithdrawalsReader reads the withdrawals of a staker from the delegation manager
+type WithdrawalsReader interface {
+	// CumulativeWithdrawalsQueued is the number of withdrawals the staker ever queued
+	CumulativeWithdrawalsQueued(ctx context.Context, staker gethcommon.Address) (uint64, error)
+	// QueuedWithdrawals returns the withdrawals the staker queued in the last lookbackBlocks
+	// blocks, and the block they were read up to
+	QueuedWithdrawals(
+		ctx context.Context,
+		staker gethcommon.Address,
+		lookbackBlocks uint64,
+	) ([]QueuedWithdrawal, uint64, error)
+	IsPending(ctx context.Context, root [32]byte) (bool, error)
+	// WithdrawalDelay is the number of blocks a withdrawal from the strategies has to wait
+	WithdrawalDelay(ctx context.Context, strategies []gethcommon.Address) (uint64, error)
+}
+
+type delegationManager interface {
+	CumulativeWithdrawalsQueued(opts *bind.CallOpts, staker gethcommon.Address) (*big.Int, error)
+	PendingWithdrawals(opts *bind.CallOpts, root [32]byte) (bool, error)
+	GetWithdrawalDelay(opts *bind.CallOpts, strategies []gethcommon.Address) (*big.Int, error)
+	FilterWithdrawalQueued(
+		opts *bind.FilterOpts,
+	) (*delegationmanager.ContractDelegationManagerWithdrawalQueuedIterator, error)
+}
+
+type blockNumberReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+type withdrawalsReader struct {
+	client            blockNumberReader
+	delegationManager delegationManager
+}
+
+func NewWithdrawalsReader(client blockNumberReader, delegationManager delegationManager) WithdrawalsReader {
+	return &withdrawalsReader{client: client, delegationManager: delegationManager}
+}
+
+func (r *withdrawalsReader) CumulativeWithdrawalsQueued(
+	ctx context.Context,
+	staker gethcommon.Address,
+) (uint64, error) {
+	queued, err := r.delegationManager.CumulativeWithdrawalsQueued(&bind.CallOpts{Context: ctx}, staker)
+	if err != nil {
+		return 0, err
+	}
+	return queued.Uint64(), nil
+}
+
+// QueuedWithdrawals scans the WithdrawalQueued events in chunks. The staker of the event isn't
+// indexed, so the events of all stakers are read and filtered here.
+func (r *withdrawalsReader) QueuedWithdrawals(
+	ctx context.Context,
+	staker gethcommon.Address,
+	lookbackBlocks uint64,
+) ([]QueuedWithdrawal, uint64, error) {
+	head, err := r.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	start := uint64(0)
+	if head > lookbackBlocks {
+		start = head - lookbackBlocks
+	}
+
+	withdrawals := make([]QueuedWithdrawal, 0)
+	for from := start; from <= head; from += withdrawalLogsChunkBlocks {
+		to := min(from+withdrawalLogsChunkBlocks-1, head)
+		iter, err := r.delegationManager.FilterWithdrawalQueued(&bind.FilterOpts{Start: from, End: &to, Context: ctx})
+		if err != nil {
+			return nil, 0, err
+		}
+		for iter.Next() {
+			withdrawal := iter.Event.Withdrawal
+			if withdrawal.Staker != staker {
+				continue
+			}
+			withdrawals = append(withdrawals, QueuedWithdrawal{
+				Root:       iter.Event.WithdrawalRoot,
+				Nonce:      withdrawal.Nonce,
+				StartBlock: withdrawal.StartBlock,
+				Strategies: withdrawal.Strategies,
+				Shares:     withdrawal.Shares,
+			})
+		}
+		err = iter.Error()
+		iter.Close()
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return withdrawals, head, nil
+}
+
+func (r *withdrawalsReader) IsPending(ctx context.Context, root [32]byte) (bool, error) {
+	return r.delegationManager.PendingWithdrawals(&bind.CallOpts{Context: ctx}, root)
+}
+
+func (r *withdrawalsReader) WithdrawalDelay(ctx context.Context, strategies []gethcommon.Address) (uint64, error) {
+	delay, err := r.delegationManager.GetWithdrawalDelay(&bind.CallOpts{Context: ctx}, strategies)
+	if err != nil {
+		return 0, err
+	}
+	return delay.Uint64(), nil
+}
+
+func getWithdrawals(
+	ctx context.Context,
+	reader WithdrawalsReader,
+	staker gethcommon.Address,
+	lookbackBlocks uint64,
+) (*Withdrawals, error) {
+	queued, err := reader.CumulativeWithdrawalsQueued(ctx, staker)
+	if err != nil {
+		return nil, err
+	}
+	withdrawals := &Withdrawals{
+		Queued:         queued,
+		LookbackBlocks: lookbackBlocks,
+		Pending:        make([]PendingWithdrawal, 0),
+	}
+	if queued == 0 {
+		return withdrawals, nil
+	}
+
+	queuedWithdrawals, head, err := reader.QueuedWithdrawals(ctx, staker, lookbackBlocks)
+	if err != nil {
+		return nil, err
+	}
+	for _, queuedWithdrawal := range queuedWithdrawals {
+		pending, err := reader.IsPending(ctx, queuedWithdrawal.Root)
+		if err != nil {
+			return nil, err
+		}
+		if !pending {
+			continue
+		}
+		delay, err := reader.WithdrawalDelay(ctx, queuedWithdrawal.Strategies)
+		if err != nil {
+			return nil, err
+		}
+
+		completableBlock := uint64(queuedWithdrawal.StartBlock) + delay
+		withdrawal := PendingWithdrawal{
+			Root:             gethcommon.Hash(queuedWithdrawal.Root).Hex(),
+			Nonce:            queuedWithdrawal.Nonce.String(),
+			StartBlock:       queuedWithdrawal.StartBlock,
+			CompletableBlock: completableBlock,
+			Completable:      head >= completableBlock,
+			Strategies:       make([]string, 0, len(queuedWithdrawal.Strategies)),
+			Shares:           make([]string, 0, len(queuedWithdrawal.Shares)),
+		}
+		for _, strategy := range queuedWithdrawal.Strategies {
+			withdrawal.Strategies = append(withdrawal.Strategies, strategy.Hex())
+		}
+		for _, shares := range queuedWithdrawal.Shares {
+			withdrawal.Shares = append(withdrawal.Shares, shares.String())
+		}
+		withdrawals.Pending = append(withdrawals.Pending, withdrawal)
+	}
+	return withdrawals, nil
+}
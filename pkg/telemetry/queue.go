@@ -0,0 +1,97 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+)
+
+const (
+	// queueSubPath is the location of the telemetry queue inside the home directory
+	queueSubPath = ".eigenlayer/telemetry/queue.json"
+	// maxQueuedEvents bounds the queue while the telemetry endpoint is unreachable. The oldest
+	// events are dropped first.
+	maxQueuedEvents = 100
+	// flushInterval is the minimum time between two attempts to send the queue
+	flushInterval = time.Minute
+)
+
+// Event is a telemetry event waiting in the queue to be sent
+type Event struct {
+	DistinctID string                 `json:"distinctId"`
+	Name       string                 `json:"name"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// queue is the content of the queue file
+type queue struct {
+	Events    []Event   `json:"events"`
+	LastFlush time.Time `json:"lastFlush"`
+}
+
+func getQueuePath() string {
+	homePath, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homePath, queueSubPath)
+}
+
+// updateQueue changes the queue file under its lock, so parallel runs of the CLI don't lose events
+func updateQueue(path string, update func(q *queue) error) error {
+	return common.UpdateFileLocked(path, 0o600, func(data []byte) ([]byte, error) {
+		var q queue
+		if len(data) > 0 {
+			// A corrupt queue is dropped rather than blocking telemetry for good
+			_ = json.Unmarshal(data, &q)
+		}
+		if err := update(&q); err != nil {
+			return nil, err
+		}
+		return json.Marshal(q)
+	})
+}
+
+// enqueue adds the event to the queue and returns true if the queue is due to be sent
+func enqueue(path string, event Event, now time.Time) (bool, error) {
+	due := false
+	err := updateQueue(path, func(q *queue) error {
+		q.Events = append(q.Events, event)
+		if len(q.Events) > maxQueuedEvents {
+			q.Events = q.Events[len(q.Events)-maxQueuedEvents:]
+		}
+		if now.Sub(q.LastFlush) >= flushInterval {
+			// Claim the flush, so parallel runs don't all start one
+			q.LastFlush = now
+			due = true
+		}
+		return nil
+	})
+	return due, err
+}
+
+// takeEvents removes all events from the queue and returns them
+func takeEvents(path string) ([]Event, error) {
+	var events []Event
+	err := updateQueue(path, func(q *queue) error {
+		events = q.Events
+		q.Events = nil
+		return nil
+	})
+	return events, err
+}
+
+// requeue puts events which could not be sent back in front of the queue
+func requeue(path string, events []Event) error {
+	return updateQueue(path, func(q *queue) error {
+		q.Events = append(append([]Event{}, events...), q.Events...)
+		if len(q.Events) > maxQueuedEvents {
+			q.Events = q.Events[len(q.Events)-maxQueuedEvents:]
+		}
+		return nil
+	})
+}
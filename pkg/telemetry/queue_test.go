@@ -0,0 +1,55 @@
+package telemetry
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry", "queue.json")
+	now := time.Now()
+
+	due, err := enqueue(path, Event{Name: "first"}, now)
+	assert.NoError(t, err)
+	assert.True(t, due)
+
+	// Flushes are rate limited
+	due, err = enqueue(path, Event{Name: "second"}, now.Add(time.Second))
+	assert.NoError(t, err)
+	assert.False(t, due)
+	due, err = enqueue(path, Event{Name: "third"}, now.Add(flushInterval))
+	assert.NoError(t, err)
+	assert.True(t, due)
+
+	events, err := takeEvents(path)
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+	events, err = takeEvents(path)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestQueueIsBounded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+	for i := 0; i < maxQueuedEvents+10; i++ {
+		_, err := enqueue(path, Event{Name: fmt.Sprintf("event-%d", i)}, time.Now())
+		assert.NoError(t, err)
+	}
+
+	events, err := takeEvents(path)
+	assert.NoError(t, err)
+	assert.Len(t, events, maxQueuedEvents)
+	assert.Equal(t, "event-10", events[0].Name)
+
+	// Events which could not be sent go back in front of newer ones
+	assert.NoError(t, requeue(path, events[:2]))
+	_, err = enqueue(path, Event{Name: "new"}, time.Now())
+	assert.NoError(t, err)
+	events, err = takeEvents(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"event-10", "event-11", "new"}, []string{events[0].Name, events[1].Name, events[2].Name})
+}
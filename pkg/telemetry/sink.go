@@ -0,0 +1,209 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+
+	"github.com/posthog/posthog-go"
+)
+
+var errSendFailed = errors.New("failed to send telemetry events")
+
+// SendError is returned by a sink which could only send some of the events. Only the failed
+// events are queued again, so the sent ones are not sent twice.
+type SendError struct {
+	Failed []Event
+}
+
+func (e *SendError) Error() string {
+	return fmt.Sprintf("%s: %d event(s) failed", errSendFailed, len(e.Failed))
+}
+
+func (e *SendError) Unwrap() error {
+	return errSendFailed
+}
+
+// Sink receives the telemetry events of the CLI. The events are sent in the background, from a
+// detached run of the same binary, so a sink set with SetSink in main is used there as well.
+// Apps without FlushCmd send them from the run itself. Send must return once ctx is done.
+type Sink interface {
+	Send(ctx context.Context, events []Event) error
+}
+
+var sink Sink
+
+// SetSink replaces the default sink, which sends events to PostHog if the binary was built with
+// a telemetry token. It must be called before the app runs.
+func SetSink(s Sink) {
+	sink = s
+}
+
+func getSink() Sink {
+	if sink != nil {
+		return sink
+	}
+	if telemetryToken == "" {
+		return NoopSink{}
+	}
+	return &posthogSink{token: telemetryToken, endpoint: telemetryInstance}
+}
+
+// NoopSink drops all events. Nothing is queued while it is the sink.
+type NoopSink struct{}
+
+func (NoopSink) Send(ctx context.Context, events []Event) error {
+	return nil
+}
+
+// fileSink appends events to a file as JSON lines
+type fileSink struct {
+	path string
+}
+
+func NewFileSink(path string) Sink {
+	return &fileSink{path: path}
+}
+
+func (s *fileSink) Send(ctx context.Context, events []Event) error {
+	return common.UpdateFileLocked(s.path, 0o600, func(data []byte) ([]byte, error) {
+		for _, event := range events {
+			line, err := json.Marshal(event)
+			if err != nil {
+				return nil, err
+			}
+			data = append(append(data, line...), '\n')
+		}
+		return data, nil
+	})
+}
+
+// httpSink posts events as a JSON array to a URL
+type httpSink struct {
+	client *http.Client
+	url    string
+}
+
+func NewHTTPSink(url string, client *http.Client) Sink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &httpSink{client: client, url: url}
+}
+
+func (s *httpSink) Send(ctx context.Context, events []Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: status %d", errSendFailed, resp.StatusCode)
+	}
+	return nil
+}
+
+// posthogSink is the default sink of released binaries
+type posthogSink struct {
+	token    string
+	endpoint string
+}
+
+func (s *posthogSink) Send(ctx context.Context, events []Event) error {
+	callback := &posthogCallback{failed: make(map[string]bool)}
+	// The client doesn't take a context, so requests and retries stop through the transport and
+	// the retry policy once ctx is done
+	backoff := posthog.DefaultBacko()
+	client, err := posthog.NewWithConfig(s.token, posthog.Config{
+		Endpoint:  s.endpoint,
+		Callback:  callback,
+		Transport: contextTransport{ctx: ctx, base: http.DefaultTransport},
+		RetryAfter: func(attempt int) time.Duration {
+			if ctx.Err() != nil {
+				return 0
+			}
+			return backoff.Duration(attempt)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		_ = client.Enqueue(posthog.Capture{
+			DistinctId: event.DistinctID,
+			Event:      event.Name,
+			Timestamp:  event.Timestamp,
+			Properties: event.Properties,
+		})
+	}
+	// Close waits until the events are sent
+	_ = client.Close()
+
+	if callback.all {
+		return errSendFailed
+	}
+	failed := make([]Event, 0)
+	for _, event := range events {
+		if callback.failed[posthogEventKey(event.DistinctID, event.Name, event.Timestamp)] {
+			failed = append(failed, event)
+		}
+	}
+	if len(failed) > 0 {
+		return &SendError{Failed: failed}
+	}
+	return nil
+}
+
+// contextTransport sends the requests of a client with ctx
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
+
+// posthogEventKey identifies an event in the callbacks of the client, which only get the message
+// sent to the API
+func posthogEventKey(distinctID string, name string, timestamp time.Time) string {
+	return fmt.Sprintf("%s|%s|%d", distinctID, name, timestamp.UnixNano())
+}
+
+// posthogCallback records the events which failed to send. The client calls it from its own
+// goroutine before Close returns.
+type posthogCallback struct {
+	mu     sync.Mutex
+	failed map[string]bool
+	// all is set if a failed message can't be matched to its event
+	all bool
+}
+
+func (c *posthogCallback) Success(posthog.APIMessage) {}
+
+func (c *posthogCallback) Failure(msg posthog.APIMessage, _ error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	capture, ok := msg.(posthog.CaptureInApi)
+	if !ok {
+		c.all = true
+		return
+	}
+	c.failed[posthogEventKey(capture.DistinctId, capture.Event, capture.Timestamp)] = true
+}
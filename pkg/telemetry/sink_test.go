@@ -0,0 +1,119 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+)
+
+type failingSink struct{}
+
+func (failingSink) Send(ctx context.Context, events []Event) error {
+	return errors.New("offline")
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink := NewFileSink(path)
+	assert.NoError(t, sink.Send(context.Background(), []Event{{Name: "first"}}))
+	assert.NoError(t, sink.Send(context.Background(), []Event{{Name: "second"}}))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 2)
+	var event Event
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &event))
+	assert.Equal(t, "second", event.Name)
+}
+
+func TestHTTPSink(t *testing.T) {
+	var received []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	err := NewHTTPSink(server.URL, nil).Send(context.Background(), []Event{{Name: "event"}})
+	assert.NoError(t, err)
+	assert.Len(t, received, 1)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	err = NewHTTPSink(failing.URL, nil).Send(context.Background(), []Event{{Name: "event"}})
+	assert.True(t, errors.Is(err, errSendFailed))
+}
+
+func TestFlush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+	_, err := enqueue(path, Event{Name: "event"}, time.Now())
+	assert.NoError(t, err)
+
+	// Events stay queued while the sink is unreachable
+	assert.NoError(t, flush(context.Background(), path, failingSink{}))
+	eventsFile := filepath.Join(t.TempDir(), "events.jsonl")
+	assert.NoError(t, flush(context.Background(), path, NewFileSink(eventsFile)))
+
+	events, err := takeEvents(path)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+	data, err := os.ReadFile(eventsFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"name":"event"`)
+}
+
+// partialSink sends every event but the first one
+type partialSink struct{}
+
+func (partialSink) Send(ctx context.Context, events []Event) error {
+	return &SendError{Failed: events[:1]}
+}
+
+func TestFlushRequeuesFailedEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+	for _, name := range []string{"first", "second"} {
+		_, err := enqueue(path, Event{Name: name}, time.Now())
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, flush(context.Background(), path, partialSink{}))
+	events, err := takeEvents(path)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "first", events[0].Name)
+}
+
+func TestFlushInProcessWithoutFlushCmd(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(cliTelemetryEnabledKey, "true")
+	eventsFile := filepath.Join(t.TempDir(), "events.jsonl")
+	SetSink(NewFileSink(eventsFile))
+	defer SetSink(nil)
+
+	// An embedding app which doesn't register FlushCmd can't flush from a background process
+	app := &cli.App{
+		Name:     "embedder",
+		Commands: []*cli.Command{{Name: "status", Action: func(cCtx *cli.Context) error { return nil }}},
+		After:    AfterRunAction(),
+	}
+	assert.NoError(t, app.Run([]string{"embedder", "status"}))
+
+	data, err := os.ReadFile(eventsFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"name":"eigenlayer-cli"`)
+	events, err := takeEvents(getQueuePath())
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
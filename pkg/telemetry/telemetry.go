@@ -1,23 +1,30 @@
 package telemetry
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"os/user"
 	"runtime"
 	"strconv"
+	"time"
 
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
 
-	"github.com/posthog/posthog-go"
-
 	"github.com/urfave/cli/v2"
 )
 
 const (
 	cliTelemetryEnabledKey = "EIGENLAYER_CLI_TELEMETRY_ENABLED"
+
+	// flushCommandName is the hidden command which sends the queued events in the background
+	flushCommandName = "telemetry-flush"
+	// inProcessFlushTimeout bounds the flush of apps which don't register FlushCmd
+	inProcessFlushTimeout = 5 * time.Second
 )
 
 // telemetryToken value is set at build and install scripts using ldflags
@@ -28,26 +35,54 @@ var (
 	version           = "development"
 )
 
+// AfterRunAction queues a telemetry event for the command. Events are sent to the Sink by a
+// background process, so a slow or unreachable sink never slows down or fails a command.
+// While the endpoint is unreachable the events are kept in a bounded queue and sent later.
+// Apps which embed the commands without registering FlushCmd send the events in-process instead,
+// waiting at most a few seconds.
 func AfterRunAction() cli.AfterFunc {
 	return func(c *cli.Context) error {
-		if isEnabled() {
+		if isEnabled() && c.Command.Name != flushCommandName {
 			handleTacking(c)
 		}
 		return nil
 	}
 }
 
+// IsFlushRun returns whether the app runs the background flush. The flush is not a command run of
+// the user, so it must not be tracked or check for a new version.
+func IsFlushRun(cCtx *cli.Context) bool {
+	return cCtx.Args().First() == flushCommandName
+}
+
+// FlushCmd is the hidden command run in the background to send the queued events
+func FlushCmd() *cli.Command {
+	return &cli.Command{
+		Name:   flushCommandName,
+		Usage:  "Send queued telemetry events",
+		Hidden: true,
+		Action: func(cCtx *cli.Context) error {
+			if !isEnabled() {
+				return nil
+			}
+			return flush(cCtx.Context, getQueuePath(), getSink())
+		},
+	}
+}
+
 func isEnabled() bool {
 	telemetryEnabled := os.Getenv(cliTelemetryEnabledKey)
 	return len(telemetryEnabled) == 0 || telemetryEnabled == "true"
 }
 
 func handleTacking(cCtx *cli.Context) {
-	if telemetryToken == "" {
+	if _, ok := getSink().(NoopSink); ok {
+		return
+	}
+	path := getQueuePath()
+	if path == "" {
 		return
 	}
-	client, _ := posthog.NewWithConfig(telemetryToken, posthog.Config{Endpoint: telemetryInstance})
-	defer client.Close()
 
 	// In v3, c.Command.FullName() can be used to get the full command name
 	// TODO(madhur): to update once v3 is released
@@ -69,9 +104,61 @@ func handleTacking(cCtx *cli.Context) {
 	telemetryProperties["version"] = version
 	telemetryProperties["os"] = runtime.GOOS
 	telemetryProperties["network"] = network
-	_ = client.Enqueue(posthog.Capture{
-		DistinctId: userID,
-		Event:      "eigenlayer-cli",
+
+	due, err := enqueue(path, Event{
+		DistinctID: userID,
+		Name:       "eigenlayer-cli",
+		Timestamp:  time.Now().UTC(),
 		Properties: telemetryProperties,
-	})
+	}, time.Now())
+	// Telemetry must never fail a command
+	if err != nil || !due {
+		return
+	}
+	if cCtx.App.Command(flushCommandName) == nil {
+		flushInProcess(cCtx.Context, path)
+		return
+	}
+	startFlush()
+}
+
+// flushInProcess sends the queue from the current process, for apps which can't be run to flush
+func flushInProcess(ctx context.Context, path string) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, inProcessFlushTimeout)
+	defer cancel()
+	_ = flush(ctx, path, getSink())
+}
+
+// startFlush sends the queue from a detached process, which the command doesn't wait for. The
+// process runs FlushCmd, which the app must register.
+func startFlush() {
+	executable, err := os.Executable()
+	if err != nil {
+		return
+	}
+	// #nosec G204 -- the command runs the CLI binary itself
+	cmd := exec.Command(executable, flushCommandName)
+	if err := cmd.Start(); err != nil {
+		return
+	}
+	_ = cmd.Process.Release()
+}
+
+// flush sends the queued events to the sink. Events which could not be sent are put back in the queue.
+func flush(ctx context.Context, path string, s Sink) error {
+	events, err := takeEvents(path)
+	if err != nil || len(events) == 0 {
+		return err
+	}
+	if err := s.Send(ctx, events); err != nil {
+		var sendErr *SendError
+		if errors.As(err, &sendErr) {
+			return requeue(path, sendErr.Failed)
+		}
+		return requeue(path, events)
+	}
+	return nil
 }
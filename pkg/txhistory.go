@@ -0,0 +1,20 @@
+package pkg
+
+import (
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/txhistory"
+
+	"github.com/urfave/cli/v2"
+)
+
+func TxHistoryCmd() *cli.Command {
+	var txHistoryCmd = &cli.Command{
+		Name:  "tx-history",
+		Usage: "Inspect and re-check the transactions sent by the CLI",
+		Subcommands: []*cli.Command{
+			txhistory.ListCmd(),
+			txhistory.CheckCmd(),
+		},
+	}
+
+	return txHistoryCmd
+}
@@ -0,0 +1,121 @@
+package txhistory
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/urfave/cli/v2"
+)
+
+var RebroadcastFlag = cli.BoolFlag{
+	Name:    "rebroadcast",
+	Usage:   "Send the transactions whose confirmation disappeared again",
+	EnvVars: []string{"REBROADCAST"},
+}
+
+func ListCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "list",
+		Usage:     "List the transactions sent by the CLI",
+		UsageText: "list",
+		Description: `
+List the transactions recorded in the local tx history, with the status of their confirmation.
+		`,
+		After: telemetry.AfterRunAction(),
+		Flags: []cli.Flag{
+			&flags.VerboseFlag,
+			&flags.OutputTypeFlag,
+		},
+		Action: func(cCtx *cli.Context) error {
+			entries, err := common.ReadTxHistory(common.GetTxHistoryPath())
+			if err != nil {
+				return eigenSdkUtils.WrapError("failed to read tx history", err)
+			}
+			return printEntries(entries, cCtx.String(flags.OutputTypeFlag.Name))
+		},
+	}
+}
+
+func CheckCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "check",
+		Usage:     "Re-check the confirmations of the transactions sent by the CLI",
+		UsageText: "check",
+		Description: `
+Re-check the confirmations recorded in the local tx history for the chain of the RPC. Transactions
+which are not included anymore after a reorg are marked as reorged, and with --rebroadcast they are
+sent again.
+		`,
+		After: telemetry.AfterRunAction(),
+		Flags: []cli.Flag{
+			&flags.VerboseFlag,
+			&flags.ETHRpcUrlFlag,
+			&flags.OutputTypeFlag,
+			&RebroadcastFlag,
+		},
+		Action: func(cCtx *cli.Context) error {
+			logger := common.GetLogger(cCtx)
+			ethClient, err := common.DialEthClient(cCtx.String(flags.ETHRpcUrlFlag.Name))
+			if err != nil {
+				return eigenSdkUtils.WrapError("failed to create new eth client", err)
+			}
+			chainID, err := ethClient.ChainID(cCtx.Context)
+			if err != nil {
+				return eigenSdkUtils.WrapError("failed to get chain ID", err)
+			}
+			cCtx.App.Metadata["network"] = chainID.String()
+
+			entries, err := common.CheckTxHistory(
+				cCtx.Context,
+				ethClient,
+				common.GetTxHistoryPath(),
+				chainID,
+				cCtx.Bool(RebroadcastFlag.Name),
+				logger,
+			)
+			if err != nil {
+				return eigenSdkUtils.WrapError("failed to check tx history", err)
+			}
+			return printEntries(entries, cCtx.String(flags.OutputTypeFlag.Name))
+		},
+	}
+}
+
+func printEntries(entries []common.TxHistoryEntry, outputType string) error {
+	table := common.NewTableWithHeaders("Sent At", "Chain ID", "Command", "Tx Hash", "Block", "Status")
+	for _, entry := range entries {
+		block := ""
+		if entry.BlockNumber != 0 {
+			block = strconv.FormatUint(entry.BlockNumber, 10)
+		}
+		table.AddRow(
+			common.FormatTime(entry.SentAt),
+			entry.ChainID,
+			entry.Command,
+			entry.TxHash,
+			block,
+			string(entry.Status),
+		)
+	}
+
+	switch outputType {
+	case string(common.OutputType_Json):
+		out, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	case string(common.OutputType_Markdown):
+		table.PrintMarkdown()
+	default:
+		table.Print()
+	}
+	return nil
+}